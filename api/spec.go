@@ -0,0 +1,11 @@
+// Package api holds the OpenAPI spec the server implements. It is embedded
+// so the binary validates requests against, and serves, the spec it was
+// built from wherever it runs.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI document in openapi.yaml.
+//
+//go:embed openapi.yaml
+var Spec []byte
@@ -0,0 +1,164 @@
+// Command apikeys manages a store's API keys.
+//
+//	apikeys mint   -store acme -scopes orders:write -label pos [-expires 720h]
+//	apikeys rotate -store acme -prefix 0123456789ab [-overlap 24h]
+//	apikeys revoke -store acme -prefix 0123456789ab
+//	apikeys list   -store acme
+//
+// Minted keys are printed once; only their salted hash is stored.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"kart/internal/config"
+	"kart/internal/repo"
+	"kart/internal/service"
+	"kart/internal/sqlc"
+	"kart/internal/store"
+	"kart/internal/tenant"
+)
+
+const usage = "usage: apikeys mint|rotate|revoke|list -store ID [flags]"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	storeID := fs.String("store", "default", "ID of the store the keys belong to")
+	var (
+		scopes  *string
+		label   *string
+		expires *time.Duration
+		prefix  *string
+		overlap *time.Duration
+	)
+	switch cmd {
+	case "mint":
+		scopes = fs.String("scopes", "", "comma-separated scopes, e.g. orders:write,coupons:admin")
+		label = fs.String("label", "", "free-form note on who holds the key")
+		expires = fs.Duration("expires", 0, "lifetime of the key; 0 never expires")
+	case "rotate":
+		prefix = fs.String("prefix", "", "prefix of the key to replace")
+		overlap = fs.Duration("overlap", 24*time.Hour, "how long the old key keeps working")
+	case "revoke":
+		prefix = fs.String("prefix", "", "prefix of the key to revoke")
+	case "list":
+	default:
+		log.Fatal(usage)
+	}
+	_ = fs.Parse(os.Args[2:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	keys, closeDB, err := open(ctx, *storeID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeDB()
+	ctx = tenant.WithStore(ctx, *storeID)
+
+	switch cmd {
+	case "mint":
+		var expiresAt time.Time
+		if *expires > 0 {
+			expiresAt = time.Now().Add(*expires)
+		}
+		k, err := keys.Mint(ctx, *label, splitScopes(*scopes), expiresAt)
+		if err != nil {
+			log.Fatalf("mint: %v", err)
+		}
+		printMinted(k)
+	case "rotate":
+		k, err := keys.Rotate(ctx, *prefix, *overlap)
+		if err != nil {
+			log.Fatalf("rotate: %v", err)
+		}
+		printMinted(k)
+		fmt.Fprintf(os.Stderr, "key %s stops working at %s\n", *prefix, time.Now().Add(*overlap).UTC().Format(time.RFC3339))
+	case "revoke":
+		if err := keys.Revoke(ctx, *prefix); err != nil {
+			log.Fatalf("revoke: %v", err)
+		}
+	case "list":
+		ks, err := keys.List(ctx)
+		if err != nil {
+			log.Fatalf("list: %v", err)
+		}
+		printKeys(ks)
+	}
+}
+
+// open connects to the database and checks that storeID exists.
+func open(ctx context.Context, storeID string) (*service.APIKeyService, func(), error) {
+	if !tenant.ValidID(storeID) {
+		return nil, nil, fmt.Errorf("invalid -store %q", storeID)
+	}
+	cfg := config.Load()
+	sdb, err := store.Open(cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open db: %w", err)
+	}
+	closeDB := func() { _ = sdb.Close() }
+	q := sqlc.New(sdb)
+	if _, err := q.GetStore(ctx, storeID); err != nil {
+		closeDB()
+		return nil, nil, fmt.Errorf("store %s: %w", storeID, err)
+	}
+	return service.NewAPIKeyService(repo.NewAPIKeyRepo(q)), closeDB, nil
+}
+
+func splitScopes(s string) []string {
+	var out []string
+	for _, sc := range strings.Split(s, ",") {
+		if sc = strings.TrimSpace(sc); sc != "" {
+			out = append(out, sc)
+		}
+	}
+	return out
+}
+
+func printMinted(k service.MintedKey) {
+	fmt.Fprintf(os.Stderr, "minted key %s with scopes [%s]; it will not be shown again\n", k.Prefix, strings.Join(k.Scopes, " "))
+	fmt.Println(k.Key)
+}
+
+func printKeys(ks []repo.APIKey) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "PREFIX\tLABEL\tSCOPES\tCREATED\tEXPIRES\tLAST USED\tSTATUS")
+	now := time.Now()
+	for _, k := range ks {
+		status := "active"
+		switch {
+		case k.RevokedAt.Valid:
+			status = "revoked"
+		case k.ExpiresAt.Valid && !now.Before(k.ExpiresAt.Time):
+			status = "expired"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.Prefix, k.Label, strings.Join(k.Scopes, ","),
+			k.CreatedAt.Format(time.RFC3339), nullTime(k.ExpiresAt.Time, k.ExpiresAt.Valid),
+			nullTime(k.LastUsedAt.Time, k.LastUsedAt.Valid), status)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func nullTime(t time.Time, valid bool) string {
+	if !valid {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
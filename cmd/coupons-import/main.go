@@ -17,20 +17,23 @@ import (
 	"kart/internal/config"
 	"kart/internal/sqlc"
 	"kart/internal/store"
+	"kart/internal/tenant"
 )
 
-// Importer ingests coupon codes from a newline-delimited file into the database.
-// It streams the file to avoid excessive memory use and batches inserts for throughput.
+// Importer ingests coupon codes from a newline-delimited file into one store's
+// coupons. It streams the file to avoid excessive memory use and batches
+// inserts for throughput.
 type Importer struct {
 	db        *sql.DB
+	storeID   string
 	batchSize int
 }
 
-func newImporter(db *sql.DB, batchSize int) *Importer {
+func newImporter(db *sql.DB, storeID string, batchSize int) *Importer {
 	if batchSize <= 0 {
 		batchSize = 1000
 	}
-	return &Importer{db: db, batchSize: batchSize}
+	return &Importer{db: db, storeID: storeID, batchSize: batchSize}
 }
 
 func (im *Importer) insertBatch(ctx context.Context, codes []string) error {
@@ -48,7 +51,7 @@ func (im *Importer) insertBatch(ctx context.Context, codes []string) error {
 	}()
 
 	// Insert with initial presence bit; on conflict, increment 8-bit counter (wrap at 256)
-	stmt, err := tx.PrepareContext(ctx, "INSERT INTO coupons (code, presence_mask) VALUES ($1, B'00000001') ON CONFLICT (code) DO UPDATE SET presence_mask = (((coupons.presence_mask)::int + 1) % 256)::bit(8)")
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO coupons (store_id, code, presence_mask) VALUES ($1, $2, B'00000001') ON CONFLICT (store_id, code) DO UPDATE SET presence_mask = (((coupons.presence_mask)::int + 1) % 256)::bit(8)")
 	if err != nil {
 		return err
 	}
@@ -58,7 +61,7 @@ func (im *Importer) insertBatch(ctx context.Context, codes []string) error {
 		if code == "" {
 			continue
 		}
-		if _, err := stmt.ExecContext(ctx, code); err != nil {
+		if _, err := stmt.ExecContext(ctx, im.storeID, code); err != nil {
 			return err
 		}
 	}
@@ -69,10 +72,13 @@ func (im *Importer) insertBatch(ctx context.Context, codes []string) error {
 	return nil
 }
 
-func run(ctx context.Context, path string, batchSize int) error {
+func run(ctx context.Context, path, storeID string, batchSize int) error {
 	if path == "" {
 		return errors.New("-file is required")
 	}
+	if !tenant.ValidID(storeID) {
+		return fmt.Errorf("invalid -store %q", storeID)
+	}
 
 	cfg := config.Load()
 	sdb, err := store.Open(cfg.DatabaseURL)
@@ -86,8 +92,9 @@ func run(ctx context.Context, path string, batchSize int) error {
 		return fmt.Errorf("db ping: %w", err)
 	}
 
-	// Touch sqlc to ensure it's linked and available for migrations in other cmds
-	_ = sqlc.New(sdb)
+	if _, err := sqlc.New(sdb).GetStore(ctx, storeID); err != nil {
+		return fmt.Errorf("store %s: %w", storeID, err)
+	}
 
 	f, err := os.Open(path)
 	if err != nil {
@@ -102,7 +109,7 @@ func run(ctx context.Context, path string, batchSize int) error {
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, maxCapacity)
 
-	importer := newImporter(sdb.DB, batchSize)
+	importer := newImporter(sdb.DB, storeID, batchSize)
 
 	var (
 		batch   []string
@@ -152,16 +159,18 @@ func run(ctx context.Context, path string, batchSize int) error {
 func main() {
 	var (
 		filePath  string
+		storeID   string
 		batchSize int
 	)
 	flag.StringVar(&filePath, "file", "", "path to newline-delimited coupon codes file")
+	flag.StringVar(&storeID, "store", "default", "ID of the store the coupons belong to")
 	flag.IntVar(&batchSize, "batch", 2000, "number of rows per transaction")
 	flag.Parse()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	if err := run(ctx, filePath, batchSize); err != nil {
+	if err := run(ctx, filePath, storeID, batchSize); err != nil {
 		log.Fatalf("import failed: %v", err)
 	}
 }
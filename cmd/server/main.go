@@ -2,41 +2,186 @@ package main
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Store time zones must resolve on hosts without a zoneinfo database.
+	_ "time/tzdata"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	kartdb "kart/db"
+	"kart/internal/auth"
+	"kart/internal/buildinfo"
 	"kart/internal/config"
+	"kart/internal/health"
+	"kart/internal/logging"
+	"kart/internal/metrics"
+	"kart/internal/orderstatus"
+	"kart/internal/payments"
+	"kart/internal/ratelimit"
 	"kart/internal/repo"
 	"kart/internal/server"
 	"kart/internal/service"
 	"kart/internal/sqlc"
 	"kart/internal/store"
+	"kart/internal/tax"
+	"kart/internal/tenant"
+	"kart/internal/tracing"
 )
 
 func main() {
 	cfg := config.Load()
+	// Distroless images have no curl, so the container health check runs the
+	// binary itself: "server healthcheck".
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(probe(cfg.HTTPAddr))
+	}
+	logger, err := logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fatal("logging", err)
+	}
+	slog.SetDefault(logger)
+	if err := cfg.Validate(); err != nil {
+		fatal("config", err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter, "kart", os.Stdout)
+	if err != nil {
+		fatal("tracing", err)
+	}
+
 	db, err := store.Open(cfg.DatabaseURL)
 	if err != nil {
-		log.Fatalf("open db: %v", err)
+		fatal("open db", err)
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	if err := metrics.RegisterDB(reg, db.DB); err != nil {
+		fatal("db metrics", err)
+	}
+	m := metrics.New(reg)
+	schema, err := kartdb.LatestVersion()
+	if err != nil {
+		fatal("migrations", err)
 	}
+	checker := &health.Checker{}
+	checker.Add("database", health.Ping(db.DB))
+	checker.Add("migrations", health.Migrations(db.DB, schema))
 
 	// sqlc querier
-	q := sqlc.New(db.DB)
+	q := sqlc.New(tracing.DB(db.DB))
 	// repositories
 	pr := repo.NewProductRepo(q)
 	cr := repo.NewCouponRepo(q)
 	or := repo.NewOrderRepo(db.DB)
+	cartr := repo.NewCartRepo(q)
+	payr := repo.NewPaymentRepo(q)
+	refr := repo.NewRefundRepo(db.DB)
+	taxr := repo.NewTaxRepo(q)
+	plr := repo.NewPriceListRepo(q)
+	storer := repo.NewStoreRepo(q)
+	keyr := repo.NewAPIKeyRepo(q)
+	loyr := repo.NewLoyaltyRepo(q)
+	gcr := repo.NewGiftCardRepo(db.DB)
 	// services
+	prices := &service.PriceLists{Lists: plr, BaseCurrency: cfg.Currency}
+	prices.Schedule = service.NewPriceSchedule(repo.NewPriceAdjustmentRepo(q))
 	ps := service.NewProductService(pr)
+	ps.Prices = prices
 	osvc := service.NewOrderService(pr, cr, or)
+	// order status fan-out for WebSocket subscribers
+	hub := orderstatus.NewHub(16)
+	osvc.Events = hub
+	osvc.Currency = cfg.Currency
+	osvc.Prices = prices
+	osvc.Tax = taxr
+	osvc.Loyalty = &service.Loyalty{Ledger: loyr, PointValue: cfg.LoyaltyPointValue}
+	osvc.GiftCards = gcr
+	osvc.Promotions = repo.NewPromotionRepo(q)
+	if osvc.TaxMode, err = tax.ParseMode(cfg.TaxMode); err != nil {
+		fatal("tax mode", err)
+	}
+	if osvc.TaxRounding, err = tax.ParseRounding(cfg.TaxRounding); err != nil {
+		fatal("tax rounding", err)
+	}
+	switch cfg.PaymentProvider {
+	case "fake":
+		osvc.Payments = payments.NewFake()
+		osvc.PaymentRecords = payr
+		osvc.Refunds = refr
+	case "none", "":
+	default:
+		fatal("payments", fmt.Errorf("unknown PAYMENT_PROVIDER %q", cfg.PaymentProvider))
+	}
+	carts := service.NewCartService(cartr, pr, osvc, cfg.CartTTL)
+	carts.Prices = prices
+	carts.CheckoutTimeout = cfg.CartCheckoutTimeout
 
-	h := &server.Server{Cfg: cfg, Products: ps, Orders: osvc}
-	r, err := server.NewRouter(cfg.APIKey, h)
+	h := &server.Server{
+		Cfg:          cfg,
+		Products:     ps,
+		Orders:       osvc,
+		Carts:        carts,
+		GiftCards:    service.NewGiftCardService(gcr, cfg.Currency),
+		StatusHub:    hub,
+		StatusTokens: orderstatus.NewTokenSigner(cfg.OrderTokenSecret, cfg.OrderTokenTTL),
+		Metrics:      m,
+	}
+	// Rate limit buckets live in memory unless every replica is to share them.
+	var limitStore ratelimit.Store
+	var limitRepo *repo.RateLimitRepo
+	switch cfg.RateLimitBackend {
+	case "memory":
+		limitStore = ratelimit.NewMemory()
+	case "postgres":
+		limitRepo = repo.NewRateLimitRepo(q)
+		limitStore = limitRepo
+	default:
+		fatal("rate limits", fmt.Errorf("unknown RATE_LIMIT_BACKEND %q", cfg.RateLimitBackend))
+	}
+	if cfg.GiftCardLookupsPerMinute > 0 {
+		h.GiftCardLookups = ratelimit.PerMinute(cfg.GiftCardLookupsPerMinute)
+		h.GiftCardLookups.Store = limitStore
+	}
+	limits, err := server.ParseRateLimits(cfg.RateLimits, limitStore)
+	if err != nil {
+		fatal("rate limits", err)
+	}
+	trusted, err := server.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		fatal("trusted proxies", err)
+	}
+	tenancy := server.Tenancy{
+		Stores:       storer,
+		Keys:         service.NewAPIKeyService(keyr),
+		DefaultStore: cfg.StoreID,
+		LegacyAPIKey: cfg.APIKey,
+	}
+	if cfg.JWKS != "" {
+		tenancy.Tokens = auth.NewTokenVerifier(auth.NewKeySet(cfg.JWKS, cfg.JWKSTTL), cfg.JWTIssuer, cfg.JWTAudience)
+	}
+	validation, err := server.ParseResponseValidation(cfg.ResponseValidation)
 	if err != nil {
-		log.Fatalf("router init: %v", err)
+		fatal("response validation", err)
+	}
+	r, err := server.NewRouter(tenancy, h, server.RouterOptions{
+		Logger:             logger,
+		Metrics:            m,
+		Gatherer:           reg,
+		Health:             checker,
+		RequestTimeout:     cfg.RequestTimeout,
+		ResponseValidation: validation,
+		RateLimits:         limits,
+		TrustedProxies:     trusted,
+	})
+	if err != nil {
+		fatal("router init", err)
 	}
 
 	srv := &http.Server{
@@ -46,24 +191,121 @@ func main() {
 		ReadTimeout:       10 * time.Second,
 		WriteTimeout:      15 * time.Second,
 		IdleTimeout:       60 * time.Second,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
 	}
+	// Hijacked WebSocket connections are not tracked by Shutdown; tell them to
+	// close as soon as draining starts and wait for them below.
+	srv.RegisterOnShutdown(hub.Close)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	go purgeExpiredCarts(ctx, storer, carts, time.Hour)
+	if limitRepo != nil {
+		go purgeRateLimitBuckets(ctx, limitRepo, 10*time.Minute)
+	}
+
 	go func() {
-		log.Printf("env=%s listening on %s", cfg.Env, cfg.HTTPAddr)
+		slog.Info("listening", "env", cfg.Env, "addr", cfg.HTTPAddr, "version", buildinfo.Get().Version)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %v", err)
+			fatal("server error", err)
 		}
 	}()
 
 	<-ctx.Done()
+	// Report not ready first and give load balancers ShutdownDelay to notice
+	// before connections are drained.
+	checker.Drain()
+	slog.Info("draining", "delay", cfg.ShutdownDelay)
+	time.Sleep(cfg.ShutdownDelay)
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Printf("graceful shutdown failed: %v", err)
+		slog.Error("graceful shutdown failed", "err", err)
 		_ = srv.Close()
 	}
+	if err := hub.Wait(shutdownCtx); err != nil {
+		slog.Error("order status subscribers did not close", "err", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("flush traces", "err", err)
+	}
 	_ = db.Close()
 }
+
+// purgeExpiredCarts deletes every store's abandoned carts every interval
+// until ctx is done.
+func purgeExpiredCarts(ctx context.Context, stores *repo.StoreRepo, carts *service.CartService, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			all, err := stores.List(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "purge expired carts: list stores", "err", err)
+				continue
+			}
+			for _, st := range all {
+				n, err := carts.PurgeExpired(tenant.WithStore(ctx, st.ID))
+				if err != nil {
+					slog.ErrorContext(ctx, "purge expired carts", "store", st.ID, "err", err)
+					continue
+				}
+				if n > 0 {
+					slog.InfoContext(ctx, "purged expired carts", "store", st.ID, "count", n)
+				}
+			}
+		}
+	}
+}
+
+// purgeRateLimitBuckets deletes refilled rate limit buckets every interval,
+// so callers that have gone away do not accumulate.
+func purgeRateLimitBuckets(ctx context.Context, buckets *repo.RateLimitRepo, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := buckets.PurgeFull(ctx); err != nil {
+				slog.ErrorContext(ctx, "purge rate limit buckets", "err", err)
+			}
+		}
+	}
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
+
+// probe asks the server listening on addr whether it is ready, returning the
+// process exit code.
+func probe(addr string) int {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + net.JoinHostPort(host, port) + "/readyz")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(os.Stderr, "not ready:", resp.Status)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,44 @@
+// Package db embeds the schema migrations so the server knows which schema
+// version it was built for.
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// LatestVersion returns the goose version of the newest migration, which
+// goose takes from the file name up to the first underscore.
+func LatestVersion() (int64, error) {
+	return latestVersion(Migrations, "migrations")
+}
+
+func latestVersion(fsys fs.FS, dir string) (int64, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, e := range entries {
+		if path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		prefix, _, _ := strings.Cut(e.Name(), "_")
+		v, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: no version prefix", e.Name())
+		}
+		latest = max(latest, v)
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations in %s", dir)
+	}
+	return latest, nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	type tc struct {
+		name    string
+		files   []string
+		want    int64
+		wantErr bool
+	}
+	cases := []tc{
+		{name: "newest wins", files: []string{"m/20250918_000001_init.sql", "m/20251013_000017_price_adjustments.sql", "m/README"}, want: 20251013},
+		{name: "unversioned file", files: []string{"m/init.sql"}, wantErr: true},
+		{name: "empty", files: []string{"m/README"}, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for _, f := range c.files {
+				fsys[f] = &fstest.MapFile{}
+			}
+			got, err := latestVersion(fsys, "m")
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+
+	// The embedded migrations must always parse.
+	_, err := LatestVersion()
+	require.NoError(t, err)
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'placed'
+  CHECK (status IN ('placed', 'preparing', 'ready', 'completed', 'cancelled'));
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS eta_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS eta_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS carts (
+  id TEXT PRIMARY KEY,
+  coupon_code TEXT,
+  expires_at TIMESTAMP NOT NULL,
+  checkout_started_at TIMESTAMP,
+  order_id TEXT REFERENCES orders(id),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts(expires_at) WHERE order_id IS NULL;
+CREATE TABLE IF NOT EXISTS cart_items (
+  cart_id TEXT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+  product_id TEXT NOT NULL REFERENCES products(id),
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (cart_id, product_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS cart_items;
+DROP INDEX IF EXISTS idx_carts_expires_at;
+DROP TABLE IF EXISTS carts;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_cents BIGINT NOT NULL DEFAULT 0 CHECK (total_cents >= 0);
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+  CHECK (status IN ('pending_payment', 'payment_failed', 'placed', 'preparing', 'ready', 'completed', 'cancelled'));
+
+CREATE TABLE IF NOT EXISTS payments (
+  id TEXT PRIMARY KEY,
+  order_id TEXT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL,
+  provider_ref TEXT,
+  status TEXT NOT NULL,
+  amount_cents BIGINT NOT NULL CHECK (amount_cents >= 0),
+  captured_cents BIGINT NOT NULL DEFAULT 0 CHECK (captured_cents >= 0),
+  currency TEXT NOT NULL,
+  failure_reason TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments(order_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_payments_order_id;
+DROP TABLE IF EXISTS payments;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+  CHECK (status IN ('placed', 'preparing', 'ready', 'completed', 'cancelled'));
+ALTER TABLE orders DROP COLUMN IF EXISTS total_cents;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_cents BIGINT NOT NULL DEFAULT 0 CHECK (discount_cents >= 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS refunded_cents BIGINT NOT NULL DEFAULT 0 CHECK (refunded_cents >= 0);
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+  CHECK (status IN ('pending_payment', 'payment_failed', 'placed', 'preparing', 'ready', 'completed', 'cancelled', 'refunded'));
+
+-- Line prices are fixed when the order is placed so refunds do not depend on
+-- later catalog changes.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price_cents BIGINT NOT NULL DEFAULT 0 CHECK (unit_price_cents >= 0);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS refunded_quantity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD CONSTRAINT order_items_refunded_quantity_check
+  CHECK (refunded_quantity >= 0 AND refunded_quantity <= quantity);
+
+CREATE TABLE IF NOT EXISTS refunds (
+  id TEXT PRIMARY KEY,
+  order_id TEXT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  payment_id TEXT NOT NULL REFERENCES payments(id),
+  amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+  reason TEXT NOT NULL,
+  operator TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+  provider_ref TEXT,
+  failure_reason TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_id ON refunds(order_id);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+  refund_id TEXT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+  order_item_id TEXT NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+  quantity INTEGER NOT NULL CHECK (quantity > 0),
+  amount_cents BIGINT NOT NULL CHECK (amount_cents >= 0),
+  PRIMARY KEY (refund_id, order_item_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refund_items;
+DROP INDEX IF EXISTS idx_refunds_order_id;
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_refunded_quantity_check;
+ALTER TABLE order_items DROP COLUMN IF EXISTS refunded_quantity;
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_price_cents;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+  CHECK (status IN ('pending_payment', 'payment_failed', 'placed', 'preparing', 'ready', 'completed', 'cancelled'));
+ALTER TABLE orders DROP COLUMN IF EXISTS refunded_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_cents;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tax_classes (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL
+);
+
+-- Rates are per store; rate_basis_points is hundredths of a percent (1000 = 10%).
+CREATE TABLE IF NOT EXISTS tax_rates (
+  store_id TEXT NOT NULL,
+  tax_class TEXT NOT NULL REFERENCES tax_classes(code),
+  rate_basis_points INTEGER NOT NULL CHECK (rate_basis_points >= 0 AND rate_basis_points <= 100000),
+  PRIMARY KEY (store_id, tax_class)
+);
+
+CREATE TABLE IF NOT EXISTS category_tax_classes (
+  category TEXT PRIMARY KEY,
+  tax_class TEXT NOT NULL REFERENCES tax_classes(code)
+);
+
+-- A product's own tax class overrides its category's.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_class TEXT REFERENCES tax_classes(code);
+
+INSERT INTO tax_classes (code, name) VALUES
+  ('standard', 'Standard rate'),
+  ('exempt', 'Tax free')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO tax_rates (store_id, tax_class, rate_basis_points) VALUES
+  ('default', 'standard', 1000),
+  ('default', 'exempt', 0)
+ON CONFLICT (store_id, tax_class) DO NOTHING;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS subtotal_cents BIGINT NOT NULL DEFAULT 0 CHECK (subtotal_cents >= 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_cents BIGINT NOT NULL DEFAULT 0 CHECK (tax_cents >= 0);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_class TEXT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS tax_cents BIGINT NOT NULL DEFAULT 0 CHECK (tax_cents >= 0);
+
+-- Tax collected per class, as charged. Class totals are authoritative when
+-- tax is rounded per order rather than per line.
+CREATE TABLE IF NOT EXISTS order_taxes (
+  order_id TEXT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+  tax_class TEXT NOT NULL,
+  rate_basis_points INTEGER NOT NULL,
+  taxable_cents BIGINT NOT NULL,
+  tax_cents BIGINT NOT NULL,
+  PRIMARY KEY (order_id, tax_class)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_taxes;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_cents;
+ALTER TABLE order_items DROP COLUMN IF EXISTS tax_class;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_inclusive;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS subtotal_cents;
+ALTER TABLE products DROP COLUMN IF EXISTS tax_class;
+DROP TABLE IF EXISTS category_tax_classes;
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_classes;
+-- +goose StatementEnd
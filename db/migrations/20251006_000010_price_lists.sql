@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A price list prices a store's catalog in one currency. Lists that use base
+-- prices fall back to products.price_cents for products they do not override;
+-- other lists only sell the products they list.
+CREATE TABLE IF NOT EXISTS price_lists (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL,
+  currency TEXT NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+  is_default BOOLEAN NOT NULL DEFAULT FALSE,
+  uses_base_prices BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (store_id, currency)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS price_lists_store_default ON price_lists (store_id) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS price_list_prices (
+  price_list_id TEXT NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+  product_id TEXT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  price_cents INTEGER NOT NULL CHECK (price_cents >= 0),
+  PRIMARY KEY (price_list_id, product_id)
+);
+
+INSERT INTO price_lists (id, store_id, currency, is_default, uses_base_prices) VALUES
+  ('default-aud', 'default', 'AUD', TRUE, TRUE),
+  ('default-nzd', 'default', 'NZD', FALSE, FALSE)
+ON CONFLICT (id) DO NOTHING;
+INSERT INTO price_list_prices (price_list_id, product_id, price_cents)
+SELECT 'default-nzd', id, ROUND(price_cents * 1.09)::INTEGER FROM products
+ON CONFLICT (price_list_id, product_id) DO NOTHING;
+
+-- Existing orders and carts were all priced in AUD.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'AUD';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS price_list_id TEXT REFERENCES price_lists(id);
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'AUD';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE carts DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders DROP COLUMN IF EXISTS price_list_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+DROP TABLE IF EXISTS price_list_prices;
+DROP TABLE IF EXISTS price_lists;
+-- +goose StatementEnd
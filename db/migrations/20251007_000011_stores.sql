@@ -0,0 +1,123 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Stores are tenants. Everything a store sells or is sold is keyed by its
+-- store_id, and queries always filter by it.
+CREATE TABLE IF NOT EXISTS stores (
+  id TEXT PRIMARY KEY CHECK (id ~ '^[a-z0-9][a-z0-9-]{0,62}$'),
+  name TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO stores (id, name) VALUES ('default', 'Default store') ON CONFLICT (id) DO NOTHING;
+
+-- Existing rows belong to the default store. The defaults are dropped again
+-- so new rows must name their store.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE products ALTER COLUMN store_id DROP DEFAULT;
+
+-- Product IDs are unique per store rather than globally, so rows naming a
+-- product name its store too.
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_product_id_fkey;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_product_id_fkey;
+ALTER TABLE price_list_prices DROP CONSTRAINT IF EXISTS price_list_prices_product_id_fkey;
+DROP INDEX IF EXISTS idx_products_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_pkey;
+ALTER TABLE products ADD PRIMARY KEY (store_id, id);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE order_items ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE order_items ADD CONSTRAINT order_items_product_fkey
+  FOREIGN KEY (store_id, product_id) REFERENCES products(store_id, id);
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE cart_items ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_product_fkey
+  FOREIGN KEY (store_id, product_id) REFERENCES products(store_id, id);
+ALTER TABLE price_list_prices ADD COLUMN IF NOT EXISTS store_id TEXT;
+UPDATE price_list_prices p SET store_id = l.store_id FROM price_lists l WHERE l.id = p.price_list_id;
+ALTER TABLE price_list_prices ALTER COLUMN store_id SET NOT NULL;
+ALTER TABLE price_list_prices ADD CONSTRAINT price_list_prices_product_fkey
+  FOREIGN KEY (store_id, product_id) REFERENCES products(store_id, id) ON DELETE CASCADE;
+
+-- Coupon codes are unique per store rather than globally.
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_coupon_code_fkey;
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS coupon_redemptions_code_fkey;
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS coupon_redemptions_pkey;
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS coupons_code_key;
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS coupons_pkey;
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE coupons ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE coupons ADD PRIMARY KEY (store_id, code);
+ALTER TABLE coupon_redemptions ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE coupon_redemptions ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE coupon_redemptions ADD PRIMARY KEY (store_id, code);
+ALTER TABLE coupon_redemptions ADD CONSTRAINT coupon_redemptions_coupon_fkey
+  FOREIGN KEY (store_id, code) REFERENCES coupons(store_id, code) ON DELETE CASCADE;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE orders ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE orders ADD CONSTRAINT orders_coupon_fkey
+  FOREIGN KEY (store_id, coupon_code) REFERENCES coupons(store_id, code);
+CREATE INDEX IF NOT EXISTS idx_orders_store ON orders(store_id, created_at);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE payments ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE refunds ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE carts ALTER COLUMN store_id DROP DEFAULT;
+
+-- Category tax classes were global; they are per store like tax rates.
+ALTER TABLE category_tax_classes DROP CONSTRAINT IF EXISTS category_tax_classes_pkey;
+ALTER TABLE category_tax_classes ADD COLUMN IF NOT EXISTS store_id TEXT NOT NULL DEFAULT 'default' REFERENCES stores(id);
+ALTER TABLE category_tax_classes ALTER COLUMN store_id DROP DEFAULT;
+ALTER TABLE category_tax_classes ADD PRIMARY KEY (store_id, category);
+
+ALTER TABLE tax_rates ADD CONSTRAINT tax_rates_store_fkey FOREIGN KEY (store_id) REFERENCES stores(id);
+ALTER TABLE price_lists ADD CONSTRAINT price_lists_store_fkey FOREIGN KEY (store_id) REFERENCES stores(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE price_lists DROP CONSTRAINT IF EXISTS price_lists_store_fkey;
+ALTER TABLE tax_rates DROP CONSTRAINT IF EXISTS tax_rates_store_fkey;
+
+ALTER TABLE category_tax_classes DROP CONSTRAINT IF EXISTS category_tax_classes_pkey;
+DELETE FROM category_tax_classes WHERE store_id <> 'default';
+ALTER TABLE category_tax_classes DROP COLUMN IF EXISTS store_id;
+ALTER TABLE category_tax_classes ADD PRIMARY KEY (category);
+
+ALTER TABLE carts DROP COLUMN IF EXISTS store_id;
+ALTER TABLE refunds DROP COLUMN IF EXISTS store_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS store_id;
+
+DROP INDEX IF EXISTS idx_orders_store;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_coupon_fkey;
+ALTER TABLE orders DROP COLUMN IF EXISTS store_id;
+
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS coupon_redemptions_coupon_fkey;
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS coupon_redemptions_pkey;
+ALTER TABLE coupon_redemptions DROP COLUMN IF EXISTS store_id;
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS coupons_pkey;
+ALTER TABLE coupons DROP COLUMN IF EXISTS store_id;
+ALTER TABLE coupons ADD PRIMARY KEY (code);
+ALTER TABLE coupon_redemptions ADD PRIMARY KEY (code);
+ALTER TABLE coupon_redemptions ADD CONSTRAINT coupon_redemptions_code_fkey
+  FOREIGN KEY (code) REFERENCES coupons(code) ON DELETE CASCADE;
+ALTER TABLE orders ADD CONSTRAINT orders_coupon_code_fkey FOREIGN KEY (coupon_code) REFERENCES coupons(code);
+
+ALTER TABLE price_list_prices DROP CONSTRAINT IF EXISTS price_list_prices_product_fkey;
+ALTER TABLE price_list_prices DROP COLUMN IF EXISTS store_id;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_product_fkey;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS store_id;
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_product_fkey;
+ALTER TABLE order_items DROP COLUMN IF EXISTS store_id;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_pkey;
+DELETE FROM products WHERE store_id <> 'default';
+ALTER TABLE products DROP COLUMN IF EXISTS store_id;
+ALTER TABLE products ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_products_id ON products(id);
+ALTER TABLE order_items ADD CONSTRAINT order_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id);
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id);
+ALTER TABLE price_list_prices ADD CONSTRAINT price_list_prices_product_id_fkey
+  FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+DROP TABLE IF EXISTS stores;
+-- +goose StatementEnd
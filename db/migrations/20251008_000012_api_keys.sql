@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- API keys are bound to one store. Each key is looked up by its clear-text
+-- prefix and checked against SHA-256(salt || secret).
+-- Several keys may be live for a store at once, which is how keys are rotated
+-- without downtime: mint the new key, give the old one an expiry, deploy.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  prefix TEXT NOT NULL UNIQUE,
+  salt BYTEA NOT NULL,
+  hash BYTEA NOT NULL,
+  scopes TEXT[] NOT NULL DEFAULT '{}',
+  label TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP,
+  revoked_at TIMESTAMP,
+  last_used_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_store ON api_keys(store_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_store;
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
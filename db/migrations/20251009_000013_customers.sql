@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Customers are the identity provider's users as seen by one store; id is the
+-- token subject. Rows are created the first time a customer orders.
+CREATE TABLE IF NOT EXISTS customers (
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  id TEXT NOT NULL,
+  email TEXT,
+  phone TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (store_id, id)
+);
+
+-- Orders belong to a signed-in customer, or to a guest known only by the
+-- contact details given at checkout. Orders placed with an API key alone have
+-- neither.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_id TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS contact_email TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS contact_phone TEXT;
+ALTER TABLE orders ADD CONSTRAINT orders_customer_fkey
+  FOREIGN KEY (store_id, customer_id) REFERENCES customers(store_id, id);
+CREATE INDEX IF NOT EXISTS idx_orders_customer ON orders(store_id, customer_id, created_at DESC);
+
+-- Recording who redeemed a coupon makes per-customer limits possible.
+ALTER TABLE coupon_redemptions ADD COLUMN IF NOT EXISTS customer_id TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE coupon_redemptions DROP COLUMN IF EXISTS customer_id;
+DROP INDEX IF EXISTS idx_orders_customer;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_customer_fkey;
+ALTER TABLE orders DROP COLUMN IF EXISTS contact_phone;
+ALTER TABLE orders DROP COLUMN IF EXISTS contact_email;
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_id;
+DROP TABLE IF EXISTS customers;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Points earned per major currency unit spent on products in a category. The
+-- '*' category applies to products whose category has no rule of its own.
+CREATE TABLE IF NOT EXISTS loyalty_rules (
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  category TEXT NOT NULL,
+  points_per_unit INTEGER NOT NULL CHECK (points_per_unit >= 0),
+  PRIMARY KEY (store_id, category)
+);
+
+-- The ledger is append-only: corrections are new entries. key makes posting
+-- idempotent, e.g. "earn:<order>" or "reverse:<refund>".
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL,
+  customer_id TEXT NOT NULL,
+  order_id TEXT REFERENCES orders(id),
+  kind TEXT NOT NULL CHECK (kind IN ('earn', 'redeem', 'restore', 'reverse')),
+  points BIGINT NOT NULL,
+  key TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (store_id, customer_id) REFERENCES customers(store_id, id),
+  UNIQUE (store_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_customer ON loyalty_entries(store_id, customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_order ON loyalty_entries(order_id);
+
+CREATE OR REPLACE FUNCTION loyalty_entries_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'loyalty_entries is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER loyalty_entries_append_only BEFORE UPDATE OR DELETE ON loyalty_entries
+  FOR EACH ROW EXECUTE FUNCTION loyalty_entries_append_only();
+
+-- The running sum of a customer's entries, kept alongside each posting so
+-- redemption can check and debit it under a row lock. Redemption never takes
+-- it below zero; reversing points that were already spent can.
+CREATE TABLE IF NOT EXISTS loyalty_balances (
+  store_id TEXT NOT NULL,
+  customer_id TEXT NOT NULL,
+  balance BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (store_id, customer_id),
+  FOREIGN KEY (store_id, customer_id) REFERENCES customers(store_id, id)
+);
+
+-- Points redeemed on an order and what they were worth in its currency.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS points_redeemed BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS points_cents BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS points_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS points_redeemed;
+DROP TABLE IF EXISTS loyalty_balances;
+DROP TABLE IF EXISTS loyalty_entries;
+DROP FUNCTION IF EXISTS loyalty_entries_append_only();
+DROP TABLE IF EXISTS loyalty_rules;
+-- +goose StatementEnd
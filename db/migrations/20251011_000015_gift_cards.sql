@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Gift cards are stored value in one currency. Codes are bearer secrets: only
+-- their SHA-256 digest is kept, with the last four characters for display.
+CREATE TABLE IF NOT EXISTS gift_cards (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  code_hash BYTEA NOT NULL,
+  last4 TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  initial_cents BIGINT NOT NULL CHECK (initial_cents > 0),
+  balance_cents BIGINT NOT NULL CHECK (balance_cents >= 0),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP,
+  disabled_at TIMESTAMP,
+  UNIQUE (store_id, code_hash)
+);
+
+-- Every change to a balance, signed: issue and restore add, redeem takes
+-- away. Rows are never changed; an order's redemptions are restored at most
+-- once each.
+CREATE TABLE IF NOT EXISTS gift_card_transactions (
+  id TEXT PRIMARY KEY,
+  store_id TEXT NOT NULL,
+  gift_card_id TEXT NOT NULL REFERENCES gift_cards(id),
+  order_id TEXT REFERENCES orders(id),
+  kind TEXT NOT NULL CHECK (kind IN ('issue', 'redeem', 'restore')),
+  amount_cents BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_gift_card_transactions_card ON gift_card_transactions(gift_card_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gift_card_transactions_order
+  ON gift_card_transactions(store_id, order_id, gift_card_id, kind) WHERE order_id IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION gift_card_transactions_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'gift_card_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER gift_card_transactions_append_only BEFORE UPDATE OR DELETE ON gift_card_transactions
+  FOR EACH ROW EXECUTE FUNCTION gift_card_transactions_append_only();
+
+-- What gift cards paid of the order total.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS gift_card_cents BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS gift_card_cents;
+DROP TABLE IF EXISTS gift_card_transactions;
+DROP FUNCTION IF EXISTS gift_card_transactions_append_only();
+DROP TABLE IF EXISTS gift_cards;
+-- +goose StatementEnd
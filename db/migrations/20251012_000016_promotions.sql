@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Automatic promotions. rule holds the declarative conditions and action
+-- (see internal/promo), e.g.
+--   {"buy": [{"categories": ["Waffle"], "quantity": 2}],
+--    "action": "free_item", "get": {"products": ["latte"], "quantity": 1}}
+-- Higher priority rules claim order items first; an exclusive rule is never
+-- combined with another.
+CREATE TABLE IF NOT EXISTS promotions (
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  priority INTEGER NOT NULL DEFAULT 0,
+  exclusive BOOLEAN NOT NULL DEFAULT FALSE,
+  rule JSONB NOT NULL,
+  starts_at TIMESTAMP,
+  ends_at TIMESTAMP,
+  PRIMARY KEY (store_id, id),
+  CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+-- What promotions and other discounts took off each line, so refunds and
+-- loyalty points follow the price actually paid for it.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_cents BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_cents;
+DROP TABLE IF EXISTS promotions;
+-- +goose StatementEnd
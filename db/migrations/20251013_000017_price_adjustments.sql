@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- IANA time zone the store's local times (happy hours) are in.
+ALTER TABLE stores ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Scheduled price adjustments, e.g. beverages 30% off 15:00-17:00 on
+-- weekdays. weekdays is a bitmask with bit n set for day n counting from
+-- Sunday = 0 (weekdays = 62). A window ending at or before its start runs
+-- past midnight into the next day. starts_at and ends_at optionally bound the
+-- whole schedule.
+CREATE TABLE IF NOT EXISTS price_adjustments (
+  store_id TEXT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+  id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  product_id TEXT,
+  category TEXT,
+  percent_off INTEGER NOT NULL CHECK (percent_off BETWEEN 1 AND 100),
+  weekdays SMALLINT NOT NULL DEFAULT 127 CHECK (weekdays BETWEEN 1 AND 127),
+  local_start TIME NOT NULL,
+  local_end TIME NOT NULL,
+  starts_at TIMESTAMP,
+  ends_at TIMESTAMP,
+  PRIMARY KEY (store_id, id),
+  CHECK ((product_id IS NULL) <> (category IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS price_adjustments;
+ALTER TABLE stores DROP COLUMN IF EXISTS timezone;
+-- +goose StatementEnd
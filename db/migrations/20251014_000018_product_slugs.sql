@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Human-readable product URLs (/product/slug/chicken-waffle). Existing
+-- products get a slug derived from their name; where two products in a store
+-- share a name, the later IDs get the ID appended.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS slug TEXT
+  CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$');
+
+WITH derived AS (
+  SELECT store_id, id,
+    COALESCE(NULLIF(trim(both '-' from lower(regexp_replace(name, '[^A-Za-z0-9]+', '-', 'g'))), ''), 'product') AS base
+  FROM products
+), numbered AS (
+  SELECT store_id, id, base,
+    row_number() OVER (PARTITION BY store_id, base ORDER BY id) AS n
+  FROM derived
+)
+UPDATE products p
+SET slug = CASE WHEN n.n = 1 THEN n.base
+  ELSE n.base || '-' || trim(both '-' from lower(regexp_replace(p.id, '[^A-Za-z0-9]+', '-', 'g'))) END
+FROM numbered n
+WHERE p.store_id = n.store_id AND p.id = n.id AND p.slug IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_slug ON products(store_id, slug);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_slug;
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
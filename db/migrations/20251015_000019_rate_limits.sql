@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Token buckets shared by every replica when RATE_LIMIT_BACKEND=postgres.
+-- A bucket holding tokens at updated_at refills at rate tokens a second up to
+-- burst. Losing them in a crash only resets the limits, so the table is not
+-- WAL-logged.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+  key TEXT PRIMARY KEY,
+  tokens DOUBLE PRECISION NOT NULL,
+  rate DOUBLE PRECISION NOT NULL,
+  burst INTEGER NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limit_buckets;
+-- +goose StatementEnd
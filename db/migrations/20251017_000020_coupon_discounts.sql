@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What a coupon takes off the order, after automatic promotions. Existing
+-- coupons only gate single use and keep taking nothing off.
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS percent_off INTEGER NOT NULL DEFAULT 0 CHECK (percent_off BETWEEN 0 AND 100);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE coupons DROP COLUMN IF EXISTS percent_off;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The cart an order was checked out from. A cart has at most one order that
+-- has not failed payment, so a checkout that takes over a stale claim cannot
+-- place a second order while the first is still running.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cart_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS orders_cart_live_key ON orders(store_id, cart_id)
+  WHERE cart_id IS NOT NULL AND status <> 'payment_failed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_cart_live_key;
+ALTER TABLE orders DROP COLUMN IF EXISTS cart_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The API only addresses product IDs matching ProductId in api/openapi.yaml.
+-- Rather than leave products it cannot reach, refuse to migrate while any
+-- exist, naming them, and keep new IDs inside the format from here on.
+DO $$
+DECLARE
+  bad TEXT;
+BEGIN
+  SELECT string_agg(store_id || '/' || id, ', ' ORDER BY store_id, id) INTO bad
+  FROM (
+    SELECT store_id, id FROM products
+    WHERE id !~ '^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$'
+    ORDER BY store_id, id LIMIT 20
+  ) b;
+  IF bad IS NOT NULL THEN
+    RAISE EXCEPTION 'product IDs outside the API format: %', bad
+      USING HINT = 'Rename them to letters, digits, - and _ (up to 64, starting with a letter or digit), with the order, cart and price rows that name them, then migrate again.';
+  END IF;
+END $$;
+ALTER TABLE products ADD CONSTRAINT products_id_format
+  CHECK (id ~ '^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_id_format;
+-- +goose StatementEnd
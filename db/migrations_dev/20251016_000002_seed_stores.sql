@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The demo catalog keyed the way products and coupons are keyed now, by
+-- store. 20250922_000001_seed already inserted these rows against the
+-- earlier schema, which moved them into the default store and gave them
+-- slugs, so nothing is inserted twice.
+INSERT INTO products (store_id, id, slug, name, category, price_cents) VALUES
+  ('default','10','chicken-waffle','Chicken Waffle','Waffle',1299),
+  ('default','11','berry-waffle','Berry Waffle','Waffle',999),
+  ('default','12','latte','Latte','Beverage',499)
+ON CONFLICT(store_id, id) DO NOTHING;
+
+INSERT INTO coupons (store_id, code, presence_mask) VALUES
+  ('default', 'HAPPYHRS', B'00000111'),  -- applies to 3 categories
+  ('default', 'FIFTYOFF', B'00000011'),  -- applies to 2 categories
+  ('default', 'SUPER100', B'00000001')   -- single category (will fail validation in service)
+ON CONFLICT(store_id, code) DO NOTHING;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM coupons WHERE store_id = 'default' AND code IN ('HAPPYHRS', 'FIFTYOFF', 'SUPER100');
+DELETE FROM products WHERE store_id = 'default' AND id IN ('10', '11', '12');
+-- +goose StatementEnd
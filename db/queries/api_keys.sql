@@ -0,0 +1,21 @@
+-- name: InsertAPIKey :exec
+INSERT INTO api_keys (store_id, id, prefix, salt, hash, scopes, label, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetAPIKeyByPrefix :one
+SELECT * FROM api_keys WHERE prefix = $1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys WHERE store_id = $1 ORDER BY created_at, prefix;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = $3
+WHERE store_id = $1 AND prefix = $2 AND revoked_at IS NULL;
+
+-- name: ExpireAPIKey :execrows
+UPDATE api_keys SET expires_at = LEAST(expires_at, sqlc.arg(expires_at)::timestamp)
+WHERE store_id = sqlc.arg(store_id) AND prefix = sqlc.arg(prefix) AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = sqlc.arg(used_at)
+WHERE id = sqlc.arg(id) AND (last_used_at IS NULL OR last_used_at < sqlc.arg(stale_before));
@@ -0,0 +1,52 @@
+-- name: InsertCart :exec
+INSERT INTO carts (store_id, id, expires_at, currency)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetCart :one
+SELECT * FROM carts WHERE store_id = $1 AND id = $2;
+
+-- name: ListCartItems :many
+SELECT ci.* FROM cart_items ci
+JOIN carts c ON c.id = ci.cart_id
+WHERE c.store_id = $1 AND ci.cart_id = $2
+ORDER BY ci.created_at, ci.product_id;
+
+-- name: AddCartItem :exec
+INSERT INTO cart_items (store_id, cart_id, product_id, quantity)
+SELECT c.store_id, c.id, sqlc.arg(product_id)::text, sqlc.arg(quantity)::int4
+FROM carts c WHERE c.store_id = sqlc.arg(store_id) AND c.id = sqlc.arg(cart_id)
+ON CONFLICT (cart_id, product_id)
+DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP;
+
+-- name: SetCartItem :exec
+INSERT INTO cart_items (store_id, cart_id, product_id, quantity)
+SELECT c.store_id, c.id, sqlc.arg(product_id)::text, sqlc.arg(quantity)::int4
+FROM carts c WHERE c.store_id = sqlc.arg(store_id) AND c.id = sqlc.arg(cart_id)
+ON CONFLICT (cart_id, product_id)
+DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteCartItem :execrows
+DELETE FROM cart_items ci
+USING carts c
+WHERE c.id = ci.cart_id AND c.store_id = $1 AND ci.cart_id = $2 AND ci.product_id = $3;
+
+-- name: SetCartCoupon :exec
+UPDATE carts SET coupon_code = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2;
+
+-- name: TouchCart :exec
+UPDATE carts SET expires_at = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2;
+
+-- name: ClaimCartCheckout :execrows
+UPDATE carts SET checkout_started_at = sqlc.arg(started_at)
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id) AND order_id IS NULL
+  AND (checkout_started_at IS NULL OR checkout_started_at < sqlc.arg(stale_before)::timestamp);
+
+-- name: ReleaseCartCheckout :exec
+UPDATE carts SET checkout_started_at = NULL
+WHERE store_id = $1 AND id = $2 AND checkout_started_at = $3 AND order_id IS NULL;
+
+-- name: CompleteCartCheckout :exec
+UPDATE carts SET order_id = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2;
+
+-- name: DeleteExpiredCarts :execrows
+DELETE FROM carts WHERE store_id = $1 AND order_id IS NULL AND expires_at < $2;
@@ -1,8 +1,11 @@
 -- name: GetCoupon :one
-SELECT * FROM coupons WHERE code = $1;
+SELECT * FROM coupons WHERE store_id = $1 AND code = $2;
 
 -- name: TryRedeemSingleUse :one
-INSERT INTO coupon_redemptions (code)
-VALUES ($1)
+INSERT INTO coupon_redemptions (store_id, code, customer_id)
+VALUES ($1, $2, $3)
 ON CONFLICT DO NOTHING
 RETURNING code;
+
+-- name: ReleaseRedemption :exec
+DELETE FROM coupon_redemptions WHERE store_id = $1 AND code = $2;
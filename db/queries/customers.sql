@@ -0,0 +1,9 @@
+-- name: UpsertCustomer :exec
+-- Contact details are only overwritten by ones that were given.
+INSERT INTO customers (store_id, id, email, phone)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (store_id, id) DO UPDATE
+SET email = COALESCE(EXCLUDED.email, customers.email),
+    phone = COALESCE(EXCLUDED.phone, customers.phone),
+    updated_at = CURRENT_TIMESTAMP;
+
@@ -0,0 +1,40 @@
+-- name: InsertGiftCard :exec
+INSERT INTO gift_cards (id, store_id, code_hash, last4, currency, initial_cents, balance_cents, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $6, $7);
+
+-- name: GetGiftCard :one
+SELECT * FROM gift_cards WHERE store_id = $1 AND id = $2;
+
+-- name: GetGiftCardByCode :one
+SELECT * FROM gift_cards WHERE store_id = $1 AND code_hash = $2;
+
+-- name: DebitGiftCard :execrows
+-- Takes amount off the card unless it is disabled, expired or the balance
+-- falls short.
+UPDATE gift_cards SET balance_cents = balance_cents - sqlc.arg(amount_cents)::int8
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id)
+  AND disabled_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+  AND balance_cents >= sqlc.arg(amount_cents)::int8;
+
+-- name: InsertGiftCardTransaction :exec
+INSERT INTO gift_card_transactions (id, store_id, gift_card_id, order_id, kind, amount_cents)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListGiftCardTransactions :many
+SELECT * FROM gift_card_transactions
+WHERE store_id = $1 AND gift_card_id = $2
+ORDER BY created_at, id;
+
+-- name: RestoreOrderGiftCards :execrows
+-- Credits back what the order took from each gift card, skipping cards
+-- already restored for it.
+WITH r AS (
+  INSERT INTO gift_card_transactions (id, store_id, gift_card_id, order_id, kind, amount_cents)
+  SELECT gen_random_uuid()::text, t.store_id, t.gift_card_id, t.order_id, 'restore', -t.amount_cents
+  FROM gift_card_transactions t
+  WHERE t.store_id = $1 AND t.order_id = $2 AND t.kind = 'redeem'
+  ON CONFLICT DO NOTHING
+  RETURNING store_id, gift_card_id, amount_cents
+)
+UPDATE gift_cards g SET balance_cents = g.balance_cents + r.amount_cents
+FROM r WHERE g.store_id = r.store_id AND g.id = r.gift_card_id;
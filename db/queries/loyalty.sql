@@ -0,0 +1,40 @@
+-- name: ListLoyaltyRules :many
+SELECT * FROM loyalty_rules WHERE store_id = $1 ORDER BY category;
+
+-- name: GetLoyaltyBalance :one
+SELECT balance FROM loyalty_balances WHERE store_id = $1 AND customer_id = $2;
+
+-- name: ListLoyaltyEntries :many
+SELECT * FROM loyalty_entries
+WHERE store_id = $1 AND customer_id = $2
+ORDER BY created_at DESC, id
+LIMIT $3;
+
+-- name: ListOrderLoyaltyEntries :many
+SELECT * FROM loyalty_entries
+WHERE store_id = $1 AND order_id = $2
+ORDER BY created_at, id;
+
+-- name: PostLoyaltyEntry :execrows
+-- Appends the entry and applies it to the balance, or does nothing if an
+-- entry with the same key was posted before.
+WITH e AS (
+  INSERT INTO loyalty_entries (id, store_id, customer_id, order_id, kind, points, key)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  ON CONFLICT (store_id, key) DO NOTHING
+  RETURNING store_id, customer_id, points
+)
+INSERT INTO loyalty_balances (store_id, customer_id, balance)
+SELECT store_id, customer_id, points FROM e
+ON CONFLICT (store_id, customer_id) DO UPDATE
+SET balance = loyalty_balances.balance + EXCLUDED.balance, updated_at = CURRENT_TIMESTAMP;
+
+-- name: DebitLoyaltyBalance :execrows
+-- Takes points off the balance unless that would make it negative.
+UPDATE loyalty_balances
+SET balance = balance - sqlc.arg(points)::int8, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = sqlc.arg(store_id) AND customer_id = sqlc.arg(customer_id) AND balance >= sqlc.arg(points)::int8;
+
+-- name: InsertLoyaltyEntry :exec
+INSERT INTO loyalty_entries (id, store_id, customer_id, order_id, kind, points, key)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
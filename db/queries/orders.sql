@@ -1,11 +1,59 @@
 -- name: InsertOrder :exec
-INSERT INTO orders (id, coupon_code)
-VALUES ($1, $2);
-
--- name: InsertOrderItem :exec
-INSERT INTO order_items (id, order_id, product_id, quantity)
-VALUES ($1, $2, $3, $4);
+INSERT INTO orders (store_id, id, coupon_code, status, total_cents, discount_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id,
+  customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18);
 
 -- name: InsertOrderItems :exec
-INSERT INTO order_items (id, order_id, product_id, quantity)
-SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::int4[]);
+INSERT INTO order_items (store_id, id, order_id, product_id, quantity, unit_price_cents, tax_class, tax_cents, discount_cents)
+SELECT sqlc.arg(store_id)::text, UNNEST(sqlc.arg(ids)::text[]), sqlc.arg(order_id)::text, UNNEST(sqlc.arg(product_ids)::text[]),
+  UNNEST(sqlc.arg(quantities)::int4[]), UNNEST(sqlc.arg(unit_prices)::int8[]),
+  UNNEST(sqlc.arg(tax_classes)::text[]), UNNEST(sqlc.arg(tax_cents)::int8[]),
+  UNNEST(sqlc.arg(discount_cents)::int8[])
+WHERE EXISTS (SELECT 1 FROM orders o WHERE o.store_id = sqlc.arg(store_id) AND o.id = sqlc.arg(order_id));
+
+-- name: InsertOrderTax :exec
+INSERT INTO order_taxes (order_id, tax_class, rate_basis_points, taxable_cents, tax_cents)
+SELECT o.id, sqlc.arg(tax_class)::text, sqlc.arg(rate_basis_points)::int4, sqlc.arg(taxable_cents)::int8, sqlc.arg(tax_cents)::int8
+FROM orders o WHERE o.store_id = sqlc.arg(store_id) AND o.id = sqlc.arg(order_id);
+
+-- name: ListOrderTaxes :many
+SELECT t.* FROM order_taxes t
+JOIN orders o ON o.id = t.order_id
+WHERE o.store_id = $1 AND t.order_id = $2
+ORDER BY t.tax_class;
+
+-- name: GetOrder :one
+SELECT * FROM orders WHERE store_id = $1 AND id = $2;
+
+-- name: ListCustomerOrders :many
+SELECT * FROM orders
+WHERE store_id = $1 AND customer_id = $2
+ORDER BY created_at DESC, id
+LIMIT $3;
+
+-- name: LockOrder :one
+SELECT * FROM orders WHERE store_id = $1 AND id = $2 FOR UPDATE;
+
+-- name: ListOrderItems :many
+SELECT i.* FROM order_items i
+JOIN orders o ON o.id = i.order_id
+WHERE o.store_id = $1 AND i.order_id = $2
+ORDER BY i.created_at, i.id;
+
+-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status = sqlc.arg(status), eta_at = sqlc.arg(eta_at), updated_at = CURRENT_TIMESTAMP
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: AddOrderRefunded :exec
+UPDATE orders
+SET refunded_cents = refunded_cents + sqlc.arg(amount_cents)::int8, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = sqlc.arg(store_id) AND id = sqlc.arg(id);
+
+-- name: AddOrderItemRefunded :execrows
+UPDATE order_items i
+SET refunded_quantity = i.refunded_quantity + sqlc.arg(delta)::int4, updated_at = CURRENT_TIMESTAMP
+FROM orders o
+WHERE o.id = i.order_id AND o.store_id = sqlc.arg(store_id) AND i.id = sqlc.arg(id)
+  AND i.refunded_quantity + sqlc.arg(delta)::int4 BETWEEN 0 AND i.quantity;
@@ -0,0 +1,12 @@
+-- name: InsertPayment :exec
+INSERT INTO payments (store_id, id, order_id, provider, status, amount_cents, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: UpdatePayment :one
+UPDATE payments
+SET provider_ref = $3, status = $4, captured_cents = $5, failure_reason = $6, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $1 AND id = $2
+RETURNING *;
+
+-- name: GetPaymentByOrder :one
+SELECT * FROM payments WHERE store_id = $1 AND order_id = $2 ORDER BY created_at DESC LIMIT 1;
@@ -0,0 +1,11 @@
+-- name: ListPriceAdjustments :many
+-- Returns the store's adjustments whose schedule covers at, with the store's
+-- time zone to evaluate their daily windows in.
+SELECT pa.id, pa.name, pa.product_id, pa.category, pa.percent_off, pa.weekdays,
+  pa.local_start, pa.local_end, s.timezone
+FROM price_adjustments pa
+JOIN stores s ON s.id = pa.store_id
+WHERE pa.store_id = sqlc.arg(store_id)
+  AND (pa.starts_at IS NULL OR pa.starts_at <= sqlc.arg(at)::timestamp)
+  AND (pa.ends_at IS NULL OR pa.ends_at > sqlc.arg(at)::timestamp)
+ORDER BY pa.id;
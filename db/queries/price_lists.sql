@@ -0,0 +1,16 @@
+-- name: GetPriceList :one
+SELECT * FROM price_lists WHERE store_id = $1 AND currency = $2;
+
+-- name: GetDefaultPriceList :one
+SELECT * FROM price_lists WHERE store_id = $1 AND is_default;
+
+-- name: ListPriceListPrices :many
+SELECT p.* FROM price_list_prices p
+JOIN price_lists l ON l.id = p.price_list_id
+WHERE l.store_id = $1 AND p.price_list_id = $2;
+
+-- name: GetPriceListPrices :many
+SELECT p.* FROM price_list_prices p
+JOIN price_lists l ON l.id = p.price_list_id
+WHERE l.store_id = sqlc.arg(store_id) AND p.price_list_id = sqlc.arg(price_list_id)
+  AND p.product_id = ANY(sqlc.arg(product_ids)::text[]);
@@ -1,11 +1,11 @@
 -- name: ListProducts :many
-SELECT * FROM products ORDER BY id;
+SELECT * FROM products WHERE store_id = $1 ORDER BY id;
 
 -- name: GetProduct :one
-SELECT * FROM products WHERE id = $1;
-
--- name: ListAllProducts :many
-SELECT * FROM products ORDER BY id;
+SELECT * FROM products WHERE store_id = $1 AND id = $2;
 
 -- name: GetProductsByIDs :many
-SELECT * FROM products WHERE id = ANY($1::text[]);
+SELECT * FROM products WHERE store_id = sqlc.arg(store_id) AND id = ANY(sqlc.arg(ids)::text[]);
+
+-- name: GetProductBySlug :one
+SELECT * FROM products WHERE store_id = $1 AND slug = $2;
@@ -0,0 +1,6 @@
+-- name: ListActivePromotions :many
+SELECT * FROM promotions
+WHERE store_id = sqlc.arg(store_id)
+  AND (starts_at IS NULL OR starts_at <= sqlc.arg(at)::timestamp)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg(at)::timestamp)
+ORDER BY priority DESC, id;
@@ -0,0 +1,22 @@
+-- name: TakeRateLimitToken :one
+-- Takes a token from the bucket, creating it full. No row is returned when
+-- the bucket is empty.
+INSERT INTO rate_limit_buckets AS b (key, tokens, rate, burst, updated_at)
+VALUES (sqlc.arg(key), sqlc.arg(burst)::integer - 1, sqlc.arg(rate)::float8, sqlc.arg(burst)::integer, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(EXCLUDED.burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * EXCLUDED.rate) - 1,
+    rate = EXCLUDED.rate,
+    burst = EXCLUDED.burst,
+    updated_at = now()
+WHERE LEAST(EXCLUDED.burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * EXCLUDED.rate) >= 1
+RETURNING tokens;
+
+-- name: GetRateLimitTokens :one
+SELECT LEAST(burst, tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 * rate)::float8 AS tokens
+FROM rate_limit_buckets
+WHERE key = $1;
+
+-- name: PurgeFullRateLimitBuckets :execrows
+-- Buckets that have refilled behave like new ones.
+DELETE FROM rate_limit_buckets
+WHERE tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 * rate >= burst;
@@ -0,0 +1,31 @@
+-- name: InsertRefund :exec
+INSERT INTO refunds (store_id, id, order_id, payment_id, amount_cents, reason, operator)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: InsertRefundItem :exec
+INSERT INTO refund_items (refund_id, order_item_id, quantity, amount_cents)
+SELECT r.id, sqlc.arg(order_item_id)::text, sqlc.arg(quantity)::int4, sqlc.arg(amount_cents)::int8
+FROM refunds r WHERE r.store_id = sqlc.arg(store_id) AND r.id = sqlc.arg(refund_id);
+
+-- name: UpdateRefund :one
+UPDATE refunds
+SET status = $3, provider_ref = $4, failure_reason = $5, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $1 AND id = $2
+RETURNING *;
+
+-- name: SumSucceededRefunds :one
+SELECT COALESCE(SUM(amount_cents), 0)::int8 FROM refunds
+WHERE store_id = $1 AND order_id = $2 AND status = 'succeeded';
+
+-- name: ListRefundsByOrder :many
+SELECT * FROM refunds WHERE store_id = $1 AND order_id = $2 ORDER BY created_at, id;
+
+-- name: ListRefundItems :many
+SELECT ri.* FROM refund_items ri
+JOIN refunds r ON r.id = ri.refund_id
+WHERE r.store_id = $1 AND ri.refund_id = $2;
+
+-- name: ListRefundItemsByOrder :many
+SELECT ri.* FROM refund_items ri
+JOIN refunds r ON r.id = ri.refund_id
+WHERE r.store_id = $1 AND r.order_id = $2;
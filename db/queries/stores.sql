@@ -0,0 +1,5 @@
+-- name: GetStore :one
+SELECT * FROM stores WHERE id = $1;
+
+-- name: ListStores :many
+SELECT * FROM stores ORDER BY id;
@@ -0,0 +1,5 @@
+-- name: ListTaxRates :many
+SELECT * FROM tax_rates WHERE store_id = $1;
+
+-- name: ListCategoryTaxClasses :many
+SELECT * FROM category_tax_classes WHERE store_id = $1;
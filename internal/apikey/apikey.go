@@ -0,0 +1,75 @@
+// Package apikey generates API keys and checks them against stored hashes.
+//
+// A key looks like "kart_<prefix>_<secret>". The prefix is stored in clear
+// so a presented key can be looked up without scanning; the secret is only
+// stored as SHA-256(salt || secret) with a per-key random salt. Keys carry
+// 256 bits of entropy, so a fast hash is sufficient.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	scheme    = "kart_"
+	prefixLen = 12 // hex characters
+	secretLen = 32 // bytes of entropy
+	saltLen   = 16
+)
+
+// Key is a freshly generated key. Plaintext is shown to the caller once and
+// never stored.
+type Key struct {
+	Plaintext string
+	Prefix    string
+	Salt      []byte
+	Hash      []byte
+}
+
+// Generate returns a new random key.
+func Generate() (Key, error) {
+	prefix := make([]byte, prefixLen/2)
+	secret := make([]byte, secretLen)
+	salt := make([]byte, saltLen)
+	for _, b := range [][]byte{prefix, secret, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return Key{}, err
+		}
+	}
+	p := hex.EncodeToString(prefix)
+	s := base64.RawURLEncoding.EncodeToString(secret)
+	return Key{
+		Plaintext: scheme + p + "_" + s,
+		Prefix:    p,
+		Salt:      salt,
+		Hash:      Hash(salt, s),
+	}, nil
+}
+
+// Parse splits a presented key into its prefix and secret. It reports false
+// when key is not in the kart_<prefix>_<secret> format.
+func Parse(key string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(key, scheme)
+	if !found || len(rest) < prefixLen+2 || rest[prefixLen] != '_' {
+		return "", "", false
+	}
+	return rest[:prefixLen], rest[prefixLen+1:], true
+}
+
+// Hash returns SHA-256(salt || secret).
+func Hash(salt []byte, secret string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+	return h.Sum(nil)
+}
+
+// Verify reports whether secret hashes to hash under salt, in constant time.
+func Verify(salt, hash []byte, secret string) bool {
+	return subtle.ConstantTimeCompare(Hash(salt, secret), hash) == 1
+}
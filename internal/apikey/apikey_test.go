@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_RoundTrip(t *testing.T) {
+	k, err := Generate()
+	require.NoError(t, err)
+	assert.Len(t, k.Prefix, prefixLen)
+
+	prefix, secret, ok := Parse(k.Plaintext)
+	require.True(t, ok)
+	assert.Equal(t, k.Prefix, prefix)
+	assert.True(t, Verify(k.Salt, k.Hash, secret))
+	assert.False(t, Verify(k.Salt, k.Hash, secret+"x"))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, k.Prefix, other.Prefix)
+	assert.False(t, Verify(other.Salt, other.Hash, secret))
+}
+
+func TestParse(t *testing.T) {
+	type tc struct {
+		name       string
+		key        string
+		wantOK     bool
+		wantPrefix string
+		wantSecret string
+	}
+	cases := []tc{
+		{name: "ok", key: "kart_0123456789ab_s3cr-et_", wantOK: true, wantPrefix: "0123456789ab", wantSecret: "s3cr-et_"},
+		{name: "no scheme", key: "0123456789ab_secret"},
+		{name: "short prefix", key: "kart_0123_secret"},
+		{name: "no secret", key: "kart_0123456789ab_"},
+		{name: "legacy plaintext", key: "apitest"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			prefix, secret, ok := Parse(c.key)
+			assert.Equal(t, c.wantOK, ok)
+			assert.Equal(t, c.wantPrefix, prefix)
+			assert.Equal(t, c.wantSecret, secret)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipal_HasScopes(t *testing.T) {
+	p := Principal{Scopes: []string{ScopeOrdersWrite, ScopeCouponsAdmin}}
+	type tc struct {
+		name     string
+		required []string
+		want     bool
+	}
+	cases := []tc{
+		{name: "none required", want: true},
+		{name: "held", required: []string{ScopeOrdersWrite}, want: true},
+		{name: "all held", required: []string{ScopeOrdersWrite, ScopeCouponsAdmin}, want: true},
+		{name: "one missing", required: []string{ScopeOrdersWrite, ScopeCatalogAdmin}, want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, p.HasScopes(c.required...))
+		})
+	}
+}
+
+func TestPrincipalFrom(t *testing.T) {
+	_, ok := PrincipalFrom(context.Background())
+	assert.False(t, ok)
+
+	p := Principal{Subject: "api_key:abc", StoreID: "acme"}
+	got, ok := PrincipalFrom(WithPrincipal(context.Background(), p))
+	assert.True(t, ok)
+	assert.Equal(t, p, got)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if pub, ok := k[kid]; ok {
+		return pub, nil
+	}
+	return nil, errors.New("unknown key")
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	now := time.Date(2025, 10, 9, 12, 0, 0, 0, time.UTC)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	v := NewTokenVerifier(staticKeys{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey}, "https://id.example.com/", "kart")
+	v.now = func() time.Time { return now }
+
+	claims := func(mod func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   "https://id.example.com/",
+			"aud":   "kart",
+			"sub":   "cust_1",
+			"exp":   now.Add(time.Hour).Unix(),
+			"scope": "orders:write",
+		}
+		if mod != nil {
+			mod(c)
+		}
+		return c
+	}
+	sign := func(m jwt.SigningMethod, kid string, key any, c jwt.MapClaims) string {
+		tok := jwt.NewWithClaims(m, c)
+		tok.Header["kid"] = kid
+		s, err := tok.SignedString(key)
+		require.NoError(t, err)
+		return s
+	}
+
+	type tc struct {
+		name       string
+		token      string
+		wantScopes []string
+		wantErr    bool
+	}
+	cases := []tc{
+		{name: "RS256", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(nil)), wantScopes: []string{ScopeOrdersWrite}},
+		{name: "ES256", token: sign(jwt.SigningMethodES256, "ec", ecKey, claims(nil)), wantScopes: []string{ScopeOrdersWrite}},
+		{name: "scp array", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) {
+			delete(c, "scope")
+			c["scp"] = []string{ScopeOrdersWrite}
+		})), wantScopes: []string{ScopeOrdersWrite}},
+		{name: "no scopes", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { delete(c, "scope") })), wantScopes: []string{}},
+		{name: "within leeway", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-10 * time.Second).Unix() })), wantScopes: []string{ScopeOrdersWrite}},
+		{name: "expired", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() })), wantErr: true},
+		{name: "no exp", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { delete(c, "exp") })), wantErr: true},
+		{name: "other issuer", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com/" })), wantErr: true},
+		{name: "other audience", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { c["aud"] = "billing" })), wantErr: true},
+		{name: "no subject", token: sign(jwt.SigningMethodRS256, "rsa", rsaKey, claims(func(c jwt.MapClaims) { delete(c, "sub") })), wantErr: true},
+		{name: "wrong key", token: sign(jwt.SigningMethodRS256, "rsa", otherKey, claims(nil)), wantErr: true},
+		{name: "unknown kid", token: sign(jwt.SigningMethodRS256, "nope", rsaKey, claims(nil)), wantErr: true},
+		{name: "HS256", token: sign(jwt.SigningMethodHS256, "rsa", []byte("secret"), claims(nil)), wantErr: true},
+		{name: "garbage", token: "not.a.jwt", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, err := v.Verify(context.Background(), c.token)
+			if c.wantErr {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Principal{Scheme: SchemeBearer, Subject: "customer:cust_1", CustomerID: "cust_1", Scopes: c.wantScopes}, p)
+		})
+	}
+}
+
+func TestTokenVerifier_RequiresIssuerAndAudience(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "cust_1", "exp": time.Now().Add(time.Hour).Unix()})
+	tok.Header["kid"] = "rsa"
+	token, err := tok.SignedString(key)
+	require.NoError(t, err)
+
+	for _, v := range []*TokenVerifier{
+		NewTokenVerifier(staticKeys{"rsa": &key.PublicKey}, "", "kart"),
+		NewTokenVerifier(staticKeys{"rsa": &key.PublicKey}, "https://id.example.com/", ""),
+	} {
+		_, err := v.Verify(context.Background(), token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrKeySetUnavailable is returned when the JWKS cannot be loaded and no
+// earlier copy is cached.
+var ErrKeySetUnavailable = errors.New("jwks unavailable")
+
+// minRefresh bounds how often the set is reloaded early for an unknown kid or
+// retried after a failure, so tokens naming made-up keys or an outage cannot
+// hammer the identity provider.
+const minRefresh = time.Minute
+
+// KeySet is a JSON Web Key Set loaded from a file or an http(s) URL. Keys
+// are cached for TTL and reloaded early when a token names a key the cached
+// set does not hold, which picks up the provider's key rotations.
+type KeySet struct {
+	Source string
+	TTL    time.Duration
+	Client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetched   time.Time
+	attempted time.Time
+	// loading is closed when the load in flight finishes; nil when none is.
+	loading chan struct{}
+	loadErr error
+	now     func() time.Time
+}
+
+func NewKeySet(source string, ttl time.Duration) *KeySet {
+	return &KeySet{Source: source, TTL: ttl, Client: &http.Client{Timeout: 10 * time.Second}, now: time.Now}
+}
+
+// Key returns the public key with kid. The set is loaded outside the lock,
+// one load at a time: callers whose key is cached keep using it while a
+// refresh runs, and only those that need the new set wait for it.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	now := s.now()
+	_, known := s.keys[kid]
+	stale := now.Sub(s.fetched) >= s.TTL
+	if s.loading == nil && (s.keys == nil || ((stale || !known) && now.Sub(s.attempted) >= minRefresh)) {
+		s.attempted = now
+		s.loading = make(chan struct{})
+		go s.refresh(context.WithoutCancel(ctx), now, s.loading)
+	}
+	loading := s.loading
+	s.mu.Unlock()
+
+	if loading != nil && !known {
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeySetUnavailable, s.loadErr)
+	}
+	k, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	return k, nil
+}
+
+// refresh loads the set and closes done once it is stored. A failed load
+// keeps the cached set in service until the source recovers.
+func (s *KeySet) refresh(ctx context.Context, at time.Time, done chan struct{}) {
+	keys, err := s.load(ctx)
+	s.mu.Lock()
+	if err == nil {
+		s.keys, s.fetched = keys, at
+	}
+	s.loadErr = err
+	s.loading = nil
+	s.mu.Unlock()
+	close(done)
+}
+
+func (s *KeySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if !strings.HasPrefix(s.Source, "https://") && !strings.HasPrefix(s.Source, "http://") {
+		b, err := os.ReadFile(s.Source)
+		if err != nil {
+			return nil, err
+		}
+		return ParseJWKS(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", s.Source, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(b)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS decodes the RSA and EC signing keys in a JWKS document, keyed by
+// kid. Keys of other types or for encryption are skipped.
+func ParseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			pub crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			pub, err = k.rsa()
+		case "EC":
+			pub, err = k.ec()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwk %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+func (k jwk) rsa() (*rsa.PublicKey, error) {
+	n, err := b64Int(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := b64Int(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("rsa exponent out of range")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jwk) ec() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := b64Int(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := b64Int(k.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func b64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	doc := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"r1","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"e1","crv":"P-256","x":%q,"y":%q},
+		{"kty":"RSA","kid":"enc","use":"enc","n":%q,"e":"AQAB"},
+		{"kty":"oct","kid":"h1","k":"c2VjcmV0"}
+	]}`, b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.Bytes()), b64(ecKey.Y.Bytes()), b64(rsaKey.N.Bytes()))
+
+	keys, err := ParseJWKS([]byte(doc))
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.True(t, rsaKey.PublicKey.Equal(keys["r1"]))
+	assert.True(t, ecKey.PublicKey.Equal(keys["e1"]))
+
+	_, err = ParseJWKS([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	require.Error(t, err)
+}
+
+func TestKeySet_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"RSA","kid":"r1","n":"AQAB","e":"AQAB"}]}`), 0o600))
+
+	ks := NewKeySet(path, time.Hour)
+	_, err := ks.Key(context.Background(), "r1")
+	require.NoError(t, err)
+	_, err = ks.Key(context.Background(), "r2")
+	require.Error(t, err)
+
+	_, err = NewKeySet(filepath.Join(t.TempDir(), "missing.json"), time.Hour).Key(context.Background(), "r1")
+	require.ErrorIs(t, err, ErrKeySetUnavailable)
+}
+
+func TestKeySet_URLCaching(t *testing.T) {
+	var fetches atomic.Int32
+	var down atomic.Bool
+	kids := []string{"r1"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"keys":[`)
+		for i, kid := range kids {
+			if i > 0 {
+				_, _ = fmt.Fprint(w, ",")
+			}
+			_, _ = fmt.Fprintf(w, `{"kty":"RSA","kid":%q,"n":"AQAB","e":"AQAB"}`, kid)
+		}
+		_, _ = fmt.Fprint(w, `]}`)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 10, 9, 12, 0, 0, 0, time.UTC)
+	ks := NewKeySet(srv.URL, 10*time.Minute)
+	ks.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := ks.Key(ctx, "r1")
+	require.NoError(t, err)
+	_, err = ks.Key(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "cached")
+
+	// A rotated-in key is picked up once the refresh floor has passed.
+	kids = []string{"r1", "r2"}
+	_, err = ks.Key(ctx, "r2")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "unknown kid inside the refresh floor")
+	now = now.Add(2 * time.Minute)
+	_, err = ks.Key(ctx, "r2")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// An outage keeps the cached set in service and retries are throttled.
+	down.Store(true)
+	now = now.Add(time.Hour)
+	_, err = ks.Key(ctx, "r1")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return fetches.Load() == 3 }, time.Second, time.Millisecond)
+	_, err = ks.Key(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestKeySet_RefreshOutsideLock(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		_, _ = fmt.Fprint(w, `{"keys":[{"kty":"RSA","kid":"r1","n":"AQAB","e":"AQAB"},{"kty":"RSA","kid":"r2","n":"AQAB","e":"AQAB"}]}`)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	now := time.Date(2025, 10, 9, 12, 0, 0, 0, time.UTC)
+	ks := NewKeySet(srv.URL, 10*time.Minute)
+	ks.now = func() time.Time { return now }
+	ctx := context.Background()
+	_, err := ks.Key(ctx, "r1")
+	require.NoError(t, err)
+
+	// The refresh hangs, yet a cached key is served at once, and repeated
+	// lookups do not start another fetch.
+	now = now.Add(time.Hour)
+	for range 3 {
+		_, err = ks.Key(ctx, "r1")
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return fetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// A lookup that needs the new set waits for it, up to its deadline.
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = ks.Key(waitCtx, "r3")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), fetches.Load())
+}
@@ -0,0 +1,60 @@
+// Package buildinfo reports what the running binary was built from.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at build time with
+//
+//	-ldflags "-X kart/internal/buildinfo.Version=v1.2.3 -X kart/internal/buildinfo.Commit=abc123 -X kart/internal/buildinfo.BuildTime=2025-10-14T09:00:00Z"
+//
+// When unset, Get falls back to what the Go toolchain recorded.
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build info, preferring the values set through ldflags.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return withDefaults(info)
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = s.Value
+		case s.Key == "vcs.time" && info.BuildTime == "":
+			info.BuildTime = s.Value
+		}
+	}
+	return withDefaults(info)
+}
+
+func withDefaults(info Info) Info {
+	if info.Version == "" || info.Version == "(devel)" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
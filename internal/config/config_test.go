@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	type tc struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}
+	cases := []tc{
+		{name: "fake payments in dev", cfg: Config{Env: "dev", PaymentProvider: "fake"}},
+		{name: "fake payments in production", cfg: Config{Env: "production", PaymentProvider: "fake"}, wantErr: true},
+		{name: "no payments in production", cfg: Config{Env: "production", PaymentProvider: "none"}},
+		{name: "dev api key in dev", cfg: Config{Env: "dev", APIKey: "apitest"}},
+		{name: "dev api key in production", cfg: Config{Env: "production", APIKey: "apitest"}, wantErr: true},
+		{name: "own api key in production", cfg: Config{Env: "production", APIKey: "k3y-0f-our-own"}},
+		{name: "bearer tokens", cfg: Config{Env: "production", JWKS: "jwks.json", JWTIssuer: "https://id.example.com/", JWTAudience: "kart"}},
+		{name: "bearer tokens without issuer", cfg: Config{Env: "production", JWKS: "jwks.json", JWTAudience: "kart"}, wantErr: true},
+		{name: "bearer tokens without audience in dev", cfg: Config{Env: "dev", JWKS: "jwks.json", JWTIssuer: "https://id.example.com/"}, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.cfg.Validate()
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package giftcard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_RoundTrip(t *testing.T) {
+	c, err := Generate()
+	require.NoError(t, err)
+	assert.Regexp(t, `^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`, c.Plaintext)
+	assert.Equal(t, c.Plaintext[len(c.Plaintext)-4:], c.Last4)
+
+	n, ok := Normalize(c.Plaintext)
+	require.True(t, ok)
+	assert.Equal(t, c.Hash, Hash(n))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, c.Plaintext, other.Plaintext)
+	assert.NotEqual(t, c.Hash, other.Hash)
+}
+
+func TestNormalize(t *testing.T) {
+	type tc struct {
+		name   string
+		code   string
+		want   string
+		wantOK bool
+	}
+	cases := []tc{
+		{name: "printed", code: "K7QM-3XWD-9HTP-R2ZC", want: "K7QM3XWD9HTPR2ZC", wantOK: true},
+		{name: "typed loosely", code: " k7qm 3xwd-9htp r2zc ", want: "K7QM3XWD9HTPR2ZC", wantOK: true},
+		{name: "too short", code: "K7QM-3XWD-9HTP"},
+		{name: "look-alike", code: "K7QM-3XWD-9HTP-R2Z0"},
+		{name: "coupon code", code: "HAPPYHRS"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := Normalize(c.code)
+			assert.Equal(t, c.wantOK, ok)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package health answers liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds each readiness check when Checker.Timeout is unset.
+const DefaultTimeout = 2 * time.Second
+
+// Check reports whether one dependency is usable.
+type Check func(ctx context.Context) error
+
+// Checker runs the readiness checks. It reports not ready once Drain has
+// been called, so load balancers stop routing to a process shutting down.
+type Checker struct {
+	Timeout time.Duration
+
+	mu       sync.Mutex
+	names    []string
+	checks   map[string]Check
+	draining atomic.Bool
+}
+
+// Add registers a readiness check under name, replacing any with that name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checks == nil {
+		c.checks = map[string]Check{}
+	}
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Drain marks the process as shutting down.
+func (c *Checker) Drain() { c.draining.Store(true) }
+
+// Draining reports whether Drain has been called.
+func (c *Checker) Draining() bool { return c.draining.Load() }
+
+// Report is the outcome of the readiness checks: "ok" or the error for each.
+type Report struct {
+	Ready    bool
+	Draining bool
+	Checks   map[string]string
+}
+
+// Ready runs every check concurrently, each under the timeout.
+func (c *Checker) Ready(ctx context.Context) Report {
+	c.mu.Lock()
+	names := append([]string(nil), c.names...)
+	checks := make([]Check, len(names))
+	for i, n := range names {
+		checks[i] = c.checks[n]
+	}
+	c.mu.Unlock()
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	r := Report{Ready: !c.Draining(), Draining: c.Draining(), Checks: make(map[string]string, len(names))}
+	for i, n := range names {
+		if errs[i] != nil {
+			r.Ready = false
+			r.Checks[n] = errs[i].Error()
+			continue
+		}
+		r.Checks[n] = "ok"
+	}
+	return r
+}
+
+// Ping checks that db accepts connections.
+func Ping(db *sql.DB) Check {
+	return func(ctx context.Context) error { return db.PingContext(ctx) }
+}
+
+// schemaVersionSQL reads the newest applied schema version the way goose
+// does: goose appends a row for every up and down, so a version counts only
+// if its latest row is an apply.
+const schemaVersionSQL = `SELECT COALESCE(MAX(version_id), 0) FROM (
+  SELECT DISTINCT ON (version_id) version_id, is_applied FROM goose_db_version ORDER BY version_id, id DESC
+) latest WHERE is_applied`
+
+// Migrations checks that the goose migrations are applied up to want, the
+// version the binary was built for.
+func Migrations(db *sql.DB, want int64) Check {
+	return func(ctx context.Context) error {
+		var got int64
+		err := db.QueryRowContext(ctx, schemaVersionSQL).Scan(&got)
+		if err != nil {
+			return fmt.Errorf("read schema version: %w", err)
+		}
+		if got < want {
+			return fmt.Errorf("schema at version %d, want %d", got, want)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Ready(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }
+
+	type tc struct {
+		name   string
+		checks map[string]Check
+		drain  bool
+		want   Report
+	}
+	cases := []tc{
+		{
+			name:   "all pass",
+			checks: map[string]Check{"database": ok, "openapi": ok},
+			want:   Report{Ready: true, Checks: map[string]string{"database": "ok", "openapi": "ok"}},
+		},
+		{
+			name:   "failing check",
+			checks: map[string]Check{"database": down, "openapi": ok},
+			want:   Report{Checks: map[string]string{"database": "connection refused", "openapi": "ok"}},
+		},
+		{
+			name:   "check times out",
+			checks: map[string]Check{"database": slow},
+			want:   Report{Checks: map[string]string{"database": "context deadline exceeded"}},
+		},
+		{
+			name:   "draining",
+			checks: map[string]Check{"database": ok},
+			drain:  true,
+			want:   Report{Draining: true, Checks: map[string]string{"database": "ok"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ch := &Checker{Timeout: 10 * time.Millisecond}
+			for name, check := range c.checks {
+				ch.Add(name, check)
+			}
+			if c.drain {
+				ch.Drain()
+			}
+			assert.Equal(t, c.want, ch.Ready(context.Background()))
+		})
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	type tc struct {
+		name    string
+		applied int64
+		err     error
+		wantErr string
+	}
+	cases := []tc{
+		{name: "current", applied: 20251013},
+		{name: "ahead", applied: 20251101},
+		{name: "behind", applied: 20251001, wantErr: "schema at version 20251001, want 20251013"},
+		{name: "no goose table", err: errors.New(`relation "goose_db_version" does not exist`), wantErr: "read schema version"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, m, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			q := m.ExpectQuery(regexp.QuoteMeta(schemaVersionSQL))
+			if c.err != nil {
+				q.WillReturnError(c.err)
+			} else {
+				q.WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(c.applied))
+			}
+
+			err = Migrations(db, 20251013)(context.Background())
+			if c.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.NoError(t, m.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	type tc struct {
+		name    string
+		level   string
+		format  string
+		wantErr bool
+	}
+	cases := []tc{
+		{name: "json", level: "info", format: "json"},
+		{name: "text", level: "DEBUG", format: "text"},
+		{name: "unknown level", level: "loud", format: "json", wantErr: true},
+		{name: "unknown format", level: "info", format: "xml", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := New(&bytes.Buffer{}, c.level, c.format)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRequestIDAttached(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, "info", "json")
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	l.With("component", "test").InfoContext(ctx, "hello")
+	l.Debug("filtered out")
+
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	require.Equal(t, "req-1", rec["request_id"])
+	require.Equal(t, "test", rec["component"])
+	require.Equal(t, "hello", rec["msg"])
+}
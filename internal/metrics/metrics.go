@@ -0,0 +1,122 @@
+// Package metrics defines the Prometheus metrics the service exports: HTTP
+// traffic, the database pool and business counters.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"kart/internal/money"
+)
+
+const namespace = "kart"
+
+// Metrics holds the collectors, registered with the registry given to New.
+// Its methods do nothing on a nil *Metrics, so recording is optional.
+type Metrics struct {
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+
+	OrdersPlaced *prometheus.CounterVec
+	// OrderValue is in major units of the order currency.
+	OrderValue       *prometheus.HistogramVec
+	CouponRejections *prometheus.CounterVec
+	// CouponConflicts counts orders refused because their single-use coupon
+	// had been redeemed in the meantime.
+	CouponConflicts prometheus.Counter
+}
+
+// New creates the metrics and registers them with reg. Tests pass a fresh
+// prometheus.NewRegistry() to read values back.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by route pattern, method and status.",
+		}, []string{"route", "method", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to serve HTTP requests, by route pattern and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		OrdersPlaced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_placed_total",
+			Help:      "Orders placed, by currency.",
+		}, []string{"currency"}),
+		OrderValue: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "order_value",
+			Help:      "Order totals in major units, by currency.",
+			Buckets:   []float64{5, 10, 20, 30, 50, 75, 100, 150, 250, 500},
+		}, []string{"currency"}),
+		CouponRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "coupon_rejections_total",
+			Help:      "Coupon codes rejected, by reason.",
+		}, []string{"reason"}),
+		CouponConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "coupon_redeemed_conflicts_total",
+			Help:      "Orders refused because their single-use coupon was already redeemed.",
+		}),
+	}
+	reg.MustRegister(m.HTTPRequests, m.HTTPDuration, m.OrdersPlaced, m.OrderValue, m.CouponRejections, m.CouponConflicts)
+	return m
+}
+
+// RegisterDB exports the pool statistics (sql.DBStats) of db.
+func RegisterDB(reg prometheus.Registerer, db *sql.DB) error {
+	return reg.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler serves the metrics gathered by g in the Prometheus text format.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served HTTP request. route is the pattern the
+// request matched, not its path, so label values stay bounded.
+func (m *Metrics) ObserveRequest(route, method string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	if route == "" {
+		route = "unmatched"
+	}
+	m.HTTPRequests.WithLabelValues(route, method, strconv.Itoa(status)).Inc()
+	m.HTTPDuration.WithLabelValues(route, method).Observe(d.Seconds())
+}
+
+// OrderPlaced records an order for total.
+func (m *Metrics) OrderPlaced(total money.Money) {
+	if m == nil {
+		return
+	}
+	m.OrdersPlaced.WithLabelValues(total.Currency).Inc()
+	m.OrderValue.WithLabelValues(total.Currency).Observe(total.Float64())
+}
+
+// CouponRejected records a coupon code rejected for reason.
+func (m *Metrics) CouponRejected(reason string) {
+	if m == nil {
+		return
+	}
+	m.CouponRejections.WithLabelValues(reason).Inc()
+}
+
+// CouponConflict records an order refused because its coupon was redeemed.
+func (m *Metrics) CouponConflict() {
+	if m == nil {
+		return
+	}
+	m.CouponConflicts.Inc()
+}
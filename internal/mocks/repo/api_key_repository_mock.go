@@ -0,0 +1,182 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+
+	time "time"
+)
+
+// APIKeyRepository is an autogenerated mock type for the APIKeyRepository type
+type APIKeyRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, k
+func (_m *APIKeyRepository) Create(ctx context.Context, k sqlc.ApiKey) error {
+	ret := _m.Called(ctx, k)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ApiKey) error); ok {
+		r0 = rf(ctx, k)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Expire provides a mock function with given fields: ctx, prefix, at
+func (_m *APIKeyRepository) Expire(ctx context.Context, prefix string, at time.Time) (bool, error) {
+	ret := _m.Called(ctx, prefix, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return rf(ctx, prefix, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(ctx, prefix, at)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, prefix, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (sqlc.ApiKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPrefix")
+	}
+
+	var r0 sqlc.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.ApiKey, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.ApiKey); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(sqlc.ApiKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *APIKeyRepository) List(ctx context.Context) ([]sqlc.ApiKey, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []sqlc.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]sqlc.ApiKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []sqlc.ApiKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.ApiKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, prefix, at
+func (_m *APIKeyRepository) Revoke(ctx context.Context, prefix string, at time.Time) (bool, error) {
+	ret := _m.Called(ctx, prefix, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (bool, error)); ok {
+		return rf(ctx, prefix, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) bool); ok {
+		r0 = rf(ctx, prefix, at)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, prefix, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Touch provides a mock function with given fields: ctx, id, at, staleBefore
+func (_m *APIKeyRepository) Touch(ctx context.Context, id string, at time.Time, staleBefore time.Time) error {
+	ret := _m.Called(ctx, id, at, staleBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, id, at, staleBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyRepository {
+	mock := &APIKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
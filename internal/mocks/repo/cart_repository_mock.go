@@ -0,0 +1,302 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
+
+	sqlc "kart/internal/sqlc"
+
+	time "time"
+)
+
+// CartRepository is an autogenerated mock type for the CartRepository type
+type CartRepository struct {
+	mock.Mock
+}
+
+// AddItem provides a mock function with given fields: ctx, cartID, productID, qty
+func (_m *CartRepository) AddItem(ctx context.Context, cartID string, productID string, qty int32) error {
+	ret := _m.Called(ctx, cartID, productID, qty)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32) error); ok {
+		r0 = rf(ctx, cartID, productID, qty)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimCheckout provides a mock function with given fields: ctx, cartID, startedAt, staleBefore
+func (_m *CartRepository) ClaimCheckout(ctx context.Context, cartID string, startedAt time.Time, staleBefore time.Time) (bool, error) {
+	ret := _m.Called(ctx, cartID, startedAt, staleBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimCheckout")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (bool, error)); ok {
+		return rf(ctx, cartID, startedAt, staleBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) bool); ok {
+		r0 = rf(ctx, cartID, startedAt, staleBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, cartID, startedAt, staleBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteCheckout provides a mock function with given fields: ctx, cartID, orderID
+func (_m *CartRepository) CompleteCheckout(ctx context.Context, cartID string, orderID string) error {
+	ret := _m.Called(ctx, cartID, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteCheckout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, cartID, orderID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, id, currency, expiresAt
+func (_m *CartRepository) Create(ctx context.Context, id string, currency string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, id, currency, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, currency, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: ctx, now
+func (_m *CartRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *CartRepository) Get(ctx context.Context, id string) (sqlc.Cart, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Cart, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Cart); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Items provides a mock function with given fields: ctx, cartID
+func (_m *CartRepository) Items(ctx context.Context, cartID string) ([]sqlc.CartItem, error) {
+	ret := _m.Called(ctx, cartID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Items")
+	}
+
+	var r0 []sqlc.CartItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.CartItem, error)); ok {
+		return rf(ctx, cartID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.CartItem); ok {
+		r0 = rf(ctx, cartID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.CartItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, cartID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseCheckout provides a mock function with given fields: ctx, cartID, startedAt
+func (_m *CartRepository) ReleaseCheckout(ctx context.Context, cartID string, startedAt time.Time) error {
+	ret := _m.Called(ctx, cartID, startedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseCheckout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, cartID, startedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveItem provides a mock function with given fields: ctx, cartID, productID
+func (_m *CartRepository) RemoveItem(ctx context.Context, cartID string, productID string) (bool, error) {
+	ret := _m.Called(ctx, cartID, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveItem")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, cartID, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, cartID, productID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, cartID, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCoupon provides a mock function with given fields: ctx, cartID, code
+func (_m *CartRepository) SetCoupon(ctx context.Context, cartID string, code sql.NullString) error {
+	ret := _m.Called(ctx, cartID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCoupon")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, sql.NullString) error); ok {
+		r0 = rf(ctx, cartID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetItem provides a mock function with given fields: ctx, cartID, productID, qty
+func (_m *CartRepository) SetItem(ctx context.Context, cartID string, productID string, qty int32) error {
+	ret := _m.Called(ctx, cartID, productID, qty)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32) error); ok {
+		r0 = rf(ctx, cartID, productID, qty)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Touch provides a mock function with given fields: ctx, cartID, expiresAt
+func (_m *CartRepository) Touch(ctx context.Context, cartID string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, cartID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, cartID, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCartRepository creates a new instance of CartRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCartRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CartRepository {
+	mock := &CartRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,134 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// GiftCardRepository is an autogenerated mock type for the GiftCardRepository type
+type GiftCardRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, c
+func (_m *GiftCardRepository) Create(ctx context.Context, c sqlc.GiftCard) error {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GiftCard) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *GiftCardRepository) Get(ctx context.Context, id string) (sqlc.GiftCard, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.GiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.GiftCard, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.GiftCard); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.GiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByCode provides a mock function with given fields: ctx, codeHash
+func (_m *GiftCardRepository) GetByCode(ctx context.Context, codeHash []byte) (sqlc.GiftCard, error) {
+	ret := _m.Called(ctx, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByCode")
+	}
+
+	var r0 sqlc.GiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (sqlc.GiftCard, error)); ok {
+		return rf(ctx, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) sqlc.GiftCard); ok {
+		r0 = rf(ctx, codeHash)
+	} else {
+		r0 = ret.Get(0).(sqlc.GiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transactions provides a mock function with given fields: ctx, id
+func (_m *GiftCardRepository) Transactions(ctx context.Context, id string) ([]sqlc.GiftCardTransaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transactions")
+	}
+
+	var r0 []sqlc.GiftCardTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.GiftCardTransaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.GiftCardTransaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.GiftCardTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewGiftCardRepository creates a new instance of GiftCardRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGiftCardRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GiftCardRepository {
+	mock := &GiftCardRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,176 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// LoyaltyRepository is an autogenerated mock type for the LoyaltyRepository type
+type LoyaltyRepository struct {
+	mock.Mock
+}
+
+// Balance provides a mock function with given fields: ctx, customerID
+func (_m *LoyaltyRepository) Balance(ctx context.Context, customerID string) (int64, error) {
+	ret := _m.Called(ctx, customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Balance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, customerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Entries provides a mock function with given fields: ctx, customerID, limit
+func (_m *LoyaltyRepository) Entries(ctx context.Context, customerID string, limit int32) ([]sqlc.LoyaltyEntry, error) {
+	ret := _m.Called(ctx, customerID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Entries")
+	}
+
+	var r0 []sqlc.LoyaltyEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) ([]sqlc.LoyaltyEntry, error)); ok {
+		return rf(ctx, customerID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) []sqlc.LoyaltyEntry); ok {
+		r0 = rf(ctx, customerID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.LoyaltyEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, customerID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OrderEntries provides a mock function with given fields: ctx, orderID
+func (_m *LoyaltyRepository) OrderEntries(ctx context.Context, orderID string) ([]sqlc.LoyaltyEntry, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrderEntries")
+	}
+
+	var r0 []sqlc.LoyaltyEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.LoyaltyEntry, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.LoyaltyEntry); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.LoyaltyEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Post provides a mock function with given fields: ctx, e
+func (_m *LoyaltyRepository) Post(ctx context.Context, e sqlc.LoyaltyEntry) (bool, error) {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.LoyaltyEntry) (bool, error)); ok {
+		return rf(ctx, e)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.LoyaltyEntry) bool); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.LoyaltyEntry) error); ok {
+		r1 = rf(ctx, e)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rules provides a mock function with given fields: ctx
+func (_m *LoyaltyRepository) Rules(ctx context.Context) (map[string]int32, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rules")
+	}
+
+	var r0 map[string]int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int32, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int32); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int32)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLoyaltyRepository creates a new instance of LoyaltyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoyaltyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoyaltyRepository {
+	mock := &LoyaltyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,9 +4,12 @@ package repomock
 
 import (
 	context "context"
+	repo "kart/internal/repo"
 
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	sqlc "kart/internal/sqlc"
 )
 
@@ -15,9 +18,16 @@ type OrderRepository struct {
 	mock.Mock
 }
 
-// CreateWithItems provides a mock function with given fields: ctx, o, items
-func (_m *OrderRepository) CreateWithItems(ctx context.Context, o sqlc.Order, items []sqlc.OrderItem) (string, error) {
-	ret := _m.Called(ctx, o, items)
+// CreateWithItems provides a mock function with given fields: ctx, o, items, taxes, giftCards
+func (_m *OrderRepository) CreateWithItems(ctx context.Context, o sqlc.Order, items []sqlc.OrderItem, taxes []sqlc.OrderTax, giftCards ...repo.GiftCardDebit) (string, error) {
+	_va := make([]interface{}, len(giftCards))
+	for _i := range giftCards {
+		_va[_i] = giftCards[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, o, items, taxes)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateWithItems")
@@ -25,17 +35,223 @@ func (_m *OrderRepository) CreateWithItems(ctx context.Context, o sqlc.Order, it
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Order, []sqlc.OrderItem) (string, error)); ok {
-		return rf(ctx, o, items)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Order, []sqlc.OrderItem, []sqlc.OrderTax, ...repo.GiftCardDebit) (string, error)); ok {
+		return rf(ctx, o, items, taxes, giftCards...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Order, []sqlc.OrderItem) string); ok {
-		r0 = rf(ctx, o, items)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Order, []sqlc.OrderItem, []sqlc.OrderTax, ...repo.GiftCardDebit) string); ok {
+		r0 = rf(ctx, o, items, taxes, giftCards...)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, sqlc.Order, []sqlc.OrderItem) error); ok {
-		r1 = rf(ctx, o, items)
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.Order, []sqlc.OrderItem, []sqlc.OrderTax, ...repo.GiftCardDebit) error); ok {
+		r1 = rf(ctx, o, items, taxes, giftCards...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailPayment provides a mock function with given fields: ctx, id, from, status
+func (_m *OrderRepository) FailPayment(ctx context.Context, id string, from string, status string) error {
+	ret := _m.Called(ctx, id, from, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, id, from, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *OrderRepository) Get(ctx context.Context, id string) (sqlc.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Order, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Items provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) Items(ctx context.Context, orderID string) ([]sqlc.OrderItem, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Items")
+	}
+
+	var r0 []sqlc.OrderItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.OrderItem, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.OrderItem); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.OrderItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByCustomer provides a mock function with given fields: ctx, customerID, limit
+func (_m *OrderRepository) ListByCustomer(ctx context.Context, customerID string, limit int32) ([]sqlc.Order, error) {
+	ret := _m.Called(ctx, customerID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByCustomer")
+	}
+
+	var r0 []sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) ([]sqlc.Order, error)); ok {
+		return rf(ctx, customerID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int32) []sqlc.Order); ok {
+		r0 = rf(ctx, customerID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int32) error); ok {
+		r1 = rf(ctx, customerID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reverse provides a mock function with given fields: ctx, id, from, status, eta, entries
+func (_m *OrderRepository) Reverse(ctx context.Context, id string, from string, status string, eta sql.NullTime, entries ...sqlc.LoyaltyEntry) (sqlc.Order, error) {
+	_va := make([]interface{}, len(entries))
+	for _i := range entries {
+		_va[_i] = entries[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id, from, status, eta)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reverse")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) (sqlc.Order, error)); ok {
+		return rf(ctx, id, from, status, eta, entries...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) sqlc.Order); ok {
+		r0 = rf(ctx, id, from, status, eta, entries...)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) error); ok {
+		r1 = rf(ctx, id, from, status, eta, entries...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Taxes provides a mock function with given fields: ctx, orderID
+func (_m *OrderRepository) Taxes(ctx context.Context, orderID string) ([]sqlc.OrderTax, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Taxes")
+	}
+
+	var r0 []sqlc.OrderTax
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.OrderTax, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.OrderTax); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.OrderTax)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, from, status, eta, entries
+func (_m *OrderRepository) UpdateStatus(ctx context.Context, id string, from string, status string, eta sql.NullTime, entries ...sqlc.LoyaltyEntry) (sqlc.Order, error) {
+	_va := make([]interface{}, len(entries))
+	for _i := range entries {
+		_va[_i] = entries[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id, from, status, eta)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) (sqlc.Order, error)); ok {
+		return rf(ctx, id, from, status, eta, entries...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) sqlc.Order); ok {
+		r0 = rf(ctx, id, from, status, eta, entries...)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, sql.NullTime, ...sqlc.LoyaltyEntry) error); ok {
+		r1 = rf(ctx, id, from, status, eta, entries...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,104 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// PaymentRepository is an autogenerated mock type for the PaymentRepository type
+type PaymentRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, p
+func (_m *PaymentRepository) Create(ctx context.Context, p sqlc.Payment) error {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Payment) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByOrder provides a mock function with given fields: ctx, orderID
+func (_m *PaymentRepository) GetByOrder(ctx context.Context, orderID string) (sqlc.Payment, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByOrder")
+	}
+
+	var r0 sqlc.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Payment, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Payment); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(sqlc.Payment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, p
+func (_m *PaymentRepository) Update(ctx context.Context, p sqlc.Payment) (sqlc.Payment, error) {
+	ret := _m.Called(ctx, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 sqlc.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Payment) (sqlc.Payment, error)); ok {
+		return rf(ctx, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Payment) sqlc.Payment); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Get(0).(sqlc.Payment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.Payment) error); ok {
+		r1 = rf(ctx, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentRepository {
+	mock := &PaymentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
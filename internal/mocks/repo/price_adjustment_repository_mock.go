@@ -0,0 +1,62 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+
+	time "time"
+)
+
+// PriceAdjustmentRepository is an autogenerated mock type for the PriceAdjustmentRepository type
+type PriceAdjustmentRepository struct {
+	mock.Mock
+}
+
+// Scheduled provides a mock function with given fields: ctx, at
+func (_m *PriceAdjustmentRepository) Scheduled(ctx context.Context, at time.Time) ([]sqlc.ListPriceAdjustmentsRow, error) {
+	ret := _m.Called(ctx, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scheduled")
+	}
+
+	var r0 []sqlc.ListPriceAdjustmentsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]sqlc.ListPriceAdjustmentsRow, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []sqlc.ListPriceAdjustmentsRow); ok {
+		r0 = rf(ctx, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.ListPriceAdjustmentsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPriceAdjustmentRepository creates a new instance of PriceAdjustmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPriceAdjustmentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PriceAdjustmentRepository {
+	mock := &PriceAdjustmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,88 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// PriceListRepository is an autogenerated mock type for the PriceListRepository type
+type PriceListRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, currency
+func (_m *PriceListRepository) Get(ctx context.Context, currency string) (sqlc.PriceList, error) {
+	ret := _m.Called(ctx, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.PriceList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.PriceList, error)); ok {
+		return rf(ctx, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.PriceList); ok {
+		r0 = rf(ctx, currency)
+	} else {
+		r0 = ret.Get(0).(sqlc.PriceList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Prices provides a mock function with given fields: ctx, listID, productIDs
+func (_m *PriceListRepository) Prices(ctx context.Context, listID string, productIDs []string) (map[string]int32, error) {
+	ret := _m.Called(ctx, listID, productIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prices")
+	}
+
+	var r0 map[string]int32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (map[string]int32, error)); ok {
+		return rf(ctx, listID, productIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) map[string]int32); ok {
+		r0 = rf(ctx, listID, productIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int32)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, listID, productIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPriceListRepository creates a new instance of PriceListRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPriceListRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PriceListRepository {
+	mock := &PriceListRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -43,6 +43,34 @@ func (_m *ProductRepository) Get(ctx context.Context, id string) (sqlc.Product,
 	return r0, r1
 }
 
+// GetBySlug provides a mock function with given fields: ctx, slug
+func (_m *ProductRepository) GetBySlug(ctx context.Context, slug string) (sqlc.Product, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 sqlc.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Product, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Product); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(sqlc.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMany provides a mock function with given fields: ctx, ids
 func (_m *ProductRepository) GetMany(ctx context.Context, ids []string) (map[string]sqlc.Product, error) {
 	ret := _m.Called(ctx, ids)
@@ -0,0 +1,62 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+
+	time "time"
+)
+
+// PromotionRepository is an autogenerated mock type for the PromotionRepository type
+type PromotionRepository struct {
+	mock.Mock
+}
+
+// Active provides a mock function with given fields: ctx, at
+func (_m *PromotionRepository) Active(ctx context.Context, at time.Time) ([]sqlc.Promotion, error) {
+	ret := _m.Called(ctx, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Active")
+	}
+
+	var r0 []sqlc.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]sqlc.Promotion, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []sqlc.Promotion); ok {
+		r0 = rf(ctx, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPromotionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PromotionRepository {
+	mock := &PromotionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
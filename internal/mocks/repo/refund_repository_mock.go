@@ -0,0 +1,182 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repomock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// RefundRepository is an autogenerated mock type for the RefundRepository type
+type RefundRepository struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: ctx, id, providerRef
+func (_m *RefundRepository) Complete(ctx context.Context, id string, providerRef string) (sqlc.Refund, error) {
+	ret := _m.Called(ctx, id, providerRef)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 sqlc.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (sqlc.Refund, error)); ok {
+		return rf(ctx, id, providerRef)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) sqlc.Refund); ok {
+		r0 = rf(ctx, id, providerRef)
+	} else {
+		r0 = ret.Get(0).(sqlc.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, providerRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, r, items, capturedCents
+func (_m *RefundRepository) Create(ctx context.Context, r sqlc.Refund, items []sqlc.RefundItem, capturedCents int64) error {
+	ret := _m.Called(ctx, r, items, capturedCents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.Refund, []sqlc.RefundItem, int64) error); ok {
+		r0 = rf(ctx, r, items, capturedCents)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fail provides a mock function with given fields: ctx, id, reason
+func (_m *RefundRepository) Fail(ctx context.Context, id string, reason string) error {
+	ret := _m.Called(ctx, id, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ItemsByOrder provides a mock function with given fields: ctx, orderID
+func (_m *RefundRepository) ItemsByOrder(ctx context.Context, orderID string) ([]sqlc.RefundItem, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ItemsByOrder")
+	}
+
+	var r0 []sqlc.RefundItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.RefundItem, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.RefundItem); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.RefundItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByOrder provides a mock function with given fields: ctx, orderID
+func (_m *RefundRepository) ListByOrder(ctx context.Context, orderID string) ([]sqlc.Refund, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByOrder")
+	}
+
+	var r0 []sqlc.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.Refund, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.Refund); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Succeeded provides a mock function with given fields: ctx, orderID
+func (_m *RefundRepository) Succeeded(ctx context.Context, orderID string) (int64, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Succeeded")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRefundRepository creates a new instance of RefundRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefundRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RefundRepository {
+	mock := &RefundRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package servermock
+
+import (
+	context "context"
+	auth "kart/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BearerVerifier is an autogenerated mock type for the BearerVerifier type
+type BearerVerifier struct {
+	mock.Mock
+}
+
+// Verify provides a mock function with given fields: ctx, token
+func (_m *BearerVerifier) Verify(ctx context.Context, token string) (auth.Principal, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 auth.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (auth.Principal, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) auth.Principal); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(auth.Principal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBearerVerifier creates a new instance of BearerVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBearerVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BearerVerifier {
+	mock := &BearerVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
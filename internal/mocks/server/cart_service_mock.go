@@ -0,0 +1,254 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package servermock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "kart/internal/service"
+)
+
+// CartService is an autogenerated mock type for the CartService type
+type CartService struct {
+	mock.Mock
+}
+
+// AddItem provides a mock function with given fields: ctx, id, productID, qty, currency
+func (_m *CartService) AddItem(ctx context.Context, id string, productID string, qty int32, currency string) (service.Cart, error) {
+	ret := _m.Called(ctx, id, productID, qty, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddItem")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32, string) (service.Cart, error)); ok {
+		return rf(ctx, id, productID, qty, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32, string) service.Cart); ok {
+		r0 = rf(ctx, id, productID, qty, currency)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int32, string) error); ok {
+		r1 = rf(ctx, id, productID, qty, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApplyCoupon provides a mock function with given fields: ctx, id, code
+func (_m *CartService) ApplyCoupon(ctx context.Context, id string, code string) (service.Cart, error) {
+	ret := _m.Called(ctx, id, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyCoupon")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (service.Cart, error)); ok {
+		return rf(ctx, id, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) service.Cart); ok {
+		r0 = rf(ctx, id, code)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Checkout provides a mock function with given fields: ctx, id, in
+func (_m *CartService) Checkout(ctx context.Context, id string, in service.CheckoutInput) (service.PlaceOrderResult, error) {
+	ret := _m.Called(ctx, id, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkout")
+	}
+
+	var r0 service.PlaceOrderResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, service.CheckoutInput) (service.PlaceOrderResult, error)); ok {
+		return rf(ctx, id, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, service.CheckoutInput) service.PlaceOrderResult); ok {
+		r0 = rf(ctx, id, in)
+	} else {
+		r0 = ret.Get(0).(service.PlaceOrderResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, service.CheckoutInput) error); ok {
+		r1 = rf(ctx, id, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, currency
+func (_m *CartService) Create(ctx context.Context, currency string) (service.Cart, error) {
+	ret := _m.Called(ctx, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (service.Cart, error)); ok {
+		return rf(ctx, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) service.Cart); ok {
+		r0 = rf(ctx, currency)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *CartService) Get(ctx context.Context, id string) (service.Cart, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (service.Cart, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) service.Cart); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveCoupon provides a mock function with given fields: ctx, id
+func (_m *CartService) RemoveCoupon(ctx context.Context, id string) (service.Cart, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveCoupon")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (service.Cart, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) service.Cart); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveItem provides a mock function with given fields: ctx, id, productID
+func (_m *CartService) RemoveItem(ctx context.Context, id string, productID string) (service.Cart, error) {
+	ret := _m.Called(ctx, id, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveItem")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (service.Cart, error)); ok {
+		return rf(ctx, id, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) service.Cart); ok {
+		r0 = rf(ctx, id, productID)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetItem provides a mock function with given fields: ctx, id, productID, qty, currency
+func (_m *CartService) SetItem(ctx context.Context, id string, productID string, qty int32, currency string) (service.Cart, error) {
+	ret := _m.Called(ctx, id, productID, qty, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetItem")
+	}
+
+	var r0 service.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32, string) (service.Cart, error)); ok {
+		return rf(ctx, id, productID, qty, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32, string) service.Cart); ok {
+		r0 = rf(ctx, id, productID, qty, currency)
+	} else {
+		r0 = ret.Get(0).(service.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int32, string) error); ok {
+		r1 = rf(ctx, id, productID, qty, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCartService creates a new instance of CartService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCartService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CartService {
+	mock := &CartService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
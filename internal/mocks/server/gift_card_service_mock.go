@@ -0,0 +1,118 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package servermock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	service "kart/internal/service"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// GiftCardService is an autogenerated mock type for the GiftCardService type
+type GiftCardService struct {
+	mock.Mock
+}
+
+// Balance provides a mock function with given fields: ctx, code
+func (_m *GiftCardService) Balance(ctx context.Context, code string) (sqlc.GiftCard, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Balance")
+	}
+
+	var r0 sqlc.GiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.GiftCard, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.GiftCard); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(sqlc.GiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Issue provides a mock function with given fields: ctx, in
+func (_m *GiftCardService) Issue(ctx context.Context, in service.IssueGiftCardInput) (service.IssuedGiftCard, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 service.IssuedGiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, service.IssueGiftCardInput) (service.IssuedGiftCard, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, service.IssueGiftCardInput) service.IssuedGiftCard); ok {
+		r0 = rf(ctx, in)
+	} else {
+		r0 = ret.Get(0).(service.IssuedGiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, service.IssueGiftCardInput) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transactions provides a mock function with given fields: ctx, id
+func (_m *GiftCardService) Transactions(ctx context.Context, id string) ([]sqlc.GiftCardTransaction, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transactions")
+	}
+
+	var r0 []sqlc.GiftCardTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.GiftCardTransaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.GiftCardTransaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.GiftCardTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewGiftCardService creates a new instance of GiftCardService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGiftCardService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GiftCardService {
+	mock := &GiftCardService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package servermock
+
+import (
+	context "context"
+	auth "kart/internal/auth"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyAuthenticator is an autogenerated mock type for the KeyAuthenticator type
+type KeyAuthenticator struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, key
+func (_m *KeyAuthenticator) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 auth.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (auth.Principal, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) auth.Principal); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(auth.Principal)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewKeyAuthenticator creates a new instance of KeyAuthenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyAuthenticator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyAuthenticator {
+	mock := &KeyAuthenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,6 +8,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	service "kart/internal/service"
+
+	sqlc "kart/internal/sqlc"
 )
 
 // OrderService is an autogenerated mock type for the OrderService type
@@ -15,6 +17,148 @@ type OrderService struct {
 	mock.Mock
 }
 
+// ConfirmPayment provides a mock function with given fields: ctx, orderID
+func (_m *OrderService) ConfirmPayment(ctx context.Context, orderID string) (service.PaymentResult, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmPayment")
+	}
+
+	var r0 service.PaymentResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (service.PaymentResult, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) service.PaymentResult); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(service.PaymentResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CustomerOrders provides a mock function with given fields: ctx, customerID, limit
+func (_m *OrderService) CustomerOrders(ctx context.Context, customerID string, limit int) ([]sqlc.Order, error) {
+	ret := _m.Called(ctx, customerID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CustomerOrders")
+	}
+
+	var r0 []sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]sqlc.Order, error)); ok {
+		return rf(ctx, customerID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []sqlc.Order); ok {
+		r0 = rf(ctx, customerID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, customerID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Details provides a mock function with given fields: ctx, id
+func (_m *OrderService) Details(ctx context.Context, id string) (service.OrderDetails, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Details")
+	}
+
+	var r0 service.OrderDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (service.OrderDetails, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) service.OrderDetails); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(service.OrderDetails)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *OrderService) Get(ctx context.Context, id string) (sqlc.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Order, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoyaltyAccount provides a mock function with given fields: ctx, customerID, limit
+func (_m *OrderService) LoyaltyAccount(ctx context.Context, customerID string, limit int) (service.LoyaltyAccount, error) {
+	ret := _m.Called(ctx, customerID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoyaltyAccount")
+	}
+
+	var r0 service.LoyaltyAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (service.LoyaltyAccount, error)); ok {
+		return rf(ctx, customerID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) service.LoyaltyAccount); ok {
+		r0 = rf(ctx, customerID, limit)
+	} else {
+		r0 = ret.Get(0).(service.LoyaltyAccount)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, customerID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PlaceOrder provides a mock function with given fields: ctx, in
 func (_m *OrderService) PlaceOrder(ctx context.Context, in service.PlaceOrderInput) (service.PlaceOrderResult, error) {
 	ret := _m.Called(ctx, in)
@@ -43,6 +187,62 @@ func (_m *OrderService) PlaceOrder(ctx context.Context, in service.PlaceOrderInp
 	return r0, r1
 }
 
+// Refund provides a mock function with given fields: ctx, in
+func (_m *OrderService) Refund(ctx context.Context, in service.RefundInput) (service.Refund, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refund")
+	}
+
+	var r0 service.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, service.RefundInput) (service.Refund, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, service.RefundInput) service.Refund); ok {
+		r0 = rf(ctx, in)
+	} else {
+		r0 = ret.Get(0).(service.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, service.RefundInput) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, in
+func (_m *OrderService) UpdateStatus(ctx context.Context, in service.UpdateStatusInput) (sqlc.Order, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, service.UpdateStatusInput) (sqlc.Order, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, service.UpdateStatusInput) sqlc.Order); ok {
+		r0 = rf(ctx, in)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, service.UpdateStatusInput) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewOrderService creates a new instance of OrderService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderService(t interface {
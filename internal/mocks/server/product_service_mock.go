@@ -7,7 +7,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
-	sqlc "kart/internal/sqlc"
+	service "kart/internal/service"
 )
 
 // ProductService is an autogenerated mock type for the ProductService type
@@ -15,27 +15,55 @@ type ProductService struct {
 	mock.Mock
 }
 
-// Get provides a mock function with given fields: ctx, id
-func (_m *ProductService) Get(ctx context.Context, id string) (sqlc.Product, error) {
-	ret := _m.Called(ctx, id)
+// Get provides a mock function with given fields: ctx, id, currency
+func (_m *ProductService) Get(ctx context.Context, id string, currency string) (service.PricedProduct, error) {
+	ret := _m.Called(ctx, id, currency)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
 	}
 
-	var r0 sqlc.Product
+	var r0 service.PricedProduct
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Product, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (service.PricedProduct, error)); ok {
+		return rf(ctx, id, currency)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Product); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) service.PricedProduct); ok {
+		r0 = rf(ctx, id, currency)
 	} else {
-		r0 = ret.Get(0).(sqlc.Product)
+		r0 = ret.Get(0).(service.PricedProduct)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBySlug provides a mock function with given fields: ctx, slug, currency
+func (_m *ProductService) GetBySlug(ctx context.Context, slug string, currency string) (service.PricedProduct, error) {
+	ret := _m.Called(ctx, slug, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBySlug")
+	}
+
+	var r0 service.PricedProduct
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (service.PricedProduct, error)); ok {
+		return rf(ctx, slug, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) service.PricedProduct); ok {
+		r0 = rf(ctx, slug, currency)
+	} else {
+		r0 = ret.Get(0).(service.PricedProduct)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, slug, currency)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,29 +71,29 @@ func (_m *ProductService) Get(ctx context.Context, id string) (sqlc.Product, err
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx
-func (_m *ProductService) List(ctx context.Context) ([]sqlc.Product, error) {
-	ret := _m.Called(ctx)
+// List provides a mock function with given fields: ctx, currency
+func (_m *ProductService) List(ctx context.Context, currency string) ([]service.PricedProduct, error) {
+	ret := _m.Called(ctx, currency)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []sqlc.Product
+	var r0 []service.PricedProduct
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]sqlc.Product, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]service.PricedProduct, error)); ok {
+		return rf(ctx, currency)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []sqlc.Product); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []service.PricedProduct); ok {
+		r0 = rf(ctx, currency)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]sqlc.Product)
+			r0 = ret.Get(0).([]service.PricedProduct)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package servermock
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	sqlc "kart/internal/sqlc"
+)
+
+// StoreResolver is an autogenerated mock type for the StoreResolver type
+type StoreResolver struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *StoreResolver) Get(ctx context.Context, id string) (sqlc.Store, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 sqlc.Store
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Store, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Store); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.Store)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStoreResolver creates a new instance of StoreResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStoreResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StoreResolver {
+	mock := &StoreResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
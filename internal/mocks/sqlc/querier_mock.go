@@ -14,27 +14,1439 @@ type Querier struct {
 	mock.Mock
 }
 
-// GetCoupon provides a mock function with given fields: ctx, code
-func (_m *Querier) GetCoupon(ctx context.Context, code string) (sqlc.Coupon, error) {
-	ret := _m.Called(ctx, code)
+// AddCartItem provides a mock function with given fields: ctx, arg
+func (_m *Querier) AddCartItem(ctx context.Context, arg sqlc.AddCartItemParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCartItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AddCartItemParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddOrderItemRefunded provides a mock function with given fields: ctx, arg
+func (_m *Querier) AddOrderItemRefunded(ctx context.Context, arg sqlc.AddOrderItemRefundedParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddOrderItemRefunded")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AddOrderItemRefundedParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AddOrderItemRefundedParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.AddOrderItemRefundedParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddOrderRefunded provides a mock function with given fields: ctx, arg
+func (_m *Querier) AddOrderRefunded(ctx context.Context, arg sqlc.AddOrderRefundedParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddOrderRefunded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.AddOrderRefundedParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimCartCheckout provides a mock function with given fields: ctx, arg
+func (_m *Querier) ClaimCartCheckout(ctx context.Context, arg sqlc.ClaimCartCheckoutParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimCartCheckout")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ClaimCartCheckoutParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ClaimCartCheckoutParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ClaimCartCheckoutParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteCartCheckout provides a mock function with given fields: ctx, arg
+func (_m *Querier) CompleteCartCheckout(ctx context.Context, arg sqlc.CompleteCartCheckoutParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteCartCheckout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.CompleteCartCheckoutParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DebitGiftCard provides a mock function with given fields: ctx, arg
+func (_m *Querier) DebitGiftCard(ctx context.Context, arg sqlc.DebitGiftCardParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebitGiftCard")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitGiftCardParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitGiftCardParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DebitGiftCardParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DebitLoyaltyBalance provides a mock function with given fields: ctx, arg
+func (_m *Querier) DebitLoyaltyBalance(ctx context.Context, arg sqlc.DebitLoyaltyBalanceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DebitLoyaltyBalance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitLoyaltyBalanceParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DebitLoyaltyBalanceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DebitLoyaltyBalanceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCartItem provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteCartItem(ctx context.Context, arg sqlc.DeleteCartItemParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCartItem")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DeleteCartItemParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DeleteCartItemParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DeleteCartItemParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredCarts provides a mock function with given fields: ctx, arg
+func (_m *Querier) DeleteExpiredCarts(ctx context.Context, arg sqlc.DeleteExpiredCartsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredCarts")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DeleteExpiredCartsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.DeleteExpiredCartsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.DeleteExpiredCartsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireAPIKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) ExpireAPIKey(ctx context.Context, arg sqlc.ExpireAPIKeyParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireAPIKey")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ExpireAPIKeyParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ExpireAPIKeyParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ExpireAPIKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeyByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *Querier) GetAPIKeyByPrefix(ctx context.Context, prefix string) (sqlc.ApiKey, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByPrefix")
+	}
+
+	var r0 sqlc.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.ApiKey, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.ApiKey); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(sqlc.ApiKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCart provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetCart(ctx context.Context, arg sqlc.GetCartParams) (sqlc.Cart, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCart")
+	}
+
+	var r0 sqlc.Cart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetCartParams) (sqlc.Cart, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetCartParams) sqlc.Cart); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Cart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetCartParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoupon provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetCoupon(ctx context.Context, arg sqlc.GetCouponParams) (sqlc.Coupon, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoupon")
+	}
+
+	var r0 sqlc.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetCouponParams) (sqlc.Coupon, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetCouponParams) sqlc.Coupon); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Coupon)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetCouponParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDefaultPriceList provides a mock function with given fields: ctx, storeID
+func (_m *Querier) GetDefaultPriceList(ctx context.Context, storeID string) (sqlc.PriceList, error) {
+	ret := _m.Called(ctx, storeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDefaultPriceList")
+	}
+
+	var r0 sqlc.PriceList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.PriceList, error)); ok {
+		return rf(ctx, storeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.PriceList); ok {
+		r0 = rf(ctx, storeID)
+	} else {
+		r0 = ret.Get(0).(sqlc.PriceList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGiftCard provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetGiftCard(ctx context.Context, arg sqlc.GetGiftCardParams) (sqlc.GiftCard, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGiftCard")
+	}
+
+	var r0 sqlc.GiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetGiftCardParams) (sqlc.GiftCard, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetGiftCardParams) sqlc.GiftCard); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.GiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetGiftCardParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGiftCardByCode provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetGiftCardByCode(ctx context.Context, arg sqlc.GetGiftCardByCodeParams) (sqlc.GiftCard, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGiftCardByCode")
+	}
+
+	var r0 sqlc.GiftCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetGiftCardByCodeParams) (sqlc.GiftCard, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetGiftCardByCodeParams) sqlc.GiftCard); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.GiftCard)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetGiftCardByCodeParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoyaltyBalance provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetLoyaltyBalance(ctx context.Context, arg sqlc.GetLoyaltyBalanceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoyaltyBalance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetLoyaltyBalanceParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetLoyaltyBalanceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetLoyaltyBalanceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetOrder(ctx context.Context, arg sqlc.GetOrderParams) (sqlc.Order, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetOrderParams) (sqlc.Order, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetOrderParams) sqlc.Order); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetOrderParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentByOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetPaymentByOrder(ctx context.Context, arg sqlc.GetPaymentByOrderParams) (sqlc.Payment, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentByOrder")
+	}
+
+	var r0 sqlc.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPaymentByOrderParams) (sqlc.Payment, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPaymentByOrderParams) sqlc.Payment); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Payment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetPaymentByOrderParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPriceList provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetPriceList(ctx context.Context, arg sqlc.GetPriceListParams) (sqlc.PriceList, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceList")
+	}
+
+	var r0 sqlc.PriceList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPriceListParams) (sqlc.PriceList, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPriceListParams) sqlc.PriceList); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.PriceList)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetPriceListParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPriceListPrices provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetPriceListPrices(ctx context.Context, arg sqlc.GetPriceListPricesParams) ([]sqlc.PriceListPrice, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceListPrices")
+	}
+
+	var r0 []sqlc.PriceListPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPriceListPricesParams) ([]sqlc.PriceListPrice, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetPriceListPricesParams) []sqlc.PriceListPrice); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.PriceListPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetPriceListPricesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProduct provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetProduct(ctx context.Context, arg sqlc.GetProductParams) (sqlc.Product, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProduct")
+	}
+
+	var r0 sqlc.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductParams) (sqlc.Product, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductParams) sqlc.Product); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetProductParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductBySlug provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetProductBySlug(ctx context.Context, arg sqlc.GetProductBySlugParams) (sqlc.Product, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductBySlug")
+	}
+
+	var r0 sqlc.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductBySlugParams) (sqlc.Product, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductBySlugParams) sqlc.Product); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Product)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetProductBySlugParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProductsByIDs provides a mock function with given fields: ctx, arg
+func (_m *Querier) GetProductsByIDs(ctx context.Context, arg sqlc.GetProductsByIDsParams) ([]sqlc.Product, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductsByIDs")
+	}
+
+	var r0 []sqlc.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductsByIDsParams) ([]sqlc.Product, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.GetProductsByIDsParams) []sqlc.Product); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.GetProductsByIDsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRateLimitTokens provides a mock function with given fields: ctx, key
+func (_m *Querier) GetRateLimitTokens(ctx context.Context, key string) (float64, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRateLimitTokens")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStore provides a mock function with given fields: ctx, id
+func (_m *Querier) GetStore(ctx context.Context, id string) (sqlc.Store, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStore")
+	}
+
+	var r0 sqlc.Store
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Store, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Store); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(sqlc.Store)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertAPIKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertAPIKey(ctx context.Context, arg sqlc.InsertAPIKeyParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertAPIKeyParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertCart provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertCart(ctx context.Context, arg sqlc.InsertCartParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertCart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertCartParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertGiftCard provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertGiftCard(ctx context.Context, arg sqlc.InsertGiftCardParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertGiftCard")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertGiftCardParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertGiftCardTransaction provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertGiftCardTransaction(ctx context.Context, arg sqlc.InsertGiftCardTransactionParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertGiftCardTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertGiftCardTransactionParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertLoyaltyEntry provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertLoyaltyEntry(ctx context.Context, arg sqlc.InsertLoyaltyEntryParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertLoyaltyEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertLoyaltyEntryParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertOrder(ctx context.Context, arg sqlc.InsertOrderParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertOrderItems provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertOrderItems(ctx context.Context, arg sqlc.InsertOrderItemsParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOrderItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderItemsParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertOrderTax provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertOrderTax(ctx context.Context, arg sqlc.InsertOrderTaxParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertOrderTax")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderTaxParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertPayment provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertPayment(ctx context.Context, arg sqlc.InsertPaymentParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPayment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertPaymentParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertRefund provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertRefund(ctx context.Context, arg sqlc.InsertRefundParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRefund")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertRefundParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertRefundItem provides a mock function with given fields: ctx, arg
+func (_m *Querier) InsertRefundItem(ctx context.Context, arg sqlc.InsertRefundItemParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertRefundItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertRefundItemParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAPIKeys provides a mock function with given fields: ctx, storeID
+func (_m *Querier) ListAPIKeys(ctx context.Context, storeID string) ([]sqlc.ApiKey, error) {
+	ret := _m.Called(ctx, storeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []sqlc.ApiKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.ApiKey, error)); ok {
+		return rf(ctx, storeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.ApiKey); ok {
+		r0 = rf(ctx, storeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.ApiKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListActivePromotions provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListActivePromotions(ctx context.Context, arg sqlc.ListActivePromotionsParams) ([]sqlc.Promotion, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActivePromotions")
+	}
+
+	var r0 []sqlc.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListActivePromotionsParams) ([]sqlc.Promotion, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListActivePromotionsParams) []sqlc.Promotion); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListActivePromotionsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCartItems provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListCartItems(ctx context.Context, arg sqlc.ListCartItemsParams) ([]sqlc.CartItem, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCartItems")
+	}
+
+	var r0 []sqlc.CartItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListCartItemsParams) ([]sqlc.CartItem, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListCartItemsParams) []sqlc.CartItem); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.CartItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListCartItemsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCategoryTaxClasses provides a mock function with given fields: ctx, storeID
+func (_m *Querier) ListCategoryTaxClasses(ctx context.Context, storeID string) ([]sqlc.CategoryTaxClass, error) {
+	ret := _m.Called(ctx, storeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCategoryTaxClasses")
+	}
+
+	var r0 []sqlc.CategoryTaxClass
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.CategoryTaxClass, error)); ok {
+		return rf(ctx, storeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.CategoryTaxClass); ok {
+		r0 = rf(ctx, storeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.CategoryTaxClass)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCustomerOrders provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListCustomerOrders(ctx context.Context, arg sqlc.ListCustomerOrdersParams) ([]sqlc.Order, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCustomerOrders")
+	}
+
+	var r0 []sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListCustomerOrdersParams) ([]sqlc.Order, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListCustomerOrdersParams) []sqlc.Order); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListCustomerOrdersParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListGiftCardTransactions provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListGiftCardTransactions(ctx context.Context, arg sqlc.ListGiftCardTransactionsParams) ([]sqlc.GiftCardTransaction, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGiftCardTransactions")
+	}
+
+	var r0 []sqlc.GiftCardTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListGiftCardTransactionsParams) ([]sqlc.GiftCardTransaction, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListGiftCardTransactionsParams) []sqlc.GiftCardTransaction); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.GiftCardTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListGiftCardTransactionsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoyaltyEntries provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListLoyaltyEntries(ctx context.Context, arg sqlc.ListLoyaltyEntriesParams) ([]sqlc.LoyaltyEntry, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoyaltyEntries")
+	}
+
+	var r0 []sqlc.LoyaltyEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListLoyaltyEntriesParams) ([]sqlc.LoyaltyEntry, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListLoyaltyEntriesParams) []sqlc.LoyaltyEntry); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.LoyaltyEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListLoyaltyEntriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoyaltyRules provides a mock function with given fields: ctx, storeID
+func (_m *Querier) ListLoyaltyRules(ctx context.Context, storeID string) ([]sqlc.LoyaltyRule, error) {
+	ret := _m.Called(ctx, storeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoyaltyRules")
+	}
+
+	var r0 []sqlc.LoyaltyRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.LoyaltyRule, error)); ok {
+		return rf(ctx, storeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.LoyaltyRule); ok {
+		r0 = rf(ctx, storeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.LoyaltyRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrderItems provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListOrderItems(ctx context.Context, arg sqlc.ListOrderItemsParams) ([]sqlc.OrderItem, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderItems")
+	}
+
+	var r0 []sqlc.OrderItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderItemsParams) ([]sqlc.OrderItem, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderItemsParams) []sqlc.OrderItem); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.OrderItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListOrderItemsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrderLoyaltyEntries provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListOrderLoyaltyEntries(ctx context.Context, arg sqlc.ListOrderLoyaltyEntriesParams) ([]sqlc.LoyaltyEntry, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderLoyaltyEntries")
+	}
+
+	var r0 []sqlc.LoyaltyEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderLoyaltyEntriesParams) ([]sqlc.LoyaltyEntry, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderLoyaltyEntriesParams) []sqlc.LoyaltyEntry); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.LoyaltyEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListOrderLoyaltyEntriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOrderTaxes provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListOrderTaxes(ctx context.Context, arg sqlc.ListOrderTaxesParams) ([]sqlc.OrderTax, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderTaxes")
+	}
+
+	var r0 []sqlc.OrderTax
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderTaxesParams) ([]sqlc.OrderTax, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListOrderTaxesParams) []sqlc.OrderTax); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.OrderTax)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListOrderTaxesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPriceAdjustments provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListPriceAdjustments(ctx context.Context, arg sqlc.ListPriceAdjustmentsParams) ([]sqlc.ListPriceAdjustmentsRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPriceAdjustments")
+	}
+
+	var r0 []sqlc.ListPriceAdjustmentsRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListPriceAdjustmentsParams) ([]sqlc.ListPriceAdjustmentsRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListPriceAdjustmentsParams) []sqlc.ListPriceAdjustmentsRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.ListPriceAdjustmentsRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListPriceAdjustmentsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPriceListPrices provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListPriceListPrices(ctx context.Context, arg sqlc.ListPriceListPricesParams) ([]sqlc.PriceListPrice, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPriceListPrices")
+	}
+
+	var r0 []sqlc.PriceListPrice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListPriceListPricesParams) ([]sqlc.PriceListPrice, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListPriceListPricesParams) []sqlc.PriceListPrice); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.PriceListPrice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListPriceListPricesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProducts provides a mock function with given fields: ctx, storeID
+func (_m *Querier) ListProducts(ctx context.Context, storeID string) ([]sqlc.Product, error) {
+	ret := _m.Called(ctx, storeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListProducts")
+	}
+
+	var r0 []sqlc.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.Product, error)); ok {
+		return rf(ctx, storeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.Product); ok {
+		r0 = rf(ctx, storeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRefundItems provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListRefundItems(ctx context.Context, arg sqlc.ListRefundItemsParams) ([]sqlc.RefundItem, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRefundItems")
+	}
+
+	var r0 []sqlc.RefundItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundItemsParams) ([]sqlc.RefundItem, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundItemsParams) []sqlc.RefundItem); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.RefundItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListRefundItemsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRefundItemsByOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListRefundItemsByOrder(ctx context.Context, arg sqlc.ListRefundItemsByOrderParams) ([]sqlc.RefundItem, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRefundItemsByOrder")
+	}
+
+	var r0 []sqlc.RefundItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundItemsByOrderParams) ([]sqlc.RefundItem, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundItemsByOrderParams) []sqlc.RefundItem); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.RefundItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListRefundItemsByOrderParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRefundsByOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) ListRefundsByOrder(ctx context.Context, arg sqlc.ListRefundsByOrderParams) ([]sqlc.Refund, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRefundsByOrder")
+	}
+
+	var r0 []sqlc.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundsByOrderParams) ([]sqlc.Refund, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ListRefundsByOrderParams) []sqlc.Refund); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Refund)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.ListRefundsByOrderParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStores provides a mock function with given fields: ctx
+func (_m *Querier) ListStores(ctx context.Context) ([]sqlc.Store, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCoupon")
+		panic("no return value specified for ListStores")
 	}
 
-	var r0 sqlc.Coupon
+	var r0 []sqlc.Store
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Coupon, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context) ([]sqlc.Store, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Coupon); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context) []sqlc.Store); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(sqlc.Coupon)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.Store)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,27 +1454,29 @@ func (_m *Querier) GetCoupon(ctx context.Context, code string) (sqlc.Coupon, err
 	return r0, r1
 }
 
-// GetProduct provides a mock function with given fields: ctx, id
-func (_m *Querier) GetProduct(ctx context.Context, id string) (sqlc.Product, error) {
-	ret := _m.Called(ctx, id)
+// ListTaxRates provides a mock function with given fields: ctx, storeID
+func (_m *Querier) ListTaxRates(ctx context.Context, storeID string) ([]sqlc.TaxRate, error) {
+	ret := _m.Called(ctx, storeID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetProduct")
+		panic("no return value specified for ListTaxRates")
 	}
 
-	var r0 sqlc.Product
+	var r0 []sqlc.TaxRate
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (sqlc.Product, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]sqlc.TaxRate, error)); ok {
+		return rf(ctx, storeID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) sqlc.Product); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []sqlc.TaxRate); ok {
+		r0 = rf(ctx, storeID)
 	} else {
-		r0 = ret.Get(0).(sqlc.Product)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sqlc.TaxRate)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+		r1 = rf(ctx, storeID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,29 +1484,27 @@ func (_m *Querier) GetProduct(ctx context.Context, id string) (sqlc.Product, err
 	return r0, r1
 }
 
-// GetProductsByIDs provides a mock function with given fields: ctx, dollar_1
-func (_m *Querier) GetProductsByIDs(ctx context.Context, dollar_1 []string) ([]sqlc.Product, error) {
-	ret := _m.Called(ctx, dollar_1)
+// LockOrder provides a mock function with given fields: ctx, arg
+func (_m *Querier) LockOrder(ctx context.Context, arg sqlc.LockOrderParams) (sqlc.Order, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetProductsByIDs")
+		panic("no return value specified for LockOrder")
 	}
 
-	var r0 []sqlc.Product
+	var r0 sqlc.Order
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]sqlc.Product, error)); ok {
-		return rf(ctx, dollar_1)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.LockOrderParams) (sqlc.Order, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string) []sqlc.Product); ok {
-		r0 = rf(ctx, dollar_1)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.LockOrderParams) sqlc.Order); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]sqlc.Product)
-		}
+		r0 = ret.Get(0).(sqlc.Order)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
-		r1 = rf(ctx, dollar_1)
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.LockOrderParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,16 +1512,72 @@ func (_m *Querier) GetProductsByIDs(ctx context.Context, dollar_1 []string) ([]s
 	return r0, r1
 }
 
-// InsertOrder provides a mock function with given fields: ctx, arg
-func (_m *Querier) InsertOrder(ctx context.Context, arg sqlc.InsertOrderParams) error {
+// PostLoyaltyEntry provides a mock function with given fields: ctx, arg
+func (_m *Querier) PostLoyaltyEntry(ctx context.Context, arg sqlc.PostLoyaltyEntryParams) (int64, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertOrder")
+		panic("no return value specified for PostLoyaltyEntry")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.PostLoyaltyEntryParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.PostLoyaltyEntryParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.PostLoyaltyEntryParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeFullRateLimitBuckets provides a mock function with given fields: ctx
+func (_m *Querier) PurgeFullRateLimitBuckets(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeFullRateLimitBuckets")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseCartCheckout provides a mock function with given fields: ctx, arg
+func (_m *Querier) ReleaseCartCheckout(ctx context.Context, arg sqlc.ReleaseCartCheckoutParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseCartCheckout")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderParams) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ReleaseCartCheckoutParams) error); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Error(0)
@@ -118,16 +1586,16 @@ func (_m *Querier) InsertOrder(ctx context.Context, arg sqlc.InsertOrderParams)
 	return r0
 }
 
-// InsertOrderItem provides a mock function with given fields: ctx, arg
-func (_m *Querier) InsertOrderItem(ctx context.Context, arg sqlc.InsertOrderItemParams) error {
+// ReleaseRedemption provides a mock function with given fields: ctx, arg
+func (_m *Querier) ReleaseRedemption(ctx context.Context, arg sqlc.ReleaseRedemptionParams) error {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertOrderItem")
+		panic("no return value specified for ReleaseRedemption")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderItemParams) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.ReleaseRedemptionParams) error); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Error(0)
@@ -136,16 +1604,72 @@ func (_m *Querier) InsertOrderItem(ctx context.Context, arg sqlc.InsertOrderItem
 	return r0
 }
 
-// InsertOrderItems provides a mock function with given fields: ctx, arg
-func (_m *Querier) InsertOrderItems(ctx context.Context, arg sqlc.InsertOrderItemsParams) error {
+// RestoreOrderGiftCards provides a mock function with given fields: ctx, arg
+func (_m *Querier) RestoreOrderGiftCards(ctx context.Context, arg sqlc.RestoreOrderGiftCardsParams) (int64, error) {
 	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertOrderItems")
+		panic("no return value specified for RestoreOrderGiftCards")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.RestoreOrderGiftCardsParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.RestoreOrderGiftCardsParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.RestoreOrderGiftCardsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) RevokeAPIKey(ctx context.Context, arg sqlc.RevokeAPIKeyParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.RevokeAPIKeyParams) (int64, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.RevokeAPIKeyParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.RevokeAPIKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCartCoupon provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetCartCoupon(ctx context.Context, arg sqlc.SetCartCouponParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCartCoupon")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, sqlc.InsertOrderItemsParams) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SetCartCouponParams) error); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Error(0)
@@ -154,29 +1678,45 @@ func (_m *Querier) InsertOrderItems(ctx context.Context, arg sqlc.InsertOrderIte
 	return r0
 }
 
-// ListAllProducts provides a mock function with given fields: ctx
-func (_m *Querier) ListAllProducts(ctx context.Context) ([]sqlc.Product, error) {
-	ret := _m.Called(ctx)
+// SetCartItem provides a mock function with given fields: ctx, arg
+func (_m *Querier) SetCartItem(ctx context.Context, arg sqlc.SetCartItemParams) error {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAllProducts")
+		panic("no return value specified for SetCartItem")
 	}
 
-	var r0 []sqlc.Product
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SetCartItemParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SumSucceededRefunds provides a mock function with given fields: ctx, arg
+func (_m *Querier) SumSucceededRefunds(ctx context.Context, arg sqlc.SumSucceededRefundsParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumSucceededRefunds")
+	}
+
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]sqlc.Product, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SumSucceededRefundsParams) (int64, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []sqlc.Product); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.SumSucceededRefundsParams) int64); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]sqlc.Product)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.SumSucceededRefundsParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -184,29 +1724,27 @@ func (_m *Querier) ListAllProducts(ctx context.Context) ([]sqlc.Product, error)
 	return r0, r1
 }
 
-// ListProducts provides a mock function with given fields: ctx
-func (_m *Querier) ListProducts(ctx context.Context) ([]sqlc.Product, error) {
-	ret := _m.Called(ctx)
+// TakeRateLimitToken provides a mock function with given fields: ctx, arg
+func (_m *Querier) TakeRateLimitToken(ctx context.Context, arg sqlc.TakeRateLimitTokenParams) (float64, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListProducts")
+		panic("no return value specified for TakeRateLimitToken")
 	}
 
-	var r0 []sqlc.Product
+	var r0 float64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]sqlc.Product, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TakeRateLimitTokenParams) (float64, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []sqlc.Product); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TakeRateLimitTokenParams) float64); ok {
+		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]sqlc.Product)
-		}
+		r0 = ret.Get(0).(float64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.TakeRateLimitTokenParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -214,9 +1752,45 @@ func (_m *Querier) ListProducts(ctx context.Context) ([]sqlc.Product, error) {
 	return r0, r1
 }
 
-// TryRedeemSingleUse provides a mock function with given fields: ctx, code
-func (_m *Querier) TryRedeemSingleUse(ctx context.Context, code string) (string, error) {
-	ret := _m.Called(ctx, code)
+// TouchAPIKey provides a mock function with given fields: ctx, arg
+func (_m *Querier) TouchAPIKey(ctx context.Context, arg sqlc.TouchAPIKeyParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TouchAPIKeyParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchCart provides a mock function with given fields: ctx, arg
+func (_m *Querier) TouchCart(ctx context.Context, arg sqlc.TouchCartParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchCart")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TouchCartParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TryRedeemSingleUse provides a mock function with given fields: ctx, arg
+func (_m *Querier) TryRedeemSingleUse(ctx context.Context, arg sqlc.TryRedeemSingleUseParams) (string, error) {
+	ret := _m.Called(ctx, arg)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TryRedeemSingleUse")
@@ -224,17 +1798,73 @@ func (_m *Querier) TryRedeemSingleUse(ctx context.Context, code string) (string,
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TryRedeemSingleUseParams) (string, error)); ok {
+		return rf(ctx, arg)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, code)
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.TryRedeemSingleUseParams) string); ok {
+		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, code)
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.TryRedeemSingleUseParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateOrderStatus(ctx context.Context, arg sqlc.UpdateOrderStatusParams) (sqlc.Order, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrderStatus")
+	}
+
+	var r0 sqlc.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdateOrderStatusParams) (sqlc.Order, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdateOrderStatusParams) sqlc.Order); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.UpdateOrderStatusParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePayment provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdatePayment(ctx context.Context, arg sqlc.UpdatePaymentParams) (sqlc.Payment, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePayment")
+	}
+
+	var r0 sqlc.Payment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdatePaymentParams) (sqlc.Payment, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdatePaymentParams) sqlc.Payment); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Payment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.UpdatePaymentParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -242,6 +1872,52 @@ func (_m *Querier) TryRedeemSingleUse(ctx context.Context, code string) (string,
 	return r0, r1
 }
 
+// UpdateRefund provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpdateRefund(ctx context.Context, arg sqlc.UpdateRefundParams) (sqlc.Refund, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRefund")
+	}
+
+	var r0 sqlc.Refund
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdateRefundParams) (sqlc.Refund, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpdateRefundParams) sqlc.Refund); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(sqlc.Refund)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sqlc.UpdateRefundParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertCustomer provides a mock function with given fields: ctx, arg
+func (_m *Querier) UpsertCustomer(ctx context.Context, arg sqlc.UpsertCustomerParams) error {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertCustomer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, sqlc.UpsertCustomerParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewQuerier creates a new instance of Querier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewQuerier(t interface {
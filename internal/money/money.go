@@ -0,0 +1,66 @@
+// Package money represents amounts exactly, as an integer count of a
+// currency's minor units, and formats them as decimal strings.
+package money
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in the minor units of an ISO 4217 currency (cents for
+// AUD). It is never converted through floating point except by Float64.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// exponents lists currencies whose minor unit is not a hundredth.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Exponent returns the number of decimal places in currency's minor unit.
+func Exponent(currency string) int {
+	if e, ok := exponents[strings.ToUpper(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// String formats m as a decimal with the currency's number of places, such
+// as "12.99" for 1299 AUD or "-0.05" for -5 AUD. The currency code is not
+// included.
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	digits := strconv.FormatUint(abs(m.Amount), 10)
+	if exp > 0 {
+		if len(digits) <= exp {
+			digits = strings.Repeat("0", exp-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+	}
+	if m.Amount < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// Float64 approximates m in major units. It exists for clients of the legacy
+// float price field and must not be used for arithmetic.
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.String(), 64)
+	return f
+}
+
+func abs(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
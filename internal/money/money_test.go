@@ -0,0 +1,36 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_String(t *testing.T) {
+	type tc struct {
+		name string
+		m    Money
+		want string
+	}
+	cases := []tc{
+		{name: "cents", m: New(1299, "AUD"), want: "12.99"},
+		{name: "padded fraction", m: New(5, "AUD"), want: "0.05"},
+		{name: "zero", m: New(0, "USD"), want: "0.00"},
+		{name: "negative", m: New(-1205, "AUD"), want: "-12.05"},
+		{name: "no minor unit", m: New(1500, "JPY"), want: "1500"},
+		{name: "three places", m: New(1250, "KWD"), want: "1.250"},
+		{name: "lower case currency", m: New(100, "jpy"), want: "100"},
+		{name: "min int64", m: New(math.MinInt64, "AUD"), want: "-92233720368547758.08"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, c.m.String())
+		})
+	}
+}
+
+func TestMoney_Float64(t *testing.T) {
+	require.Equal(t, 12.99, New(1299, "AUD").Float64())
+	require.Equal(t, 1500.0, New(1500, "JPY").Float64())
+}
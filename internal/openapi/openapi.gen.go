@@ -5,114 +5,1666 @@ package openapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 const (
-	Api_keyScopes = "api_key.Scopes"
+	Api_keyScopes    = "api_key.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
 )
 
+// Defines values for GiftCardTransactionKind.
+const (
+	GiftCardTransactionKindIssue   GiftCardTransactionKind = "issue"
+	GiftCardTransactionKindRedeem  GiftCardTransactionKind = "redeem"
+	GiftCardTransactionKindRestore GiftCardTransactionKind = "restore"
+)
+
+// Defines values for LoyaltyEntryKind.
+const (
+	LoyaltyEntryKindEarn    LoyaltyEntryKind = "earn"
+	LoyaltyEntryKindRedeem  LoyaltyEntryKind = "redeem"
+	LoyaltyEntryKindRestore LoyaltyEntryKind = "restore"
+	LoyaltyEntryKindReverse LoyaltyEntryKind = "reverse"
+)
+
+// Defines values for OrderStatus.
+const (
+	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusCompleted      OrderStatus = "completed"
+	OrderStatusPaymentFailed  OrderStatus = "payment_failed"
+	OrderStatusPendingPayment OrderStatus = "pending_payment"
+	OrderStatusPlaced         OrderStatus = "placed"
+	OrderStatusPreparing      OrderStatus = "preparing"
+	OrderStatusReady          OrderStatus = "ready"
+	OrderStatusRefunded       OrderStatus = "refunded"
+)
+
+// Defines values for PaymentStatus.
+const (
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusDeclined   PaymentStatus = "declined"
+	PaymentStatusFailed     PaymentStatus = "failed"
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusVoided     PaymentStatus = "voided"
+)
+
+// Defines values for RefundStatus.
+const (
+	Failed    RefundStatus = "failed"
+	Pending   RefundStatus = "pending"
+	Succeeded RefundStatus = "succeeded"
+)
+
+// AppliedPromotion An automatic promotion and what it took off the order
+type AppliedPromotion struct {
+	DiscountCents int64  `json:"discountCents"`
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+
+	// Times How many times the promotion applied
+	Times int `json:"times"`
+}
+
+// Cart defines model for Cart.
+type Cart struct {
+	// Coupon Whether the attached coupon would be accepted, and what it would take off at checkout.
+	Coupon *CouponPreview `json:"coupon,omitempty"`
+
+	// Currency ISO 4217 currency code
+	Currency      Currency   `json:"currency"`
+	DiscountCents int64      `json:"discountCents"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	Id            string     `json:"id"`
+	Items         []CartLine `json:"items"`
+
+	// OrderId Set once the cart has been checked out
+	OrderId *string `json:"orderId,omitempty"`
+
+	// Promotions Automatic promotions included in discountCents
+	Promotions    *[]AppliedPromotion `json:"promotions,omitempty"`
+	SubtotalCents int64               `json:"subtotalCents"`
+
+	// TaxCents Tax on the cart; already part of totalCents when taxInclusive
+	TaxCents     int64 `json:"taxCents"`
+	TaxInclusive bool  `json:"taxInclusive"`
+
+	// Total Exact amount in major units, with the currency's number of decimal places
+	Total      DecimalAmount `json:"total"`
+	TotalCents int64         `json:"totalCents"`
+}
+
+// CartCouponReq defines model for CartCouponReq.
+type CartCouponReq struct {
+	CouponCode string `json:"couponCode"`
+}
+
+// CartItemReq defines model for CartItemReq.
+type CartItemReq struct {
+	ProductId string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CartLine defines model for CartLine.
+type CartLine struct {
+	LineTotalCents int64   `json:"lineTotalCents"`
+	Product        Product `json:"product"`
+	Quantity       int     `json:"quantity"`
+}
+
+// CartQuantityReq defines model for CartQuantityReq.
+type CartQuantityReq struct {
+	Quantity int `json:"quantity"`
+}
+
+// CheckoutReq defines model for CheckoutReq.
+type CheckoutReq struct {
+	// Customer How to reach the customer about the order. Guests give these in place of
+	// an account; for signed-in customers they update the customer record.
+	Customer *Contact `json:"customer,omitempty"`
+
+	// GiftCards Gift card codes to pay with. Cards are drawn on in the order given for
+	// whatever loyalty points leave to pay; the payment method is charged the rest.
+	GiftCards *GiftCardCodes `json:"giftCards,omitempty"`
+
+	// PaymentToken Payment method token from the payment provider's client SDK
+	PaymentToken *string `json:"paymentToken,omitempty"`
+
+	// RedeemPoints Loyalty points to spend on the order; signed-in customers only
+	RedeemPoints *int64 `json:"redeemPoints,omitempty"`
+}
+
+// Contact How to reach the customer about the order. Guests give these in place of
+// an account; for signed-in customers they update the customer record.
+type Contact struct {
+	Email *openapi_types.Email `json:"email,omitempty"`
+
+	// Phone E.164 phone number
+	Phone *string `json:"phone,omitempty"`
+}
+
+// CouponPreview Whether the attached coupon would be accepted, and what it would take off at checkout.
+type CouponPreview struct {
+	Code string `json:"code"`
+
+	// DiscountCents What the coupon takes off the cart after automatic promotions, included in the cart's discountCents; 0 when invalid
+	DiscountCents int64 `json:"discountCents"`
+
+	// Reason Why the coupon would be rejected
+	Reason *string `json:"reason,omitempty"`
+
+	// Valid Whether checkout would currently accept the coupon
+	Valid bool `json:"valid"`
+}
+
+// Currency ISO 4217 currency code
+type Currency = string
+
+// DecimalAmount Exact amount in major units, with the currency's number of decimal places
+type DecimalAmount = string
+
+// GiftCard defines model for GiftCard.
+type GiftCard struct {
+	BalanceCents int64 `json:"balanceCents"`
+
+	// Code The card's code; only returned when the card is issued
+	Code      *string   `json:"code,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Currency ISO 4217 currency code
+	Currency     Currency   `json:"currency"`
+	Disabled     *bool      `json:"disabled,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Id           string     `json:"id"`
+	InitialCents int64      `json:"initialCents"`
+	Last4        string     `json:"last4"`
+}
+
+// GiftCardCodeReq defines model for GiftCardCodeReq.
+type GiftCardCodeReq struct {
+	Code string `json:"code"`
+}
+
+// GiftCardCodes Gift card codes to pay with. Cards are drawn on in the order given for
+// whatever loyalty points leave to pay; the payment method is charged the rest.
+type GiftCardCodes = []string
+
+// GiftCardTransaction defines model for GiftCardTransaction.
+type GiftCardTransaction struct {
+	// AmountCents Amount added to (positive) or taken from (negative) the balance
+	AmountCents int64                   `json:"amountCents"`
+	CreatedAt   time.Time               `json:"createdAt"`
+	Id          string                  `json:"id"`
+	Kind        GiftCardTransactionKind `json:"kind"`
+	OrderId     *string                 `json:"orderId,omitempty"`
+}
+
+// GiftCardTransactionKind defines model for GiftCardTransaction.Kind.
+type GiftCardTransactionKind string
+
+// GiftCardUse defines model for GiftCardUse.
+type GiftCardUse struct {
+	// AmountCents What the card paid towards the order
+	AmountCents int64  `json:"amountCents"`
+	Last4       string `json:"last4"`
+}
+
+// IssueGiftCardReq defines model for IssueGiftCardReq.
+type IssueGiftCardReq struct {
+	AmountCents int64 `json:"amountCents"`
+
+	// Currency ISO 4217 currency code
+	Currency  *Currency  `json:"currency,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// LegacyPrice Amount in major units as a binary float; may not round-trip exactly
+type LegacyPrice = float64
+
+// LoyaltyAccount defines model for LoyaltyAccount.
+type LoyaltyAccount struct {
+	// Balance Points available to redeem; negative if refunds took back points already spent
+	Balance int64          `json:"balance"`
+	Entries []LoyaltyEntry `json:"entries"`
+}
+
+// LoyaltyEntry defines model for LoyaltyEntry.
+type LoyaltyEntry struct {
+	CreatedAt time.Time        `json:"createdAt"`
+	Kind      LoyaltyEntryKind `json:"kind"`
+	OrderId   *string          `json:"orderId,omitempty"`
+
+	// Points Points added to (positive) or taken from (negative) the balance
+	Points int64 `json:"points"`
+}
+
+// LoyaltyEntryKind defines model for LoyaltyEntry.Kind.
+type LoyaltyEntryKind string
+
 // Order defines model for Order.
 type Order struct {
-	Id       *string      `json:"id,omitempty"`
-	Items    *[]OrderItem `json:"items,omitempty"`
-	Products *[]Product   `json:"products,omitempty"`
+	// Contact How to reach the customer about the order. Guests give these in place of
+	// an account; for signed-in customers they update the customer record.
+	Contact   *Contact   `json:"contact,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+
+	// Currency ISO 4217 currency code
+	Currency *Currency `json:"currency,omitempty"`
+
+	// CustomerId The signed-in customer the order was placed by; absent for guest orders
+	CustomerId *string `json:"customerId,omitempty"`
+
+	// DiscountCents Promotions and other discounts taken off subtotalCents
+	DiscountCents *int64 `json:"discountCents,omitempty"`
+
+	// GiftCardCents What gift cards paid of totalCents
+	GiftCardCents *int64 `json:"giftCardCents,omitempty"`
+
+	// GiftCards The gift cards the order drew on
+	GiftCards *[]GiftCardUse `json:"giftCards,omitempty"`
+	Id        *string        `json:"id,omitempty"`
+	Items     *[]OrderItem   `json:"items,omitempty"`
+	Payment   *Payment       `json:"payment,omitempty"`
+
+	// PointsCents What the redeemed points paid of totalCents; the rest is charged to the payment method
+	PointsCents *int64 `json:"pointsCents,omitempty"`
+
+	// PointsRedeemed Loyalty points spent on the order
+	PointsRedeemed *int64     `json:"pointsRedeemed,omitempty"`
+	Products       *[]Product `json:"products,omitempty"`
+
+	// Promotions Automatic promotions applied when the order was placed
+	Promotions *[]AppliedPromotion `json:"promotions,omitempty"`
+
+	// RefundedCents Amount refunded so far, including refunds still being processed
+	RefundedCents *int64       `json:"refundedCents,omitempty"`
+	Refunds       *[]Refund    `json:"refunds,omitempty"`
+	Status        *OrderStatus `json:"status,omitempty"`
+
+	// StatusToken Short-lived token authorizing a subscription to /order/{orderId}/events
+	StatusToken          *string    `json:"statusToken,omitempty"`
+	StatusTokenExpiresAt *time.Time `json:"statusTokenExpiresAt,omitempty"`
+	SubtotalCents        *int64     `json:"subtotalCents,omitempty"`
+
+	// TaxCents Tax on the order; already part of totalCents when taxInclusive
+	TaxCents *int64 `json:"taxCents,omitempty"`
+
+	// TaxInclusive Whether prices include tax (GST style) or tax is added on top
+	TaxInclusive *bool `json:"taxInclusive,omitempty"`
+
+	// Taxes Tax per tax class
+	Taxes *[]TaxLine `json:"taxes,omitempty"`
+
+	// Total Exact amount in major units, with the currency's number of decimal places
+	Total      *DecimalAmount `json:"total,omitempty"`
+	TotalCents *int64         `json:"totalCents,omitempty"`
+}
+
+// OrderItem defines model for OrderItem.
+type OrderItem struct {
+	// DiscountCents What promotions and other discounts took off the line
+	DiscountCents *int64 `json:"discountCents,omitempty"`
+
+	// ProductId ID of the product
+	ProductId *string `json:"productId,omitempty"`
+
+	// Quantity Item count
+	Quantity *int `json:"quantity,omitempty"`
+
+	// RefundedQuantity Units refunded so far
+	RefundedQuantity *int `json:"refundedQuantity,omitempty"`
+
+	// TaxCents Tax on the line after its discount
+	TaxCents *int64  `json:"taxCents,omitempty"`
+	TaxClass *string `json:"taxClass,omitempty"`
+
+	// UnitPriceCents Price paid per unit
+	UnitPriceCents *int64 `json:"unitPriceCents,omitempty"`
+}
+
+// OrderReq Place a new order
+type OrderReq struct {
+	// CouponCode Optional promo code applied to the order
+	CouponCode *string `json:"couponCode,omitempty"`
+
+	// Customer How to reach the customer about the order. Guests give these in place of
+	// an account; for signed-in customers they update the customer record.
+	Customer *Contact `json:"customer,omitempty"`
+
+	// GiftCards Gift card codes to pay with. Cards are drawn on in the order given for
+	// whatever loyalty points leave to pay; the payment method is charged the rest.
+	GiftCards *GiftCardCodes `json:"giftCards,omitempty"`
+	Items     []struct {
+		// ProductId ID of the product (required)
+		ProductId string `json:"productId"`
+
+		// Quantity Item count (required)
+		Quantity int `json:"quantity"`
+	} `json:"items"`
+
+	// PaymentToken Payment method token from the payment provider's client SDK; required when payments are enabled
+	PaymentToken *string `json:"paymentToken,omitempty"`
+
+	// RedeemPoints Loyalty points to spend on the order; signed-in customers only
+	RedeemPoints *int64 `json:"redeemPoints,omitempty"`
+}
+
+// OrderStatus defines model for OrderStatus.
+type OrderStatus string
+
+// OrderStatusEvent defines model for OrderStatusEvent.
+type OrderStatusEvent struct {
+	At      time.Time   `json:"at"`
+	Eta     *time.Time  `json:"eta,omitempty"`
+	OrderId string      `json:"orderId"`
+	Status  OrderStatus `json:"status"`
+}
+
+// OrderStatusUpdate defines model for OrderStatusUpdate.
+type OrderStatusUpdate struct {
+	// Eta Estimated time the order will be ready
+	Eta    *time.Time  `json:"eta,omitempty"`
+	Status OrderStatus `json:"status"`
+}
+
+// Payment defines model for Payment.
+type Payment struct {
+	AmountCents int64   `json:"amountCents"`
+	Currency    string  `json:"currency"`
+	DeclineCode *string `json:"declineCode,omitempty"`
+	Id          string  `json:"id"`
+
+	// NextActionUrl Where the customer completes a 3-D Secure challenge before the payment is confirmed
+	NextActionUrl *string       `json:"nextActionUrl,omitempty"`
+	Status        PaymentStatus `json:"status"`
+}
+
+// PaymentStatus defines model for Payment.Status.
+type PaymentStatus string
+
+// PriceAdjustment Scheduled price adjustment, such as a happy hour, running in the store's
+// time zone. Orders placed while it runs are priced with it.
+type PriceAdjustment struct {
+	// EndsAt When the current window closes
+	EndsAt     time.Time `json:"endsAt"`
+	Name       string    `json:"name"`
+	PercentOff int32     `json:"percentOff"`
+}
+
+// Problem An RFC 9457 problem details object, sent as application/problem+json
+// for every error. Clients should branch on `code` (or `type`, which
+// embeds it); `title` and `detail` are for people.
+type Problem struct {
+	// Code Stable machine-readable error code
+	Code string `json:"code"`
+
+	// Detail Explanation specific to this occurrence
+	Detail *string `json:"detail,omitempty"`
+
+	// Errors The individual problems with the request, for invalid requests
+	Errors *[]ProblemField `json:"errors,omitempty"`
+
+	// Status HTTP status code
+	Status int `json:"status"`
+
+	// Title Short summary of the problem type
+	Title string `json:"title"`
+
+	// Type URI identifying the problem type
+	Type string `json:"type"`
+}
+
+// ProblemField defines model for ProblemField.
+type ProblemField struct {
+	Detail string `json:"detail"`
+
+	// Parameter The request parameter at fault
+	Parameter *string `json:"parameter,omitempty"`
+
+	// Pointer JSON Pointer into the request body
+	Pointer *string `json:"pointer,omitempty"`
+}
+
+// Product defines model for Product.
+type Product struct {
+	Category *string `json:"category,omitempty"`
+
+	// Currency ISO 4217 currency code
+	Currency *Currency `json:"currency,omitempty"`
+	Id       *string   `json:"id,omitempty"`
+	Name     *string   `json:"name,omitempty"`
+
+	// Price Selling price in major units. A decimal string by default; servers
+	// running with LEGACY_FLOAT_PRICES send the deprecated JSON number
+	// instead until clients have migrated to the string or priceCents.
+	Price *Product_Price `json:"price,omitempty"`
+
+	// PriceAdjustment Scheduled price adjustment, such as a happy hour, running in the store's
+	// time zone. Orders placed while it runs are priced with it.
+	PriceAdjustment *PriceAdjustment `json:"priceAdjustment,omitempty"`
+
+	// PriceCents Selling price in minor units of currency
+	PriceCents *int64 `json:"priceCents,omitempty"`
+
+	// RegularPrice Exact amount in major units, with the currency's number of decimal places
+	RegularPrice *DecimalAmount `json:"regularPrice,omitempty"`
+
+	// RegularPriceCents Price before the scheduled adjustment in priceAdjustment, in minor
+	// units of currency. Only present while an adjustment is running.
+	RegularPriceCents *int64 `json:"regularPriceCents,omitempty"`
+
+	// Slug Lower-case words joined by hyphens
+	Slug *ProductSlug `json:"slug,omitempty"`
+}
+
+// Product_Price Selling price in major units. A decimal string by default; servers
+// running with LEGACY_FLOAT_PRICES send the deprecated JSON number
+// instead until clients have migrated to the string or priceCents.
+type Product_Price struct {
+	union json.RawMessage
+}
+
+// ProductId Product ID; letters, digits, `-` and `_`
+type ProductId = string
+
+// ProductSlug Lower-case words joined by hyphens
+type ProductSlug = string
+
+// Refund defines model for Refund.
+type Refund struct {
+	AmountCents int64        `json:"amountCents"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	Id          string       `json:"id"`
+	Lines       []RefundLine `json:"lines"`
+
+	// Operator Credential that issued the refund, such as api_key:<prefix>
+	Operator string       `json:"operator"`
+	Reason   string       `json:"reason"`
+	Status   RefundStatus `json:"status"`
+}
+
+// RefundStatus defines model for Refund.Status.
+type RefundStatus string
+
+// RefundLine defines model for RefundLine.
+type RefundLine struct {
+	AmountCents int64  `json:"amountCents"`
+	ProductId   string `json:"productId"`
+	Quantity    int    `json:"quantity"`
+}
+
+// RefundReq Either lines or amountCents must be given, not both
+type RefundReq struct {
+	AmountCents *int64 `json:"amountCents,omitempty"`
+	Lines       *[]struct {
+		ProductId string `json:"productId"`
+		Quantity  int    `json:"quantity"`
+	} `json:"lines,omitempty"`
+
+	// Operator Ignored. Refunds record the credential that issued them as the operator.
+	// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
+	Operator *string `json:"operator,omitempty"`
+	Reason   string  `json:"reason"`
+}
+
+// TaxLine defines model for TaxLine.
+type TaxLine struct {
+	// RateBasisPoints Rate in hundredths of a percent (1000 = 10%)
+	RateBasisPoints int    `json:"rateBasisPoints"`
+	TaxCents        int64  `json:"taxCents"`
+	TaxClass        string `json:"taxClass"`
+	TaxableCents    int64  `json:"taxableCents"`
+}
+
+// AcceptCurrency defines model for AcceptCurrency.
+type AcceptCurrency = string
+
+// CartId defines model for CartId.
+type CartId = string
+
+// CartProductId Product ID; letters, digits, `-` and `_`
+type CartProductId = ProductId
+
+// CurrencyQuery ISO 4217 currency code
+type CurrencyQuery = Currency
+
+// TooManyRequests An RFC 9457 problem details object, sent as application/problem+json
+// for every error. Clients should branch on `code` (or `type`, which
+// embeds it); `title` and `detail` are for people.
+type TooManyRequests = Problem
+
+// CreateCartParams defines parameters for CreateCart.
+type CreateCartParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// AddCartItemParams defines parameters for AddCartItem.
+type AddCartItemParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// SetCartItemParams defines parameters for SetCartItem.
+type SetCartItemParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// GetMyLoyaltyParams defines parameters for GetMyLoyalty.
+type GetMyLoyaltyParams struct {
+	// Limit Maximum number of ledger entries to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListMyOrdersParams defines parameters for ListMyOrders.
+type ListMyOrdersParams struct {
+	// Limit Maximum number of orders to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PlaceOrderParams defines parameters for PlaceOrder.
+type PlaceOrderParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// SubscribeOrderStatusParams defines parameters for SubscribeOrderStatus.
+type SubscribeOrderStatusParams struct {
+	// Token Status token returned when the order was placed
+	Token string `form:"token" json:"token"`
+}
+
+// ListProductsParams defines parameters for ListProducts.
+type ListProductsParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// GetProductBySlugParams defines parameters for GetProductBySlug.
+type GetProductBySlugParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
 }
 
-// OrderItem defines model for OrderItem.
-type OrderItem struct {
-	// ProductId ID of the product
-	ProductId *string `json:"productId,omitempty"`
+// GetProductParams defines parameters for GetProduct.
+type GetProductParams struct {
+	// Currency Currency to price in, selecting the store's price list for it. Takes
+	// precedence over Accept-Currency; the store's default price list is used
+	// when neither is given.
+	Currency *CurrencyQuery `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// AcceptCurrency Preferred currencies, most preferred first (e.g. "NZD, AUD;q=0.5"). Only the first is used.
+	AcceptCurrency *AcceptCurrency `json:"Accept-Currency,omitempty"`
+}
+
+// CheckoutCartJSONRequestBody defines body for CheckoutCart for application/json ContentType.
+type CheckoutCartJSONRequestBody = CheckoutReq
+
+// ApplyCartCouponJSONRequestBody defines body for ApplyCartCoupon for application/json ContentType.
+type ApplyCartCouponJSONRequestBody = CartCouponReq
+
+// AddCartItemJSONRequestBody defines body for AddCartItem for application/json ContentType.
+type AddCartItemJSONRequestBody = CartItemReq
+
+// SetCartItemJSONRequestBody defines body for SetCartItem for application/json ContentType.
+type SetCartItemJSONRequestBody = CartQuantityReq
+
+// IssueGiftCardJSONRequestBody defines body for IssueGiftCard for application/json ContentType.
+type IssueGiftCardJSONRequestBody = IssueGiftCardReq
+
+// CheckGiftCardBalanceJSONRequestBody defines body for CheckGiftCardBalance for application/json ContentType.
+type CheckGiftCardBalanceJSONRequestBody = GiftCardCodeReq
+
+// PlaceOrderJSONRequestBody defines body for PlaceOrder for application/json ContentType.
+type PlaceOrderJSONRequestBody = OrderReq
+
+// RefundOrderJSONRequestBody defines body for RefundOrder for application/json ContentType.
+type RefundOrderJSONRequestBody = RefundReq
+
+// UpdateOrderStatusJSONRequestBody defines body for UpdateOrderStatus for application/json ContentType.
+type UpdateOrderStatusJSONRequestBody = OrderStatusUpdate
+
+// AsDecimalAmount returns the union data inside the Product_Price as a DecimalAmount
+func (t Product_Price) AsDecimalAmount() (DecimalAmount, error) {
+	var body DecimalAmount
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromDecimalAmount overwrites any union data inside the Product_Price as the provided DecimalAmount
+func (t *Product_Price) FromDecimalAmount(v DecimalAmount) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeDecimalAmount performs a merge with any union data inside the Product_Price, using the provided DecimalAmount
+func (t *Product_Price) MergeDecimalAmount(v DecimalAmount) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsLegacyPrice returns the union data inside the Product_Price as a LegacyPrice
+func (t Product_Price) AsLegacyPrice() (LegacyPrice, error) {
+	var body LegacyPrice
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromLegacyPrice overwrites any union data inside the Product_Price as the provided LegacyPrice
+func (t *Product_Price) FromLegacyPrice(v LegacyPrice) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeLegacyPrice performs a merge with any union data inside the Product_Price, using the provided LegacyPrice
+func (t *Product_Price) MergeLegacyPrice(v LegacyPrice) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t Product_Price) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *Product_Price) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Create a cart
+	// (POST /cart)
+	CreateCart(w http.ResponseWriter, r *http.Request, params CreateCartParams)
+	// Get a cart
+	// (GET /cart/{cartId})
+	GetCart(w http.ResponseWriter, r *http.Request, cartId CartId)
+	// Check out a cart
+	// (POST /cart/{cartId}/checkout)
+	CheckoutCart(w http.ResponseWriter, r *http.Request, cartId CartId)
+	// Detach the coupon from a cart
+	// (DELETE /cart/{cartId}/coupon)
+	RemoveCartCoupon(w http.ResponseWriter, r *http.Request, cartId CartId)
+	// Attach a coupon to a cart
+	// (PUT /cart/{cartId}/coupon)
+	ApplyCartCoupon(w http.ResponseWriter, r *http.Request, cartId CartId)
+	// Add an item to a cart
+	// (POST /cart/{cartId}/items)
+	AddCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, params AddCartItemParams)
+	// Remove an item from a cart
+	// (DELETE /cart/{cartId}/items/{productId})
+	RemoveCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, productId CartProductId)
+	// Set the quantity of a cart item
+	// (PUT /cart/{cartId}/items/{productId})
+	SetCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, productId CartProductId, params SetCartItemParams)
+	// Issue a gift card
+	// (POST /gift-cards)
+	IssueGiftCard(w http.ResponseWriter, r *http.Request)
+	// Check a gift card balance
+	// (POST /gift-cards/balance)
+	CheckGiftCardBalance(w http.ResponseWriter, r *http.Request)
+	// List gift card transactions
+	// (GET /gift-cards/{giftCardId}/transactions)
+	ListGiftCardTransactions(w http.ResponseWriter, r *http.Request, giftCardId string)
+	// Get my loyalty points
+	// (GET /me/loyalty)
+	GetMyLoyalty(w http.ResponseWriter, r *http.Request, params GetMyLoyaltyParams)
+	// List my orders
+	// (GET /me/orders)
+	ListMyOrders(w http.ResponseWriter, r *http.Request, params ListMyOrdersParams)
+	// Place an order
+	// (POST /order)
+	PlaceOrder(w http.ResponseWriter, r *http.Request, params PlaceOrderParams)
+	// Get an order
+	// (GET /order/{orderId})
+	GetOrder(w http.ResponseWriter, r *http.Request, orderId string)
+	// Subscribe to order status
+	// (GET /order/{orderId}/events)
+	SubscribeOrderStatus(w http.ResponseWriter, r *http.Request, orderId string, params SubscribeOrderStatusParams)
+	// Confirm a pending payment
+	// (POST /order/{orderId}/payment/confirm)
+	ConfirmOrderPayment(w http.ResponseWriter, r *http.Request, orderId string)
+	// Refund an order
+	// (POST /order/{orderId}/refund)
+	RefundOrder(w http.ResponseWriter, r *http.Request, orderId string)
+	// Update order status
+	// (PUT /order/{orderId}/status)
+	UpdateOrderStatus(w http.ResponseWriter, r *http.Request, orderId string)
+	// List products
+	// (GET /product)
+	ListProducts(w http.ResponseWriter, r *http.Request, params ListProductsParams)
+	// Find product by slug
+	// (GET /product/slug/{slug})
+	GetProductBySlug(w http.ResponseWriter, r *http.Request, slug ProductSlug, params GetProductBySlugParams)
+	// Find product by ID
+	// (GET /product/{productId})
+	GetProduct(w http.ResponseWriter, r *http.Request, productId ProductId, params GetProductParams)
+}
+
+// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+
+type Unimplemented struct{}
+
+// Create a cart
+// (POST /cart)
+func (_ Unimplemented) CreateCart(w http.ResponseWriter, r *http.Request, params CreateCartParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a cart
+// (GET /cart/{cartId})
+func (_ Unimplemented) GetCart(w http.ResponseWriter, r *http.Request, cartId CartId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Check out a cart
+// (POST /cart/{cartId}/checkout)
+func (_ Unimplemented) CheckoutCart(w http.ResponseWriter, r *http.Request, cartId CartId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Detach the coupon from a cart
+// (DELETE /cart/{cartId}/coupon)
+func (_ Unimplemented) RemoveCartCoupon(w http.ResponseWriter, r *http.Request, cartId CartId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Attach a coupon to a cart
+// (PUT /cart/{cartId}/coupon)
+func (_ Unimplemented) ApplyCartCoupon(w http.ResponseWriter, r *http.Request, cartId CartId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Add an item to a cart
+// (POST /cart/{cartId}/items)
+func (_ Unimplemented) AddCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, params AddCartItemParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Remove an item from a cart
+// (DELETE /cart/{cartId}/items/{productId})
+func (_ Unimplemented) RemoveCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, productId CartProductId) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Set the quantity of a cart item
+// (PUT /cart/{cartId}/items/{productId})
+func (_ Unimplemented) SetCartItem(w http.ResponseWriter, r *http.Request, cartId CartId, productId CartProductId, params SetCartItemParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Issue a gift card
+// (POST /gift-cards)
+func (_ Unimplemented) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Check a gift card balance
+// (POST /gift-cards/balance)
+func (_ Unimplemented) CheckGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List gift card transactions
+// (GET /gift-cards/{giftCardId}/transactions)
+func (_ Unimplemented) ListGiftCardTransactions(w http.ResponseWriter, r *http.Request, giftCardId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get my loyalty points
+// (GET /me/loyalty)
+func (_ Unimplemented) GetMyLoyalty(w http.ResponseWriter, r *http.Request, params GetMyLoyaltyParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List my orders
+// (GET /me/orders)
+func (_ Unimplemented) ListMyOrders(w http.ResponseWriter, r *http.Request, params ListMyOrdersParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Place an order
+// (POST /order)
+func (_ Unimplemented) PlaceOrder(w http.ResponseWriter, r *http.Request, params PlaceOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get an order
+// (GET /order/{orderId})
+func (_ Unimplemented) GetOrder(w http.ResponseWriter, r *http.Request, orderId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Subscribe to order status
+// (GET /order/{orderId}/events)
+func (_ Unimplemented) SubscribeOrderStatus(w http.ResponseWriter, r *http.Request, orderId string, params SubscribeOrderStatusParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Confirm a pending payment
+// (POST /order/{orderId}/payment/confirm)
+func (_ Unimplemented) ConfirmOrderPayment(w http.ResponseWriter, r *http.Request, orderId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Refund an order
+// (POST /order/{orderId}/refund)
+func (_ Unimplemented) RefundOrder(w http.ResponseWriter, r *http.Request, orderId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update order status
+// (PUT /order/{orderId}/status)
+func (_ Unimplemented) UpdateOrderStatus(w http.ResponseWriter, r *http.Request, orderId string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List products
+// (GET /product)
+func (_ Unimplemented) ListProducts(w http.ResponseWriter, r *http.Request, params ListProductsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Find product by slug
+// (GET /product/slug/{slug})
+func (_ Unimplemented) GetProductBySlug(w http.ResponseWriter, r *http.Request, slug ProductSlug, params GetProductBySlugParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Find product by ID
+// (GET /product/{productId})
+func (_ Unimplemented) GetProduct(w http.ResponseWriter, r *http.Request, productId ProductId, params GetProductParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// CreateCart operation middleware
+func (siw *ServerInterfaceWrapper) CreateCart(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateCartParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCart(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCart operation middleware
+func (siw *ServerInterfaceWrapper) GetCart(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCart(w, r, cartId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CheckoutCart operation middleware
+func (siw *ServerInterfaceWrapper) CheckoutCart(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CheckoutCart(w, r, cartId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RemoveCartCoupon operation middleware
+func (siw *ServerInterfaceWrapper) RemoveCartCoupon(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemoveCartCoupon(w, r, cartId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ApplyCartCoupon operation middleware
+func (siw *ServerInterfaceWrapper) ApplyCartCoupon(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ApplyCartCoupon(w, r, cartId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AddCartItem operation middleware
+func (siw *ServerInterfaceWrapper) AddCartItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AddCartItemParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddCartItem(w, r, cartId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RemoveCartItem operation middleware
+func (siw *ServerInterfaceWrapper) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "productId" -------------
+	var productId CartProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", chi.URLParam(r, "productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "productId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RemoveCartItem(w, r, cartId, productId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetCartItem operation middleware
+func (siw *ServerInterfaceWrapper) SetCartItem(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "cartId" -------------
+	var cartId CartId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "cartId", chi.URLParam(r, "cartId"), &cartId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cartId", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "productId" -------------
+	var productId CartProductId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "productId", chi.URLParam(r, "productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "productId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SetCartItemParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetCartItem(w, r, cartId, productId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// IssueGiftCard operation middleware
+func (siw *ServerInterfaceWrapper) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"giftcards:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.IssueGiftCard(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CheckGiftCardBalance operation middleware
+func (siw *ServerInterfaceWrapper) CheckGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CheckGiftCardBalance(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListGiftCardTransactions operation middleware
+func (siw *ServerInterfaceWrapper) ListGiftCardTransactions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "giftCardId" -------------
+	var giftCardId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "giftCardId", chi.URLParam(r, "giftCardId"), &giftCardId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "giftCardId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"giftcards:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListGiftCardTransactions(w, r, giftCardId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetMyLoyalty operation middleware
+func (siw *ServerInterfaceWrapper) GetMyLoyalty(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMyLoyaltyParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMyLoyalty(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListMyOrders operation middleware
+func (siw *ServerInterfaceWrapper) ListMyOrders(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListMyOrdersParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListMyOrders(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PlaceOrder operation middleware
+func (siw *ServerInterfaceWrapper) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:write"})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{"orders:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PlaceOrderParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
 
-	// Quantity Item count
-	Quantity *int `json:"quantity,omitempty"`
-}
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
 
-// OrderReq Place a new order
-type OrderReq struct {
-	// CouponCode Optional promo code applied to the order
-	CouponCode *string `json:"couponCode,omitempty"`
-	Items      []struct {
-		// ProductId ID of the product (required)
-		ProductId string `json:"productId"`
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
 
-		// Quantity Item count (required)
-		Quantity int `json:"quantity"`
-	} `json:"items"`
-}
+		params.AcceptCurrency = &AcceptCurrency
 
-// Product defines model for Product.
-type Product struct {
-	Category *string `json:"category,omitempty"`
-	Id       *string `json:"id,omitempty"`
-	Name     *string `json:"name,omitempty"`
+	}
 
-	// Price Selling price
-	Price *float32 `json:"price,omitempty"`
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PlaceOrder(w, r, params)
+	}))
 
-// PlaceOrderJSONRequestBody defines body for PlaceOrder for application/json ContentType.
-type PlaceOrderJSONRequestBody = OrderReq
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Place an order
-	// (POST /order)
-	PlaceOrder(w http.ResponseWriter, r *http.Request)
-	// List products
-	// (GET /product)
-	ListProducts(w http.ResponseWriter, r *http.Request)
-	// Find product by ID
-	// (GET /product/{productId})
-	GetProduct(w http.ResponseWriter, r *http.Request, productId int64)
+	handler.ServeHTTP(w, r)
 }
 
-// Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
+// GetOrder operation middleware
+func (siw *ServerInterfaceWrapper) GetOrder(w http.ResponseWriter, r *http.Request) {
 
-type Unimplemented struct{}
+	var err error
 
-// Place an order
-// (POST /order)
-func (_ Unimplemented) PlaceOrder(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+	// ------------- Path parameter "orderId" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOrder(w, r, orderId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// List products
-// (GET /product)
-func (_ Unimplemented) ListProducts(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
+// SubscribeOrderStatus operation middleware
+func (siw *ServerInterfaceWrapper) SubscribeOrderStatus(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SubscribeOrderStatusParams
+
+	// ------------- Required query parameter "token" -------------
+
+	if paramValue := r.URL.Query().Get("token"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "token"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "token", r.URL.Query(), &params.Token)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SubscribeOrderStatus(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// Find product by ID
-// (GET /product/{productId})
-func (_ Unimplemented) GetProduct(w http.ResponseWriter, r *http.Request, productId int64) {
-	w.WriteHeader(http.StatusNotImplemented)
+// ConfirmOrderPayment operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmOrderPayment(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConfirmOrderPayment(w, r, orderId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// RefundOrder operation middleware
+func (siw *ServerInterfaceWrapper) RefundOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:admin"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RefundOrder(w, r, orderId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// UpdateOrderStatus operation middleware
+func (siw *ServerInterfaceWrapper) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
 
-// PlaceOrder operation middleware
-func (siw *ServerInterfaceWrapper) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
 
 	ctx := r.Context()
 
-	ctx = context.WithValue(ctx, Api_keyScopes, []string{})
+	ctx = context.WithValue(ctx, Api_keyScopes, []string{"orders:admin"})
 
 	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PlaceOrder(w, r)
+		siw.Handler.UpdateOrderStatus(w, r, orderId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -125,8 +1677,99 @@ func (siw *ServerInterfaceWrapper) PlaceOrder(w http.ResponseWriter, r *http.Req
 // ListProducts operation middleware
 func (siw *ServerInterfaceWrapper) ListProducts(w http.ResponseWriter, r *http.Request) {
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListProductsParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProducts(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProductBySlug operation middleware
+func (siw *ServerInterfaceWrapper) GetProductBySlug(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "slug" -------------
+	var slug ProductSlug
+
+	err = runtime.BindStyledParameterWithOptions("simple", "slug", chi.URLParam(r, "slug"), &slug, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "slug", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetProductBySlugParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListProducts(w, r)
+		siw.Handler.GetProductBySlug(w, r, slug, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -142,7 +1785,7 @@ func (siw *ServerInterfaceWrapper) GetProduct(w http.ResponseWriter, r *http.Req
 	var err error
 
 	// ------------- Path parameter "productId" -------------
-	var productId int64
+	var productId ProductId
 
 	err = runtime.BindStyledParameterWithOptions("simple", "productId", chi.URLParam(r, "productId"), &productId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
@@ -150,8 +1793,40 @@ func (siw *ServerInterfaceWrapper) GetProduct(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetProductParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Accept-Currency" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Currency")]; found {
+		var AcceptCurrency AcceptCurrency
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Currency", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Currency", valueList[0], &AcceptCurrency, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Currency", Err: err})
+			return
+		}
+
+		params.AcceptCurrency = &AcceptCurrency
+
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetProduct(w, r, productId)
+		siw.Handler.GetProduct(w, r, productId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -274,12 +1949,69 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cart", wrapper.CreateCart)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/cart/{cartId}", wrapper.GetCart)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cart/{cartId}/checkout", wrapper.CheckoutCart)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cart/{cartId}/coupon", wrapper.RemoveCartCoupon)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/cart/{cartId}/coupon", wrapper.ApplyCartCoupon)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/cart/{cartId}/items", wrapper.AddCartItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/cart/{cartId}/items/{productId}", wrapper.RemoveCartItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/cart/{cartId}/items/{productId}", wrapper.SetCartItem)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/gift-cards", wrapper.IssueGiftCard)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/gift-cards/balance", wrapper.CheckGiftCardBalance)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/gift-cards/{giftCardId}/transactions", wrapper.ListGiftCardTransactions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/me/loyalty", wrapper.GetMyLoyalty)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/me/orders", wrapper.ListMyOrders)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/order", wrapper.PlaceOrder)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/order/{orderId}", wrapper.GetOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/order/{orderId}/events", wrapper.SubscribeOrderStatus)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/order/{orderId}/payment/confirm", wrapper.ConfirmOrderPayment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/order/{orderId}/refund", wrapper.RefundOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/order/{orderId}/status", wrapper.UpdateOrderStatus)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/product", wrapper.ListProducts)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/product/slug/{slug}", wrapper.GetProductBySlug)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/product/{productId}", wrapper.GetProduct)
 	})
@@ -0,0 +1,145 @@
+// Package orderstatus fans out order status transitions to subscribed
+// clients and issues the short-lived tokens that authorize a subscription.
+package orderstatus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Subscribe once the hub has been shut down.
+var ErrClosed = errors.New("order status hub closed")
+
+// Event is a status transition or ETA update for a single order.
+type Event struct {
+	OrderID string     `json:"orderId"`
+	Status  string     `json:"status"`
+	ETA     *time.Time `json:"eta,omitempty"`
+	At      time.Time  `json:"at"`
+}
+
+// Hub delivers published events to the subscribers of each order.
+// Delivery never blocks the publisher: when a subscriber's buffer is full the
+// oldest pending event is dropped, since clients only care about the latest state.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	buffer int
+	closed bool
+	active sync.WaitGroup
+}
+
+// NewHub creates a hub whose subscriptions buffer up to buffer events.
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 8
+	}
+	return &Hub{subs: make(map[string]map[*Subscription]struct{}), buffer: buffer}
+}
+
+// Subscription receives events for one order until it is closed by the
+// subscriber or the hub shuts down.
+type Subscription struct {
+	hub     *Hub
+	orderID string
+	ch      chan Event
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Subscribe registers interest in orderID. Callers must Close the subscription.
+func (h *Hub) Subscribe(orderID string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	sub := &Subscription{
+		hub:     h,
+		orderID: orderID,
+		ch:      make(chan Event, h.buffer),
+		done:    make(chan struct{}),
+	}
+	if h.subs[orderID] == nil {
+		h.subs[orderID] = make(map[*Subscription]struct{})
+	}
+	h.subs[orderID][sub] = struct{}{}
+	h.active.Add(1)
+	return sub, nil
+}
+
+// Publish delivers ev to every subscriber of ev.OrderID.
+func (h *Hub) Publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs[ev.OrderID] {
+		select {
+		case sub.ch <- ev:
+		default:
+			// slow consumer: make room by discarding the oldest pending event
+			select {
+			case <-sub.ch:
+			default:
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// Close stops accepting subscriptions and signals every open subscription
+// to finish. It is safe to call more than once.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for _, subs := range h.subs {
+		for sub := range subs {
+			close(sub.done)
+		}
+	}
+}
+
+// Wait blocks until every subscription has been closed or ctx is done.
+func (h *Hub) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Events returns the channel of events for the subscribed order.
+func (s *Subscription) Events() <-chan Event { return s.ch }
+
+// Done is closed when the hub shuts down.
+func (s *Subscription) Done() <-chan struct{} { return s.done }
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		h := s.hub
+		h.mu.Lock()
+		if subs := h.subs[s.orderID]; subs != nil {
+			delete(subs, s)
+			if len(subs) == 0 {
+				delete(h.subs, s.orderID)
+			}
+		}
+		h.mu.Unlock()
+		h.active.Done()
+	})
+}
@@ -0,0 +1,70 @@
+package orderstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHub_PublishRoutesByOrder(t *testing.T) {
+	h := NewHub(4)
+	a, err := h.Subscribe("a")
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := h.Subscribe("b")
+	require.NoError(t, err)
+	defer b.Close()
+
+	h.Publish(Event{OrderID: "a", Status: "preparing"})
+
+	select {
+	case ev := <-a.Events():
+		assert.Equal(t, "preparing", ev.Status)
+	default:
+		t.Fatal("expected event for order a")
+	}
+	assert.Empty(t, b.Events())
+}
+
+func TestHub_SlowSubscriberKeepsLatest(t *testing.T) {
+	h := NewHub(2)
+	sub, err := h.Subscribe("a")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	for _, st := range []string{"placed", "preparing", "ready"} {
+		h.Publish(Event{OrderID: "a", Status: st})
+	}
+
+	var got []string
+	for len(sub.Events()) > 0 {
+		got = append(got, (<-sub.Events()).Status)
+	}
+	assert.Equal(t, []string{"preparing", "ready"}, got)
+}
+
+func TestHub_CloseSignalsSubscribersAndWaits(t *testing.T) {
+	h := NewHub(1)
+	sub, err := h.Subscribe("a")
+	require.NoError(t, err)
+
+	h.Close()
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("expected done to be closed")
+	}
+	_, err = h.Subscribe("a")
+	require.ErrorIs(t, err, ErrClosed)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, h.Wait(ctx), context.DeadlineExceeded)
+
+	sub.Close()
+	sub.Close()
+	require.NoError(t, h.Wait(context.Background()))
+}
@@ -0,0 +1,69 @@
+package orderstatus
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTokenInvalid indicates a malformed token or one issued for another
+	// order or store.
+	ErrTokenInvalid = errors.New("invalid status token")
+	// ErrTokenExpired indicates the token's validity window has passed.
+	ErrTokenExpired = errors.New("status token expired")
+)
+
+// TokenSigner issues and verifies HMAC-signed tokens scoped to one order of
+// one store.
+// A token has the form "<unix expiry>.<base64url signature>".
+type TokenSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewTokenSigner(secret string, ttl time.Duration) *TokenSigner {
+	return &TokenSigner{secret: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// Issue returns a token for storeID's order orderID and the time it expires.
+func (s *TokenSigner) Issue(storeID, orderID string) (string, time.Time) {
+	exp := s.now().Add(s.ttl).Truncate(time.Second)
+	ts := strconv.FormatInt(exp.Unix(), 10)
+	return ts + "." + s.sign(storeID, orderID, ts), exp
+}
+
+// Verify checks that token was issued for storeID's order orderID and has
+// not expired.
+func (s *TokenSigner) Verify(token, storeID, orderID string) error {
+	ts, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrTokenInvalid
+	}
+	exp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(storeID, orderID, ts))) {
+		return ErrTokenInvalid
+	}
+	if !s.now().Before(time.Unix(exp, 0)) {
+		return ErrTokenExpired
+	}
+	return nil
+}
+
+func (s *TokenSigner) sign(storeID, orderID, ts string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(storeID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(orderID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(ts))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
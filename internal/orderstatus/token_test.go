@@ -0,0 +1,44 @@
+package orderstatus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSigner_Verify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := NewTokenSigner("secret", time.Minute)
+	s.now = func() time.Time { return now }
+	tok, exp := s.Issue("store-a", "order-1")
+	require.Equal(t, now.Add(time.Minute), exp)
+
+	type tc struct {
+		name    string
+		token   string
+		storeID string
+		orderID string
+		at      time.Time
+		wantErr error
+	}
+	cases := []tc{
+		{name: "ok", token: tok, storeID: "store-a", orderID: "order-1", at: now},
+		{name: "other order", token: tok, storeID: "store-a", orderID: "order-2", at: now, wantErr: ErrTokenInvalid},
+		{name: "other store", token: tok, storeID: "store-b", orderID: "order-1", at: now, wantErr: ErrTokenInvalid},
+		{name: "tampered expiry", token: "9999999999" + tok[10:], storeID: "store-a", orderID: "order-1", at: now, wantErr: ErrTokenInvalid},
+		{name: "malformed", token: "garbage", storeID: "store-a", orderID: "order-1", at: now, wantErr: ErrTokenInvalid},
+		{name: "expired", token: tok, storeID: "store-a", orderID: "order-1", at: exp, wantErr: ErrTokenExpired},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s.now = func() time.Time { return c.at }
+			err := s.Verify(c.token, c.storeID, c.orderID)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
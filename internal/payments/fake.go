@@ -0,0 +1,154 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Payment method tokens with special behaviour in the fake provider. Any
+// other non-empty token is authorized immediately.
+const (
+	FakeTokenDecline           = "tok_decline"
+	FakeTokenInsufficientFunds = "tok_insufficient_funds"
+	FakeTokenTimeout           = "tok_timeout"
+	FakeToken3DS               = "tok_3ds"
+	FakeToken3DSFail           = "tok_3ds_fail"
+)
+
+// Fake is a deterministic in-process Provider for local development and tests.
+// References are issued sequentially and all state lives in memory.
+type Fake struct {
+	mu       sync.Mutex
+	seq      int
+	payments map[string]*fakePayment
+}
+
+type fakePayment struct {
+	token      string
+	status     Status
+	amount     int64
+	captured   int64
+	refunded   int64
+	idempotent string
+}
+
+func NewFake() *Fake {
+	return &Fake{payments: make(map[string]*fakePayment)}
+}
+
+func (f *Fake) Name() string { return "fake" }
+
+func (f *Fake) Authorize(ctx context.Context, req AuthorizeRequest) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	if req.Token == FakeTokenTimeout {
+		return Result{}, ErrTimeout
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ref, p := range f.payments {
+		if req.IdempotencyKey != "" && p.idempotent == req.IdempotencyKey {
+			return f.result(ref, p), nil
+		}
+	}
+
+	f.seq++
+	ref := fmt.Sprintf("fake_%06d", f.seq)
+	p := &fakePayment{token: req.Token, amount: req.AmountCents, idempotent: req.IdempotencyKey}
+	switch req.Token {
+	case FakeTokenDecline, FakeTokenInsufficientFunds:
+		p.status = StatusDeclined
+	case FakeToken3DS, FakeToken3DSFail:
+		p.status = StatusPending
+	default:
+		p.status = StatusAuthorized
+	}
+	f.payments[ref] = p
+	return f.result(ref, p), nil
+}
+
+func (f *Fake) Confirm(ctx context.Context, ref string) (Result, error) {
+	return f.update(ctx, ref, func(p *fakePayment) error {
+		if p.status != StatusPending {
+			return ErrInvalidState
+		}
+		if p.token == FakeToken3DSFail {
+			p.status = StatusDeclined
+		} else {
+			p.status = StatusAuthorized
+		}
+		return nil
+	})
+}
+
+func (f *Fake) Capture(ctx context.Context, ref string, amountCents int64) (Result, error) {
+	return f.update(ctx, ref, func(p *fakePayment) error {
+		if p.status != StatusAuthorized {
+			return ErrInvalidState
+		}
+		if amountCents > p.amount {
+			return ErrAmountExceeded
+		}
+		p.captured = amountCents
+		p.status = StatusCaptured
+		return nil
+	})
+}
+
+func (f *Fake) Void(ctx context.Context, ref string) (Result, error) {
+	return f.update(ctx, ref, func(p *fakePayment) error {
+		if p.status != StatusAuthorized && p.status != StatusPending {
+			return ErrInvalidState
+		}
+		p.status = StatusVoided
+		return nil
+	})
+}
+
+func (f *Fake) Refund(ctx context.Context, ref string, amountCents int64) (Result, error) {
+	return f.update(ctx, ref, func(p *fakePayment) error {
+		if p.status != StatusCaptured && p.status != StatusRefunded {
+			return ErrInvalidState
+		}
+		if p.refunded+amountCents > p.captured {
+			return ErrAmountExceeded
+		}
+		p.refunded += amountCents
+		if p.refunded == p.captured {
+			p.status = StatusRefunded
+		}
+		return nil
+	})
+}
+
+func (f *Fake) update(ctx context.Context, ref string, fn func(p *fakePayment) error) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payments[ref]
+	if !ok {
+		return Result{}, ErrUnknownReference
+	}
+	if err := fn(p); err != nil {
+		return Result{}, err
+	}
+	return f.result(ref, p), nil
+}
+
+func (f *Fake) result(ref string, p *fakePayment) Result {
+	res := Result{Reference: ref, Status: p.status}
+	switch {
+	case p.status == StatusPending:
+		res.NextActionURL = "https://3ds.fake.invalid/challenge/" + ref
+	case p.status == StatusDeclined && p.token == FakeTokenInsufficientFunds:
+		res.DeclineCode = "insufficient_funds"
+	case p.status == StatusDeclined:
+		res.DeclineCode = "card_declined"
+	}
+	return res
+}
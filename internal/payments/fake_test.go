@@ -0,0 +1,96 @@
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_Authorize(t *testing.T) {
+	type tc struct {
+		name        string
+		token       string
+		wantStatus  Status
+		wantDecline string
+		wantErr     error
+	}
+	cases := []tc{
+		{name: "approved", token: "tok_visa", wantStatus: StatusAuthorized},
+		{name: "declined", token: FakeTokenDecline, wantStatus: StatusDeclined, wantDecline: "card_declined"},
+		{name: "insufficient funds", token: FakeTokenInsufficientFunds, wantStatus: StatusDeclined, wantDecline: "insufficient_funds"},
+		{name: "3ds challenge", token: FakeToken3DS, wantStatus: StatusPending},
+		{name: "timeout", token: FakeTokenTimeout, wantErr: ErrTimeout},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := NewFake()
+			res, err := f.Authorize(context.Background(), AuthorizeRequest{OrderID: "o1", AmountCents: 1000, Token: c.token})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "fake_000001", res.Reference)
+			assert.Equal(t, c.wantStatus, res.Status)
+			assert.Equal(t, c.wantDecline, res.DeclineCode)
+			if c.wantStatus == StatusPending {
+				assert.NotEmpty(t, res.NextActionURL)
+			}
+		})
+	}
+}
+
+func TestFake_Idempotency(t *testing.T) {
+	f := NewFake()
+	req := AuthorizeRequest{OrderID: "o1", AmountCents: 1000, Token: "tok_visa", IdempotencyKey: "k1"}
+	a, err := f.Authorize(context.Background(), req)
+	require.NoError(t, err)
+	b, err := f.Authorize(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, a.Reference, b.Reference)
+}
+
+func TestFake_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	f := NewFake()
+
+	res, err := f.Authorize(ctx, AuthorizeRequest{AmountCents: 1000, Token: FakeToken3DS})
+	require.NoError(t, err)
+	_, err = f.Capture(ctx, res.Reference, 1000)
+	require.ErrorIs(t, err, ErrInvalidState, "pending payments cannot be captured")
+
+	res, err = f.Confirm(ctx, res.Reference)
+	require.NoError(t, err)
+	require.Equal(t, StatusAuthorized, res.Status)
+
+	_, err = f.Capture(ctx, res.Reference, 1001)
+	require.ErrorIs(t, err, ErrAmountExceeded)
+	res, err = f.Capture(ctx, res.Reference, 1000)
+	require.NoError(t, err)
+	require.Equal(t, StatusCaptured, res.Status)
+
+	res, err = f.Refund(ctx, res.Reference, 400)
+	require.NoError(t, err)
+	require.Equal(t, StatusCaptured, res.Status)
+	_, err = f.Refund(ctx, res.Reference, 601)
+	require.ErrorIs(t, err, ErrAmountExceeded)
+	res, err = f.Refund(ctx, res.Reference, 600)
+	require.NoError(t, err)
+	require.Equal(t, StatusRefunded, res.Status)
+
+	_, err = f.Void(ctx, res.Reference)
+	require.ErrorIs(t, err, ErrInvalidState)
+	_, err = f.Void(ctx, "nope")
+	require.ErrorIs(t, err, ErrUnknownReference)
+}
+
+func TestFake_3DSFailure(t *testing.T) {
+	f := NewFake()
+	res, err := f.Authorize(context.Background(), AuthorizeRequest{AmountCents: 1000, Token: FakeToken3DSFail})
+	require.NoError(t, err)
+	res, err = f.Confirm(context.Background(), res.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDeclined, res.Status)
+}
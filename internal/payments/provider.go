@@ -0,0 +1,66 @@
+// Package payments abstracts the card payment provider used at checkout.
+package payments
+
+import (
+	"context"
+	"errors"
+)
+
+// Status is the state of a payment at the provider.
+type Status string
+
+const (
+	StatusPending    Status = "pending"
+	StatusAuthorized Status = "authorized"
+	StatusDeclined   Status = "declined"
+	StatusFailed     Status = "failed"
+	StatusCaptured   Status = "captured"
+	StatusVoided     Status = "voided"
+	StatusRefunded   Status = "refunded"
+)
+
+var (
+	// ErrTimeout means the provider did not answer in time. The outcome of the
+	// operation is unknown; callers must void anything it may have authorized
+	// before treating the payment as failed.
+	ErrTimeout = errors.New("payment provider timeout")
+	// ErrUnknownReference is returned for a provider reference the provider has never issued.
+	ErrUnknownReference = errors.New("unknown payment reference")
+	// ErrInvalidState is returned when the operation does not apply to the payment's current status.
+	ErrInvalidState = errors.New("payment is not in a valid state for this operation")
+	// ErrAmountExceeded is returned when capturing or refunding more than is available.
+	ErrAmountExceeded = errors.New("amount exceeds what is available")
+)
+
+// AuthorizeRequest asks the provider to hold funds for an order.
+type AuthorizeRequest struct {
+	OrderID     string
+	AmountCents int64
+	Currency    string
+	// Token identifies the customer's payment method, as issued by the provider's client SDK.
+	Token string
+	// IdempotencyKey lets the provider deduplicate retried requests.
+	IdempotencyKey string
+}
+
+// Result is the provider's view of a payment after an operation.
+type Result struct {
+	Reference string
+	Status    Status
+	// DeclineCode explains a declined payment, e.g. "insufficient_funds".
+	DeclineCode string
+	// NextActionURL is set for pending payments that need the customer to
+	// complete a challenge (3-D Secure) before Confirm can succeed.
+	NextActionURL string
+}
+
+// Provider is implemented by each payment gateway integration.
+type Provider interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (Result, error)
+	// Confirm resumes an authorization left pending for customer action.
+	Confirm(ctx context.Context, ref string) (Result, error)
+	Capture(ctx context.Context, ref string, amountCents int64) (Result, error)
+	Void(ctx context.Context, ref string) (Result, error)
+	Refund(ctx context.Context, ref string, amountCents int64) (Result, error)
+}
@@ -0,0 +1,301 @@
+// Package promo evaluates automatic promotions (buy X get Y, bundles and
+// percentage discounts) against priced order lines.
+package promo
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Actions a promotion can take once its conditions hold.
+const (
+	// FreeItem gives Get.Quantity units matching Get away for every time the
+	// Buy selectors are met, cheapest units first.
+	FreeItem = "free_item"
+	// PercentOff takes PercentOff percent off every unit matching Get once the
+	// Buy selectors are met.
+	PercentOff = "percent_off"
+	// BundlePrice sells the units matching the Buy selectors together for
+	// BundlePriceCents, as many times as they can be made up.
+	BundlePrice = "bundle_price"
+)
+
+var ErrInvalidRule = errors.New("invalid promotion rule")
+
+// Selector matches order lines by product ID or category; an empty selector
+// matches every line. Quantity is how many matching units it needs.
+type Selector struct {
+	Products   []string `json:"products,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Quantity   int32    `json:"quantity,omitempty"`
+}
+
+func (s Selector) matches(l Line) bool {
+	if len(s.Products) == 0 && len(s.Categories) == 0 {
+		return true
+	}
+	return slices.Contains(s.Products, l.ProductID) || slices.Contains(s.Categories, l.Category)
+}
+
+// Definition is the declarative part of a rule, stored as JSON.
+type Definition struct {
+	// MinSubtotalCents is the order subtotal, before any promotion, the rule
+	// needs.
+	MinSubtotalCents int64 `json:"minSubtotalCents,omitempty"`
+	// Buy lists the units the order must contain. Units used to meet them
+	// cannot be used by the action or by another promotion.
+	Buy    []Selector `json:"buy,omitempty"`
+	Action string     `json:"action"`
+	// Get selects the units FreeItem and PercentOff discount.
+	Get              Selector `json:"get"`
+	PercentOff       int32    `json:"percentOff,omitempty"`
+	BundlePriceCents int64    `json:"bundlePriceCents,omitempty"`
+	// MaxApplications caps how many times one order gets the rule; 0 is no cap.
+	MaxApplications int32 `json:"maxApplications,omitempty"`
+}
+
+// Validate reports whether the definition can be evaluated.
+func (d Definition) Validate() error {
+	for _, b := range d.Buy {
+		if b.Quantity < 1 {
+			return fmt.Errorf("%w: buy quantity must be at least 1", ErrInvalidRule)
+		}
+	}
+	if d.MaxApplications < 0 || d.MinSubtotalCents < 0 {
+		return fmt.Errorf("%w: negative limit", ErrInvalidRule)
+	}
+	switch d.Action {
+	case FreeItem:
+		if d.Get.Quantity < 1 {
+			return fmt.Errorf("%w: free_item needs a get quantity", ErrInvalidRule)
+		}
+	case PercentOff:
+		if d.PercentOff < 1 || d.PercentOff > 100 {
+			return fmt.Errorf("%w: percentOff must be between 1 and 100", ErrInvalidRule)
+		}
+	case BundlePrice:
+		if len(d.Buy) == 0 || d.BundlePriceCents < 0 {
+			return fmt.Errorf("%w: bundle_price needs buy selectors and a price", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidRule, d.Action)
+	}
+	return nil
+}
+
+// Rule is a promotion. Rules are evaluated by descending Priority, then by
+// ID. An Exclusive rule only applies to an order no other rule has applied
+// to, and no rule after it applies once it has.
+type Rule struct {
+	ID        string
+	Name      string
+	Priority  int32
+	Exclusive bool
+	Definition
+}
+
+// Line is a priced order line.
+type Line struct {
+	ProductID      string
+	Category       string
+	Quantity       int32
+	UnitPriceCents int64
+}
+
+// Applied is a rule that discounted the order, Times times in total.
+type Applied struct {
+	RuleID        string
+	Name          string
+	Times         int32
+	DiscountCents int64
+}
+
+// Result is what the promotions take off a set of lines. LineDiscounts holds
+// each line's share in input order and sums to DiscountCents.
+type Result struct {
+	Applied       []Applied
+	LineDiscounts []int64
+	DiscountCents int64
+}
+
+// Evaluate applies rules to lines. Each unit is used by at most one rule,
+// whether to meet its conditions or to be discounted, so the outcome does not
+// depend on the order rules are given in.
+func Evaluate(rules []Rule, lines []Line) Result {
+	sorted := slices.Clone(rules)
+	slices.SortFunc(sorted, func(a, b Rule) int {
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	var subtotal int64
+	free := make([]int32, len(lines))
+	for i, l := range lines {
+		subtotal += l.UnitPriceCents * int64(l.Quantity)
+		free[i] = l.Quantity
+	}
+	res := Result{LineDiscounts: make([]int64, len(lines))}
+	for _, r := range sorted {
+		if r.Exclusive && len(res.Applied) > 0 {
+			continue
+		}
+		if subtotal < r.MinSubtotalCents || r.Validate() != nil {
+			continue
+		}
+		e := evaluation{lines: lines, free: slices.Clone(free), discounts: make([]int64, len(lines))}
+		times := e.apply(r.Definition)
+		var discount int64
+		for _, d := range e.discounts {
+			discount += d
+		}
+		if times == 0 || discount == 0 {
+			continue
+		}
+		free = e.free
+		for i, d := range e.discounts {
+			res.LineDiscounts[i] += d
+		}
+		res.DiscountCents += discount
+		res.Applied = append(res.Applied, Applied{RuleID: r.ID, Name: r.Name, Times: times, DiscountCents: discount})
+		if r.Exclusive {
+			break
+		}
+	}
+	return res
+}
+
+// evaluation tracks the units still free for one rule, and the discounts it
+// has given, so a rule that falls through can be discarded.
+type evaluation struct {
+	lines     []Line
+	free      []int32
+	discounts []int64
+}
+
+func (e *evaluation) apply(d Definition) int32 {
+	var times int32
+	for d.MaxApplications == 0 || times < d.MaxApplications {
+		free := slices.Clone(e.free)
+		var bought []int32
+		ok := true
+		for _, b := range d.Buy {
+			// Conditions use up the dearest units, leaving the cheapest to
+			// be discounted.
+			taken, got := take(e.lines, free, b, b.Quantity, false)
+			if !got {
+				ok = false
+				break
+			}
+			bought = addUnits(bought, taken)
+		}
+		if !ok {
+			break
+		}
+
+		switch d.Action {
+		case FreeItem:
+			taken, got := take(e.lines, free, d.Get, d.Get.Quantity, true)
+			if !got {
+				return times
+			}
+			for i, n := range taken {
+				e.discounts[i] += e.lines[i].UnitPriceCents * int64(n)
+			}
+		case PercentOff:
+			taken, _ := take(e.lines, free, d.Get, -1, true)
+			for i, n := range taken {
+				e.discounts[i] += e.lines[i].UnitPriceCents * int64(n) * int64(d.PercentOff) / 100
+			}
+		case BundlePrice:
+			value := make([]int64, len(e.lines))
+			var total int64
+			for i, n := range bought {
+				value[i] = e.lines[i].UnitPriceCents * int64(n)
+				total += value[i]
+			}
+			if total <= d.BundlePriceCents {
+				return times
+			}
+			for i, s := range spread(value, total-d.BundlePriceCents) {
+				e.discounts[i] += s
+			}
+		}
+		e.free = free
+		times++
+		// A percentage applies once, to every unit it selects.
+		if d.Action == PercentOff {
+			break
+		}
+	}
+	return times
+}
+
+// take claims n units matching sel from free, cheapest first when cheap is
+// set and dearest first otherwise; n < 0 claims every matching unit. It
+// reports how many units it took from each line and whether it found n.
+func take(lines []Line, free []int32, sel Selector, n int32, cheap bool) ([]int32, bool) {
+	idx := make([]int, 0, len(lines))
+	for i, l := range lines {
+		if free[i] > 0 && sel.matches(l) {
+			idx = append(idx, i)
+		}
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		if cheap {
+			return cmp.Compare(lines[a].UnitPriceCents, lines[b].UnitPriceCents)
+		}
+		return cmp.Compare(lines[b].UnitPriceCents, lines[a].UnitPriceCents)
+	})
+	taken := make([]int32, len(lines))
+	for _, i := range idx {
+		if n == 0 {
+			break
+		}
+		k := free[i]
+		if n > 0 {
+			k = min(k, n)
+			n -= k
+		}
+		free[i] -= k
+		taken[i] = k
+	}
+	return taken, n <= 0
+}
+
+func addUnits(a, b []int32) []int32 {
+	if a == nil {
+		return b
+	}
+	for i := range a {
+		a[i] += b[i]
+	}
+	return a
+}
+
+// spread splits amount over value in proportion, rounding down and giving
+// the remainder to the last line with any value.
+func spread(value []int64, amount int64) []int64 {
+	out := make([]int64, len(value))
+	var total int64
+	last := -1
+	for i, v := range value {
+		total += v
+		if v > 0 {
+			last = i
+		}
+	}
+	if total == 0 {
+		return out
+	}
+	var given int64
+	for i, v := range value {
+		out[i] = amount * v / total
+		given += out[i]
+	}
+	out[last] += amount - given
+	return out
+}
@@ -0,0 +1,183 @@
+package promo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	waffle := Line{ProductID: "w", Category: "Waffle", UnitPriceCents: 1000}
+	latte := Line{ProductID: "l", Category: "Coffee", UnitPriceCents: 500}
+	cake := Line{ProductID: "c", Category: "Cake", UnitPriceCents: 800}
+	qty := func(l Line, n int32) Line { l.Quantity = n; return l }
+
+	buy2WafflesFreeLatte := Rule{ID: "b2gl", Name: "Buy 2 waffles get a latte free", Priority: 10, Definition: Definition{
+		Buy:    []Selector{{Categories: []string{"Waffle"}, Quantity: 2}},
+		Action: FreeItem,
+		Get:    Selector{Products: []string{"l"}, Quantity: 1},
+	}}
+	combo := Rule{ID: "combo", Name: "Waffle and latte for $12", Priority: 5, Definition: Definition{
+		Buy:              []Selector{{Products: []string{"w"}, Quantity: 1}, {Products: []string{"l"}, Quantity: 1}},
+		Action:           BundlePrice,
+		BundlePriceCents: 1200,
+	}}
+	tenOff := Rule{ID: "ten", Name: "10% off orders over $30", Definition: Definition{
+		MinSubtotalCents: 3000,
+		Action:           PercentOff,
+		PercentOff:       10,
+	}}
+
+	type tc struct {
+		name        string
+		rules       []Rule
+		lines       []Line
+		wantApplied []Applied
+		wantLines   []int64
+	}
+	cases := []tc{
+		{
+			name:        "buy x get y",
+			rules:       []Rule{buy2WafflesFreeLatte},
+			lines:       []Line{qty(waffle, 2), qty(latte, 1)},
+			wantApplied: []Applied{{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 1, DiscountCents: 500}},
+			wantLines:   []int64{0, 500},
+		},
+		{
+			name:        "buy x get y repeats",
+			rules:       []Rule{buy2WafflesFreeLatte},
+			lines:       []Line{qty(waffle, 5), qty(latte, 3)},
+			wantApplied: []Applied{{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 2, DiscountCents: 1000}},
+			wantLines:   []int64{0, 1000},
+		},
+		{
+			name:      "buy x get y without the free item",
+			rules:     []Rule{buy2WafflesFreeLatte},
+			lines:     []Line{qty(waffle, 2)},
+			wantLines: []int64{0},
+		},
+		{
+			// Two bundles take 2 x (10.00 + 5.00 - 12.00); the third waffle is full price.
+			name:        "bundle price",
+			rules:       []Rule{combo},
+			lines:       []Line{qty(waffle, 3), qty(latte, 2)},
+			wantApplied: []Applied{{RuleID: "combo", Name: combo.Name, Times: 2, DiscountCents: 600}},
+			wantLines:   []int64{400, 200},
+		},
+		{
+			// The higher priority rule claims both waffles and the latte, so
+			// there is nothing left to bundle.
+			name:        "units are not shared",
+			rules:       []Rule{combo, buy2WafflesFreeLatte},
+			lines:       []Line{qty(waffle, 2), qty(latte, 1)},
+			wantApplied: []Applied{{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 1, DiscountCents: 500}},
+			wantLines:   []int64{0, 500},
+		},
+		{
+			name:  "stacks on the units left over",
+			rules: []Rule{tenOff, buy2WafflesFreeLatte},
+			lines: []Line{qty(waffle, 2), qty(latte, 1), qty(cake, 2)},
+			wantApplied: []Applied{
+				{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 1, DiscountCents: 500},
+				{RuleID: "ten", Name: tenOff.Name, Times: 1, DiscountCents: 160},
+			},
+			wantLines: []int64{0, 500, 160},
+		},
+		{
+			name:      "below minimum subtotal",
+			rules:     []Rule{tenOff},
+			lines:     []Line{qty(cake, 3)},
+			wantLines: []int64{0},
+		},
+		{
+			name:  "exclusive rule stands alone",
+			rules: []Rule{buy2WafflesFreeLatte, withExclusive(tenOff, 20)},
+			lines: []Line{qty(waffle, 2), qty(latte, 1), qty(cake, 2)},
+			wantApplied: []Applied{
+				{RuleID: "ten", Name: tenOff.Name, Times: 1, DiscountCents: 410},
+			},
+			wantLines: []int64{200, 50, 160},
+		},
+		{
+			name:  "exclusive rule skipped once another applied",
+			rules: []Rule{buy2WafflesFreeLatte, withExclusive(tenOff, 0)},
+			lines: []Line{qty(waffle, 2), qty(latte, 1), qty(cake, 2)},
+			wantApplied: []Applied{
+				{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 1, DiscountCents: 500},
+			},
+			wantLines: []int64{0, 500, 0},
+		},
+		{
+			name:        "capped applications",
+			rules:       []Rule{withMax(buy2WafflesFreeLatte, 1)},
+			lines:       []Line{qty(waffle, 4), qty(latte, 2)},
+			wantApplied: []Applied{{RuleID: "b2gl", Name: buy2WafflesFreeLatte.Name, Times: 1, DiscountCents: 500}},
+			wantLines:   []int64{0, 500},
+		},
+		{
+			name:      "invalid rule ignored",
+			rules:     []Rule{{ID: "bad", Definition: Definition{Action: "half_price"}}},
+			lines:     []Line{qty(waffle, 1)},
+			wantLines: []int64{0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Evaluate(c.rules, c.lines)
+			require.Equal(t, c.wantApplied, got.Applied)
+			require.Equal(t, c.wantLines, got.LineDiscounts)
+			var sum int64
+			for _, d := range got.LineDiscounts {
+				sum += d
+			}
+			require.Equal(t, sum, got.DiscountCents)
+		})
+	}
+}
+
+func TestEvaluate_Deterministic(t *testing.T) {
+	a := Rule{ID: "a", Definition: Definition{Action: PercentOff, PercentOff: 50, Get: Selector{Products: []string{"x"}}}}
+	b := Rule{ID: "b", Definition: Definition{Action: FreeItem, Get: Selector{Products: []string{"x"}, Quantity: 1}}}
+	lines := []Line{{ProductID: "x", Quantity: 1, UnitPriceCents: 1000}}
+
+	// Equal priorities fall back to the rule ID, whatever order they come in.
+	require.Equal(t, Evaluate([]Rule{a, b}, lines), Evaluate([]Rule{b, a}, lines))
+	require.Equal(t, "a", Evaluate([]Rule{b, a}, lines).Applied[0].RuleID)
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	type tc struct {
+		name    string
+		def     Definition
+		wantErr bool
+	}
+	cases := []tc{
+		{name: "free item", def: Definition{Action: FreeItem, Get: Selector{Quantity: 1}}},
+		{name: "free item without quantity", def: Definition{Action: FreeItem}, wantErr: true},
+		{name: "percent over 100", def: Definition{Action: PercentOff, PercentOff: 101}, wantErr: true},
+		{name: "bundle without components", def: Definition{Action: BundlePrice, BundlePriceCents: 100}, wantErr: true},
+		{name: "buy without quantity", def: Definition{Action: PercentOff, PercentOff: 5, Buy: []Selector{{}}}, wantErr: true},
+		{name: "unknown action", def: Definition{Action: "bogo"}, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.def.Validate()
+			if c.wantErr {
+				require.ErrorIs(t, err, ErrInvalidRule)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func withExclusive(r Rule, priority int32) Rule {
+	r.Exclusive = true
+	r.Priority = priority
+	return r
+}
+
+func withMax(r Rule, n int32) Rule {
+	r.MaxApplications = n
+	return r
+}
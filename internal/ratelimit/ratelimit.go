@@ -0,0 +1,154 @@
+// Package ratelimit throttles callers with a token bucket per key.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many calls pass between sweeps of idle buckets.
+const sweepEvery = 1024
+
+// Store holds token buckets. Memory keeps them in the process; a store shared
+// by every replica, such as the Postgres one in repo, makes limits hold across
+// them.
+type Store interface {
+	// Take takes a token from key's bucket, which holds up to burst tokens and
+	// refills at rate tokens a second; a new bucket is full. It reports
+	// whether a token was taken and the tokens left in the bucket.
+	Take(ctx context.Context, key string, rate float64, burst int) (taken bool, tokens float64, err error)
+}
+
+// Limiter allows each key Burst calls at once, refilled at Rate per second.
+type Limiter struct {
+	Rate  float64
+	Burst int
+	// Store holds the buckets.
+	Store Store
+}
+
+// Result is the state of a caller's bucket after a call.
+type Result struct {
+	Allowed bool
+	// Limit is how many calls the bucket holds.
+	Limit int
+	// Remaining is how many more calls the bucket allows right now.
+	Remaining int
+	// RetryAfter is how long until a call is allowed again, when it was not.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// PerMinute returns a limiter allowing n calls a minute per key, all of which
+// may come at once. Its buckets live in memory, so limits are per process.
+func PerMinute(n int) *Limiter {
+	return New(float64(n)/60, n)
+}
+
+// New returns a limiter keeping its buckets in memory.
+func New(rate float64, burst int) *Limiter {
+	return &Limiter{Rate: rate, Burst: burst, Store: NewMemory()}
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it reports
+// false and how long until a token is available. Calls are allowed when the
+// store fails, so an outage of a shared store does not take the API down.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	res, err := l.Take(context.Background(), key)
+	if err != nil {
+		return true, 0
+	}
+	return res.Allowed, res.RetryAfter
+}
+
+// Take takes a token from key's bucket and reports the bucket's state.
+func (l *Limiter) Take(ctx context.Context, key string) (Result, error) {
+	taken, tokens, err := l.Store.Take(ctx, key, l.Rate, l.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+	res := Result{Allowed: taken, Limit: l.Burst, Remaining: int(math.Max(0, math.Floor(tokens)))}
+	if l.Rate <= 0 {
+		res.Reset = time.Duration(math.MaxInt64)
+		if !taken {
+			res.RetryAfter = time.Duration(math.MaxInt64)
+		}
+		return res, nil
+	}
+	res.Reset = seconds((float64(l.Burst) - tokens) / l.Rate)
+	if !taken {
+		res.RetryAfter = seconds((1 - tokens) / l.Rate)
+	}
+	return res, nil
+}
+
+// Window is how long an empty bucket takes to refill.
+func (l *Limiter) Window() time.Duration {
+	if l.Rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return seconds(float64(l.Burst) / l.Rate)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// Memory is a Store in process memory.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	// full is when the bucket will have refilled.
+	full time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (m *Memory) Take(_ context.Context, key string, rate float64, burst int) (bool, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		m.sweep(now)
+	}
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), at: now}
+		m.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.at).Seconds()*rate)
+	b.at = now
+	taken := b.tokens >= 1
+	if taken {
+		b.tokens--
+	}
+	if rate > 0 {
+		b.full = now.Add(seconds((float64(burst) - b.tokens) / rate))
+	} else {
+		b.full = time.Time{}
+	}
+	return taken, b.tokens, nil
+}
+
+// sweep forgets buckets that have refilled, which behave like new ones.
+func (m *Memory) sweep(now time.Time) {
+	for k, b := range m.buckets {
+		if !b.full.IsZero() && !b.full.After(now) {
+			delete(m.buckets, k)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	l := PerMinute(3)
+	l.Store.(*Memory).now = func() time.Time { return now }
+
+	for i := range 3 {
+		ok, _ := l.Allow("ip:192.0.2.1")
+		require.True(t, ok, "call %d", i)
+	}
+	ok, wait := l.Allow("ip:192.0.2.1")
+	require.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Other callers have their own bucket.
+	ok, _ = l.Allow("api_key:abc")
+	assert.True(t, ok)
+
+	now = now.Add(20 * time.Second)
+	ok, _ = l.Allow("ip:192.0.2.1")
+	assert.True(t, ok)
+	ok, _ = l.Allow("ip:192.0.2.1")
+	assert.False(t, ok)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	l := PerMinute(60)
+	m := l.Store.(*Memory)
+	m.now = func() time.Time { return now }
+	l.Allow("a")
+	l.Allow("b")
+	now = now.Add(time.Second)
+	l.Allow("b")
+	l.Allow("b")
+
+	now = now.Add(time.Second)
+	m.sweep(now)
+	assert.NotContains(t, m.buckets, "a")
+	assert.Contains(t, m.buckets, "b")
+}
+
+func TestLimiter_Take(t *testing.T) {
+	now := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	l := PerMinute(2)
+	l.Store.(*Memory).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	type tc struct {
+		name    string
+		advance time.Duration
+		want    Result
+	}
+	cases := []tc{
+		{name: "first call", want: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}},
+		{name: "last token", want: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}},
+		{name: "empty", advance: 10 * time.Second, want: Result{Limit: 2, Remaining: 0, RetryAfter: 20 * time.Second, Reset: 50 * time.Second}},
+		{name: "refilled one", advance: 20 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}},
+	}
+	for _, c := range cases {
+		now = now.Add(c.advance)
+		got, err := l.Take(ctx, "ip:192.0.2.1")
+		require.NoError(t, err, c.name)
+		assert.Equal(t, c.want, got, c.name)
+	}
+	assert.Equal(t, time.Minute, l.Window())
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, float64, int) (bool, float64, error) {
+	return false, 0, errors.New("store down")
+}
+
+func TestLimiter_StoreFails(t *testing.T) {
+	l := &Limiter{Rate: 1, Burst: 1, Store: failingStore{}}
+	_, err := l.Take(context.Background(), "k")
+	require.Error(t, err)
+	// Allow lets calls through rather than failing them.
+	ok, _ := l.Allow("k")
+	assert.True(t, ok)
+}
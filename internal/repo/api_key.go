@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type APIKeyRepo struct{ q sqldb.Querier }
+
+func NewAPIKeyRepo(q sqldb.Querier) *APIKeyRepo { return &APIKeyRepo{q: q} }
+
+// Create stores a key for the context's store.
+func (r *APIKeyRepo) Create(ctx context.Context, k APIKey) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.InsertAPIKey(ctx, sqldb.InsertAPIKeyParams{
+		StoreID:   storeID,
+		ID:        k.ID,
+		Prefix:    k.Prefix,
+		Salt:      k.Salt,
+		Hash:      k.Hash,
+		Scopes:    k.Scopes,
+		Label:     k.Label,
+		ExpiresAt: k.ExpiresAt,
+	})
+}
+
+// GetByPrefix returns the key with prefix from any store. It is how a
+// presented key finds its store, so it is not scoped to one.
+func (r *APIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (APIKey, error) {
+	return oneRow(r.q.GetAPIKeyByPrefix(ctx, prefix))
+}
+
+func (r *APIKeyRepo) List(ctx context.Context) ([]APIKey, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListAPIKeys(ctx, storeID)
+}
+
+// Revoke disables the key at once and reports whether a live key was found.
+func (r *APIKeyRepo) Revoke(ctx context.Context, prefix string, at time.Time) (bool, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.RevokeAPIKey(ctx, sqldb.RevokeAPIKeyParams{
+		StoreID:   storeID,
+		Prefix:    prefix,
+		RevokedAt: sql.NullTime{Time: at, Valid: true},
+	})
+	return n > 0, err
+}
+
+// Expire brings the key's expiry forward to at, leaving an earlier expiry in
+// place. It reports whether a live key was found.
+func (r *APIKeyRepo) Expire(ctx context.Context, prefix string, at time.Time) (bool, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.ExpireAPIKey(ctx, sqldb.ExpireAPIKeyParams{StoreID: storeID, Prefix: prefix, ExpiresAt: at})
+	return n > 0, err
+}
+
+// Touch records that the key was used at at, unless that was already
+// recorded after staleBefore. Skipping fresh timestamps keeps busy keys from
+// writing on every request.
+func (r *APIKeyRepo) Touch(ctx context.Context, id string, at, staleBefore time.Time) error {
+	return r.q.TouchAPIKey(ctx, sqldb.TouchAPIKeyParams{
+		ID:          id,
+		UsedAt:      sql.NullTime{Time: at, Valid: true},
+		StaleBefore: sql.NullTime{Time: staleBefore, Valid: true},
+	})
+}
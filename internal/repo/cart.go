@@ -0,0 +1,138 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type CartRepo struct{ q sqldb.Querier }
+
+func NewCartRepo(q sqldb.Querier) *CartRepo { return &CartRepo{q: q} }
+
+// Create inserts an empty cart priced in currency.
+func (r *CartRepo) Create(ctx context.Context, id, currency string, expiresAt time.Time) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.InsertCart(ctx, sqldb.InsertCartParams{StoreID: storeID, ID: id, ExpiresAt: expiresAt, Currency: currency})
+}
+
+func (r *CartRepo) Get(ctx context.Context, id string) (Cart, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Cart{}, err
+	}
+	return oneRow(r.q.GetCart(ctx, sqldb.GetCartParams{StoreID: storeID, ID: id}))
+}
+
+func (r *CartRepo) Items(ctx context.Context, cartID string) ([]CartItem, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListCartItems(ctx, sqldb.ListCartItemsParams{StoreID: storeID, CartID: cartID})
+}
+
+// AddItem increments the quantity of productID in the cart, inserting it if absent.
+func (r *CartRepo) AddItem(ctx context.Context, cartID, productID string, qty int32) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.AddCartItem(ctx, sqldb.AddCartItemParams{StoreID: storeID, CartID: cartID, ProductID: productID, Quantity: qty})
+}
+
+// SetItem sets the quantity of productID in the cart, inserting it if absent.
+func (r *CartRepo) SetItem(ctx context.Context, cartID, productID string, qty int32) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.SetCartItem(ctx, sqldb.SetCartItemParams{StoreID: storeID, CartID: cartID, ProductID: productID, Quantity: qty})
+}
+
+// RemoveItem deletes productID from the cart and reports whether it was present.
+func (r *CartRepo) RemoveItem(ctx context.Context, cartID, productID string) (bool, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.DeleteCartItem(ctx, sqldb.DeleteCartItemParams{StoreID: storeID, CartID: cartID, ProductID: productID})
+	return n > 0, err
+}
+
+func (r *CartRepo) SetCoupon(ctx context.Context, cartID string, code sql.NullString) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.SetCartCoupon(ctx, sqldb.SetCartCouponParams{StoreID: storeID, ID: cartID, CouponCode: code})
+}
+
+// Touch extends the cart's expiry.
+func (r *CartRepo) Touch(ctx context.Context, cartID string, expiresAt time.Time) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.TouchCart(ctx, sqldb.TouchCartParams{StoreID: storeID, ID: cartID, ExpiresAt: expiresAt})
+}
+
+// ClaimCheckout marks the cart as checking out from startedAt. It returns
+// false when another checkout holds a claim taken at or after staleBefore or
+// the cart has been converted to an order; older claims are taken over.
+func (r *CartRepo) ClaimCheckout(ctx context.Context, cartID string, startedAt, staleBefore time.Time) (bool, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.ClaimCartCheckout(ctx, sqldb.ClaimCartCheckoutParams{
+		StoreID:     storeID,
+		ID:          cartID,
+		StartedAt:   sql.NullTime{Time: startedAt, Valid: true},
+		StaleBefore: staleBefore,
+	})
+	return n > 0, err
+}
+
+// ReleaseCheckout drops the claim ClaimCheckout took at startedAt after a
+// failed checkout. A claim another checkout has since taken over is kept.
+func (r *CartRepo) ReleaseCheckout(ctx context.Context, cartID string, startedAt time.Time) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.ReleaseCartCheckout(ctx, sqldb.ReleaseCartCheckoutParams{
+		StoreID:           storeID,
+		ID:                cartID,
+		CheckoutStartedAt: sql.NullTime{Time: startedAt, Valid: true},
+	})
+}
+
+// CompleteCheckout records the order the cart was converted into.
+func (r *CartRepo) CompleteCheckout(ctx context.Context, cartID, orderID string) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.CompleteCartCheckout(ctx, sqldb.CompleteCartCheckoutParams{
+		StoreID: storeID,
+		ID:      cartID,
+		OrderID: sql.NullString{String: orderID, Valid: true},
+	})
+}
+
+// DeleteExpired removes the store's carts that expired before now and were
+// never checked out.
+func (r *CartRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.DeleteExpiredCarts(ctx, sqldb.DeleteExpiredCartsParams{StoreID: storeID, ExpiresAt: now})
+}
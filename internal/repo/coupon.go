@@ -4,6 +4,7 @@ import (
 	"context"
 
 	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
 )
 
 type CouponRepo struct{ q sqldb.Querier }
@@ -11,7 +12,11 @@ type CouponRepo struct{ q sqldb.Querier }
 func NewCouponRepo(q sqldb.Querier) *CouponRepo { return &CouponRepo{q: q} }
 
 func (r *CouponRepo) Get(ctx context.Context, code string) (Coupon, error) {
-	c, err := r.q.GetCoupon(ctx, code)
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Coupon{}, err
+	}
+	c, err := oneRow(r.q.GetCoupon(ctx, sqldb.GetCouponParams{StoreID: storeID, Code: code}))
 	if err != nil {
 		return Coupon{}, err
 	}
@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound indicates the row a lookup or update named does not exist in
+// the context's store.
+var ErrNotFound = errors.New("not found")
+
+// oneRow translates sql.ErrNoRows from a single-row query into ErrNotFound,
+// so callers need not know the repositories are backed by database/sql.
+func oneRow[T any](v T, err error) (T, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return v, ErrNotFound
+	}
+	return v, err
+}
+
+// IsUnavailable reports whether err means the database could not serve the
+// query in time rather than that the query failed: every pooled connection
+// stayed busy until the deadline, the connection failed, or the server is
+// out of connections or shutting down. Retrying later may succeed.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || pgconn.Timeout(err) {
+		return true
+	}
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 53 is insufficient resources (53300 too_many_connections),
+		// 57P01-57P03 are the server shutting down or starting up.
+		return strings.HasPrefix(pgErr.Code, "53") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+	return false
+}
+
+// isUniqueViolation reports whether err is a unique violation of the named
+// constraint or index.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
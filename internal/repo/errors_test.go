@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUnavailable(t *testing.T) {
+	type tc struct {
+		name string
+		err  error
+		want bool
+	}
+	cases := []tc{
+		{name: "nil"},
+		{name: "not found", err: ErrNotFound},
+		{name: "constraint violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "deadline waiting for a connection", err: fmt.Errorf("get cart: %w", context.DeadlineExceeded), want: true},
+		{name: "bad connection", err: driver.ErrBadConn, want: true},
+		{name: "too many connections", err: &pgconn.PgError{Code: "53300"}, want: true},
+		{name: "server shutting down", err: &pgconn.PgError{Code: "57P01"}, want: true},
+		{name: "cannot connect", err: &pgconn.ConnectError{}, want: true},
+		{name: "canceled by the client", err: context.Canceled},
+		{name: "other", err: errors.New("syntax error")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, IsUnavailable(c.err))
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+	"kart/internal/tracing"
+)
+
+// ErrGiftCardBalance indicates a gift card no longer holds what an order
+// planned to take from it, usually because another order spent it first.
+var ErrGiftCardBalance = errors.New("gift card balance changed")
+
+// Gift card transaction kinds.
+const (
+	GiftCardIssue   = "issue"
+	GiftCardRedeem  = "redeem"
+	GiftCardRestore = "restore"
+)
+
+// GiftCardDebit is an amount an order takes from a gift card.
+type GiftCardDebit struct {
+	GiftCardID  string
+	AmountCents int64
+}
+
+type GiftCardRepo struct{ db *sql.DB }
+
+func NewGiftCardRepo(db *sql.DB) *GiftCardRepo { return &GiftCardRepo{db: db} }
+
+// Create stores a new card with its full balance and records the issue.
+func (r *GiftCardRepo) Create(ctx context.Context, c GiftCard) (err error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := sqldb.New(tracing.DB(tx))
+	if err = q.InsertGiftCard(ctx, sqldb.InsertGiftCardParams{
+		ID:           c.ID,
+		StoreID:      storeID,
+		CodeHash:     c.CodeHash,
+		Last4:        c.Last4,
+		Currency:     c.Currency,
+		InitialCents: c.InitialCents,
+		ExpiresAt:    c.ExpiresAt,
+	}); err != nil {
+		return err
+	}
+	if err = q.InsertGiftCardTransaction(ctx, sqldb.InsertGiftCardTransactionParams{
+		ID:          uuid.NewString(),
+		StoreID:     storeID,
+		GiftCardID:  c.ID,
+		Kind:        GiftCardIssue,
+		AmountCents: c.InitialCents,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *GiftCardRepo) Get(ctx context.Context, id string) (GiftCard, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return GiftCard{}, err
+	}
+	return oneRow(sqldb.New(tracing.DB(r.db)).GetGiftCard(ctx, sqldb.GetGiftCardParams{StoreID: storeID, ID: id}))
+}
+
+// GetByCode looks a card up by the digest of its code.
+func (r *GiftCardRepo) GetByCode(ctx context.Context, codeHash []byte) (GiftCard, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return GiftCard{}, err
+	}
+	return oneRow(sqldb.New(tracing.DB(r.db)).GetGiftCardByCode(ctx, sqldb.GetGiftCardByCodeParams{StoreID: storeID, CodeHash: codeHash}))
+}
+
+// Transactions returns the card's history, oldest first.
+func (r *GiftCardRepo) Transactions(ctx context.Context, id string) ([]GiftCardTransaction, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListGiftCardTransactions(ctx, sqldb.ListGiftCardTransactionsParams{StoreID: storeID, GiftCardID: id})
+}
+
+// debitGiftCards takes each debit off its card and records it against the
+// order. The guarded update locks the card row, so concurrent orders cannot
+// together take more than it holds.
+func debitGiftCards(ctx context.Context, q sqldb.Querier, storeID, orderID string, debits []GiftCardDebit) error {
+	for _, d := range debits {
+		n, err := q.DebitGiftCard(ctx, sqldb.DebitGiftCardParams{StoreID: storeID, ID: d.GiftCardID, AmountCents: d.AmountCents})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrGiftCardBalance
+		}
+		if err := q.InsertGiftCardTransaction(ctx, sqldb.InsertGiftCardTransactionParams{
+			ID:          uuid.NewString(),
+			StoreID:     storeID,
+			GiftCardID:  d.GiftCardID,
+			OrderID:     sql.NullString{String: orderID, Valid: true},
+			Kind:        GiftCardRedeem,
+			AmountCents: -d.AmountCents,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
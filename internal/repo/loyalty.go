@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+// ErrInsufficientPoints indicates a customer's loyalty balance cannot cover
+// the points an order tries to redeem.
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+// Loyalty entry kinds.
+const (
+	LoyaltyEarn    = "earn"
+	LoyaltyRedeem  = "redeem"
+	LoyaltyRestore = "restore"
+	LoyaltyReverse = "reverse"
+)
+
+// DefaultLoyaltyCategory is the rule category applied to products whose
+// category has no rule of its own.
+const DefaultLoyaltyCategory = "*"
+
+type LoyaltyRepo struct{ q sqldb.Querier }
+
+func NewLoyaltyRepo(q sqldb.Querier) *LoyaltyRepo { return &LoyaltyRepo{q: q} }
+
+func (r *LoyaltyRepo) Rules(ctx context.Context) (map[string]int32, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListLoyaltyRules(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		out[row.Category] = row.PointsPerUnit
+	}
+	return out, nil
+}
+
+// Balance returns the customer's points balance, zero if they have never
+// earned any.
+func (r *LoyaltyRepo) Balance(ctx context.Context, customerID string) (int64, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	b, err := r.q.GetLoyaltyBalance(ctx, sqldb.GetLoyaltyBalanceParams{StoreID: storeID, CustomerID: customerID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return b, err
+}
+
+// Entries returns up to limit of the customer's ledger entries, newest first.
+func (r *LoyaltyRepo) Entries(ctx context.Context, customerID string, limit int32) ([]LoyaltyEntry, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListLoyaltyEntries(ctx, sqldb.ListLoyaltyEntriesParams{StoreID: storeID, CustomerID: customerID, Limit: limit})
+}
+
+// OrderEntries returns the ledger entries posted for an order, oldest first.
+func (r *LoyaltyRepo) OrderEntries(ctx context.Context, orderID string) ([]LoyaltyEntry, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListOrderLoyaltyEntries(ctx, sqldb.ListOrderLoyaltyEntriesParams{StoreID: storeID, OrderID: sql.NullString{String: orderID, Valid: true}})
+}
+
+// Post appends e to the ledger and applies it to the customer's balance. It
+// reports false, changing nothing, if an entry with e's key already exists.
+func (r *LoyaltyRepo) Post(ctx context.Context, e LoyaltyEntry) (bool, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return postLoyaltyEntry(ctx, r.q, storeID, e)
+}
+
+func postLoyaltyEntry(ctx context.Context, q sqldb.Querier, storeID string, e LoyaltyEntry) (bool, error) {
+	if e.ID == "" {
+		e.ID = uuid.NewString()
+	}
+	n, err := q.PostLoyaltyEntry(ctx, sqldb.PostLoyaltyEntryParams{
+		ID:         e.ID,
+		StoreID:    storeID,
+		CustomerID: e.CustomerID,
+		OrderID:    e.OrderID,
+		Kind:       e.Kind,
+		Points:     e.Points,
+		Key:        e.Key,
+	})
+	return n > 0, err
+}
+
+// redeemLoyaltyPoints debits the order's redeemed points from the customer's
+// balance and records the redemption. The guarded debit locks the balance
+// row, so concurrent orders cannot together spend more than it holds.
+func redeemLoyaltyPoints(ctx context.Context, q sqldb.Querier, storeID string, o Order) error {
+	n, err := q.DebitLoyaltyBalance(ctx, sqldb.DebitLoyaltyBalanceParams{StoreID: storeID, CustomerID: o.CustomerID.String, Points: o.PointsRedeemed})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInsufficientPoints
+	}
+	return q.InsertLoyaltyEntry(ctx, sqldb.InsertLoyaltyEntryParams{
+		ID:         uuid.NewString(),
+		StoreID:    storeID,
+		CustomerID: o.CustomerID.String,
+		OrderID:    sql.NullString{String: o.ID, Valid: true},
+		Kind:       LoyaltyRedeem,
+		Points:     -o.PointsRedeemed,
+		Key:        LoyaltyRedeem + ":" + o.ID,
+	})
+}
+
+// RestoreEntry is the entry returning an order's redeemed points to the
+// customer when the order does not go ahead or is refunded in full.
+func RestoreEntry(o Order) LoyaltyEntry {
+	return LoyaltyEntry{
+		CustomerID: o.CustomerID.String,
+		OrderID:    sql.NullString{String: o.ID, Valid: true},
+		Kind:       LoyaltyRestore,
+		Points:     o.PointsRedeemed,
+		Key:        LoyaltyRestore + ":" + o.ID,
+	}
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlcmock "kart/internal/mocks/sqlc"
+	"kart/internal/sqlc"
+	"kart/internal/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyRepo_Balance(t *testing.T) {
+	type tc struct {
+		name  string
+		setup func(m *sqlcmock.Querier)
+		want  int64
+	}
+	cases := []tc{
+		{
+			name: "existing balance",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetLoyaltyBalance", mock.Anything, sqlc.GetLoyaltyBalanceParams{StoreID: "default", CustomerID: "cust_1"}).Return(int64(420), nil)
+			},
+			want: 420,
+		},
+		{
+			name: "never earned",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetLoyaltyBalance", mock.Anything, mock.Anything).Return(int64(0), sql.ErrNoRows)
+			},
+			want: 0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sqlcmock.NewQuerier(t)
+			c.setup(m)
+			got, err := NewLoyaltyRepo(m).Balance(tenant.WithStore(context.Background(), "default"), "cust_1")
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestLoyaltyRepo_Post(t *testing.T) {
+	e := LoyaltyEntry{
+		CustomerID: "cust_1",
+		OrderID:    sql.NullString{String: "o1", Valid: true},
+		Kind:       LoyaltyEarn,
+		Points:     35,
+		Key:        "earn:o1",
+	}
+	type tc struct {
+		name string
+		rows int64
+		want bool
+	}
+	cases := []tc{
+		{name: "posted", rows: 1, want: true},
+		{name: "already posted", rows: 0, want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sqlcmock.NewQuerier(t)
+			m.On("PostLoyaltyEntry", mock.Anything, mock.MatchedBy(func(p sqlc.PostLoyaltyEntryParams) bool {
+				return p.ID != "" && p.StoreID == "default" && p.Key == "earn:o1" && p.Points == 35
+			})).Return(c.rows, nil)
+			got, err := NewLoyaltyRepo(m).Post(tenant.WithStore(context.Background(), "default"), e)
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestLoyaltyRepo_RequiresStore(t *testing.T) {
+	_, err := NewLoyaltyRepo(sqlcmock.NewQuerier(t)).Balance(context.Background(), "cust_1")
+	require.ErrorIs(t, err, tenant.ErrNoStore)
+}
@@ -8,16 +8,38 @@ import (
 	"github.com/google/uuid"
 
 	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+	"kart/internal/tracing"
 )
 
 // ErrCouponRedeemed indicates a single-use coupon has already been redeemed.
 var ErrCouponRedeemed = errors.New("coupon redeemed")
 
+// ErrStatusChanged indicates an order was not in the status an update
+// expected, because it has been moved on since it was read.
+var ErrStatusChanged = errors.New("order status changed")
+
+// ErrCartOrdered indicates the cart an order is checked out from already has
+// an order that has not failed payment.
+var ErrCartOrdered = errors.New("cart already ordered")
+
 type OrderRepo struct{ db *sql.DB }
 
 func NewOrderRepo(db *sql.DB) *OrderRepo { return &OrderRepo{db: db} }
 
-func (r *OrderRepo) CreateWithItems(ctx context.Context, o Order, items []OrderItem) (string, error) {
+// CreateWithItems inserts the order with its lines and tax breakdown in one
+// transaction, redeeming a single-use coupon if the order carries one. An
+// order for a customer creates or updates their customer record, keeping the
+// contact details the order was placed with, and spends any loyalty points
+// the order redeems, failing with ErrInsufficientPoints if the balance falls
+// short. Each gift card debit is taken off its card, failing with
+// ErrGiftCardBalance if the card no longer holds it. An order checked out
+// from a cart fails with ErrCartOrdered if the cart already has a live order.
+func (r *OrderRepo) CreateWithItems(ctx context.Context, o Order, items []OrderItem, taxes []OrderTax, giftCards ...GiftCardDebit) (string, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return "", err
+	}
 	if o.ID == "" {
 		o.ID = uuid.NewString()
 	}
@@ -31,11 +53,22 @@ func (r *OrderRepo) CreateWithItems(ctx context.Context, o Order, items []OrderI
 		}
 	}()
 
-	q := sqldb.New(tx)
+	q := sqldb.New(tracing.DB(tx))
+
+	if o.CustomerID.Valid {
+		if err = q.UpsertCustomer(ctx, sqldb.UpsertCustomerParams{
+			StoreID: storeID,
+			ID:      o.CustomerID.String,
+			Email:   o.ContactEmail,
+			Phone:   o.ContactPhone,
+		}); err != nil {
+			return "", err
+		}
+	}
 
 	// Single-use coupon redemption within the same transaction
 	if o.CouponCode.Valid {
-		if _, err := q.TryRedeemSingleUse(ctx, o.CouponCode.String); err != nil {
+		if _, err := q.TryRedeemSingleUse(ctx, sqldb.TryRedeemSingleUseParams{StoreID: storeID, Code: o.CouponCode.String, CustomerID: o.CustomerID}); err != nil {
 			// sqlc returns sql.ErrNoRows when ON CONFLICT DO NOTHING prevented insert
 			if err == sql.ErrNoRows {
 				return "", ErrCouponRedeemed
@@ -44,32 +77,82 @@ func (r *OrderRepo) CreateWithItems(ctx context.Context, o Order, items []OrderI
 		}
 	}
 	err = q.InsertOrder(ctx, sqldb.InsertOrderParams{
-		ID:         o.ID,
-		CouponCode: o.CouponCode,
+		StoreID:        storeID,
+		ID:             o.ID,
+		CouponCode:     o.CouponCode,
+		Status:         o.Status,
+		TotalCents:     o.TotalCents,
+		DiscountCents:  o.DiscountCents,
+		SubtotalCents:  o.SubtotalCents,
+		TaxCents:       o.TaxCents,
+		TaxInclusive:   o.TaxInclusive,
+		Currency:       o.Currency,
+		PriceListID:    o.PriceListID,
+		CustomerID:     o.CustomerID,
+		ContactEmail:   o.ContactEmail,
+		ContactPhone:   o.ContactPhone,
+		PointsRedeemed: o.PointsRedeemed,
+		PointsCents:    o.PointsCents,
+		GiftCardCents:  o.GiftCardCents,
+		CartID:         o.CartID,
 	})
 	if err != nil {
+		if isUniqueViolation(err, "orders_cart_live_key") {
+			err = ErrCartOrdered
+		}
+		return "", err
+	}
+	if o.PointsRedeemed > 0 {
+		if err = redeemLoyaltyPoints(ctx, q, storeID, o); err != nil {
+			return "", err
+		}
+	}
+	if err = debitGiftCards(ctx, q, storeID, o.ID, giftCards); err != nil {
 		return "", err
 	}
 	if len(items) > 0 {
 		ids := make([]string, len(items))
-		orderIDs := make([]string, len(items))
 		productIDs := make([]string, len(items))
 		quantities := make([]int32, len(items))
+		prices := make([]int64, len(items))
+		taxClasses := make([]string, len(items))
+		taxCents := make([]int64, len(items))
+		discounts := make([]int64, len(items))
 		for i := range items {
 			if items[i].ID == "" {
 				items[i].ID = uuid.NewString()
 			}
 			items[i].OrderID = o.ID
 			ids[i] = items[i].ID
-			orderIDs[i] = items[i].OrderID
 			productIDs[i] = items[i].ProductID
 			quantities[i] = items[i].Quantity
+			prices[i] = items[i].UnitPriceCents
+			taxClasses[i] = items[i].TaxClass.String
+			taxCents[i] = items[i].TaxCents
+			discounts[i] = items[i].DiscountCents
 		}
 		if err = q.InsertOrderItems(ctx, sqldb.InsertOrderItemsParams{
-			Column1: ids,
-			Column2: orderIDs,
-			Column3: productIDs,
-			Column4: quantities,
+			StoreID:       storeID,
+			OrderID:       o.ID,
+			Ids:           ids,
+			ProductIds:    productIDs,
+			Quantities:    quantities,
+			UnitPrices:    prices,
+			TaxClasses:    taxClasses,
+			TaxCents:      taxCents,
+			DiscountCents: discounts,
+		}); err != nil {
+			return "", err
+		}
+	}
+	for _, t := range taxes {
+		if err = q.InsertOrderTax(ctx, sqldb.InsertOrderTaxParams{
+			StoreID:         storeID,
+			OrderID:         o.ID,
+			TaxClass:        t.TaxClass,
+			RateBasisPoints: t.RateBasisPoints,
+			TaxableCents:    t.TaxableCents,
+			TaxCents:        t.TaxCents,
 		}); err != nil {
 			return "", err
 		}
@@ -79,3 +162,148 @@ func (r *OrderRepo) CreateWithItems(ctx context.Context, o Order, items []OrderI
 	}
 	return o.ID, nil
 }
+
+func (r *OrderRepo) Get(ctx context.Context, id string) (Order, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Order{}, err
+	}
+	return oneRow(sqldb.New(tracing.DB(r.db)).GetOrder(ctx, sqldb.GetOrderParams{StoreID: storeID, ID: id}))
+}
+
+// ListByCustomer returns up to limit of the customer's orders, newest first.
+func (r *OrderRepo) ListByCustomer(ctx context.Context, customerID string, limit int32) ([]Order, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListCustomerOrders(ctx, sqldb.ListCustomerOrdersParams{StoreID: storeID, CustomerID: sql.NullString{String: customerID, Valid: true}, Limit: limit})
+}
+
+// Items returns the order's lines in the order they were placed.
+func (r *OrderRepo) Items(ctx context.Context, orderID string) ([]OrderItem, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListOrderItems(ctx, sqldb.ListOrderItemsParams{StoreID: storeID, OrderID: orderID})
+}
+
+// Taxes returns the tax collected on the order per tax class.
+func (r *OrderRepo) Taxes(ctx context.Context, orderID string) ([]OrderTax, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListOrderTaxes(ctx, sqldb.ListOrderTaxesParams{StoreID: storeID, OrderID: orderID})
+}
+
+// UpdateStatus moves the order from status from to status and sets its ETA,
+// returning the updated row and posting the given loyalty entries in the same
+// transaction. Entries already in the ledger are skipped. It returns
+// ErrStatusChanged if the order is no longer in status from.
+func (r *OrderRepo) UpdateStatus(ctx context.Context, id, from, status string, eta sql.NullTime, entries ...LoyaltyEntry) (Order, error) {
+	return r.updateStatus(ctx, id, from, status, eta, false, entries)
+}
+
+// Reverse moves the order like UpdateStatus and, in the same transaction,
+// credits back the gift card balance it spent, for orders cancelled or
+// refunded in full. Cards already restored for the order are skipped.
+func (r *OrderRepo) Reverse(ctx context.Context, id, from, status string, eta sql.NullTime, entries ...LoyaltyEntry) (Order, error) {
+	return r.updateStatus(ctx, id, from, status, eta, true, entries)
+}
+
+func (r *OrderRepo) updateStatus(ctx context.Context, id, from, status string, eta sql.NullTime, restoreGiftCards bool, entries []LoyaltyEntry) (o Order, err error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Order{}, err
+	}
+	params := sqldb.UpdateOrderStatusParams{
+		StoreID:    storeID,
+		ID:         id,
+		FromStatus: from,
+		Status:     status,
+		EtaAt:      eta,
+	}
+	if len(entries) == 0 && !restoreGiftCards {
+		return updateOrderStatus(ctx, sqldb.New(tracing.DB(r.db)), params)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Order{}, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := sqldb.New(tracing.DB(tx))
+	if o, err = updateOrderStatus(ctx, q, params); err != nil {
+		return Order{}, err
+	}
+	for _, e := range entries {
+		if _, err = postLoyaltyEntry(ctx, q, storeID, e); err != nil {
+			return Order{}, err
+		}
+	}
+	if restoreGiftCards && o.GiftCardCents > 0 {
+		if _, err = q.RestoreOrderGiftCards(ctx, sqldb.RestoreOrderGiftCardsParams{StoreID: storeID, OrderID: sql.NullString{String: o.ID, Valid: true}}); err != nil {
+			return Order{}, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return Order{}, err
+	}
+	return o, nil
+}
+
+func updateOrderStatus(ctx context.Context, q *sqldb.Queries, params sqldb.UpdateOrderStatusParams) (Order, error) {
+	o, err := q.UpdateOrderStatus(ctx, params)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Order{}, ErrStatusChanged
+	}
+	return o, err
+}
+
+// FailPayment moves an unpaid order from status from to status and releases
+// its coupon redemption so the code can be used again, returning any loyalty
+// points and gift card balance it spent. It returns ErrStatusChanged if the
+// order is no longer in status from.
+func (r *OrderRepo) FailPayment(ctx context.Context, id, from, status string) (err error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := sqldb.New(tracing.DB(tx))
+	o, err := updateOrderStatus(ctx, q, sqldb.UpdateOrderStatusParams{StoreID: storeID, ID: id, FromStatus: from, Status: status})
+	if err != nil {
+		return err
+	}
+	if o.CouponCode.Valid {
+		if err = q.ReleaseRedemption(ctx, sqldb.ReleaseRedemptionParams{StoreID: storeID, Code: o.CouponCode.String}); err != nil {
+			return err
+		}
+	}
+	if o.PointsRedeemed > 0 {
+		if _, err = postLoyaltyEntry(ctx, q, storeID, RestoreEntry(o)); err != nil {
+			return err
+		}
+	}
+	if o.GiftCardCents > 0 {
+		if _, err = q.RestoreOrderGiftCards(ctx, sqldb.RestoreOrderGiftCardsParams{StoreID: storeID, OrderID: sql.NullString{String: o.ID, Valid: true}}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
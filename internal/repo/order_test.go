@@ -2,55 +2,188 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"regexp"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"kart/internal/tenant"
 )
 
+var orderColumns = []string{"id", "coupon_code", "created_at", "updated_at", "status", "eta_at", "total_cents", "discount_cents", "refunded_cents", "subtotal_cents", "tax_cents", "tax_inclusive", "currency", "price_list_id", "store_id", "customer_id", "contact_email", "contact_phone", "points_redeemed", "points_cents", "gift_card_cents", "cart_id"}
+
+const insertOrderSQL = `INSERT INTO orders (store_id, id, coupon_code, status, total_cents, discount_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+
 func TestOrderRepo_CreateWithItems(t *testing.T) {
 	type tc struct {
 		name              string
 		buildExpectations func(mock sqlmock.Sqlmock)
 		order             Order
 		items             []OrderItem
-		wantErr           bool
+		taxes             []OrderTax
+		giftCards         []GiftCardDebit
+		wantErr           error
 	}
 	cases := []tc{
 		{
 			name: "success two items",
 			buildExpectations: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO orders (id, coupon_code) VALUES ($1, $2)`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", sqlmock.AnyArg(), sqlmock.AnyArg(), "placed", int64(3500), int64(0), int64(3500), int64(318), true, "AUD", sqlmock.AnyArg(), nil, nil, nil, int64(0), int64(0), int64(0), nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_items (id, order_id, product_id, quantity)
-SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::int4[])`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_items (store_id, id, order_id, product_id, quantity, unit_price_cents, tax_class, tax_cents, discount_cents)`)).
+					WithArgs("s1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_taxes`)).
+					WithArgs("standard", int32(1000), int64(3500), int64(318), "s1", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
 			},
-			order: Order{},
+			order: Order{Status: "placed", TotalCents: 3500, SubtotalCents: 3500, TaxCents: 318, TaxInclusive: true, Currency: "AUD"},
 			items: []OrderItem{{ProductID: "10", Quantity: 1}, {ProductID: "11", Quantity: 1}},
+			taxes: []OrderTax{{TaxClass: "standard", RateBasisPoints: 1000, TaxableCents: 3500, TaxCents: 318}},
+		},
+		{
+			name: "customer order",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO customers (store_id, id, email, phone)`)).
+					WithArgs("s1", "cust_1", "jo@example.com", nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO coupon_redemptions (store_id, code, customer_id)`)).
+					WithArgs("s1", "HAPPYHRS", "cust_1").
+					WillReturnRows(sqlmock.NewRows([]string{"code"}).AddRow("HAPPYHRS"))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", sqlmock.AnyArg(), "HAPPYHRS", "placed", int64(1000), int64(0), int64(1000), int64(91), true, "AUD", sqlmock.AnyArg(), "cust_1", "jo@example.com", nil, int64(0), int64(0), int64(0), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			order: Order{
+				Status: "placed", TotalCents: 1000, SubtotalCents: 1000, TaxCents: 91, TaxInclusive: true, Currency: "AUD",
+				CouponCode:   sql.NullString{String: "HAPPYHRS", Valid: true},
+				CustomerID:   sql.NullString{String: "cust_1", Valid: true},
+				ContactEmail: sql.NullString{String: "jo@example.com", Valid: true},
+			},
+		},
+		{
+			name: "redeems points",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO customers (store_id, id, email, phone)`)).
+					WithArgs("s1", "cust_1", nil, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", "o1", nil, "placed", int64(1000), int64(0), int64(1000), int64(91), true, "AUD", sqlmock.AnyArg(), "cust_1", nil, nil, int64(250), int64(250), int64(0), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE loyalty_balances`)).
+					WithArgs(int64(250), "s1", "cust_1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO loyalty_entries`)).
+					WithArgs(sqlmock.AnyArg(), "s1", "cust_1", "o1", "redeem", int64(-250), "redeem:o1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			order: Order{
+				ID: "o1", Status: "placed", TotalCents: 1000, SubtotalCents: 1000, TaxCents: 91, TaxInclusive: true, Currency: "AUD",
+				CustomerID:     sql.NullString{String: "cust_1", Valid: true},
+				PointsRedeemed: 250, PointsCents: 250,
+			},
+		},
+		{
+			name: "insufficient points",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO customers (store_id, id, email, phone)`)).
+					WithArgs("s1", "cust_1", nil, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE loyalty_balances`)).
+					WithArgs(int64(250), "s1", "cust_1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			order: Order{
+				ID: "o1", Status: "placed", TotalCents: 1000, SubtotalCents: 1000, Currency: "AUD",
+				CustomerID:     sql.NullString{String: "cust_1", Valid: true},
+				PointsRedeemed: 250, PointsCents: 250,
+			},
+			wantErr: ErrInsufficientPoints,
+		},
+		{
+			name: "debits gift cards",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", "o1", nil, "placed", int64(1000), int64(0), int64(1000), int64(91), true, "AUD", sqlmock.AnyArg(), nil, nil, nil, int64(0), int64(0), int64(1000), nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				for _, d := range []struct {
+					id     string
+					amount int64
+				}{{"gc1", 700}, {"gc2", 300}} {
+					mock.ExpectExec(regexp.QuoteMeta(`UPDATE gift_cards SET balance_cents = balance_cents - $1::int8`)).
+						WithArgs(d.amount, "s1", d.id).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO gift_card_transactions`)).
+						WithArgs(sqlmock.AnyArg(), "s1", d.id, "o1", "redeem", -d.amount).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+				}
+				mock.ExpectCommit()
+			},
+			order:     Order{ID: "o1", Status: "placed", TotalCents: 1000, SubtotalCents: 1000, TaxCents: 91, TaxInclusive: true, Currency: "AUD", GiftCardCents: 1000},
+			giftCards: []GiftCardDebit{{GiftCardID: "gc1", AmountCents: 700}, {GiftCardID: "gc2", AmountCents: 300}},
+		},
+		{
+			name: "gift card spent elsewhere",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE gift_cards`)).
+					WithArgs(int64(700), "s1", "gc1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			order:     Order{ID: "o1", Status: "placed", TotalCents: 1000, SubtotalCents: 1000, Currency: "AUD", GiftCardCents: 700},
+			giftCards: []GiftCardDebit{{GiftCardID: "gc1", AmountCents: 700}},
+			wantErr:   ErrGiftCardBalance,
+		},
+		{
+			name: "cart already ordered",
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", "o2", nil, "pending_payment", int64(1000), int64(0), int64(1000), int64(91), true, "AUD", sqlmock.AnyArg(), nil, nil, nil, int64(0), int64(0), int64(0), "c1").
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "orders_cart_live_key"})
+				mock.ExpectRollback()
+			},
+			order: Order{
+				ID: "o2", Status: "pending_payment", TotalCents: 1000, SubtotalCents: 1000, TaxCents: 91, TaxInclusive: true, Currency: "AUD",
+				CartID: sql.NullString{String: "c1", Valid: true},
+			},
+			wantErr: ErrCartOrdered,
 		},
 		{
 			name: "rollback on first item error",
 			buildExpectations: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO orders (id, coupon_code) VALUES ($1, $2)`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+				mock.ExpectExec(regexp.QuoteMeta(insertOrderSQL)).
+					WithArgs("s1", sqlmock.AnyArg(), sqlmock.AnyArg(), "placed", int64(3500), int64(0), int64(3500), int64(318), true, "AUD", sqlmock.AnyArg(), nil, nil, nil, int64(0), int64(0), int64(0), nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
-				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_items (id, order_id, product_id, quantity)
-SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::int4[])`)).
-					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO order_items (store_id, id, order_id, product_id, quantity, unit_price_cents, tax_class, tax_cents, discount_cents)`)).
+					WithArgs("s1", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnError(assert.AnError)
 				mock.ExpectRollback()
 			},
-			order:   Order{},
+			order:   Order{Status: "placed", TotalCents: 3500, SubtotalCents: 3500, TaxCents: 318, TaxInclusive: true, Currency: "AUD"},
 			items:   []OrderItem{{ProductID: "10", Quantity: 0}},
-			wantErr: true,
+			wantErr: assert.AnError,
 		},
 	}
 	for _, c := range cases {
@@ -60,9 +193,9 @@ SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::in
 			defer db.Close()
 			r := NewOrderRepo(db)
 			c.buildExpectations(mock)
-			_, err = r.CreateWithItems(context.Background(), c.order, c.items)
-			if c.wantErr {
-				require.Error(t, err)
+			_, err = r.CreateWithItems(tenant.WithStore(context.Background(), "s1"), c.order, c.items, c.taxes, c.giftCards...)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
 			} else {
 				require.NoError(t, err)
 			}
@@ -70,3 +203,133 @@ SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::in
 		})
 	}
 }
+
+func TestOrderRepo_UpdateStatus(t *testing.T) {
+	type tc struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantErr error
+	}
+	cases := []tc{
+		{
+			name: "moves the order on",
+			rows: sqlmock.NewRows(orderColumns).AddRow("o1", nil, time.Now(), time.Now(), "ready", nil, 100, 0, 0, 100, 9, true, "AUD", nil, "s1", nil, nil, nil, 0, 0, 0, nil),
+		},
+		{name: "order moved on since read", rows: sqlmock.NewRows(orderColumns), wantErr: ErrStatusChanged},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(regexp.QuoteMeta(`UPDATE orders`)).
+				WithArgs("ready", sql.NullTime{}, "s1", "o1", "preparing").
+				WillReturnRows(c.rows)
+
+			o, err := NewOrderRepo(db).UpdateStatus(tenant.WithStore(context.Background(), "s1"), "o1", "preparing", "ready", sql.NullTime{})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "ready", o.Status)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrderRepo_Reverse(t *testing.T) {
+	type tc struct {
+		name       string
+		giftCards  int64
+		restoreErr error
+		wantErr    error
+	}
+	cases := []tc{
+		{name: "restores gift cards with the cancel", giftCards: 1000},
+		{name: "nothing to restore", giftCards: 0},
+		{name: "failed restore rolls back the cancel", giftCards: 1000, restoreErr: assert.AnError, wantErr: assert.AnError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`UPDATE orders`)).
+				WithArgs("cancelled", sql.NullTime{}, "s1", "o1", "placed").
+				WillReturnRows(sqlmock.NewRows(orderColumns).AddRow("o1", nil, time.Now(), time.Now(), "cancelled", nil, 100, 0, 0, 100, 9, true, "AUD", nil, "s1", nil, nil, nil, 0, 0, c.giftCards, nil))
+			if c.giftCards > 0 {
+				restore := mock.ExpectExec(regexp.QuoteMeta(`WITH r AS (`)).WithArgs("s1", "o1")
+				if c.restoreErr != nil {
+					restore.WillReturnError(c.restoreErr)
+				} else {
+					restore.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+			if c.wantErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			_, err = NewOrderRepo(db).Reverse(tenant.WithStore(context.Background(), "s1"), "o1", "placed", "cancelled", sql.NullTime{})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrderRepo_FailPayment(t *testing.T) {
+	cols := orderColumns
+	type tc struct {
+		name      string
+		coupon    any
+		customer  any
+		points    int64
+		giftCards int64
+	}
+	cases := []tc{
+		{name: "releases coupon", coupon: "HAPPYHRS"},
+		{name: "no coupon", coupon: nil},
+		{name: "restores points", customer: "cust_1", points: 250},
+		{name: "restores gift cards", giftCards: 100},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`UPDATE orders`)).
+				WithArgs("payment_failed", nil, "s1", "o1", "pending_payment").
+				WillReturnRows(sqlmock.NewRows(cols).AddRow("o1", c.coupon, time.Now(), time.Now(), "payment_failed", nil, 100, 0, 0, 100, 9, true, "AUD", nil, "s1", c.customer, nil, nil, c.points, c.points, c.giftCards, nil))
+			if c.coupon != nil {
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM coupon_redemptions WHERE store_id = $1 AND code = $2`)).
+					WithArgs("s1", c.coupon).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			if c.giftCards > 0 {
+				mock.ExpectExec(regexp.QuoteMeta(`WITH r AS (`)).
+					WithArgs("s1", "o1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			if c.points > 0 {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO loyalty_entries`)).
+					WithArgs(sqlmock.AnyArg(), "s1", c.customer, "o1", "restore", c.points, "restore:o1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+
+			require.NoError(t, NewOrderRepo(db).FailPayment(tenant.WithStore(context.Background(), "s1"), "o1", "pending_payment", "payment_failed"))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
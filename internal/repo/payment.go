@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type PaymentRepo struct{ q sqldb.Querier }
+
+func NewPaymentRepo(q sqldb.Querier) *PaymentRepo { return &PaymentRepo{q: q} }
+
+func (r *PaymentRepo) Create(ctx context.Context, p Payment) error {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.InsertPayment(ctx, sqldb.InsertPaymentParams{
+		StoreID:     storeID,
+		ID:          p.ID,
+		OrderID:     p.OrderID,
+		Provider:    p.Provider,
+		Status:      p.Status,
+		AmountCents: p.AmountCents,
+		Currency:    p.Currency,
+	})
+}
+
+// Update persists the provider reference, status, captured amount and failure reason of p.
+func (r *PaymentRepo) Update(ctx context.Context, p Payment) (Payment, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Payment{}, err
+	}
+	return oneRow(r.q.UpdatePayment(ctx, sqldb.UpdatePaymentParams{
+		StoreID:       storeID,
+		ID:            p.ID,
+		ProviderRef:   p.ProviderRef,
+		Status:        p.Status,
+		CapturedCents: p.CapturedCents,
+		FailureReason: p.FailureReason,
+	}))
+}
+
+// GetByOrder returns the most recent payment attempt for the order.
+func (r *PaymentRepo) GetByOrder(ctx context.Context, orderID string) (Payment, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Payment{}, err
+	}
+	return oneRow(r.q.GetPaymentByOrder(ctx, sqldb.GetPaymentByOrderParams{StoreID: storeID, OrderID: orderID}))
+}
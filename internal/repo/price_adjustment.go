@@ -0,0 +1,21 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type PriceAdjustmentRepo struct{ q sqldb.Querier }
+
+func NewPriceAdjustmentRepo(q sqldb.Querier) *PriceAdjustmentRepo { return &PriceAdjustmentRepo{q: q} }
+
+func (r *PriceAdjustmentRepo) Scheduled(ctx context.Context, at time.Time) ([]PriceAdjustment, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListPriceAdjustments(ctx, sqldb.ListPriceAdjustmentsParams{StoreID: storeID, At: at.UTC()})
+}
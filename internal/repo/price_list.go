@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type PriceListRepo struct{ q sqldb.Querier }
+
+func NewPriceListRepo(q sqldb.Querier) *PriceListRepo { return &PriceListRepo{q: q} }
+
+// Get returns the store's price list in currency, or its default price list
+// when currency is empty.
+func (r *PriceListRepo) Get(ctx context.Context, currency string) (PriceList, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return PriceList{}, err
+	}
+	if currency == "" {
+		return oneRow(r.q.GetDefaultPriceList(ctx, storeID))
+	}
+	return oneRow(r.q.GetPriceList(ctx, sqldb.GetPriceListParams{StoreID: storeID, Currency: currency}))
+}
+
+// Prices returns the list's prices for productIDs, or for every product it
+// prices when productIDs is nil. Products the list does not price are omitted.
+func (r *PriceListRepo) Prices(ctx context.Context, listID string, productIDs []string) (map[string]int32, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []sqldb.PriceListPrice
+	if productIDs == nil {
+		rows, err = r.q.ListPriceListPrices(ctx, sqldb.ListPriceListPricesParams{StoreID: storeID, PriceListID: listID})
+	} else {
+		rows, err = r.q.GetPriceListPrices(ctx, sqldb.GetPriceListPricesParams{StoreID: storeID, PriceListID: listID, ProductIds: productIDs})
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		out[row.ProductID] = row.PriceCents
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	sqlcmock "kart/internal/mocks/sqlc"
+	"kart/internal/sqlc"
+	"kart/internal/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceListRepo_Get(t *testing.T) {
+	type tc struct {
+		name     string
+		currency string
+		setup    func(m *sqlcmock.Querier)
+		wantID   string
+	}
+	cases := []tc{
+		{
+			name: "store default",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetDefaultPriceList", mock.Anything, "default").Return(sqlc.PriceList{ID: "default-aud"}, nil)
+			},
+			wantID: "default-aud",
+		},
+		{
+			name:     "by currency",
+			currency: "NZD",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetPriceList", mock.Anything, sqlc.GetPriceListParams{StoreID: "default", Currency: "NZD"}).
+					Return(sqlc.PriceList{ID: "default-nzd"}, nil)
+			},
+			wantID: "default-nzd",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sqlcmock.NewQuerier(t)
+			c.setup(m)
+			got, err := NewPriceListRepo(m).Get(tenant.WithStore(context.Background(), "default"), c.currency)
+			require.NoError(t, err)
+			assert.Equal(t, c.wantID, got.ID)
+		})
+	}
+}
+
+func TestPriceListRepo_Prices(t *testing.T) {
+	m := sqlcmock.NewQuerier(t)
+	m.On("ListPriceListPrices", mock.Anything, sqlc.ListPriceListPricesParams{StoreID: "default", PriceListID: "default-nzd"}).
+		Return([]sqlc.PriceListPrice{{ProductID: "10", PriceCents: 1416}, {ProductID: "12", PriceCents: 544}}, nil)
+	m.On("GetPriceListPrices", mock.Anything, sqlc.GetPriceListPricesParams{StoreID: "default", PriceListID: "default-nzd", ProductIds: []string{"10"}}).
+		Return([]sqlc.PriceListPrice{{ProductID: "10", PriceCents: 1416}}, nil)
+	r := NewPriceListRepo(m)
+	ctx := tenant.WithStore(context.Background(), "default")
+
+	all, err := r.Prices(ctx, "default-nzd", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"10": 1416, "12": 544}, all)
+
+	some, err := r.Prices(ctx, "default-nzd", []string{"10"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"10": 1416}, some)
+}
@@ -2,8 +2,10 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 
 	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
 )
 
 type ProductRepo struct{ q sqldb.Querier }
@@ -11,7 +13,11 @@ type ProductRepo struct{ q sqldb.Querier }
 func NewProductRepo(q sqldb.Querier) *ProductRepo { return &ProductRepo{q: q} }
 
 func (r *ProductRepo) List(ctx context.Context) ([]Product, error) {
-	rows, err := r.q.ListProducts(ctx)
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListProducts(ctx, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -23,17 +29,38 @@ func (r *ProductRepo) List(ctx context.Context) ([]Product, error) {
 }
 
 func (r *ProductRepo) Get(ctx context.Context, id string) (Product, error) {
-	pr, err := r.q.GetProduct(ctx, id)
+	storeID, err := tenant.StoreID(ctx)
 	if err != nil {
 		return Product{}, err
 	}
+	pr, err := oneRow(r.q.GetProduct(ctx, sqldb.GetProductParams{StoreID: storeID, ID: id}))
+	if err != nil {
+		return Product{}, err
+	}
+
+	return Product(pr), nil
+}
 
+// GetBySlug returns the product whose human-readable slug is slug.
+func (r *ProductRepo) GetBySlug(ctx context.Context, slug string) (Product, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Product{}, err
+	}
+	pr, err := oneRow(r.q.GetProductBySlug(ctx, sqldb.GetProductBySlugParams{StoreID: storeID, Slug: sql.NullString{String: slug, Valid: true}}))
+	if err != nil {
+		return Product{}, err
+	}
 	return Product(pr), nil
 }
 
 // GetMany returns products for the given IDs. Missing IDs are not included.
 func (r *ProductRepo) GetMany(ctx context.Context, ids []string) (map[string]Product, error) {
-	rows, err := r.q.GetProductsByIDs(ctx, ids)
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetProductsByIDs(ctx, sqldb.GetProductsByIDsParams{StoreID: storeID, Ids: ids})
 	if err != nil {
 		return nil, err
 	}
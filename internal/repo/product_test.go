@@ -2,10 +2,12 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
 	sqlcmock "kart/internal/mocks/sqlc"
 	"kart/internal/sqlc"
+	"kart/internal/tenant"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,13 +25,13 @@ func TestProductRepo_List(t *testing.T) {
 		{
 			name: "two",
 			setup: func(m *sqlcmock.Querier) {
-				m.On("ListProducts", mock.Anything).Return([]sqlc.Product{{ID: "1"}, {ID: "2"}}, nil)
+				m.On("ListProducts", mock.Anything, "s1").Return([]sqlc.Product{{ID: "1"}, {ID: "2"}}, nil)
 			},
 			wantLen: 2,
 		},
 		{
 			name:    "empty",
-			setup:   func(m *sqlcmock.Querier) { m.On("ListProducts", mock.Anything).Return([]sqlc.Product{}, nil) },
+			setup:   func(m *sqlcmock.Querier) { m.On("ListProducts", mock.Anything, "s1").Return([]sqlc.Product{}, nil) },
 			wantLen: 0,
 		},
 	}
@@ -38,7 +40,7 @@ func TestProductRepo_List(t *testing.T) {
 			m := sqlcmock.NewQuerier(t)
 			c.setup(m)
 			r := NewProductRepo(m)
-			got, err := r.List(context.Background())
+			got, err := r.List(tenant.WithStore(context.Background(), "s1"))
 			if c.wantErr {
 				require.Error(t, err)
 			} else {
@@ -55,24 +57,70 @@ func TestProductRepo_Get(t *testing.T) {
 		id      string
 		setup   func(m *sqlcmock.Querier)
 		wantID  string
-		wantErr bool
+		wantErr error
 	}
 	cases := []tc{
 		{
-			name:   "ok",
-			id:     "1",
-			setup:  func(m *sqlcmock.Querier) { m.On("GetProduct", mock.Anything, "1").Return(sqlc.Product{ID: "1"}, nil) },
+			name: "ok",
+			id:   "1",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "s1", ID: "1"}).Return(sqlc.Product{ID: "1"}, nil)
+			},
 			wantID: "1",
 		},
+		{
+			name: "missing",
+			id:   "2",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "s1", ID: "2"}).Return(sqlc.Product{}, sql.ErrNoRows)
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name: "database down",
+			id:   "3",
+			setup: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "s1", ID: "3"}).Return(sqlc.Product{}, context.DeadlineExceeded)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			m := sqlcmock.NewQuerier(t)
 			c.setup(m)
 			r := NewProductRepo(m)
-			got, err := r.Get(context.Background(), c.id)
-			if c.wantErr {
-				require.Error(t, err)
+			got, err := r.Get(tenant.WithStore(context.Background(), "s1"), c.id)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, c.wantID, got.ID)
+		})
+	}
+}
+
+func TestProductRepo_GetBySlug(t *testing.T) {
+	type tc struct {
+		name    string
+		slug    string
+		row     sqlc.Product
+		err     error
+		wantID  string
+		wantErr error
+	}
+	cases := []tc{
+		{name: "ok", slug: "chicken-waffle", row: sqlc.Product{ID: "10"}, wantID: "10"},
+		{name: "missing", slug: "nope", err: sql.ErrNoRows, wantErr: ErrNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sqlcmock.NewQuerier(t)
+			m.On("GetProductBySlug", mock.Anything, sqlc.GetProductBySlugParams{StoreID: "s1", Slug: sql.NullString{String: c.slug, Valid: true}}).Return(c.row, c.err)
+			got, err := NewProductRepo(m).GetBySlug(tenant.WithStore(context.Background(), "s1"), c.slug)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
 			} else {
 				require.NoError(t, err)
 			}
@@ -80,3 +128,17 @@ func TestProductRepo_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestProductRepo_RequiresStore(t *testing.T) {
+	// The querier mock fails the test on any call: without a store nothing
+	// may reach the database.
+	r := NewProductRepo(sqlcmock.NewQuerier(t))
+	_, err := r.List(context.Background())
+	require.ErrorIs(t, err, tenant.ErrNoStore)
+	_, err = r.Get(context.Background(), "1")
+	require.ErrorIs(t, err, tenant.ErrNoStore)
+	_, err = r.GetBySlug(context.Background(), "latte")
+	require.ErrorIs(t, err, tenant.ErrNoStore)
+	_, err = r.GetMany(context.Background(), []string{"1"})
+	require.ErrorIs(t, err, tenant.ErrNoStore)
+}
@@ -0,0 +1,21 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type PromotionRepo struct{ q sqldb.Querier }
+
+func NewPromotionRepo(q sqldb.Querier) *PromotionRepo { return &PromotionRepo{q: q} }
+
+func (r *PromotionRepo) Active(ctx context.Context, at time.Time) ([]Promotion, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListActivePromotions(ctx, sqldb.ListActivePromotionsParams{StoreID: storeID, At: at.UTC()})
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	sqldb "kart/internal/sqlc"
+)
+
+// RateLimitRepo keeps rate limit token buckets in Postgres, so every replica
+// draws on the same buckets. It implements ratelimit.Store. Like StoreRepo it
+// is not scoped to a tenant: callers put whatever they limit by in the key.
+type RateLimitRepo struct{ q sqldb.Querier }
+
+func NewRateLimitRepo(q sqldb.Querier) *RateLimitRepo { return &RateLimitRepo{q: q} }
+
+// Take takes a token from key's bucket in one statement, so concurrent calls
+// from any replica cannot overdraw it.
+func (r *RateLimitRepo) Take(ctx context.Context, key string, rate float64, burst int) (bool, float64, error) {
+	if burst < 1 {
+		return false, 0, nil
+	}
+	tokens, err := oneRow(r.q.TakeRateLimitToken(ctx, sqldb.TakeRateLimitTokenParams{Key: key, Rate: rate, Burst: int32(burst)}))
+	if err == nil {
+		return true, tokens, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return false, 0, err
+	}
+	// The bucket was empty and left untouched; report how far it has refilled.
+	tokens, err = oneRow(r.q.GetRateLimitTokens(ctx, key))
+	if errors.Is(err, ErrNotFound) {
+		// Purged in between, so it is full again.
+		return false, float64(burst), nil
+	}
+	return false, tokens, err
+}
+
+// PurgeFull deletes the buckets that have refilled, which behave like new
+// ones, and returns how many there were.
+func (r *RateLimitRepo) PurgeFull(ctx context.Context) (int64, error) {
+	return r.q.PurgeFullRateLimitBuckets(ctx)
+}
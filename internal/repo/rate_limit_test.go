@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sqlcmock "kart/internal/mocks/sqlc"
+	"kart/internal/ratelimit"
+	"kart/internal/sqlc"
+)
+
+var _ ratelimit.Store = (*RateLimitRepo)(nil)
+
+func TestRateLimitRepo_Take(t *testing.T) {
+	params := sqlc.TakeRateLimitTokenParams{Key: "POST /order|ip:192.0.2.1", Rate: 0.5, Burst: 10}
+	type tc struct {
+		name       string
+		burst      int
+		setup      func(m *sqlcmock.Querier)
+		wantTaken  bool
+		wantTokens float64
+		wantErr    error
+	}
+	cases := []tc{
+		{
+			name:  "taken",
+			burst: 10,
+			setup: func(m *sqlcmock.Querier) {
+				m.On("TakeRateLimitToken", mock.Anything, params).Return(4.5, nil)
+			},
+			wantTaken:  true,
+			wantTokens: 4.5,
+		},
+		{
+			name:  "empty",
+			burst: 10,
+			setup: func(m *sqlcmock.Querier) {
+				m.On("TakeRateLimitToken", mock.Anything, params).Return(0.0, sql.ErrNoRows)
+				m.On("GetRateLimitTokens", mock.Anything, params.Key).Return(0.25, nil)
+			},
+			wantTokens: 0.25,
+		},
+		{
+			name:  "purged after the take",
+			burst: 10,
+			setup: func(m *sqlcmock.Querier) {
+				m.On("TakeRateLimitToken", mock.Anything, params).Return(0.0, sql.ErrNoRows)
+				m.On("GetRateLimitTokens", mock.Anything, params.Key).Return(0.0, sql.ErrNoRows)
+			},
+			wantTokens: 10,
+		},
+		{
+			name:  "database down",
+			burst: 10,
+			setup: func(m *sqlcmock.Querier) {
+				m.On("TakeRateLimitToken", mock.Anything, params).Return(0.0, context.DeadlineExceeded)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{name: "no burst", burst: 0, setup: func(*sqlcmock.Querier) {}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sqlcmock.NewQuerier(t)
+			c.setup(m)
+			taken, tokens, err := NewRateLimitRepo(m).Take(context.Background(), params.Key, params.Rate, c.burst)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.wantTaken, taken)
+			assert.Equal(t, c.wantTokens, tokens)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+	"kart/internal/tracing"
+)
+
+var (
+	// ErrRefundExceedsCaptured indicates the refund would take the order's
+	// refunded total past what was captured from the customer.
+	ErrRefundExceedsCaptured = errors.New("refund exceeds captured amount")
+	// ErrRefundExceedsQuantity indicates a refund line asks for more units
+	// than remain unrefunded on the order item.
+	ErrRefundExceedsQuantity = errors.New("refund exceeds remaining item quantity")
+)
+
+type RefundRepo struct{ db *sql.DB }
+
+func NewRefundRepo(db *sql.DB) *RefundRepo { return &RefundRepo{db: db} }
+
+// Create records a pending refund and reserves its amount and item quantities
+// against the order. The order row is locked so concurrent refunds cannot
+// together exceed capturedCents.
+func (r *RefundRepo) Create(ctx context.Context, ref Refund, items []RefundItem, capturedCents int64) (err error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := sqldb.New(tracing.DB(tx))
+	o, err := oneRow(q.LockOrder(ctx, sqldb.LockOrderParams{StoreID: storeID, ID: ref.OrderID}))
+	if err != nil {
+		return err
+	}
+	if o.RefundedCents+ref.AmountCents > capturedCents {
+		return ErrRefundExceedsCaptured
+	}
+	if err = q.InsertRefund(ctx, sqldb.InsertRefundParams{
+		StoreID:     storeID,
+		ID:          ref.ID,
+		OrderID:     ref.OrderID,
+		PaymentID:   ref.PaymentID,
+		AmountCents: ref.AmountCents,
+		Reason:      ref.Reason,
+		Operator:    ref.Operator,
+	}); err != nil {
+		return err
+	}
+	for _, it := range items {
+		n, err := q.AddOrderItemRefunded(ctx, sqldb.AddOrderItemRefundedParams{StoreID: storeID, ID: it.OrderItemID, Delta: it.Quantity})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrRefundExceedsQuantity
+		}
+		if err = q.InsertRefundItem(ctx, sqldb.InsertRefundItemParams{
+			StoreID:     storeID,
+			RefundID:    ref.ID,
+			OrderItemID: it.OrderItemID,
+			Quantity:    it.Quantity,
+			AmountCents: it.AmountCents,
+		}); err != nil {
+			return err
+		}
+	}
+	if err = q.AddOrderRefunded(ctx, sqldb.AddOrderRefundedParams{StoreID: storeID, ID: ref.OrderID, AmountCents: ref.AmountCents}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Complete marks the refund as succeeded at the provider.
+func (r *RefundRepo) Complete(ctx context.Context, id, providerRef string) (Refund, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return Refund{}, err
+	}
+	return oneRow(sqldb.New(tracing.DB(r.db)).UpdateRefund(ctx, sqldb.UpdateRefundParams{
+		StoreID:     storeID,
+		ID:          id,
+		Status:      "succeeded",
+		ProviderRef: sql.NullString{String: providerRef, Valid: providerRef != ""},
+	}))
+}
+
+// Fail marks the refund as failed and returns its reserved amount and item
+// quantities to the order.
+func (r *RefundRepo) Fail(ctx context.Context, id, reason string) (err error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := sqldb.New(tracing.DB(tx))
+	ref, err := oneRow(q.UpdateRefund(ctx, sqldb.UpdateRefundParams{
+		StoreID:       storeID,
+		ID:            id,
+		Status:        "failed",
+		FailureReason: sql.NullString{String: reason, Valid: reason != ""},
+	}))
+	if err != nil {
+		return err
+	}
+	items, err := q.ListRefundItems(ctx, sqldb.ListRefundItemsParams{StoreID: storeID, RefundID: id})
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if _, err = q.AddOrderItemRefunded(ctx, sqldb.AddOrderItemRefundedParams{StoreID: storeID, ID: it.OrderItemID, Delta: -it.Quantity}); err != nil {
+			return err
+		}
+	}
+	if err = q.AddOrderRefunded(ctx, sqldb.AddOrderRefundedParams{StoreID: storeID, ID: ref.OrderID, AmountCents: -ref.AmountCents}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Succeeded returns the total of the order's refunds that succeeded at the
+// provider, leaving out pending reservations that may yet fail.
+func (r *RefundRepo) Succeeded(ctx context.Context, orderID string) (int64, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return sqldb.New(tracing.DB(r.db)).SumSucceededRefunds(ctx, sqldb.SumSucceededRefundsParams{StoreID: storeID, OrderID: orderID})
+}
+
+func (r *RefundRepo) ListByOrder(ctx context.Context, orderID string) ([]Refund, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListRefundsByOrder(ctx, sqldb.ListRefundsByOrderParams{StoreID: storeID, OrderID: orderID})
+}
+
+func (r *RefundRepo) ItemsByOrder(ctx context.Context, orderID string) ([]RefundItem, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.New(tracing.DB(r.db)).ListRefundItemsByOrder(ctx, sqldb.ListRefundItemsByOrderParams{StoreID: storeID, OrderID: orderID})
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/tenant"
+)
+
+func TestRefundRepo_Create(t *testing.T) {
+	ref := Refund{ID: "r1", OrderID: "o1", PaymentID: "p1", AmountCents: 500, Reason: "cold", Operator: "sam"}
+	items := []RefundItem{{OrderItemID: "i1", Quantity: 1, AmountCents: 500}}
+	type tc struct {
+		name              string
+		refunded          int64
+		buildExpectations func(mock sqlmock.Sqlmock)
+		wantErr           error
+	}
+	cases := []tc{
+		{
+			name:     "ok",
+			refunded: 0,
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refunds`)).
+					WithArgs("s1", "r1", "o1", "p1", int64(500), "cold", "sam").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE order_items`)).
+					WithArgs(int32(1), "s1", "i1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refund_items`)).
+					WithArgs("i1", int32(1), int64(500), "s1", "r1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders`)).
+					WithArgs(int64(500), "s1", "o1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "exceeds captured",
+			refunded: 800,
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectRollback()
+			},
+			wantErr: ErrRefundExceedsCaptured,
+		},
+		{
+			name:     "item already refunded",
+			refunded: 0,
+			buildExpectations: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO refunds`)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`UPDATE order_items`)).
+					WithArgs(int32(1), "s1", "i1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: ErrRefundExceedsQuantity,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, coupon_code, created_at, updated_at, status, eta_at, total_cents, discount_cents, refunded_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, store_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id FROM orders WHERE store_id = $1 AND id = $2 FOR UPDATE`)).
+				WithArgs("s1", "o1").
+				WillReturnRows(sqlmock.NewRows(orderColumns).AddRow("o1", nil, time.Now(), time.Now(), "completed", nil, 1000, 0, c.refunded, 1000, 91, true, "AUD", "default-aud", "s1", nil, nil, nil, 0, 0, 0, nil))
+			c.buildExpectations(mock)
+
+			err = NewRefundRepo(db).Create(tenant.WithStore(context.Background(), "s1"), ref, items, 1000)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefundRepo_Fail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	refundCols := []string{"id", "order_id", "payment_id", "amount_cents", "reason", "operator", "status", "provider_ref", "failure_reason", "created_at", "updated_at", "store_id"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE refunds`)).
+		WithArgs("s1", "r1", "failed", nil, "provider down").
+		WillReturnRows(sqlmock.NewRows(refundCols).AddRow("r1", "o1", "p1", 500, "cold", "sam", "failed", nil, "provider down", time.Now(), time.Now(), "s1"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ri.refund_id, ri.order_item_id, ri.quantity, ri.amount_cents FROM refund_items ri`)).
+		WithArgs("s1", "r1").
+		WillReturnRows(sqlmock.NewRows([]string{"refund_id", "order_item_id", "quantity", "amount_cents"}).AddRow("r1", "i1", 2, 500))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE order_items`)).
+		WithArgs(int32(-2), "s1", "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE orders`)).
+		WithArgs(int64(-500), "s1", "o1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewRefundRepo(db).Fail(tenant.WithStore(context.Background(), "s1"), "r1", "provider down"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
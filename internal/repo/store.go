@@ -0,0 +1,21 @@
+package repo
+
+import (
+	"context"
+
+	sqldb "kart/internal/sqlc"
+)
+
+// StoreRepo reads the stores themselves. Unlike the other repositories it is
+// not scoped to a tenant: it is what resolves the tenant in the first place.
+type StoreRepo struct{ q sqldb.Querier }
+
+func NewStoreRepo(q sqldb.Querier) *StoreRepo { return &StoreRepo{q: q} }
+
+func (r *StoreRepo) Get(ctx context.Context, id string) (Store, error) {
+	return oneRow(r.q.GetStore(ctx, id))
+}
+
+func (r *StoreRepo) List(ctx context.Context) ([]Store, error) {
+	return r.q.ListStores(ctx)
+}
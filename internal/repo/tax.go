@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+
+	sqldb "kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+type TaxRepo struct{ q sqldb.Querier }
+
+func NewTaxRepo(q sqldb.Querier) *TaxRepo { return &TaxRepo{q: q} }
+
+func (r *TaxRepo) Rates(ctx context.Context) (map[string]int32, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListTaxRates(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int32, len(rows))
+	for _, row := range rows {
+		out[row.TaxClass] = row.RateBasisPoints
+	}
+	return out, nil
+}
+
+func (r *TaxRepo) CategoryClasses(ctx context.Context) (map[string]string, error) {
+	storeID, err := tenant.StoreID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListCategoryTaxClasses(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(rows))
+	for _, row := range rows {
+		out[row.Category] = row.TaxClass
+	}
+	return out, nil
+}
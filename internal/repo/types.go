@@ -2,21 +2,49 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"kart/internal/sqlc"
+	"time"
 )
 
 type Product = sqlc.Product
 type Coupon = sqlc.Coupon
 type Order = sqlc.Order
 type OrderItem = sqlc.OrderItem
+type OrderTax = sqlc.OrderTax
+type Cart = sqlc.Cart
+type CartItem = sqlc.CartItem
+type Payment = sqlc.Payment
+type Refund = sqlc.Refund
+type RefundItem = sqlc.RefundItem
+type PriceList = sqlc.PriceList
+type Store = sqlc.Store
+type APIKey = sqlc.ApiKey
+type LoyaltyEntry = sqlc.LoyaltyEntry
+type GiftCard = sqlc.GiftCard
+type GiftCardTransaction = sqlc.GiftCardTransaction
+type Promotion = sqlc.Promotion
+type PriceAdjustment = sqlc.ListPriceAdjustmentsRow
 
 //go:generate mockery --name ProductRepository --dir . --output ../mocks/repo --outpkg repomock --filename product_repository_mock.go
 //go:generate mockery --name CouponRepository --dir . --output ../mocks/repo --outpkg repomock --filename coupon_repository_mock.go
 //go:generate mockery --name OrderRepository --dir . --output ../mocks/repo --outpkg repomock --filename order_repository_mock.go
+//go:generate mockery --name CartRepository --dir . --output ../mocks/repo --outpkg repomock --filename cart_repository_mock.go
+//go:generate mockery --name PaymentRepository --dir . --output ../mocks/repo --outpkg repomock --filename payment_repository_mock.go
+//go:generate mockery --name TaxRepository --dir . --output ../mocks/repo --outpkg repomock --filename tax_repository_mock.go
+//go:generate mockery --name PriceListRepository --dir . --output ../mocks/repo --outpkg repomock --filename price_list_repository_mock.go
+//go:generate mockery --name StoreRepository --dir . --output ../mocks/repo --outpkg repomock --filename store_repository_mock.go
+//go:generate mockery --name APIKeyRepository --dir . --output ../mocks/repo --outpkg repomock --filename api_key_repository_mock.go
+//go:generate mockery --name LoyaltyRepository --dir . --output ../mocks/repo --outpkg repomock --filename loyalty_repository_mock.go
+//go:generate mockery --name GiftCardRepository --dir . --output ../mocks/repo --outpkg repomock --filename gift_card_repository_mock.go
+//go:generate mockery --name PromotionRepository --dir . --output ../mocks/repo --outpkg repomock --filename promotion_repository_mock.go
+//go:generate mockery --name PriceAdjustmentRepository --dir . --output ../mocks/repo --outpkg repomock --filename price_adjustment_repository_mock.go
+//go:generate mockery --name RefundRepository --dir . --output ../mocks/repo --outpkg repomock --filename refund_repository_mock.go
 
 type ProductRepository interface {
 	List(ctx context.Context) ([]Product, error)
 	Get(ctx context.Context, id string) (Product, error)
+	GetBySlug(ctx context.Context, slug string) (Product, error)
 	GetMany(ctx context.Context, ids []string) (map[string]Product, error)
 }
 
@@ -25,5 +53,102 @@ type CouponRepository interface {
 }
 
 type OrderRepository interface {
-	CreateWithItems(ctx context.Context, o Order, items []OrderItem) (string, error)
+	CreateWithItems(ctx context.Context, o Order, items []OrderItem, taxes []OrderTax, giftCards ...GiftCardDebit) (string, error)
+	Get(ctx context.Context, id string) (Order, error)
+	ListByCustomer(ctx context.Context, customerID string, limit int32) ([]Order, error)
+	Items(ctx context.Context, orderID string) ([]OrderItem, error)
+	Taxes(ctx context.Context, orderID string) ([]OrderTax, error)
+	UpdateStatus(ctx context.Context, id, from, status string, eta sql.NullTime, entries ...LoyaltyEntry) (Order, error)
+	Reverse(ctx context.Context, id, from, status string, eta sql.NullTime, entries ...LoyaltyEntry) (Order, error)
+	FailPayment(ctx context.Context, id, from, status string) error
+}
+
+type LoyaltyRepository interface {
+	// Rules returns the store's points per major currency unit keyed by
+	// product category, with DefaultLoyaltyCategory as the fallback.
+	Rules(ctx context.Context) (map[string]int32, error)
+	Balance(ctx context.Context, customerID string) (int64, error)
+	Entries(ctx context.Context, customerID string, limit int32) ([]LoyaltyEntry, error)
+	OrderEntries(ctx context.Context, orderID string) ([]LoyaltyEntry, error)
+	Post(ctx context.Context, e LoyaltyEntry) (bool, error)
+}
+
+type PromotionRepository interface {
+	// Active returns the store's promotions running at the given time.
+	Active(ctx context.Context, at time.Time) ([]Promotion, error)
+}
+
+type PriceAdjustmentRepository interface {
+	// Scheduled returns the store's price adjustments whose schedule covers
+	// the given time, with the store's time zone. Their daily windows are
+	// left to the caller.
+	Scheduled(ctx context.Context, at time.Time) ([]PriceAdjustment, error)
+}
+
+type GiftCardRepository interface {
+	Create(ctx context.Context, c GiftCard) error
+	Get(ctx context.Context, id string) (GiftCard, error)
+	GetByCode(ctx context.Context, codeHash []byte) (GiftCard, error)
+	Transactions(ctx context.Context, id string) ([]GiftCardTransaction, error)
+}
+
+type TaxRepository interface {
+	// Rates returns the store's tax rates keyed by tax class.
+	Rates(ctx context.Context) (map[string]int32, error)
+	// CategoryClasses returns the tax class assigned to each product category.
+	CategoryClasses(ctx context.Context) (map[string]string, error)
+}
+
+type PriceListRepository interface {
+	// Get returns the store's price list in currency, or its default list
+	// when currency is empty.
+	Get(ctx context.Context, currency string) (PriceList, error)
+	// Prices returns the list's own prices keyed by product ID, restricted to
+	// productIDs unless it is nil.
+	Prices(ctx context.Context, listID string, productIDs []string) (map[string]int32, error)
+}
+
+type StoreRepository interface {
+	Get(ctx context.Context, id string) (Store, error)
+	List(ctx context.Context) ([]Store, error)
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k APIKey) error
+	// GetByPrefix looks a key up across all stores.
+	GetByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	Revoke(ctx context.Context, prefix string, at time.Time) (bool, error)
+	Expire(ctx context.Context, prefix string, at time.Time) (bool, error)
+	Touch(ctx context.Context, id string, at, staleBefore time.Time) error
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, p Payment) error
+	Update(ctx context.Context, p Payment) (Payment, error)
+	GetByOrder(ctx context.Context, orderID string) (Payment, error)
+}
+
+type RefundRepository interface {
+	Create(ctx context.Context, r Refund, items []RefundItem, capturedCents int64) error
+	Complete(ctx context.Context, id, providerRef string) (Refund, error)
+	Fail(ctx context.Context, id, reason string) error
+	Succeeded(ctx context.Context, orderID string) (int64, error)
+	ListByOrder(ctx context.Context, orderID string) ([]Refund, error)
+	ItemsByOrder(ctx context.Context, orderID string) ([]RefundItem, error)
+}
+
+type CartRepository interface {
+	Create(ctx context.Context, id, currency string, expiresAt time.Time) error
+	Get(ctx context.Context, id string) (Cart, error)
+	Items(ctx context.Context, cartID string) ([]CartItem, error)
+	AddItem(ctx context.Context, cartID, productID string, qty int32) error
+	SetItem(ctx context.Context, cartID, productID string, qty int32) error
+	RemoveItem(ctx context.Context, cartID, productID string) (bool, error)
+	SetCoupon(ctx context.Context, cartID string, code sql.NullString) error
+	Touch(ctx context.Context, cartID string, expiresAt time.Time) error
+	ClaimCheckout(ctx context.Context, cartID string, startedAt, staleBefore time.Time) (bool, error)
+	ReleaseCheckout(ctx context.Context, cartID string, startedAt time.Time) error
+	CompleteCheckout(ctx context.Context, cartID, orderID string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
 }
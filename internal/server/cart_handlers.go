@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"kart/internal/openapi"
+	"kart/internal/service"
+)
+
+// CreateCart POST /cart
+func (s *Server) CreateCart(w http.ResponseWriter, r *http.Request, params openapi.CreateCartParams) {
+	c, err := s.Carts.Create(r.Context(), requestCurrency(params.Currency, params.AcceptCurrency))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.toOpenAPICart(c))
+}
+
+// GetCart GET /cart/{cartId}
+func (s *Server) GetCart(w http.ResponseWriter, r *http.Request, cartId openapi.CartId) {
+	c, err := s.Carts.Get(r.Context(), cartId)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// AddCartItem POST /cart/{cartId}/items
+func (s *Server) AddCartItem(w http.ResponseWriter, r *http.Request, cartId openapi.CartId, params openapi.AddCartItemParams) {
+	var req openapi.CartItemReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	c, err := s.Carts.AddItem(r.Context(), cartId, req.ProductId, int32(req.Quantity), requestCurrency(params.Currency, params.AcceptCurrency))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// SetCartItem PUT /cart/{cartId}/items/{productId}
+func (s *Server) SetCartItem(w http.ResponseWriter, r *http.Request, cartId openapi.CartId, productId openapi.CartProductId, params openapi.SetCartItemParams) {
+	var req openapi.CartQuantityReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	c, err := s.Carts.SetItem(r.Context(), cartId, productId, int32(req.Quantity), requestCurrency(params.Currency, params.AcceptCurrency))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// RemoveCartItem DELETE /cart/{cartId}/items/{productId}
+func (s *Server) RemoveCartItem(w http.ResponseWriter, r *http.Request, cartId openapi.CartId, productId openapi.CartProductId) {
+	c, err := s.Carts.RemoveItem(r.Context(), cartId, productId)
+	if err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			writeError(w, http.StatusNotFound, "cart_item_not_found", "item not in cart")
+			return
+		}
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// ApplyCartCoupon PUT /cart/{cartId}/coupon
+func (s *Server) ApplyCartCoupon(w http.ResponseWriter, r *http.Request, cartId openapi.CartId) {
+	var req openapi.CartCouponReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	c, err := s.Carts.ApplyCoupon(r.Context(), cartId, req.CouponCode)
+	if err != nil {
+		s.recordCouponRejection(err)
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// RemoveCartCoupon DELETE /cart/{cartId}/coupon
+func (s *Server) RemoveCartCoupon(w http.ResponseWriter, r *http.Request, cartId openapi.CartId) {
+	c, err := s.Carts.RemoveCoupon(r.Context(), cartId)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPICart(c))
+}
+
+// CheckoutCart POST /cart/{cartId}/checkout
+func (s *Server) CheckoutCart(w http.ResponseWriter, r *http.Request, cartId openapi.CartId) {
+	var req openapi.CheckoutReq
+	if r.ContentLength != 0 && !decodeJSON(w, r, &req) {
+		return
+	}
+	giftCards := deref(req.GiftCards)
+	if !s.allowGiftCardLookups(w, r, len(giftCards)) {
+		return
+	}
+	result, err := s.Carts.Checkout(r.Context(), cartId, service.CheckoutInput{
+		PaymentToken: deref(req.PaymentToken),
+		Customer:     customerInput(r.Context(), req.Customer),
+		RedeemPoints: deref(req.RedeemPoints),
+		GiftCards:    giftCards,
+	})
+	s.recordOrder(result, err)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.orderResponse(r.Context(), result))
+}
+
+func (s *Server) toOpenAPICart(c service.Cart) openapi.Cart {
+	lines := make([]openapi.CartLine, 0, len(c.Lines))
+	for _, l := range c.Lines {
+		lines = append(lines, openapi.CartLine{
+			Product:        s.toOpenAPIProduct(l.Product, c.Currency),
+			Quantity:       int(l.Quantity),
+			LineTotalCents: l.LineTotalCents,
+		})
+	}
+	out := openapi.Cart{
+		Id:            c.ID,
+		Items:         lines,
+		SubtotalCents: c.SubtotalCents,
+		DiscountCents: c.DiscountCents,
+		TaxCents:      c.TaxCents,
+		TaxInclusive:  c.TaxInclusive,
+		TotalCents:    c.TotalCents,
+		Total:         c.Total().String(),
+		Currency:      c.Currency,
+		ExpiresAt:     c.ExpiresAt,
+	}
+	if c.Coupon != nil {
+		out.Coupon = &openapi.CouponPreview{
+			Code:          c.Coupon.Code,
+			Valid:         c.Coupon.Valid,
+			DiscountCents: c.Coupon.DiscountCents,
+		}
+		if c.Coupon.Reason != "" {
+			out.Coupon.Reason = ptr(c.Coupon.Reason)
+		}
+	}
+	if c.OrderID != "" {
+		out.OrderId = ptr(c.OrderID)
+	}
+	if len(c.Promotions) > 0 {
+		out.Promotions = toOpenAPIPromotions(c.Promotions)
+	}
+	return out
+}
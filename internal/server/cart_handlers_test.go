@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestCartHandlers(t *testing.T) {
+	cart := service.Cart{
+		ID:            "c1",
+		Lines:         []service.CartLine{{Product: repo.Product{ID: "10", PriceCents: 1299}, Quantity: 2, LineTotalCents: 2598}},
+		SubtotalCents: 2598,
+		TotalCents:    2598,
+		Coupon:        &service.CouponPreview{Code: "SUPER100", Reason: "coupon must apply to at least two categories"},
+	}
+	type tc struct {
+		name       string
+		call       func(s *Server, w http.ResponseWriter, r *http.Request)
+		body       string
+		setupMock  func(m *servermock.CartService)
+		wantStatus int
+	}
+	cases := []tc{
+		{
+			name: "create",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) {
+				s.CreateCart(w, r, openapi.CreateCartParams{})
+			},
+			setupMock:  func(m *servermock.CartService) { m.On("Create", mock.Anything, "").Return(cart, nil) },
+			wantStatus: 201,
+		},
+		{
+			name: "get not found",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.GetCart(w, r, "c1") },
+			setupMock: func(m *servermock.CartService) {
+				m.On("Get", mock.Anything, "c1").Return(service.Cart{}, repo.ErrNotFound)
+			},
+			wantStatus: 404,
+		},
+		{
+			name: "get expired",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.GetCart(w, r, "c1") },
+			setupMock: func(m *servermock.CartService) {
+				m.On("Get", mock.Anything, "c1").Return(service.Cart{}, service.ErrCartExpired)
+			},
+			wantStatus: 410,
+		},
+		{
+			name: "add item",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) {
+				s.AddCartItem(w, r, "c1", openapi.AddCartItemParams{})
+			},
+			body: `{"productId":"10","quantity":2}`,
+			setupMock: func(m *servermock.CartService) {
+				m.On("AddItem", mock.Anything, "c1", "10", int32(2), "").Return(cart, nil)
+			},
+			wantStatus: 200,
+		},
+		{
+			name: "add unknown product",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) {
+				s.AddCartItem(w, r, "c1", openapi.AddCartItemParams{})
+			},
+			body: `{"productId":"99","quantity":1}`,
+			setupMock: func(m *servermock.CartService) {
+				m.On("AddItem", mock.Anything, "c1", "99", int32(1), "").Return(service.Cart{}, service.ErrProductNotFound)
+			},
+			wantStatus: 422,
+		},
+		{
+			name: "add in another currency",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) {
+				s.AddCartItem(w, r, "c1", openapi.AddCartItemParams{AcceptCurrency: ptr("NZD")})
+			},
+			body: `{"productId":"10","quantity":1}`,
+			setupMock: func(m *servermock.CartService) {
+				m.On("AddItem", mock.Anything, "c1", "10", int32(1), "NZD").Return(service.Cart{}, service.ErrCartCurrency)
+			},
+			wantStatus: 422,
+		},
+		{
+			name: "remove missing item",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.RemoveCartItem(w, r, "c1", "10") },
+			setupMock: func(m *servermock.CartService) {
+				m.On("RemoveItem", mock.Anything, "c1", "10").Return(service.Cart{}, service.ErrProductNotFound)
+			},
+			wantStatus: 404,
+		},
+		{
+			name: "coupon rejected",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.ApplyCartCoupon(w, r, "c1") },
+			body: `{"couponCode":"ABC"}`,
+			setupMock: func(m *servermock.CartService) {
+				m.On("ApplyCoupon", mock.Anything, "c1", "ABC").Return(service.Cart{}, service.ErrCouponLength)
+			},
+			wantStatus: 422,
+		},
+		{
+			name: "checkout twice",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.CheckoutCart(w, r, "c1") },
+			setupMock: func(m *servermock.CartService) {
+				m.On("Checkout", mock.Anything, "c1", service.CheckoutInput{}).Return(service.PlaceOrderResult{}, service.ErrCartCheckedOut)
+			},
+			wantStatus: 409,
+		},
+		{
+			name: "checkout ok",
+			call: func(s *Server, w http.ResponseWriter, r *http.Request) { s.CheckoutCart(w, r, "c1") },
+			setupMock: func(m *servermock.CartService) {
+				m.On("Checkout", mock.Anything, "c1", service.CheckoutInput{}).Return(service.PlaceOrderResult{OrderID: "o1", Status: "placed"}, nil)
+			},
+			wantStatus: 200,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewCartService(t)
+			c.setupMock(m)
+			s := &Server{Carts: m}
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/cart", strings.NewReader(c.body))
+			c.call(s, rr, req)
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestToOpenAPICart(t *testing.T) {
+	got := (&Server{}).toOpenAPICart(service.Cart{
+		ID:            "c1",
+		Currency:      "AUD",
+		Lines:         []service.CartLine{{Product: repo.Product{ID: "10", PriceCents: 1299}, Quantity: 2, LineTotalCents: 2598}},
+		SubtotalCents: 2598,
+		TotalCents:    2598,
+		Coupon:        &service.CouponPreview{Code: "HAPPYHRS", Valid: true},
+	})
+	b, err := json.Marshal(got)
+	require.NoError(t, err)
+	var back openapi.Cart
+	require.NoError(t, json.Unmarshal(b, &back))
+	assert.Equal(t, int64(2598), back.TotalCents)
+	assert.Equal(t, "25.98", back.Total)
+	assert.Equal(t, "AUD", back.Currency)
+	price, err := back.Items[0].Product.Price.AsDecimalAmount()
+	require.NoError(t, err)
+	assert.Equal(t, "12.99", price)
+	require.NotNil(t, back.Coupon)
+	assert.True(t, back.Coupon.Valid)
+	assert.Nil(t, back.Coupon.Reason)
+	assert.Nil(t, back.OrderId)
+}
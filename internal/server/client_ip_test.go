@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	type tc struct {
+		in      string
+		want    []netip.Prefix
+		wantErr bool
+	}
+	cases := []tc{
+		{in: ""},
+		{in: "10.0.0.0/8, 192.0.2.7", want: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.7/32")}},
+		{in: "10.1.2.3/8", want: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+		{in: "::1", want: []netip.Prefix{netip.MustParsePrefix("::1/128")}},
+		{in: "proxy.internal", wantErr: true},
+		{in: "10.0.0.0/33", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := ParseTrustedProxies(c.in)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8")
+	require.NoError(t, err)
+	type tc struct {
+		name    string
+		peer    string
+		headers map[string]string
+		want    string
+	}
+	cases := []tc{
+		{name: "direct", peer: "192.0.2.1:5000", want: "192.0.2.1"},
+		{
+			name: "untrusted peer's headers are ignored", peer: "192.0.2.1:5000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.9"},
+			want:    "192.0.2.1",
+		},
+		{
+			name: "behind trusted proxy", peer: "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.9"},
+			want:    "198.51.100.9",
+		},
+		{
+			name: "spoofed entries before the client are skipped", peer: "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.66, 198.51.100.9, 10.0.0.3"},
+			want:    "198.51.100.9",
+		},
+		{
+			name: "only proxies", peer: "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "10.0.0.4, 10.0.0.3"},
+			want:    "10.0.0.4",
+		},
+		{name: "trusted proxy without headers", peer: "10.0.0.2:5000", want: "10.0.0.2"},
+		{
+			name: "forwarded header wins", peer: "10.0.0.2:5000",
+			headers: map[string]string{"Forwarded": `for="[2001:db8::1]:4711";proto=https, for=10.0.0.3`, "X-Forwarded-For": "198.51.100.9"},
+			want:    "2001:db8::1",
+		},
+		{
+			name: "obfuscated forwarded entries are ignored", peer: "10.0.0.2:5000",
+			headers: map[string]string{"Forwarded": "for=unknown, for=198.51.100.7"},
+			want:    "198.51.100.7",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/product", nil)
+			req.RemoteAddr = c.peer
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+			var got string
+			ClientIP(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = clientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
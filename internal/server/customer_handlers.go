@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"kart/internal/auth"
+	"kart/internal/money"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+// defaultMyOrdersLimit and defaultMyLoyaltyLimit match the limit parameters'
+// defaults in the spec.
+const (
+	defaultMyOrdersLimit  = 20
+	defaultMyLoyaltyLimit = 20
+)
+
+// ListMyOrders GET /me/orders
+func (s *Server) ListMyOrders(w http.ResponseWriter, r *http.Request, params openapi.ListMyOrdersParams) {
+	p, _ := auth.PrincipalFrom(r.Context())
+	if p.CustomerID == "" {
+		writeError(w, http.StatusUnauthorized, "customer_required", "sign in as a customer")
+		return
+	}
+	limit := defaultMyOrdersLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	orders, err := s.Orders.CustomerOrders(r.Context(), p.CustomerID, limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	out := make([]openapi.Order, 0, len(orders))
+	for _, o := range orders {
+		out = append(out, toOpenAPIOrderSummary(o))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// GetMyLoyalty GET /me/loyalty
+func (s *Server) GetMyLoyalty(w http.ResponseWriter, r *http.Request, params openapi.GetMyLoyaltyParams) {
+	p, _ := auth.PrincipalFrom(r.Context())
+	if p.CustomerID == "" {
+		writeError(w, http.StatusUnauthorized, "customer_required", "sign in as a customer")
+		return
+	}
+	limit := defaultMyLoyaltyLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	acct, err := s.Orders.LoyaltyAccount(r.Context(), p.CustomerID, limit)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	out := openapi.LoyaltyAccount{Balance: acct.Balance, Entries: make([]openapi.LoyaltyEntry, 0, len(acct.Entries))}
+	for _, e := range acct.Entries {
+		entry := openapi.LoyaltyEntry{Kind: openapi.LoyaltyEntryKind(e.Kind), Points: e.Points, CreatedAt: e.CreatedAt}
+		if e.OrderID.Valid {
+			entry.OrderId = ptr(e.OrderID.String)
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// customerInput identifies who a request places an order for: the signed-in
+// customer, if any, with the contact details from the body.
+func customerInput(ctx context.Context, c *openapi.Contact) service.CustomerInput {
+	var in service.CustomerInput
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		in.ID = p.CustomerID
+	}
+	if c != nil {
+		if c.Email != nil {
+			in.Email = string(*c.Email)
+		}
+		in.Phone = deref(c.Phone)
+	}
+	return in
+}
+
+// toOpenAPIOrderSummary converts the order row alone, without its lines,
+// taxes, payment or refunds.
+func toOpenAPIOrderSummary(o repo.Order) openapi.Order {
+	out := openapi.Order{
+		Id:            ptr(o.ID),
+		Status:        ptr(openapi.OrderStatus(o.Status)),
+		CreatedAt:     ptr(o.CreatedAt),
+		SubtotalCents: ptr(o.SubtotalCents),
+		DiscountCents: ptr(o.DiscountCents),
+		TaxCents:      ptr(o.TaxCents),
+		TaxInclusive:  ptr(o.TaxInclusive),
+		TotalCents:    ptr(o.TotalCents),
+		Total:         ptr(money.New(o.TotalCents, o.Currency).String()),
+		Currency:      ptr(o.Currency),
+		RefundedCents: ptr(o.RefundedCents),
+	}
+	if o.PointsRedeemed > 0 {
+		out.PointsRedeemed = ptr(o.PointsRedeemed)
+		out.PointsCents = ptr(o.PointsCents)
+	}
+	if o.GiftCardCents > 0 {
+		out.GiftCardCents = ptr(o.GiftCardCents)
+	}
+	if o.CustomerID.Valid {
+		out.CustomerId = ptr(o.CustomerID.String)
+	}
+	out.Contact = toOpenAPIContact(o.ContactEmail.String, o.ContactPhone.String)
+	return out
+}
+
+func toOpenAPIContact(email, phone string) *openapi.Contact {
+	if email == "" && phone == "" {
+		return nil
+	}
+	var c openapi.Contact
+	if email != "" {
+		c.Email = ptr(openapi_types.Email(email))
+	}
+	if phone != "" {
+		c.Phone = ptr(phone)
+	}
+	return &c
+}
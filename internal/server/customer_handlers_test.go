@@ -0,0 +1,226 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestListMyOrders_Handler(t *testing.T) {
+	created := time.Date(2025, 10, 9, 12, 0, 0, 0, time.UTC)
+	type tc struct {
+		name       string
+		principal  *auth.Principal
+		limit      *int
+		setup      func(m *servermock.OrderService)
+		wantStatus int
+		wantIDs    []string
+	}
+	cases := []tc{
+		{name: "anonymous", wantStatus: 401},
+		{name: "api key", principal: &auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default"}, wantStatus: 401},
+		{
+			name:      "customer",
+			principal: &auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "cust_1"},
+			setup: func(m *servermock.OrderService) {
+				m.On("CustomerOrders", mock.Anything, "cust_1", 20).Return([]repo.Order{
+					{ID: "o2", Status: "placed", TotalCents: 1250, Currency: "AUD", CreatedAt: created, CustomerID: sql.NullString{String: "cust_1", Valid: true}},
+					{ID: "o1", Status: "completed", TotalCents: 500, Currency: "AUD", CreatedAt: created.Add(-time.Hour), CustomerID: sql.NullString{String: "cust_1", Valid: true}},
+				}, nil)
+			},
+			wantStatus: 200,
+			wantIDs:    []string{"o2", "o1"},
+		},
+		{
+			name:      "limit",
+			principal: &auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "cust_1"},
+			limit:     ptr(5),
+			setup: func(m *servermock.OrderService) {
+				m.On("CustomerOrders", mock.Anything, "cust_1", 5).Return([]repo.Order{}, nil)
+			},
+			wantStatus: 200,
+			wantIDs:    []string{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			if c.setup != nil {
+				c.setup(m)
+			}
+			s := &Server{Orders: m}
+			req := httptest.NewRequest("GET", "/me/orders", nil)
+			if c.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *c.principal))
+			}
+			rr := httptest.NewRecorder()
+			s.ListMyOrders(rr, req, openapi.ListMyOrdersParams{Limit: c.limit})
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantIDs == nil {
+				return
+			}
+			var got []openapi.Order
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			ids := make([]string, 0, len(got))
+			for _, o := range got {
+				ids = append(ids, *o.Id)
+				assert.Equal(t, "cust_1", *o.CustomerId)
+				assert.Nil(t, o.Items)
+			}
+			assert.Equal(t, c.wantIDs, ids)
+			if len(got) > 0 {
+				assert.Equal(t, "12.50", *got[0].Total)
+				assert.Equal(t, created, *got[0].CreatedAt)
+			}
+		})
+	}
+}
+
+func TestPlaceOrder_CustomerIdentity(t *testing.T) {
+	type tc struct {
+		name      string
+		principal *auth.Principal
+		body      string
+		want      service.CustomerInput
+	}
+	cases := []tc{
+		{name: "anonymous", body: `{"items":[{"productId":"10","quantity":1}]}`},
+		{
+			name: "guest contact",
+			body: `{"items":[{"productId":"10","quantity":1}],"customer":{"email":"jo@example.com","phone":"+61412345678"}}`,
+			want: service.CustomerInput{Email: "jo@example.com", Phone: "+61412345678"},
+		},
+		{
+			name:      "signed-in customer",
+			principal: &auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "cust_1"},
+			body:      `{"items":[{"productId":"10","quantity":1}],"customer":{"phone":"+61412345678"}}`,
+			want:      service.CustomerInput{ID: "cust_1", Phone: "+61412345678"},
+		},
+		{
+			name:      "api key has no customer",
+			principal: &auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default"},
+			body:      `{"items":[{"productId":"10","quantity":1}]}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			m.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(in service.PlaceOrderInput) bool {
+				return in.Customer == c.want
+			})).Return(service.PlaceOrderResult{OrderID: "o1", Status: "placed", Customer: c.want}, nil)
+			s := &Server{Orders: m}
+			req := httptest.NewRequest("POST", "/order", strings.NewReader(c.body))
+			if c.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *c.principal))
+			}
+			rr := httptest.NewRecorder()
+			s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+			require.Equal(t, 200, rr.Code, rr.Body.String())
+			var got openapi.Order
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			if c.want.ID != "" {
+				assert.Equal(t, c.want.ID, *got.CustomerId)
+			} else {
+				assert.Nil(t, got.CustomerId)
+			}
+		})
+	}
+}
+
+func TestGetMyLoyalty_Handler(t *testing.T) {
+	at := time.Date(2025, 10, 10, 9, 0, 0, 0, time.UTC)
+	type tc struct {
+		name       string
+		principal  *auth.Principal
+		setup      func(m *servermock.OrderService)
+		wantStatus int
+		want       openapi.LoyaltyAccount
+	}
+	cases := []tc{
+		{name: "anonymous", wantStatus: 401},
+		{name: "api key", principal: &auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default"}, wantStatus: 401},
+		{
+			name:      "customer",
+			principal: &auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "cust_1"},
+			setup: func(m *servermock.OrderService) {
+				m.On("LoyaltyAccount", mock.Anything, "cust_1", 20).Return(service.LoyaltyAccount{
+					Balance: 120,
+					Entries: []repo.LoyaltyEntry{
+						{Kind: repo.LoyaltyRedeem, Points: -30, OrderID: sql.NullString{String: "o2", Valid: true}, CreatedAt: at},
+						{Kind: repo.LoyaltyEarn, Points: 150, OrderID: sql.NullString{String: "o1", Valid: true}, CreatedAt: at.Add(-time.Hour)},
+					},
+				}, nil)
+			},
+			wantStatus: 200,
+			want: openapi.LoyaltyAccount{
+				Balance: 120,
+				Entries: []openapi.LoyaltyEntry{
+					{Kind: openapi.LoyaltyEntryKindRedeem, Points: -30, OrderId: ptr("o2"), CreatedAt: at},
+					{Kind: openapi.LoyaltyEntryKindEarn, Points: 150, OrderId: ptr("o1"), CreatedAt: at.Add(-time.Hour)},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			if c.setup != nil {
+				c.setup(m)
+			}
+			s := &Server{Orders: m}
+			req := httptest.NewRequest("GET", "/me/loyalty", nil)
+			if c.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *c.principal))
+			}
+			rr := httptest.NewRecorder()
+			s.GetMyLoyalty(rr, req, openapi.GetMyLoyaltyParams{})
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantStatus != 200 {
+				return
+			}
+			var got openapi.LoyaltyAccount
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestPlaceOrder_RedeemPointsErrors(t *testing.T) {
+	type tc struct {
+		name       string
+		err        error
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "balance too low", err: repo.ErrInsufficientPoints, wantStatus: 409},
+		{name: "worth more than the order", err: service.ErrPointsInvalid, wantStatus: 422},
+		{name: "guest", err: service.ErrNotCustomer, wantStatus: 422},
+		{name: "redemption off", err: service.ErrLoyaltyUnavailable, wantStatus: 422},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			m.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(in service.PlaceOrderInput) bool {
+				return in.RedeemPoints == 500
+			})).Return(service.PlaceOrderResult{}, c.err)
+			s := &Server{Orders: m}
+			req := httptest.NewRequest("POST", "/order", strings.NewReader(`{"items":[{"productId":"10","quantity":1}],"redeemPoints":500}`))
+			rr := httptest.NewRecorder()
+			s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
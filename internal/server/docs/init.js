@@ -0,0 +1,23 @@
+// Renders the embedded spec with Swagger UI. Open /docs?store=<id> to send
+// requests to that store rather than the one the API key is bound to.
+(function () {
+  var store = new URLSearchParams(location.search).get("store");
+  window.ui = SwaggerUIBundle({
+    url: "openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    persistAuthorization: true,
+    presets: [SwaggerUIBundle.presets.apis],
+    layout: "BaseLayout",
+    // The default validator badge calls out to validator.swagger.io.
+    validatorUrl: null,
+    requestInterceptor: function (req) {
+      var u = new URL(req.url, location.href);
+      if (store && u.origin === location.origin && u.pathname !== "/openapi.json") {
+        u.pathname = "/stores/" + encodeURIComponent(store) + u.pathname;
+        req.url = u.toString();
+      }
+      return req;
+    }
+  });
+})();
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+// IssueGiftCard POST /gift-cards
+func (s *Server) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+	var req openapi.IssueGiftCardReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	in := service.IssueGiftCardInput{AmountCents: req.AmountCents, Currency: deref(req.Currency)}
+	if req.ExpiresAt != nil {
+		in.ExpiresAt = *req.ExpiresAt
+	}
+	card, err := s.GiftCards.Issue(r.Context(), in)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	out := toOpenAPIGiftCard(card.GiftCard)
+	out.Code = ptr(card.Code)
+	writeJSON(w, http.StatusCreated, out)
+}
+
+// CheckGiftCardBalance POST /gift-cards/balance
+func (s *Server) CheckGiftCardBalance(w http.ResponseWriter, r *http.Request) {
+	var req openapi.GiftCardCodeReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if !s.allowGiftCardLookups(w, r, 1) {
+		return
+	}
+	card, err := s.GiftCards.Balance(r.Context(), req.Code)
+	if err != nil {
+		writeGiftCardLookupError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAPIGiftCard(card))
+}
+
+// ListGiftCardTransactions GET /gift-cards/{giftCardId}/transactions
+func (s *Server) ListGiftCardTransactions(w http.ResponseWriter, r *http.Request, giftCardId string) {
+	txs, err := s.GiftCards.Transactions(r.Context(), giftCardId)
+	if err != nil {
+		writeGiftCardLookupError(w, r, err)
+		return
+	}
+	out := make([]openapi.GiftCardTransaction, 0, len(txs))
+	for _, t := range txs {
+		tx := openapi.GiftCardTransaction{
+			Id:          t.ID,
+			Kind:        openapi.GiftCardTransactionKind(t.Kind),
+			AmountCents: t.AmountCents,
+			CreatedAt:   t.CreatedAt,
+		}
+		if t.OrderID.Valid {
+			tx.OrderId = ptr(t.OrderID.String)
+		}
+		out = append(out, tx)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// allowGiftCardLookups takes n lookups from the caller's allowance, replying
+// 429 with Retry-After when it is used up. Lookups are allowed when the
+// limiter's store fails.
+func (s *Server) allowGiftCardLookups(w http.ResponseWriter, r *http.Request, n int) bool {
+	if s.GiftCardLookups == nil {
+		return true
+	}
+	key := callerKey(r)
+	for range n {
+		res, err := s.GiftCardLookups.Take(r.Context(), key)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "rate limit check failed", "limit", "gift card lookups", "err", err)
+			return true
+		}
+		if !res.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "rate_limited", "too many gift card lookups")
+			return false
+		}
+	}
+	return true
+}
+
+// writeGiftCardLookupError replies 404 when the card looked up does not
+// exist. Elsewhere an unknown card is part of an invalid request.
+func writeGiftCardLookupError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrGiftCardNotFound) || errors.Is(err, repo.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "gift_card_not_found", service.ErrGiftCardNotFound.Error())
+		return
+	}
+	writeServiceError(w, r, err)
+}
+
+func toOpenAPIGiftCard(c repo.GiftCard) openapi.GiftCard {
+	out := openapi.GiftCard{
+		Id:           c.ID,
+		Last4:        c.Last4,
+		Currency:     c.Currency,
+		InitialCents: c.InitialCents,
+		BalanceCents: c.BalanceCents,
+		CreatedAt:    c.CreatedAt,
+	}
+	if c.ExpiresAt.Valid {
+		out.ExpiresAt = ptr(c.ExpiresAt.Time)
+	}
+	if c.DisabledAt.Valid {
+		out.Disabled = ptr(true)
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/ratelimit"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestIssueGiftCard_Handler(t *testing.T) {
+	created := time.Date(2025, 10, 11, 9, 0, 0, 0, time.UTC)
+	type tc struct {
+		name       string
+		body       string
+		setup      func(m *servermock.GiftCardService)
+		wantStatus int
+		wantCode   string
+	}
+	cases := []tc{
+		{
+			name: "issued",
+			body: `{"amountCents":5000}`,
+			setup: func(m *servermock.GiftCardService) {
+				m.On("Issue", mock.Anything, service.IssueGiftCardInput{AmountCents: 5000}).Return(service.IssuedGiftCard{
+					GiftCard: repo.GiftCard{ID: "g1", Last4: "WXYZ", Currency: "AUD", InitialCents: 5000, BalanceCents: 5000, CreatedAt: created},
+					Code:     "ABCD-EFGH-JKMN-WXYZ",
+				}, nil)
+			},
+			wantStatus: 201,
+			wantCode:   "ABCD-EFGH-JKMN-WXYZ",
+		},
+		{
+			name: "bad amount",
+			body: `{"amountCents":0}`,
+			setup: func(m *servermock.GiftCardService) {
+				m.On("Issue", mock.Anything, mock.Anything).Return(service.IssuedGiftCard{}, service.ErrGiftCardAmount)
+			},
+			wantStatus: 422,
+		},
+		{name: "bad json", body: `{"amount":1}`, wantStatus: 400},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewGiftCardService(t)
+			if c.setup != nil {
+				c.setup(m)
+			}
+			s := &Server{GiftCards: m}
+			rr := httptest.NewRecorder()
+			s.IssueGiftCard(rr, httptest.NewRequest("POST", "/gift-cards", strings.NewReader(c.body)))
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantCode == "" {
+				return
+			}
+			var got openapi.GiftCard
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			assert.Equal(t, c.wantCode, *got.Code)
+			assert.Equal(t, int64(5000), got.BalanceCents)
+		})
+	}
+}
+
+func TestCheckGiftCardBalance_Handler(t *testing.T) {
+	type tc struct {
+		name       string
+		setup      func(m *servermock.GiftCardService)
+		lookups    int
+		wantStatus int
+	}
+	cases := []tc{
+		{
+			name: "found",
+			setup: func(m *servermock.GiftCardService) {
+				m.On("Balance", mock.Anything, "abcd-efgh-jkmn-wxyz").Return(repo.GiftCard{ID: "g1", Last4: "WXYZ", Currency: "AUD", InitialCents: 5000, BalanceCents: 1200}, nil)
+			},
+			wantStatus: 200,
+		},
+		{
+			name: "not found",
+			setup: func(m *servermock.GiftCardService) {
+				m.On("Balance", mock.Anything, mock.Anything).Return(repo.GiftCard{}, service.ErrGiftCardNotFound)
+			},
+			wantStatus: 404,
+		},
+		{
+			name: "store error",
+			setup: func(m *servermock.GiftCardService) {
+				m.On("Balance", mock.Anything, mock.Anything).Return(repo.GiftCard{}, errors.New("db down"))
+			},
+			wantStatus: 500,
+		},
+		{name: "rate limited", lookups: 2, wantStatus: 429},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewGiftCardService(t)
+			if c.setup != nil {
+				c.setup(m)
+			}
+			lim := ratelimit.PerMinute(2)
+			p := auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:pos"}
+			for range c.lookups {
+				lim.Allow(p.Subject + "|ip:192.0.2.1") // httptest's client address
+			}
+			s := &Server{GiftCards: m, GiftCardLookups: lim}
+			req := httptest.NewRequest("POST", "/gift-cards/balance", strings.NewReader(`{"code":"abcd-efgh-jkmn-wxyz"}`))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), p))
+			rr := httptest.NewRecorder()
+			s.CheckGiftCardBalance(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantStatus == 429 {
+				assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestPlaceOrder_GiftCardErrors(t *testing.T) {
+	type tc struct {
+		name       string
+		err        error
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "spent elsewhere", err: repo.ErrGiftCardBalance, wantStatus: 409},
+		{name: "unknown code", err: service.ErrGiftCardNotFound, wantStatus: 422},
+		{name: "expired", err: service.ErrGiftCardUnusable, wantStatus: 422},
+		{name: "other currency", err: service.ErrGiftCardCurrency, wantStatus: 422},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			m.On("PlaceOrder", mock.Anything, mock.MatchedBy(func(in service.PlaceOrderInput) bool {
+				return len(in.GiftCards) == 1 && in.GiftCards[0] == "ABCD-EFGH-JKMN-WXYZ"
+			})).Return(service.PlaceOrderResult{}, c.err)
+			s := &Server{Orders: m}
+			body := `{"items":[{"productId":"10","quantity":1}],"giftCards":["ABCD-EFGH-JKMN-WXYZ"]}`
+			rr := httptest.NewRecorder()
+			s.PlaceOrder(rr, httptest.NewRequest("POST", "/order", strings.NewReader(body)), openapi.PlaceOrderParams{})
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+		})
+	}
+}
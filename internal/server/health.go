@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"kart/internal/buildinfo"
+	"kart/internal/health"
+)
+
+// healthz reports that the process is up. It checks nothing else, so a slow
+// dependency never gets the process restarted.
+func healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyz reports whether the process should receive traffic: 200 when every
+// check passes, 503 when one fails or the process is draining. Probes are
+// unauthenticated, so each check shows only "ok" or "failed"; why a check
+// failed is logged instead, as it can name hosts or database errors.
+func readyz(c *health.Checker, l *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rep := c.Ready(r.Context())
+		status, code := "ready", http.StatusOK
+		switch {
+		case rep.Draining:
+			status, code = "draining", http.StatusServiceUnavailable
+		case !rep.Ready:
+			status, code = "not ready", http.StatusServiceUnavailable
+		}
+		checks := make(map[string]string, len(rep.Checks))
+		for name, result := range rep.Checks {
+			if result == "ok" {
+				checks[name] = "ok"
+				continue
+			}
+			checks[name] = "failed"
+			l.WarnContext(r.Context(), "readiness check failed", slog.String("check", name), slog.String("err", result))
+		}
+		writeJSON(w, code, map[string]any{"status": status, "checks": checks})
+	}
+}
+
+// version reports the build the process is running.
+func version(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, buildinfo.Get())
+}
+
+// specLoaded checks that the OpenAPI spec requests are validated against
+// has operations.
+func specLoaded(spec *openapi3.T) health.Check {
+	return func(context.Context) error {
+		if spec == nil || spec.Paths == nil || spec.Paths.Len() == 0 {
+			return errors.New("OpenAPI spec not loaded")
+		}
+		return nil
+	}
+}
+
+// getOnly replies 405 to anything but GET and HEAD.
+func getOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "method not allowed")
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
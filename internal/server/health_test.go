@@ -0,0 +1,94 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/health"
+	servermock "kart/internal/mocks/server"
+)
+
+func TestRouter_Health(t *testing.T) {
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		dbErr      error
+		drain      bool
+		wantStatus int
+		wantBody   map[string]any
+		wantLog    string
+	}
+	cases := []tc{
+		{name: "liveness", method: "GET", path: "/healthz", wantStatus: 200, wantBody: map[string]any{"status": "ok"}},
+		{
+			name: "ready", method: "GET", path: "/readyz", wantStatus: 200,
+			wantBody: map[string]any{"status": "ready", "checks": map[string]any{"database": "ok", "openapi": "ok"}},
+		},
+		{
+			name: "database down", method: "GET", path: "/readyz", dbErr: errors.New("dial tcp 10.0.0.5:5432: connection refused"), wantStatus: 503,
+			wantBody: map[string]any{"status": "not ready", "checks": map[string]any{"database": "failed", "openapi": "ok"}},
+			wantLog:  "dial tcp 10.0.0.5:5432: connection refused",
+		},
+		{
+			name: "draining", method: "GET", path: "/readyz", drain: true, wantStatus: 503,
+			wantBody: map[string]any{"status": "draining", "checks": map[string]any{"database": "ok", "openapi": "ok"}},
+		},
+		{name: "liveness ignores draining", method: "GET", path: "/healthz", drain: true, wantStatus: 200, wantBody: map[string]any{"status": "ok"}},
+		{name: "post not allowed", method: "POST", path: "/readyz", wantStatus: 405},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			checker := &health.Checker{}
+			checker.Add("database", func(context.Context) error { return c.dbErr })
+			if c.drain {
+				checker.Drain()
+			}
+			// No API key is sent: the probes bypass auth and validation.
+			var logs bytes.Buffer
+			tn := Tenancy{Keys: servermock.NewKeyAuthenticator(t), DefaultStore: "default", LegacyAPIKey: "apitest"}
+			h, err := NewRouter(tn, &Server{}, RouterOptions{
+				Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+				Health: checker,
+			})
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(c.method, c.path, nil))
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantBody != nil {
+				var got map[string]any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+				assert.Equal(t, c.wantBody, got)
+			}
+			if c.wantLog != "" {
+				assert.NotContains(t, rr.Body.String(), c.wantLog)
+				assert.Contains(t, logs.String(), c.wantLog)
+			}
+		})
+	}
+}
+
+func TestRouter_Version(t *testing.T) {
+	tn := Tenancy{Keys: servermock.NewKeyAuthenticator(t), DefaultStore: "default", LegacyAPIKey: "apitest"}
+	h, err := NewRouter(tn, &Server{}, RouterOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil)), ResponseValidation: ResponseValidationFail})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/version", nil))
+	require.Equal(t, 200, rr.Code)
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.NotEmpty(t, got["version"])
+	assert.NotEmpty(t, got["commit"])
+	assert.NotEmpty(t, got["goVersion"])
+}
@@ -4,21 +4,63 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
+	"strings"
 )
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
+	writeBody(w, status, "application/json", v)
+}
+
+func writeBody(w http.ResponseWriter, status int, contentType string, v any) {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(v); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 	_, _ = w.Write(buf.Bytes())
 }
 
 func ptr[T any](v T) *T { return &v }
 
-func writeError(w http.ResponseWriter, status int, msg string) {
-	writeJSON(w, status, map[string]string{"error": msg})
+// deref returns *p, or the zero value when p is nil.
+func deref[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+	return v
+}
+
+// writeError replies with a problem the handler detected itself; errors from
+// the services go through writeServiceError.
+func writeError(w http.ResponseWriter, status int, code, detail string) {
+	writeProblem(w, newProblem(status, code, detail))
+}
+
+// decodeJSON strictly decodes the request body into v, replying 400 on failure.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+// requestCurrency returns the currency a request asks to be priced in: the
+// currency query parameter, else the first entry of Accept-Currency, else ""
+// for the store default.
+func requestCurrency(query, header *string) string {
+	if query != nil && *query != "" {
+		return *query
+	}
+	if header == nil {
+		return ""
+	}
+	first, _, _ := strings.Cut(*header, ",")
+	first, _, _ = strings.Cut(first, ";")
+	return strings.ToUpper(strings.TrimSpace(first))
 }
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"kart/internal/metrics"
+	"kart/internal/money"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+// Instrument counts and times every request by the route pattern it matched.
+func Instrument(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r, rctx := withRouteContext(r)
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			m.ObserveRequest(rctx.RoutePattern(), r.Method, rec.Status(), time.Since(start))
+		})
+	}
+}
+
+// recordOrder feeds the business metrics with the outcome of placing an
+// order, directly or from a cart.
+func (s *Server) recordOrder(result service.PlaceOrderResult, err error) {
+	switch {
+	case err == nil:
+		s.Metrics.OrderPlaced(money.New(result.TotalCents, result.Pricing.Currency))
+	case errors.Is(err, repo.ErrCouponRedeemed):
+		s.Metrics.CouponConflict()
+	default:
+		s.recordCouponRejection(err)
+	}
+}
+
+func (s *Server) recordCouponRejection(err error) {
+	switch {
+	case errors.Is(err, service.ErrCouponLength):
+		s.Metrics.CouponRejected("length")
+	case errors.Is(err, service.ErrCouponNotFound):
+		s.Metrics.CouponRejected("not_found")
+	case errors.Is(err, service.ErrCouponCategories):
+		s.Metrics.CouponRejected("categories")
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/metrics"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestPlaceOrder_Metrics(t *testing.T) {
+	type tc struct {
+		name          string
+		result        service.PlaceOrderResult
+		err           error
+		wantPlaced    float64
+		wantConflicts float64
+		wantRejected  string
+	}
+	cases := []tc{
+		{
+			name:       "placed",
+			result:     service.PlaceOrderResult{OrderID: "o1", TotalCents: 2599, Pricing: service.Pricing{Currency: "AUD"}},
+			wantPlaced: 1,
+		},
+		{name: "coupon already redeemed", err: repo.ErrCouponRedeemed, wantConflicts: 1},
+		{name: "unknown coupon", err: service.ErrCouponNotFound, wantRejected: "not_found"},
+		{name: "short coupon", err: service.ErrCouponLength, wantRejected: "length"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := metrics.New(prometheus.NewRegistry())
+			orders := servermock.NewOrderService(t)
+			orders.On("PlaceOrder", mock.Anything, mock.Anything).Return(c.result, c.err)
+			s := &Server{Orders: orders, Metrics: m}
+
+			body := []byte(`{"items":[{"productId":"10","quantity":1}],"couponCode":"HAPPYHOUR"}`)
+			s.PlaceOrder(httptest.NewRecorder(), httptest.NewRequest("POST", "/order", bytes.NewReader(body)), openapi.PlaceOrderParams{})
+
+			assert.Equal(t, c.wantPlaced, testutil.ToFloat64(m.OrdersPlaced.WithLabelValues("AUD")))
+			assert.Equal(t, c.wantConflicts, testutil.ToFloat64(m.CouponConflicts))
+			if c.wantPlaced > 0 {
+				assert.Equal(t, 1, testutil.CollectAndCount(m.OrderValue))
+			}
+			if c.wantRejected != "" {
+				assert.Equal(t, 1.0, testutil.ToFloat64(m.CouponRejections.WithLabelValues(c.wantRejected)))
+			}
+		})
+	}
+}
+
+func TestRouter_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	products := servermock.NewProductService(t)
+	products.On("Get", mock.Anything, "10", "").Return(service.PricedProduct{}, nil)
+	tn := Tenancy{Keys: servermock.NewKeyAuthenticator(t), DefaultStore: "default", LegacyAPIKey: "apitest"}
+	h, err := NewRouter(tn, &Server{Products: products}, RouterOptions{
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Metrics:  m,
+		Gatherer: reg,
+	})
+	require.NoError(t, err)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/product/10", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/no/such/path", nil))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HTTPRequests.WithLabelValues("/product/{productId}", "GET", "200")))
+
+	// Authentication rejects this before chi routes it; it still counts
+	// against its route rather than as unmatched.
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("POST", "/order", strings.NewReader(`{}`)))
+	require.Equal(t, 401, rr.Code)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.HTTPRequests.WithLabelValues("/order", "POST", "401")))
+
+	// The metrics endpoint needs no API key and is not in the spec.
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rr.Code)
+	assert.Contains(t, rr.Body.String(), `kart_http_requests_total{method="GET",route="/product/{productId}",status="200"} 1`)
+	assert.Contains(t, rr.Body.String(), `kart_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, rr.Body.String(), `method="POST",route="unmatched"`)
+}
@@ -3,27 +3,43 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
+
+	"kart/internal/auth"
 )
 
-// NewOpenAPIAuthFunc returns an AuthenticationFunc which validates the
-// api_key header for operations using the api_key security scheme.
-func NewOpenAPIAuthFunc(expectedAPIKey string) openapi3filter.AuthenticationFunc {
+// NewOpenAPIAuthFunc returns an AuthenticationFunc which, for operations
+// using the api_key or bearerAuth security scheme, requires the request to
+// have authenticated with that scheme and to hold the operation's scopes.
+// The credentials themselves are checked by Tenancy, which runs first.
+func NewOpenAPIAuthFunc() openapi3filter.AuthenticationFunc {
 	return func(ctx context.Context, ai *openapi3filter.AuthenticationInput) error {
 		if ai == nil || ai.SecurityScheme == nil {
 			return nil
 		}
-		if ai.SecuritySchemeName == "api_key" {
-			req := ai.RequestValidationInput.Request
-			if req == nil {
-				return errors.New("missing request in auth input")
-			}
-			if req.Header.Get("api_key") != expectedAPIKey {
-				return errors.New("invalid api_key")
-			}
+		var missing string
+		switch ai.SecuritySchemeName {
+		case auth.SchemeAPIKey:
+			missing = "invalid api_key"
+		case auth.SchemeBearer:
+			missing = "invalid bearer token"
+		default:
 			return nil
 		}
+		req := ai.RequestValidationInput.Request
+		if req == nil {
+			return errors.New("missing request in auth input")
+		}
+		p, ok := auth.PrincipalFrom(req.Context())
+		if !ok || p.Scheme != ai.SecuritySchemeName {
+			return errors.New(missing)
+		}
+		if !p.HasScopes(ai.Scopes...) {
+			return fmt.Errorf("%w: requires %s", auth.ErrInsufficientScope, strings.Join(ai.Scopes, ", "))
+		}
 		return nil
 	}
 }
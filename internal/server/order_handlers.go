@@ -1,52 +1,61 @@
 package server
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 
 	"kart/internal/openapi"
-	"kart/internal/repo"
+	"kart/internal/promo"
 	"kart/internal/service"
+	"kart/internal/tenant"
 )
 
 // PlaceOrder POST /order
-func (s *Server) PlaceOrder(w http.ResponseWriter, r *http.Request) {
+func (s *Server) PlaceOrder(w http.ResponseWriter, r *http.Request, params openapi.PlaceOrderParams) {
 	var req openapi.OrderReq
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid JSON body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
+	giftCards := deref(req.GiftCards)
 
 	// basic input validation at the edge
 	if len(req.Items) == 0 {
-		writeError(w, http.StatusUnprocessableEntity, "no items")
+		writeError(w, http.StatusUnprocessableEntity, "no_items", "no items")
 		return
 	}
 	in := make([]service.OrderItemInput, 0, len(req.Items))
 	for _, it := range req.Items {
 		if it.ProductId == "" || it.Quantity <= 0 {
-			writeError(w, http.StatusUnprocessableEntity, "invalid item: productId and quantity are required")
+			writeError(w, http.StatusUnprocessableEntity, "invalid_item", "invalid item: productId and quantity are required")
 			return
 		}
 		in = append(in, service.OrderItemInput{ProductID: it.ProductId, Quantity: int32(it.Quantity)})
 	}
+	if !s.allowGiftCardLookups(w, r, len(giftCards)) {
+		return
+	}
 
 	result, err := s.Orders.PlaceOrder(r.Context(), service.PlaceOrderInput{
-		CouponCode: deref(req.CouponCode),
-		Items:      in,
+		CouponCode:   deref(req.CouponCode),
+		Items:        in,
+		PaymentToken: deref(req.PaymentToken),
+		Currency:     requestCurrency(params.Currency, params.AcceptCurrency),
+		Customer:     customerInput(r.Context(), req.Customer),
+		RedeemPoints: deref(req.RedeemPoints),
+		GiftCards:    giftCards,
 	})
+	s.recordOrder(result, err)
 	if err != nil {
-		if err == repo.ErrCouponRedeemed {
-			writeError(w, http.StatusConflict, err.Error())
-			return
-		}
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeServiceError(w, r, err)
 		return
 	}
 
-	// Build response according to OpenAPI spec
+	writeJSON(w, http.StatusOK, s.orderResponse(r.Context(), result))
+}
+
+// orderResponse builds the Order body for a placed order, including the
+// token that authorizes its status channel.
+func (s *Server) orderResponse(ctx context.Context, result service.PlaceOrderResult) openapi.Order {
 	items := make([]openapi.OrderItem, 0, len(result.Items))
 	for _, item := range result.Items {
 		qty := int(item.Quantity)
@@ -58,25 +67,106 @@ func (s *Server) PlaceOrder(w http.ResponseWriter, r *http.Request) {
 
 	products := make([]openapi.Product, 0, len(result.Products))
 	for _, p := range result.Products {
-		price := float32(p.PriceCents) / 100.0
-		products = append(products, openapi.Product{
-			Id:       ptr(p.ID),
-			Name:     ptr(p.Name),
-			Category: ptr(p.Category),
-			Price:    ptr(price),
+		products = append(products, s.toOpenAPIProduct(p, result.Pricing.Currency))
+	}
+
+	pr := result.Pricing
+	taxes := make([]openapi.TaxLine, 0, len(pr.Taxes))
+	for _, t := range pr.Taxes {
+		taxes = append(taxes, openapi.TaxLine{
+			TaxClass:        t.Class,
+			RateBasisPoints: int(t.RateBasisPoints),
+			TaxableCents:    t.TaxableCents,
+			TaxCents:        t.TaxCents,
 		})
 	}
+	// Pricing lines follow the order items one to one.
+	for i, l := range pr.Lines {
+		if i >= len(items) {
+			break
+		}
+		items[i].UnitPriceCents = ptr(l.UnitPriceCents)
+		items[i].TaxCents = ptr(l.TaxCents)
+		items[i].DiscountCents = ptr(l.DiscountCents)
+		if l.TaxClass != "" {
+			items[i].TaxClass = ptr(l.TaxClass)
+		}
+	}
 
-	writeJSON(w, http.StatusOK, openapi.Order{
-		Id:       &result.OrderID,
-		Items:    &items,
-		Products: &products,
-	})
+	resp := openapi.Order{
+		Id:            &result.OrderID,
+		Items:         &items,
+		Products:      &products,
+		Status:        ptr(openapi.OrderStatus(result.Status)),
+		SubtotalCents: ptr(pr.SubtotalCents),
+		DiscountCents: ptr(pr.DiscountCents),
+		TaxCents:      ptr(pr.TaxCents),
+		TaxInclusive:  ptr(pr.TaxInclusive),
+		Taxes:         &taxes,
+		TotalCents:    ptr(result.TotalCents),
+		Total:         ptr(pr.Total().String()),
+		Currency:      ptr(pr.Currency),
+	}
+	if len(pr.Promotions) > 0 {
+		resp.Promotions = toOpenAPIPromotions(pr.Promotions)
+	}
+	if result.PointsRedeemed > 0 {
+		resp.PointsRedeemed = ptr(result.PointsRedeemed)
+		resp.PointsCents = ptr(result.PointsCents)
+	}
+	if len(result.GiftCards) > 0 {
+		uses := make([]openapi.GiftCardUse, 0, len(result.GiftCards))
+		for _, u := range result.GiftCards {
+			uses = append(uses, openapi.GiftCardUse{Last4: u.Last4, AmountCents: u.AmountCents})
+		}
+		resp.GiftCards = &uses
+		resp.GiftCardCents = ptr(result.GiftCardCents)
+	}
+	if result.Customer.ID != "" {
+		resp.CustomerId = ptr(result.Customer.ID)
+	}
+	resp.Contact = toOpenAPIContact(result.Customer.Email, result.Customer.Phone)
+	if result.Payment != nil {
+		resp.Payment = ptr(toOpenAPIPayment(*result.Payment))
+	}
+	if storeID, err := tenant.StoreID(ctx); err == nil && s.StatusTokens != nil {
+		tok, exp := s.StatusTokens.Issue(storeID, result.OrderID)
+		resp.StatusToken = &tok
+		resp.StatusTokenExpiresAt = &exp
+	}
+	return resp
 }
 
-func deref(p *string) string {
-	if p == nil {
-		return ""
+// ConfirmOrderPayment POST /order/{orderId}/payment/confirm
+func (s *Server) ConfirmOrderPayment(w http.ResponseWriter, r *http.Request, orderId string) {
+	pay, err := s.Orders.ConfirmPayment(r.Context(), orderId)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAPIPayment(pay))
+}
+
+func toOpenAPIPromotions(applied []promo.Applied) *[]openapi.AppliedPromotion {
+	out := make([]openapi.AppliedPromotion, 0, len(applied))
+	for _, a := range applied {
+		out = append(out, openapi.AppliedPromotion{Id: a.RuleID, Name: a.Name, Times: int(a.Times), DiscountCents: a.DiscountCents})
+	}
+	return &out
+}
+
+func toOpenAPIPayment(p service.PaymentResult) openapi.Payment {
+	out := openapi.Payment{
+		Id:          p.ID,
+		Status:      openapi.PaymentStatus(p.Status),
+		AmountCents: p.AmountCents,
+		Currency:    p.Currency,
+	}
+	if p.NextActionURL != "" {
+		out.NextActionUrl = ptr(p.NextActionURL)
+	}
+	if p.DeclineCode != "" {
+		out.DeclineCode = ptr(p.DeclineCode)
 	}
-	return *p
+	return out
 }
@@ -9,8 +9,10 @@ import (
 	"kart/internal/config"
 	servermock "kart/internal/mocks/server"
 	"kart/internal/openapi"
+	"kart/internal/promo"
 	"kart/internal/repo"
 	"kart/internal/service"
+	"kart/internal/tax"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -53,6 +55,7 @@ func TestPlaceOrder_Handler(t *testing.T) {
 					return len(in.Items) == 2 && in.Items[0].ProductID == "10" && in.Items[0].Quantity == 1 && in.Items[1].ProductID == "11" && in.Items[1].Quantity == 2
 				})).Return(service.PlaceOrderResult{
 					OrderID: "order-id",
+					Status:  "placed",
 					Items: []service.OrderItemInput{
 						{ProductID: "10", Quantity: 1},
 						{ProductID: "11", Quantity: 2},
@@ -61,10 +64,27 @@ func TestPlaceOrder_Handler(t *testing.T) {
 						{ID: "10", Name: "Product 10", Category: "Category A", PriceCents: 1000},
 						{ID: "11", Name: "Product 11", Category: "Category B", PriceCents: 2000},
 					},
+					Pricing: service.Pricing{Currency: "AUD"},
 				}, nil)
 			},
 			wantStatus: 200,
 		},
+		{
+			name: "payment declined",
+			body: mkBody([]item{{"10", 1}}),
+			setupMock: func(m *servermock.OrderService) {
+				m.On("PlaceOrder", mock.Anything, mock.Anything).Return(service.PlaceOrderResult{}, service.ErrPaymentDeclined)
+			},
+			wantStatus: 402,
+		},
+		{
+			name: "payment provider down",
+			body: mkBody([]item{{"10", 1}}),
+			setupMock: func(m *servermock.OrderService) {
+				m.On("PlaceOrder", mock.Anything, mock.Anything).Return(service.PlaceOrderResult{}, service.ErrPaymentUnavailable)
+			},
+			wantStatus: 503,
+		},
 		{
 			name:       "bad json",
 			body:       []byte("{"),
@@ -91,13 +111,105 @@ func TestPlaceOrder_Handler(t *testing.T) {
 			c.setupMock(m)
 			s := &Server{Orders: m, Cfg: config.Config{APIKey: "apitest"}}
 
-			rr := httptest.NewRecorder()
 			req := httptest.NewRequest("POST", "/order", bytes.NewReader(c.body))
+			rr := recordValidated(t, req)
 			req.Header.Set("Content-Type", "application/json")
 			req.Header.Set("api_key", "apitest")
 
-			s.PlaceOrder(rr, req)
+			s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestConfirmOrderPayment_Handler(t *testing.T) {
+	type tc struct {
+		name       string
+		pay        service.PaymentResult
+		err        error
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "authorized", pay: service.PaymentResult{ID: "p1", Status: "authorized", AmountCents: 1000, Currency: "AUD"}, wantStatus: 200},
+		{name: "no payment", err: repo.ErrNotFound, wantStatus: 404},
+		{name: "not pending", err: service.ErrPaymentNotPending, wantStatus: 409},
+		{name: "declined", err: service.ErrPaymentDeclined, wantStatus: 402},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			m.On("ConfirmPayment", mock.Anything, "o1").Return(c.pay, c.err)
+			s := &Server{Orders: m}
+
+			req := httptest.NewRequest("POST", "/order/o1/payment/confirm", nil)
+			rr := recordValidated(t, req)
+			s.ConfirmOrderPayment(rr, req, "o1")
 			assert.Equal(t, c.wantStatus, rr.Code)
 		})
 	}
 }
+
+func TestPlaceOrder_TaxBreakdown(t *testing.T) {
+	m := servermock.NewOrderService(t)
+	m.On("PlaceOrder", mock.Anything, mock.Anything).Return(service.PlaceOrderResult{
+		OrderID:    "o1",
+		Status:     "placed",
+		TotalCents: 1100,
+		Items:      []service.OrderItemInput{{ProductID: "10", Quantity: 1}},
+		Pricing: service.Pricing{
+			Currency:      "AUD",
+			Lines:         []service.PricedLine{{ProductID: "10", Quantity: 1, UnitPriceCents: 1100, TaxClass: "standard", TaxCents: 100}},
+			SubtotalCents: 1100,
+			TaxCents:      100,
+			TotalCents:    1100,
+			TaxInclusive:  true,
+			Taxes:         []tax.ClassTotal{{Class: "standard", RateBasisPoints: 1000, TaxableCents: 1100, TaxCents: 100}},
+		},
+	}, nil)
+	s := &Server{Orders: m}
+
+	req := httptest.NewRequest("POST", "/order", bytes.NewReader([]byte(`{"items":[{"productId":"10","quantity":1}]}`)))
+	rr := recordValidated(t, req)
+	s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+
+	var got openapi.Order
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, int64(100), *got.TaxCents)
+	assert.True(t, *got.TaxInclusive)
+	assert.Equal(t, []openapi.TaxLine{{TaxClass: "standard", RateBasisPoints: 1000, TaxableCents: 1100, TaxCents: 100}}, *got.Taxes)
+	assert.Equal(t, "standard", *(*got.Items)[0].TaxClass)
+	assert.Equal(t, "11.00", *got.Total)
+	assert.Equal(t, "AUD", *got.Currency)
+}
+
+func TestPlaceOrder_PromotionBreakdown(t *testing.T) {
+	m := servermock.NewOrderService(t)
+	m.On("PlaceOrder", mock.Anything, mock.Anything).Return(service.PlaceOrderResult{
+		OrderID:    "o1",
+		Status:     "placed",
+		TotalCents: 2000,
+		Items:      []service.OrderItemInput{{ProductID: "10", Quantity: 2}, {ProductID: "12", Quantity: 1}},
+		Pricing: service.Pricing{
+			Currency: "AUD",
+			Lines: []service.PricedLine{
+				{ProductID: "10", Quantity: 2, UnitPriceCents: 1000},
+				{ProductID: "12", Quantity: 1, UnitPriceCents: 500, DiscountCents: 500},
+			},
+			SubtotalCents: 2500,
+			DiscountCents: 500,
+			TotalCents:    2000,
+			Promotions:    []promo.Applied{{RuleID: "b2gl", Name: "Buy 2 waffles get a latte free", Times: 1, DiscountCents: 500}},
+		},
+	}, nil)
+	s := &Server{Orders: m}
+
+	req := httptest.NewRequest("POST", "/order", bytes.NewReader([]byte(`{"items":[{"productId":"10","quantity":2},{"productId":"12","quantity":1}]}`)))
+	rr := recordValidated(t, req)
+	s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+
+	var got openapi.Order
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, int64(500), *got.DiscountCents)
+	assert.Equal(t, []openapi.AppliedPromotion{{Id: "b2gl", Name: "Buy 2 waffles get a latte free", Times: 1, DiscountCents: 500}}, *got.Promotions)
+	assert.Equal(t, int64(500), *(*got.Items)[1].DiscountCents)
+}
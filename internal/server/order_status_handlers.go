@@ -0,0 +1,155 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"kart/internal/openapi"
+	"kart/internal/orderstatus"
+	"kart/internal/service"
+	"kart/internal/tenant"
+)
+
+// WebSocket timings: the client must answer each ping within wsPongWait, and
+// a write that cannot complete within wsWriteWait marks the client as too slow.
+var (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = 50 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  512,
+	WriteBufferSize: 1024,
+	// Access is authorized by the per-order token, not by cookies, so
+	// cross-origin customer apps are allowed to connect.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// UpdateOrderStatus PUT /order/{orderId}/status
+func (s *Server) UpdateOrderStatus(w http.ResponseWriter, r *http.Request, orderId string) {
+	var req openapi.OrderStatusUpdate
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	o, err := s.Orders.UpdateStatus(r.Context(), service.UpdateStatusInput{
+		OrderID: orderId,
+		Status:  string(req.Status),
+		ETA:     req.Eta,
+	})
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAPIStatusEvent(service.OrderStatusEvent(o)))
+}
+
+// SubscribeOrderStatus GET /order/{orderId}/events
+func (s *Server) SubscribeOrderStatus(w http.ResponseWriter, r *http.Request, orderId string, params openapi.SubscribeOrderStatusParams) {
+	if s.StatusTokens == nil || s.StatusHub == nil {
+		writeError(w, http.StatusServiceUnavailable, "status_channel_unavailable", "order status channel unavailable")
+		return
+	}
+	storeID, _ := tenant.StoreID(r.Context())
+	if err := s.StatusTokens.Verify(params.Token, storeID, orderId); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_status_token", err.Error())
+		return
+	}
+
+	o, err := s.Orders.Get(r.Context(), orderId)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	// Subscribe before upgrading so no transition between the snapshot and
+	// the subscription is missed.
+	sub, err := s.StatusHub.Subscribe(orderId)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "shutting_down", "server shutting down")
+		return
+	}
+	defer sub.Close()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied to the client
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	streamOrderStatus(conn, sub, service.OrderStatusEvent(o))
+}
+
+// streamOrderStatus writes the snapshot and subsequent events to conn until
+// the order reaches a terminal status, the client goes away or the hub closes.
+func streamOrderStatus(conn *websocket.Conn, sub *orderstatus.Subscription, snapshot orderstatus.Event) {
+	// The reader only exists to process pong and close frames.
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		conn.SetReadLimit(512)
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	send := func(ev orderstatus.Event) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(toOpenAPIStatusEvent(ev)); err != nil {
+			return false
+		}
+		if service.IsTerminalStatus(ev.Status) {
+			closeWS(conn, websocket.CloseNormalClosure, "order "+ev.Status)
+			return false
+		}
+		return true
+	}
+
+	if !send(snapshot) {
+		return
+	}
+	for {
+		select {
+		case ev := <-sub.Events():
+			if !send(ev) {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-sub.Done():
+			closeWS(conn, websocket.CloseGoingAway, "server shutting down")
+			return
+		case <-readDone:
+			return
+		}
+	}
+}
+
+func closeWS(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+}
+
+func toOpenAPIStatusEvent(ev orderstatus.Event) openapi.OrderStatusEvent {
+	return openapi.OrderStatusEvent{
+		OrderId: ev.OrderID,
+		Status:  openapi.OrderStatus(ev.Status),
+		Eta:     ev.ETA,
+		At:      ev.At,
+	}
+}
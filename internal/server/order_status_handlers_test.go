@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/orderstatus"
+	"kart/internal/repo"
+	"kart/internal/service"
+	"kart/internal/tenant"
+)
+
+func TestUpdateOrderStatus_Handler(t *testing.T) {
+	type tc struct {
+		name       string
+		body       string
+		setupMock  func(m *servermock.OrderService)
+		wantStatus int
+	}
+	cases := []tc{
+		{
+			name: "ok",
+			body: `{"status":"preparing","eta":"2025-10-01T12:30:00Z"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(in service.UpdateStatusInput) bool {
+					return in.OrderID == "o1" && in.Status == "preparing" && in.ETA != nil
+				})).Return(repo.Order{ID: "o1", Status: "preparing"}, nil)
+			},
+			wantStatus: 200,
+		},
+		{
+			name: "not found",
+			body: `{"status":"preparing"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("UpdateStatus", mock.Anything, mock.Anything).Return(repo.Order{}, repo.ErrNotFound)
+			},
+			wantStatus: 404,
+		},
+		{
+			name: "invalid transition",
+			body: `{"status":"completed"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("UpdateStatus", mock.Anything, mock.Anything).Return(repo.Order{}, service.ErrInvalidStatusTransition)
+			},
+			wantStatus: 409,
+		},
+		{
+			name:       "bad json",
+			body:       `{`,
+			setupMock:  func(m *servermock.OrderService) {},
+			wantStatus: 400,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			c.setupMock(m)
+			s := &Server{Orders: m}
+
+			req := httptest.NewRequest("PUT", "/order/o1/status", strings.NewReader(c.body))
+			rr := recordValidated(t, req)
+			s.UpdateOrderStatus(rr, req, "o1")
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestSubscribeOrderStatus_Rejects(t *testing.T) {
+	signer := orderstatus.NewTokenSigner("secret", time.Minute)
+	tok, _ := signer.Issue("default", "o1")
+
+	type tc struct {
+		name       string
+		token      string
+		setupMock  func(m *servermock.OrderService)
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "bad token", token: "nope", setupMock: func(m *servermock.OrderService) {}, wantStatus: 401},
+		{
+			name:  "order missing",
+			token: tok,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Get", mock.Anything, "o1").Return(repo.Order{}, repo.ErrNotFound)
+			},
+			wantStatus: 404,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			c.setupMock(m)
+			s := &Server{Orders: m, StatusHub: orderstatus.NewHub(1), StatusTokens: signer}
+
+			req := httptest.NewRequest("GET", "/order/o1/events", nil)
+			rr := recordValidated(t, req)
+			req = req.WithContext(tenant.WithStore(req.Context(), "default"))
+			s.SubscribeOrderStatus(rr, req, "o1", openapi.SubscribeOrderStatusParams{Token: c.token})
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestSubscribeOrderStatus_Stream(t *testing.T) {
+	m := servermock.NewOrderService(t)
+	m.On("Get", mock.Anything, "o1").Return(repo.Order{ID: "o1", Status: "placed"}, nil)
+	hub := orderstatus.NewHub(4)
+	signer := orderstatus.NewTokenSigner("secret", time.Minute)
+	s := &Server{Orders: m, StatusHub: hub, StatusTokens: signer}
+
+	r := chi.NewRouter()
+	r.Get("/order/{orderId}/events", func(w http.ResponseWriter, r *http.Request) {
+		s.SubscribeOrderStatus(w, r, chi.URLParam(r, "orderId"), openapi.SubscribeOrderStatusParams{Token: r.URL.Query().Get("token")})
+	})
+	ts := httptest.NewServer(Tenancy{DefaultStore: "default"}.Middleware(r))
+	defer ts.Close()
+
+	tok, _ := signer.Issue("default", "o1")
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/order/o1/events?token=" + tok
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var ev openapi.OrderStatusEvent
+	require.NoError(t, conn.ReadJSON(&ev))
+	assert.Equal(t, openapi.OrderStatusPlaced, ev.Status)
+
+	hub.Publish(orderstatus.Event{OrderID: "o1", Status: "preparing"})
+	require.NoError(t, conn.ReadJSON(&ev))
+	assert.Equal(t, openapi.OrderStatusPreparing, ev.Status)
+
+	// shutting the hub down closes the socket with "going away"
+	hub.Close()
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+	require.NoError(t, hub.Wait(context.Background()))
+}
+
+func TestPlaceOrder_IssuesStatusToken(t *testing.T) {
+	m := servermock.NewOrderService(t)
+	m.On("PlaceOrder", mock.Anything, mock.Anything).Return(service.PlaceOrderResult{OrderID: "o1", Status: "placed", Pricing: service.Pricing{Currency: "AUD"}}, nil)
+	signer := orderstatus.NewTokenSigner("secret", time.Minute)
+	s := &Server{Orders: m, StatusTokens: signer}
+
+	req := httptest.NewRequest("POST", "/order", bytes.NewBufferString(`{"items":[{"productId":"10","quantity":1}]}`))
+	rr := recordValidated(t, req)
+	req = req.WithContext(tenant.WithStore(req.Context(), "default"))
+	s.PlaceOrder(rr, req, openapi.PlaceOrderParams{})
+	require.Equal(t, 200, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"statusToken"`)
+	assert.Contains(t, rr.Body.String(), `"status":"placed"`)
+}
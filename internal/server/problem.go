@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	oapimw "github.com/oapi-codegen/nethttp-middleware"
+
+	"kart/internal/auth"
+	"kart/internal/openapi"
+	"kart/internal/service"
+)
+
+// problemTypePrefix namespaces the problem type URIs; the code follows it.
+// The URIs are identifiers, not links, so they stay stable wherever the API
+// is hosted.
+const problemTypePrefix = "urn:kart:problem:"
+
+// statusByKind is the HTTP status for each kind of service error.
+var statusByKind = map[service.Kind]int{
+	service.KindValidation:  http.StatusUnprocessableEntity,
+	service.KindNotFound:    http.StatusNotFound,
+	service.KindConflict:    http.StatusConflict,
+	service.KindUnavailable: http.StatusServiceUnavailable,
+}
+
+// statusByCode overrides statusByKind where HTTP has a more specific status.
+var statusByCode = map[string]int{
+	"payment_declined":        http.StatusPaymentRequired,
+	"cart_expired":            http.StatusGone,
+	"payments_not_configured": http.StatusNotImplemented,
+}
+
+func newProblem(status int, code, detail string) openapi.Problem {
+	p := openapi.Problem{
+		Type:   problemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+	}
+	if detail != "" {
+		p.Detail = &detail
+	}
+	return p
+}
+
+// problemFor maps err to the problem replied to the client. Only what the
+// services wrote themselves reaches clients: errors they do not classify are
+// reported as a bare 500, and a cause wrapped with a service error, such as a
+// database or payment provider failure, is left out of the detail. Either is
+// logged in full.
+func problemFor(ctx context.Context, err error) openapi.Problem {
+	e, ok := service.AsError(err)
+	if !ok {
+		slog.ErrorContext(ctx, "request failed", "err", err)
+		return newProblem(http.StatusInternalServerError, "internal", "internal error")
+	}
+	status, ok := statusByCode[e.Code]
+	if !ok {
+		status = statusByKind[e.Kind]
+	}
+	detail := service.Detail(err)
+	if err.Error() != detail {
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "request failed", "code", e.Code, "err", err)
+	}
+	return newProblem(status, e.Code, detail)
+}
+
+func writeProblem(w http.ResponseWriter, p openapi.Problem) {
+	writeBody(w, p.Status, "application/problem+json", p)
+}
+
+// writeServiceError replies with the problem for an error from a service,
+// asking the client to retry later when a dependency was unavailable.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	p := problemFor(r.Context(), err)
+	if p.Status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(int(unavailableRetryAfter.Seconds())))
+	}
+	writeProblem(w, p)
+}
+
+// validationErrorHandler replies to requests the OpenAPI validator rejects.
+// Invalid requests list each field at fault, and a valid key lacking the
+// operation's scope gets 403 rather than 401.
+func validationErrorHandler(_ context.Context, err error, w http.ResponseWriter, _ *http.Request, opts oapimw.ErrorHandlerOpts) {
+	// Validator messages are multi-line; the first line says enough.
+	detail, _, _ := strings.Cut(err.Error(), "\n")
+	var reqErr *openapi3filter.RequestError
+	var secErr *openapi3filter.SecurityRequirementsError
+	switch {
+	case errors.Is(err, auth.ErrInsufficientScope):
+		writeError(w, http.StatusForbidden, "insufficient_scope", detail)
+	case errors.As(err, &secErr):
+		writeError(w, http.StatusUnauthorized, "unauthorized", detail)
+	case errors.Is(err, routers.ErrMethodNotAllowed):
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", detail)
+	case errors.Is(err, routers.ErrPathNotFound):
+		writeError(w, http.StatusNotFound, "not_found", detail)
+	case errors.As(err, &reqErr):
+		p := newProblem(http.StatusBadRequest, "invalid_request", requestErrorDetail(reqErr))
+		p.Errors = ptr(requestErrorFields(reqErr))
+		writeProblem(w, p)
+	default:
+		writeError(w, opts.StatusCode, "invalid_request", detail)
+	}
+}
+
+// paramErrorHandler replies to requests whose parameters the generated
+// handlers cannot bind.
+func paramErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+}
+
+func requestErrorDetail(e *openapi3filter.RequestError) string {
+	switch {
+	case e.Parameter != nil:
+		return "invalid " + e.Parameter.In + " parameter " + e.Parameter.Name
+	case e.RequestBody != nil:
+		return "invalid request body"
+	}
+	return "invalid request"
+}
+
+// requestErrorFields lists what is wrong with each field, pointing into the
+// body or naming the parameter. Schema errors carry a reason that never
+// includes the offending value.
+func requestErrorFields(e *openapi3filter.RequestError) []openapi.ProblemField {
+	errs := []error{e.Err}
+	var multi openapi3.MultiError
+	if errors.As(e.Err, &multi) {
+		errs = multi
+	}
+	fields := make([]openapi.ProblemField, 0, len(errs))
+	for _, err := range errs {
+		f := openapi.ProblemField{Detail: e.Reason}
+		var schemaErr *openapi3.SchemaError
+		if errors.As(err, &schemaErr) {
+			f.Detail = schemaErr.Reason
+			if e.RequestBody != nil {
+				f.Pointer = ptr(jsonPointer(schemaErr.JSONPointer()))
+			}
+		} else if err != nil && f.Detail == "" {
+			f.Detail, _, _ = strings.Cut(err.Error(), "\n")
+		}
+		if e.Parameter != nil {
+			f.Parameter = ptr(e.Parameter.Name)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// jsonPointer renders path as a URI fragment JSON Pointer (RFC 6901).
+func jsonPointer(path []string) string {
+	var b strings.Builder
+	b.WriteString("#")
+	for _, seg := range path {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(seg))
+	}
+	return b.String()
+}
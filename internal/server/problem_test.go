@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestProblemFor(t *testing.T) {
+	_, detailed := (&service.PriceLists{BaseCurrency: "AUD"}).Resolve(context.Background(), "EUR")
+	dbErr := errors.New(`pq: could not serialize access due to concurrent update`)
+
+	type tc struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantLog    string
+	}
+	cases := []tc{
+		{name: "validation", err: service.ErrCartEmpty, wantStatus: 422, wantCode: "cart_empty", wantDetail: "cart is empty"},
+		{
+			name: "keeps the service's detail", err: detailed,
+			wantStatus: 422, wantCode: "currency_unavailable", wantDetail: "no price list for currency: EUR",
+		},
+		{
+			name: "detail joined with a database error", err: errors.Join(detailed, dbErr),
+			wantStatus: 422, wantCode: "currency_unavailable", wantDetail: "no price list for currency: EUR", wantLog: dbErr.Error(),
+		},
+		{
+			name: "wrapped cause is hidden", err: fmt.Errorf("%w: %v", service.ErrRefundExceeded, errors.Join(errors.New("amount exceeded"), dbErr)),
+			wantStatus: 422, wantCode: "refund_exceeded", wantDetail: "refund exceeds the amount still refundable", wantLog: dbErr.Error(),
+		},
+		{name: "conflict", err: service.ErrCartCheckedOut, wantStatus: 409, wantCode: "cart_checked_out", wantDetail: "cart already checked out"},
+		{name: "status override", err: service.ErrPaymentDeclined, wantStatus: 402, wantCode: "payment_declined", wantDetail: "payment declined"},
+		{name: "gone", err: service.ErrCartExpired, wantStatus: 410, wantCode: "cart_expired", wantDetail: "cart expired"},
+		{
+			name: "not implemented", err: service.ErrPaymentsNotConfigured,
+			wantStatus: 501, wantCode: "payments_not_configured", wantDetail: "no payment provider is configured",
+		},
+		{
+			name: "unavailable hides the cause", err: fmt.Errorf("%w: dial tcp 10.0.0.7:443: i/o timeout", service.ErrPaymentUnavailable),
+			wantStatus: 503, wantCode: "payment_unavailable", wantDetail: "payment provider unavailable", wantLog: "dial tcp 10.0.0.7:443: i/o timeout",
+		},
+		{name: "repository conflict", err: repo.ErrCouponRedeemed, wantStatus: 409, wantCode: "coupon_redeemed", wantDetail: "coupon already redeemed"},
+		{name: "no rows", err: fmt.Errorf("get cart: %w", repo.ErrNotFound), wantStatus: 404, wantCode: "not_found", wantDetail: "not found"},
+		{
+			name: "unexpected", err: errors.New(`pq: relation "carts" does not exist`),
+			wantStatus: 500, wantCode: "internal", wantDetail: "internal error", wantLog: `pq: relation \"carts\" does not exist`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var logs strings.Builder
+			prev := slog.Default()
+			slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+			t.Cleanup(func() { slog.SetDefault(prev) })
+
+			p := problemFor(context.Background(), c.err)
+			assert.Equal(t, c.wantStatus, p.Status)
+			assert.Equal(t, c.wantCode, p.Code)
+			assert.Equal(t, "urn:kart:problem:"+c.wantCode, p.Type)
+			assert.Equal(t, c.wantDetail, deref(p.Detail))
+			assert.NotEmpty(t, p.Title)
+			if c.wantLog != "" {
+				assert.Contains(t, logs.String(), c.wantLog)
+			}
+		})
+	}
+}
+
+func TestRouter_Problems(t *testing.T) {
+	tn := Tenancy{DefaultStore: "default", LegacyAPIKey: "apitest"}
+	h, err := NewRouter(tn, openapi.Unimplemented{}, RouterOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil)), ResponseValidation: ResponseValidationFail})
+	require.NoError(t, err)
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		apiKey     string
+		wantStatus int
+		wantCode   string
+		wantFields []openapi.ProblemField
+	}
+	cases := []tc{
+		{
+			name: "invalid body", method: "POST", path: "/cart/c1/items", apiKey: "apitest",
+			body:       `{"productId":"10","quantity":0}`,
+			wantStatus: 400, wantCode: "invalid_request",
+			wantFields: []openapi.ProblemField{{Detail: "number must be at least 1", Pointer: ptr("#/quantity")}},
+		},
+		{
+			name: "invalid parameter", method: "GET", path: "/product?currency=dollars",
+			wantStatus: 400, wantCode: "invalid_request",
+		},
+		{
+			name: "invalid product id", method: "GET", path: "/product/waffle%20choc",
+			wantStatus: 400, wantCode: "invalid_request",
+			wantFields: []openapi.ProblemField{{Detail: `string doesn't match the regular expression "^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$"`, Parameter: ptr("productId")}},
+		},
+		{
+			name: "invalid slug", method: "GET", path: "/product/slug/Chicken_Waffle",
+			wantStatus: 400, wantCode: "invalid_request",
+		},
+		{name: "no credentials", method: "POST", path: "/cart", wantStatus: 401, wantCode: "unauthorized"},
+		{name: "unknown route", method: "GET", path: "/nope", wantStatus: 404, wantCode: "not_found"},
+		{name: "method not allowed", method: "DELETE", path: "/cart", wantStatus: 405, wantCode: "method_not_allowed"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			if c.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if c.apiKey != "" {
+				req.Header.Set("api_key", c.apiKey)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+			var p openapi.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &p))
+			assert.Equal(t, c.wantStatus, p.Status)
+			assert.Equal(t, c.wantCode, p.Code)
+			if c.wantFields != nil {
+				require.NotNil(t, p.Errors)
+				assert.Equal(t, c.wantFields, *p.Errors)
+			}
+		})
+	}
+}
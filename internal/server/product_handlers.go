@@ -1,44 +1,100 @@
 package server
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
+	"kart/internal/money"
 	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
 )
 
 // ListProducts GET /product
-func (s *Server) ListProducts(w http.ResponseWriter, r *http.Request) {
-	ps, err := s.Products.List(r.Context())
+func (s *Server) ListProducts(w http.ResponseWriter, r *http.Request, params openapi.ListProductsParams) {
+	ps, err := s.Products.List(r.Context(), requestCurrency(params.Currency, params.AcceptCurrency))
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "internal error")
+		writeProductError(w, r, err)
 		return
 	}
 	out := make([]openapi.Product, 0, len(ps))
 	for _, p := range ps {
-		price := float32(p.PriceCents) / 100.0
-		out = append(out, openapi.Product{
-			Id:       ptr(p.ID),
-			Name:     ptr(p.Name),
-			Category: ptr(p.Category),
-			Price:    ptr(price),
-		})
+		out = append(out, s.toOpenAPIPricedProduct(p))
 	}
 	writeJSON(w, http.StatusOK, out)
 }
 
 // GetProduct GET /product/{productId}
-func (s *Server) GetProduct(w http.ResponseWriter, r *http.Request, productId int64) {
-	p, err := s.Products.Get(r.Context(), strconv.FormatInt(productId, 10))
+func (s *Server) GetProduct(w http.ResponseWriter, r *http.Request, productId string, params openapi.GetProductParams) {
+	p, err := s.Products.Get(r.Context(), productId, requestCurrency(params.Currency, params.AcceptCurrency))
 	if err != nil {
-		writeError(w, http.StatusNotFound, "product not found")
+		writeProductError(w, r, err)
 		return
 	}
-	price := float32(p.PriceCents) / 100.0
-	writeJSON(w, http.StatusOK, openapi.Product{
-		Id:       ptr(p.ID),
-		Name:     ptr(p.Name),
-		Category: ptr(p.Category),
-		Price:    ptr(price),
-	})
+	writeJSON(w, http.StatusOK, s.toOpenAPIPricedProduct(p))
+}
+
+// GetProductBySlug GET /product/slug/{slug}
+func (s *Server) GetProductBySlug(w http.ResponseWriter, r *http.Request, slug string, params openapi.GetProductBySlugParams) {
+	p, err := s.Products.GetBySlug(r.Context(), slug, requestCurrency(params.Currency, params.AcceptCurrency))
+	if err != nil {
+		writeProductError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.toOpenAPIPricedProduct(p))
+}
+
+// writeProductError replies 404 for the product the path names, and 406 when
+// the catalog is not priced in the currency asked for, since reads negotiate
+// it like a media type.
+func writeProductError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		writeError(w, http.StatusNotFound, "product_not_found", err.Error())
+	case errors.Is(err, service.ErrCurrencyUnavailable):
+		writeError(w, http.StatusNotAcceptable, "currency_unavailable", err.Error())
+	default:
+		writeServiceError(w, r, err)
+	}
+}
+
+// toOpenAPIProduct renders p, whose PriceCents is in currency.
+func (s *Server) toOpenAPIProduct(p repo.Product, currency string) openapi.Product {
+	m := money.New(int64(p.PriceCents), currency)
+	var price openapi.Product_Price
+	if s.Cfg.LegacyFloatPrices {
+		_ = price.FromLegacyPrice(m.Float64())
+	} else {
+		_ = price.FromDecimalAmount(m.String())
+	}
+	out := openapi.Product{
+		Id:         ptr(p.ID),
+		Name:       ptr(p.Name),
+		Category:   ptr(p.Category),
+		Price:      &price,
+		PriceCents: ptr(m.Amount),
+		Currency:   ptr(m.Currency),
+	}
+	if p.Slug.Valid {
+		out.Slug = ptr(p.Slug.String)
+	}
+	return out
+}
+
+// toOpenAPIPricedProduct renders p with its regular price alongside while a
+// scheduled adjustment is running.
+func (s *Server) toOpenAPIPricedProduct(p service.PricedProduct) openapi.Product {
+	out := s.toOpenAPIProduct(p.Product, p.Currency)
+	if p.Adjustment == nil {
+		return out
+	}
+	regular := money.New(int64(p.RegularPriceCents), p.Currency)
+	out.RegularPriceCents = ptr(regular.Amount)
+	out.RegularPrice = ptr(regular.String())
+	out.PriceAdjustment = &openapi.PriceAdjustment{
+		Name:       p.Adjustment.Name,
+		PercentOff: p.Adjustment.PercentOff,
+		EndsAt:     p.Adjustment.EndsAt,
+	}
+	return out
 }
@@ -1,14 +1,21 @@
 package server
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"kart/internal/config"
 	servermock "kart/internal/mocks/server"
 	"kart/internal/openapi"
+	"kart/internal/service"
 	"kart/internal/sqlc"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -24,23 +31,32 @@ func TestListProducts_Handler(t *testing.T) {
 		{
 			name: "ok two products",
 			mockSetup: func(m *servermock.ProductService) {
-				m.On("List", mock.Anything).Return([]sqlc.Product{{ID: "1"}, {ID: "2"}}, nil)
+				m.On("List", mock.Anything, "").Return([]service.PricedProduct{{Product: sqlc.Product{ID: "1"}, Currency: "AUD"}, {Product: sqlc.Product{ID: "2"}, Currency: "AUD"}}, nil)
 			},
 			wantStatus: 200,
 			wantLen:    2,
 		},
 		{
-			name:       "ok empty",
-			mockSetup:  func(m *servermock.ProductService) { m.On("List", mock.Anything).Return([]sqlc.Product{}, nil) },
+			name: "ok empty",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("List", mock.Anything, "").Return([]service.PricedProduct{}, nil)
+			},
 			wantStatus: 200,
 			wantLen:    0,
 		},
 		{
 			name:       "service error",
-			mockSetup:  func(m *servermock.ProductService) { m.On("List", mock.Anything).Return(nil, assert.AnError) },
+			mockSetup:  func(m *servermock.ProductService) { m.On("List", mock.Anything, "").Return(nil, assert.AnError) },
 			wantStatus: 500,
 			wantLen:    0,
 		},
+		{
+			name: "pool exhausted",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("List", mock.Anything, "").Return(nil, fmt.Errorf("list products: %w", context.DeadlineExceeded))
+			},
+			wantStatus: 503,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -48,11 +64,14 @@ func TestListProducts_Handler(t *testing.T) {
 			c.mockSetup(m)
 			s := &Server{Products: m}
 
-			rr := httptest.NewRecorder()
 			req := httptest.NewRequest("GET", "/product", nil)
-			s.ListProducts(rr, req)
+			rr := recordValidated(t, req)
+			s.ListProducts(rr, req, openapi.ListProductsParams{})
 
 			assert.Equal(t, c.wantStatus, rr.Code)
+			if c.wantStatus == 503 {
+				assert.Equal(t, "5", rr.Header().Get("Retry-After"))
+			}
 			var got []openapi.Product
 			_ = json.Unmarshal(rr.Body.Bytes(), &got)
 			if c.wantStatus == 200 {
@@ -65,35 +84,196 @@ func TestListProducts_Handler(t *testing.T) {
 func TestGetProduct_Handler(t *testing.T) {
 	type tc struct {
 		name      string
-		productID int64
+		productID string
 		mockSetup func(m *servermock.ProductService)
 		want      int
 	}
 	cases := []tc{
 		{
 			name:      "ok",
-			productID: 1,
-			mockSetup: func(m *servermock.ProductService) { m.On("Get", mock.Anything, "1").Return(sqlc.Product{ID: "1"}, nil) },
-			want:      200,
+			productID: "1",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("Get", mock.Anything, "1", "").Return(service.PricedProduct{Product: sqlc.Product{ID: "1"}, Currency: "AUD"}, nil)
+			},
+			want: 200,
 		},
 		{
 			name:      "not found",
-			productID: 2,
+			productID: "2",
 			mockSetup: func(m *servermock.ProductService) {
-				m.On("Get", mock.Anything, "2").Return(sqlc.Product{}, assert.AnError)
+				m.On("Get", mock.Anything, "2", "").Return(service.PricedProduct{}, service.ErrProductNotFound)
 			},
 			want: 404,
 		},
+		{
+			name:      "lookup fails",
+			productID: "3",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("Get", mock.Anything, "3", "").Return(service.PricedProduct{}, assert.AnError)
+			},
+			want: 500,
+		},
+		{
+			name:      "database unavailable",
+			productID: "4",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("Get", mock.Anything, "4", "").Return(service.PricedProduct{}, &pgconn.PgError{Code: "53300", Message: "too many connections"})
+			},
+			want: 503,
+		},
+		{
+			name:      "text id",
+			productID: "waffle-choc",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("Get", mock.Anything, "waffle-choc", "").Return(service.PricedProduct{Product: sqlc.Product{ID: "waffle-choc"}, Currency: "AUD"}, nil)
+			},
+			want: 200,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			m := servermock.NewProductService(t)
 			c.mockSetup(m)
 			s := &Server{Products: m}
-			rr := httptest.NewRecorder()
 			req := httptest.NewRequest("GET", "/product/x", nil)
-			s.GetProduct(rr, req, c.productID)
+			rr := recordValidated(t, req)
+			s.GetProduct(rr, req, c.productID, openapi.GetProductParams{})
 			assert.Equal(t, c.want, rr.Code)
 		})
 	}
 }
+
+func TestGetProductBySlug_Handler(t *testing.T) {
+	type tc struct {
+		name      string
+		slug      string
+		mockSetup func(m *servermock.ProductService)
+		want      int
+		wantBody  string
+	}
+	cases := []tc{
+		{
+			name: "ok",
+			slug: "chicken-waffle",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("GetBySlug", mock.Anything, "chicken-waffle", "").Return(service.PricedProduct{
+					Product:  sqlc.Product{ID: "10", Slug: sql.NullString{String: "chicken-waffle", Valid: true}, PriceCents: 1299},
+					Currency: "AUD",
+				}, nil)
+			},
+			want:     200,
+			wantBody: `{"id":"10","slug":"chicken-waffle","name":"","category":"","price":"12.99","priceCents":1299,"currency":"AUD"}`,
+		},
+		{
+			name: "not found",
+			slug: "nope",
+			mockSetup: func(m *servermock.ProductService) {
+				m.On("GetBySlug", mock.Anything, "nope", "").Return(service.PricedProduct{}, service.ErrProductNotFound)
+			},
+			want: 404,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewProductService(t)
+			c.mockSetup(m)
+			s := &Server{Products: m}
+			req := httptest.NewRequest("GET", "/product/slug/x", nil)
+			rr := recordValidated(t, req)
+			s.GetProductBySlug(rr, req, c.slug, openapi.GetProductBySlugParams{})
+			assert.Equal(t, c.want, rr.Code)
+			if c.wantBody != "" {
+				assert.JSONEq(t, c.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestToOpenAPIProduct_Price(t *testing.T) {
+	type tc struct {
+		name   string
+		legacy bool
+		want   string
+	}
+	cases := []tc{
+		{name: "decimal string", want: `"12.99"`},
+		{name: "legacy float", legacy: true, want: `12.99`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := &Server{Cfg: config.Config{LegacyFloatPrices: c.legacy}}
+			got := s.toOpenAPIProduct(sqlc.Product{ID: "10", PriceCents: 1299}, "AUD")
+
+			b, err := json.Marshal(got)
+			assert.NoError(t, err)
+			var raw map[string]json.RawMessage
+			assert.NoError(t, json.Unmarshal(b, &raw))
+			assert.JSONEq(t, c.want, string(raw["price"]))
+			assert.JSONEq(t, `1299`, string(raw["priceCents"]))
+			assert.JSONEq(t, `"AUD"`, string(raw["currency"]))
+		})
+	}
+}
+
+func TestListProducts_Currency(t *testing.T) {
+	type tc struct {
+		name       string
+		params     openapi.ListProductsParams
+		want       string
+		err        error
+		wantStatus int
+	}
+	cases := []tc{
+		{name: "store default", wantStatus: 200},
+		{name: "accept currency first choice", params: openapi.ListProductsParams{AcceptCurrency: ptr("nzd;q=1, AUD;q=0.5")}, want: "NZD", wantStatus: 200},
+		{name: "query wins over header", params: openapi.ListProductsParams{Currency: ptr("AUD"), AcceptCurrency: ptr("NZD")}, want: "AUD", wantStatus: 200},
+		{name: "no price list", params: openapi.ListProductsParams{Currency: ptr("USD")}, want: "USD", err: service.ErrCurrencyUnavailable, wantStatus: 406},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewProductService(t)
+			m.On("List", mock.Anything, c.want).Return([]service.PricedProduct{}, c.err)
+			s := &Server{Products: m}
+
+			rr := httptest.NewRecorder()
+			s.ListProducts(rr, httptest.NewRequest("GET", "/product", nil), c.params)
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestToOpenAPIPricedProduct_Adjustment(t *testing.T) {
+	endsAt := time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC)
+	type tc struct {
+		name    string
+		product service.PricedProduct
+		want    string
+	}
+	cases := []tc{
+		{
+			name:    "regular price",
+			product: service.PricedProduct{Product: sqlc.Product{ID: "12", PriceCents: 500}, Currency: "AUD"},
+			want:    `{"id":"12","name":"","category":"","price":"5.00","priceCents":500,"currency":"AUD"}`,
+		},
+		{
+			name: "happy hour",
+			product: service.PricedProduct{
+				Product:           sqlc.Product{ID: "12", PriceCents: 350},
+				Currency:          "AUD",
+				RegularPriceCents: 500,
+				Adjustment:        &service.PriceAdjustment{ID: "coffee", Name: "Coffee happy hour", PercentOff: 30, EndsAt: endsAt},
+			},
+			want: `{"id":"12","name":"","category":"","price":"3.50","priceCents":350,"currency":"AUD",
+				"regularPrice":"5.00","regularPriceCents":500,
+				"priceAdjustment":{"name":"Coffee happy hour","percentOff":30,"endsAt":"2025-10-06T06:00:00Z"}}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := &Server{}
+			b, err := json.Marshal(s.toOpenAPIPricedProduct(c.product))
+			assert.NoError(t, err)
+			assert.JSONEq(t, c.want, string(b))
+		})
+	}
+}
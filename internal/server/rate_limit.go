@@ -0,0 +1,212 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+
+	"kart/internal/auth"
+	"kart/internal/ratelimit"
+)
+
+// Callers a RateLimit applies to.
+const (
+	// CallersAll is every caller.
+	CallersAll = ""
+	// CallersKey is callers with credentials: API keys and customers.
+	CallersKey = "key"
+	// CallersIP is callers without credentials, told apart by address.
+	CallersIP = "ip"
+)
+
+// RateLimit limits how often each caller may call the API routes it matches.
+// Customers are told apart by their token, API keys by key and client address,
+// since one storefront key serves many shoppers, and anyone else by client
+// address.
+type RateLimit struct {
+	// Method and Pattern select routes as the spec writes them ("POST",
+	// "/order"); empty selects any.
+	Method  string
+	Pattern string
+	// Callers is CallersAll, CallersKey or CallersIP.
+	Callers string
+	Limiter *ratelimit.Limiter
+}
+
+// String is the rule as ParseRateLimits reads it, less the rate. Buckets are
+// named after it.
+func (l RateLimit) String() string {
+	var parts []string
+	if l.Callers != CallersAll {
+		parts = append(parts, l.Callers)
+	}
+	if l.Method != "" {
+		parts = append(parts, l.Method)
+	}
+	if l.Pattern == "" {
+		parts = append(parts, "*")
+	} else {
+		parts = append(parts, l.Pattern)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (l RateLimit) matches(method, pattern string, authed bool) bool {
+	switch {
+	case l.Method != "" && l.Method != method,
+		l.Pattern != "" && l.Pattern != pattern,
+		l.Callers == CallersKey && !authed,
+		l.Callers == CallersIP && authed:
+		return false
+	}
+	return true
+}
+
+// ParseRateLimits parses comma-separated rules of the form
+// "[key|ip] [METHOD] PATTERN=N/PERIOD", such as "POST /order=20/1m" or
+// "ip *=300/1m". Each caller gets N requests per PERIOD on the routes a rule
+// selects, all of which may come at once. The buckets are kept in store.
+func ParseRateLimits(s string, store ratelimit.Store) ([]RateLimit, error) {
+	var out []RateLimit
+	for _, rule := range strings.Split(s, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		l, err := parseRateLimit(rule, store)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %q: %w", rule, err)
+		}
+		out = append(out, l)
+	}
+	return out, nil
+}
+
+func parseRateLimit(rule string, store ratelimit.Store) (RateLimit, error) {
+	selector, rate, ok := strings.Cut(rule, "=")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("want SELECTOR=N/PERIOD")
+	}
+	var l RateLimit
+	fields := strings.Fields(selector)
+	if len(fields) > 0 && (fields[0] == CallersKey || fields[0] == CallersIP) {
+		l.Callers, fields = fields[0], fields[1:]
+	}
+	switch len(fields) {
+	case 1:
+	case 2:
+		l.Method, fields = strings.ToUpper(fields[0]), fields[1:]
+	default:
+		return RateLimit{}, fmt.Errorf("want [key|ip] [METHOD] PATTERN before =")
+	}
+	switch p := fields[0]; {
+	case p == "*":
+	case strings.HasPrefix(p, "/"):
+		l.Pattern = p
+	default:
+		return RateLimit{}, fmt.Errorf("pattern %q must start with / or be *", p)
+	}
+
+	n, period, ok := strings.Cut(strings.TrimSpace(rate), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("want N/PERIOD after =")
+	}
+	burst, err := strconv.Atoi(n)
+	if err != nil || burst < 1 {
+		return RateLimit{}, fmt.Errorf("count %q must be a positive integer", n)
+	}
+	if period != "" && !unicode.IsDigit(rune(period[0])) {
+		period = "1" + period // "m" means "1m"
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return RateLimit{}, fmt.Errorf("period %q must be a positive duration such as 1m", period)
+	}
+	l.Limiter = &ratelimit.Limiter{Rate: float64(burst) / d.Seconds(), Burst: burst, Store: store}
+	return l, nil
+}
+
+// rateLimit refuses requests with 429 once the caller has used up a limit
+// on the route, and tells callers how much of the tightest limit they have
+// left in RateLimit-* headers (draft-ietf-httpapi-ratelimit-headers). It runs
+// inside the router, where the matched route is known. Requests are allowed
+// when the limiter's store fails.
+func rateLimit(limits []RateLimit, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(limits) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			pattern := ""
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				pattern = rctx.RoutePattern()
+			}
+			p, authed := auth.PrincipalFrom(ctx)
+			authed = authed && p.Subject != ""
+			caller := callerKey(r)
+
+			var report *ratelimit.Result
+			var reported *RateLimit
+			for i := range limits {
+				l := &limits[i]
+				if !l.matches(r.Method, pattern, authed) {
+					continue
+				}
+				res, err := l.Limiter.Take(ctx, l.String()+"|"+caller)
+				if err != nil {
+					logger.ErrorContext(ctx, "rate limit check failed", "limit", l.String(), "err", err)
+					continue
+				}
+				// Report the limit refusing the request the longest, else the
+				// one closest to refusing it.
+				switch {
+				case report == nil,
+					!res.Allowed && (report.Allowed || res.RetryAfter > report.RetryAfter),
+					res.Allowed && report.Allowed && res.Remaining < report.Remaining:
+					report, reported = &res, l
+				}
+			}
+			if report == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(report.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(report.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(report.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", report.Limit, ceilSeconds(reported.Limiter.Window())))
+			if !report.Allowed {
+				h.Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(report.RetryAfter))))
+				writeError(w, http.StatusTooManyRequests, "rate_limited", "rate limit exceeded for "+reported.String())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// callerKey tells callers apart: customers by their token's subject, API
+// keys by key and client address, and callers without credentials by client
+// address.
+func callerKey(r *http.Request) string {
+	p, ok := auth.PrincipalFrom(r.Context())
+	switch {
+	case !ok || p.Subject == "":
+		return "ip:" + clientIP(r)
+	case p.CustomerID != "":
+		return p.Subject
+	}
+	return p.Subject + "|ip:" + clientIP(r)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	"kart/internal/ratelimit"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	type tc struct {
+		name      string
+		in        string
+		want      []string
+		wantRate  float64
+		wantBurst int
+		wantErr   bool
+	}
+	cases := []tc{
+		{name: "empty", in: ""},
+		{name: "route", in: "POST /order=30/1m", want: []string{"POST /order"}, wantRate: 0.5, wantBurst: 30},
+		{name: "unit only", in: "post /order=30/m", want: []string{"POST /order"}, wantRate: 0.5, wantBurst: 30},
+		{name: "any method", in: "/cart/{cartId}/coupon=10/10s", want: []string{"/cart/{cartId}/coupon"}, wantRate: 1, wantBurst: 10},
+		{name: "callers", in: "ip *=300/1m, key GET *=600/1m", want: []string{"ip *", "key GET *"}, wantRate: 5, wantBurst: 300},
+		{name: "no rate", in: "POST /order", wantErr: true},
+		{name: "bad count", in: "POST /order=0/1m", wantErr: true},
+		{name: "bad period", in: "POST /order=5/fortnight", wantErr: true},
+		{name: "bad pattern", in: "POST order=5/1m", wantErr: true},
+		{name: "too many words", in: "ip POST /order extra=5/1m", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseRateLimits(c.in, ratelimit.NewMemory())
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, l := range got {
+				names = append(names, l.String())
+			}
+			assert.Equal(t, c.want, names)
+			if len(got) > 0 {
+				assert.InDelta(t, c.wantRate, got[0].Limiter.Rate, 1e-9)
+				assert.Equal(t, c.wantBurst, got[0].Limiter.Burst)
+			}
+		})
+	}
+}
+
+type failingLimitStore struct{}
+
+func (failingLimitStore) Take(context.Context, string, float64, int) (bool, float64, error) {
+	return false, 0, errors.New("store down")
+}
+
+func TestRouter_RateLimit(t *testing.T) {
+	const product = `{"id":"10","name":"Chicken Waffle","price":"12.99","priceCents":1299,"currency":"AUD"}`
+	stub := productStub{status: 200, contentType: "application/json", body: product}
+	newRouter := func(t *testing.T, rules string, store ratelimit.Store) func(apiKey, peer string) *httptest.ResponseRecorder {
+		limits, err := ParseRateLimits(rules, store)
+		require.NoError(t, err)
+		trusted, err := ParseTrustedProxies("10.0.0.0/8")
+		require.NoError(t, err)
+		h, err := NewRouter(Tenancy{DefaultStore: "default", LegacyAPIKey: "apitest"}, stub, RouterOptions{
+			Logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
+			ResponseValidation: ResponseValidationFail,
+			RateLimits:         limits,
+			TrustedProxies:     trusted,
+		})
+		require.NoError(t, err)
+		return func(apiKey, client string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/product/10", nil)
+			req.RemoteAddr = "10.0.0.2:4000"
+			req.Header.Set("X-Forwarded-For", client)
+			if apiKey != "" {
+				req.Header.Set("api_key", apiKey)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			return rr
+		}
+	}
+
+	t.Run("per caller", func(t *testing.T) {
+		get := newRouter(t, "GET /product/{productId}=2/1m", ratelimit.NewMemory())
+
+		rr := get("", "192.0.2.1")
+		require.Equal(t, 200, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rr.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", rr.Header().Get("RateLimit-Policy"))
+		require.Equal(t, 200, get("", "192.0.2.1").Code)
+
+		rr = get("", "192.0.2.1")
+		require.Equal(t, 429, rr.Code, rr.Body.String())
+		assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"code":"rate_limited"`)
+		assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+		// Another client address, and an API key from the same address,
+		// have buckets of their own.
+		assert.Equal(t, 200, get("", "192.0.2.2").Code)
+		assert.Equal(t, 200, get("apitest", "192.0.2.1").Code)
+	})
+
+	t.Run("tightest limit reported", func(t *testing.T) {
+		get := newRouter(t, "*=100/1m, GET /product/{productId}=5/1m, POST /order=1/1m", ratelimit.NewMemory())
+		rr := get("", "192.0.2.1")
+		require.Equal(t, 200, rr.Code)
+		assert.Equal(t, "5", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "4", rr.Header().Get("RateLimit-Remaining"))
+	})
+
+	t.Run("callers", func(t *testing.T) {
+		get := newRouter(t, "ip *=1/1m, key *=3/1m", ratelimit.NewMemory())
+		assert.Equal(t, "1", get("", "192.0.2.1").Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "3", get("apitest", "192.0.2.1").Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("shared API key", func(t *testing.T) {
+		get := newRouter(t, "GET /product/{productId}=1/1m", ratelimit.NewMemory())
+		require.Equal(t, 200, get("apitest", "192.0.2.1").Code)
+		require.Equal(t, 429, get("apitest", "192.0.2.1").Code)
+		// Shoppers behind one storefront key do not share its bucket.
+		assert.Equal(t, 200, get("apitest", "192.0.2.2").Code)
+	})
+
+	t.Run("store down", func(t *testing.T) {
+		get := newRouter(t, "GET /product/{productId}=1/1m", failingLimitStore{})
+		for range 3 {
+			rr := get("", "192.0.2.1")
+			require.Equal(t, 200, rr.Code)
+			assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("unlimited route", func(t *testing.T) {
+		get := newRouter(t, "POST /order=1/1m", ratelimit.NewMemory())
+		for range 3 {
+			rr := get("", "192.0.2.1")
+			require.Equal(t, 200, rr.Code)
+			assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+		}
+	})
+}
+
+func TestCallerKey(t *testing.T) {
+	cases := []struct {
+		name      string
+		principal *auth.Principal
+		client    string
+		want      string
+	}{
+		{name: "no credentials", client: "192.0.2.1", want: "ip:192.0.2.1"},
+		{name: "api key", principal: &auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:pos"}, client: "192.0.2.1", want: "api_key:pos|ip:192.0.2.1"},
+		{name: "customer", principal: &auth.Principal{Scheme: auth.SchemeBearer, Subject: "customer:cust_1", CustomerID: "cust_1"}, client: "192.0.2.1", want: "customer:cust_1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/product/10", nil)
+			req.RemoteAddr = c.client + ":4000"
+			if c.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *c.principal))
+			}
+			assert.Equal(t, c.want, callerKey(req))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+
+	"kart/internal/auth"
+	"kart/internal/openapi"
+	"kart/internal/service"
+)
+
+// GetOrder GET /order/{orderId}
+func (s *Server) GetOrder(w http.ResponseWriter, r *http.Request, orderId string) {
+	d, err := s.Orders.Details(r.Context(), orderId)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	// Customers only see their own orders; staff keys see every order.
+	if p, ok := auth.PrincipalFrom(r.Context()); ok && p.CustomerID != "" && d.Order.CustomerID.String != p.CustomerID {
+		writeError(w, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, toOpenAPIOrderDetails(d))
+}
+
+// RefundOrder POST /order/{orderId}/refund
+func (s *Server) RefundOrder(w http.ResponseWriter, r *http.Request, orderId string) {
+	var req openapi.RefundReq
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	// The audit trail names the credential that issued the refund, not
+	// whoever the request body claims to be.
+	p, _ := auth.PrincipalFrom(r.Context())
+	in := service.RefundInput{
+		OrderID:  orderId,
+		Reason:   req.Reason,
+		Operator: p.Subject,
+	}
+	if req.AmountCents != nil {
+		in.AmountCents = *req.AmountCents
+	}
+	if req.Lines != nil {
+		for _, l := range *req.Lines {
+			in.Lines = append(in.Lines, service.RefundLineInput{ProductID: l.ProductId, Quantity: int32(l.Quantity)})
+		}
+	}
+
+	ref, err := s.Orders.Refund(r.Context(), in)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toOpenAPIRefund(ref))
+}
+
+func toOpenAPIOrderDetails(d service.OrderDetails) openapi.Order {
+	items := make([]openapi.OrderItem, 0, len(d.Items))
+	for _, it := range d.Items {
+		items = append(items, openapi.OrderItem{
+			ProductId:        ptr(it.ProductID),
+			Quantity:         ptr(int(it.Quantity)),
+			UnitPriceCents:   ptr(it.UnitPriceCents),
+			RefundedQuantity: ptr(int(it.RefundedQuantity)),
+			TaxCents:         ptr(it.TaxCents),
+			DiscountCents:    ptr(it.DiscountCents),
+		})
+		if it.TaxClass.Valid {
+			items[len(items)-1].TaxClass = ptr(it.TaxClass.String)
+		}
+	}
+	taxes := make([]openapi.TaxLine, 0, len(d.Taxes))
+	for _, t := range d.Taxes {
+		taxes = append(taxes, openapi.TaxLine{
+			TaxClass:        t.TaxClass,
+			RateBasisPoints: int(t.RateBasisPoints),
+			TaxableCents:    t.TaxableCents,
+			TaxCents:        t.TaxCents,
+		})
+	}
+	refunds := make([]openapi.Refund, 0, len(d.Refunds))
+	for _, ref := range d.Refunds {
+		refunds = append(refunds, toOpenAPIRefund(ref))
+	}
+	out := toOpenAPIOrderSummary(d.Order)
+	out.Items = &items
+	out.Taxes = &taxes
+	out.Refunds = &refunds
+	if d.Payment != nil {
+		out.Payment = ptr(toOpenAPIPayment(*d.Payment))
+	}
+	return out
+}
+
+func toOpenAPIRefund(ref service.Refund) openapi.Refund {
+	lines := make([]openapi.RefundLine, 0, len(ref.Lines))
+	for _, l := range ref.Lines {
+		lines = append(lines, openapi.RefundLine{ProductId: l.ProductID, Quantity: int(l.Quantity), AmountCents: l.AmountCents})
+	}
+	return openapi.Refund{
+		Id:          ref.ID,
+		Status:      openapi.RefundStatus(ref.Status),
+		AmountCents: ref.AmountCents,
+		Reason:      ref.Reason,
+		Operator:    ref.Operator,
+		Lines:       lines,
+		CreatedAt:   ref.CreatedAt,
+	}
+}
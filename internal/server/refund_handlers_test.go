@@ -0,0 +1,140 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+)
+
+func TestRefundOrder_Handler(t *testing.T) {
+	type tc struct {
+		name       string
+		body       string
+		setupMock  func(m *servermock.OrderService)
+		wantStatus int
+	}
+	cases := []tc{
+		{
+			name: "line refund",
+			body: `{"lines":[{"productId":"10","quantity":1}],"reason":"cold"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Refund", mock.Anything, service.RefundInput{
+					OrderID:  "o1",
+					Lines:    []service.RefundLineInput{{ProductID: "10", Quantity: 1}},
+					Reason:   "cold",
+					Operator: "api_key:kart_ab12",
+				}).Return(service.Refund{ID: "r1", AmountCents: 1000, Status: "succeeded"}, nil)
+			},
+			wantStatus: 201,
+		},
+		{
+			name: "operator in the body is not trusted",
+			body: `{"amountCents":100,"reason":"goodwill","operator":"someone else"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Refund", mock.Anything, mock.MatchedBy(func(in service.RefundInput) bool { return in.Operator == "api_key:kart_ab12" })).
+					Return(service.Refund{ID: "r1", AmountCents: 100, Status: "succeeded", Operator: "api_key:kart_ab12"}, nil)
+			},
+			wantStatus: 201,
+		},
+		{
+			name: "amount refund exceeds captured",
+			body: `{"amountCents":9999,"reason":"goodwill","operator":"sam"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Refund", mock.Anything, mock.MatchedBy(func(in service.RefundInput) bool { return in.AmountCents == 9999 })).
+					Return(service.Refund{}, service.ErrRefundExceeded)
+			},
+			wantStatus: 422,
+		},
+		{
+			name: "nothing captured",
+			body: `{"amountCents":100,"reason":"goodwill","operator":"sam"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Refund", mock.Anything, mock.Anything).Return(service.Refund{}, service.ErrRefundNotAllowed)
+			},
+			wantStatus: 409,
+		},
+		{
+			name: "unknown order",
+			body: `{"amountCents":100,"reason":"goodwill","operator":"sam"}`,
+			setupMock: func(m *servermock.OrderService) {
+				m.On("Refund", mock.Anything, mock.Anything).Return(service.Refund{}, repo.ErrNotFound)
+			},
+			wantStatus: 404,
+		},
+		{
+			name:       "bad json",
+			body:       `{"amount":1}`,
+			setupMock:  func(m *servermock.OrderService) {},
+			wantStatus: 400,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := servermock.NewOrderService(t)
+			c.setupMock(m)
+			s := &Server{Orders: m}
+
+			req := httptest.NewRequest("POST", "/order/o1/refund", strings.NewReader(c.body))
+			req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Scheme: "api_key", Subject: "api_key:kart_ab12"}))
+			rr := recordValidated(t, req)
+			s.RefundOrder(rr, req, "o1")
+			assert.Equal(t, c.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestGetOrder_Handler(t *testing.T) {
+	m := servermock.NewOrderService(t)
+	m.On("Details", mock.Anything, "o1").Return(service.OrderDetails{
+		Order: repo.Order{ID: "o1", Status: "completed", TotalCents: 2500, RefundedCents: 1000},
+		Items: []repo.OrderItem{{ID: "i1", ProductID: "10", Quantity: 2, UnitPriceCents: 1000, RefundedQuantity: 1}},
+		Refunds: []service.Refund{{
+			ID: "r1", AmountCents: 1000, Status: "succeeded", Reason: "cold", Operator: "sam",
+			Lines: []service.RefundLine{{ProductID: "10", Quantity: 1, AmountCents: 1000}},
+		}},
+	}, nil)
+	m.On("Details", mock.Anything, "missing").Return(service.OrderDetails{}, repo.ErrNotFound)
+	s := &Server{Orders: m}
+
+	rr := httptest.NewRecorder()
+	s.GetOrder(rr, httptest.NewRequest("GET", "/order/o1", nil), "o1")
+	require.Equal(t, 200, rr.Code)
+	var got openapi.Order
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, int64(1000), *got.RefundedCents)
+	require.Len(t, *got.Refunds, 1)
+	assert.Equal(t, 1, *(*got.Items)[0].RefundedQuantity)
+
+	rr = httptest.NewRecorder()
+	s.GetOrder(rr, httptest.NewRequest("GET", "/order/missing", nil), "missing")
+	assert.Equal(t, 404, rr.Code)
+	// Customers only see their own orders.
+	m.On("Details", mock.Anything, "o2").Return(service.OrderDetails{
+		Order: repo.Order{ID: "o2", Status: "placed", CustomerID: sql.NullString{String: "cust_1", Valid: true}},
+	}, nil)
+	for customer, want := range map[string]int{"cust_1": 200, "cust_2": 404} {
+		req := httptest.NewRequest("GET", "/order/o2", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Scheme: auth.SchemeBearer, CustomerID: customer}))
+		rr = httptest.NewRecorder()
+		s.GetOrder(rr, req, "o2")
+		assert.Equal(t, want, rr.Code, customer)
+	}
+	// Staff keys see any customer's order.
+	req := httptest.NewRequest("GET", "/order/o2", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default"}))
+	rr = httptest.NewRecorder()
+	s.GetOrder(rr, req, "o2")
+	assert.Equal(t, 200, rr.Code)
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"kart/internal/logging"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID propagates the caller's X-Request-ID, or assigns a new one, and
+// puts it in the request context for logging. It is echoed on the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// validRequestID accepts up to 128 printable ASCII characters, so a caller
+// cannot inject arbitrary data into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLog logs every request once it has been served, with the route
+// pattern it matched rather than its path, so lines group by endpoint.
+func AccessLog(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r, rctx := withRouteContext(r)
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			level := slog.LevelInfo
+			if rec.Status() >= 500 {
+				level = slog.LevelError
+			}
+			l.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("route", rctx.RoutePattern()),
+				slog.Int("status", rec.Status()),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes", rec.bytes),
+			)
+		})
+	}
+}
+
+// withRouteContext gives r a chi routing context up front, so middleware
+// outside the router can read the pattern the router matched.
+func withRouteContext(r *http.Request) (*http.Request, *chi.Context) {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return r, rctx
+	}
+	rctx := chi.NewRouteContext()
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)), rctx
+}
+
+// Recoverer turns a panicking handler into a logged stack trace and a 500,
+// rather than a dropped connection.
+func Recoverer(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				l.ErrorContext(r.Context(), "panic serving request",
+					slog.String("panic", fmt.Sprint(v)),
+					slog.String("stack", string(debug.Stack())),
+				)
+				writeError(w, http.StatusInternalServerError, "internal", "internal error")
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseRecorder notes the status and body size of a response. It keeps
+// the writer hijackable for WebSocket upgrades.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not support hijacking")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (w *responseRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// Status is the response status, 200 if the handler wrote nothing.
+func (w *responseRecorder) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
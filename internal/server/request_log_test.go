@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/logging"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+)
+
+func TestRequestID(t *testing.T) {
+	type tc struct {
+		name     string
+		header   string
+		wantSame bool
+	}
+	cases := []tc{
+		{name: "propagated", header: "abc-123", wantSame: true},
+		{name: "assigned when missing"},
+		{name: "replaced when unprintable", header: "bad\x01id"},
+		{name: "replaced when too long", header: strings.Repeat("a", 129)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var seen string
+			h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = logging.RequestID(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/product", nil)
+			if c.header != "" {
+				req.Header.Set(requestIDHeader, c.header)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			require.NotEmpty(t, seen)
+			assert.Equal(t, seen, rr.Header().Get(requestIDHeader))
+			assert.Equal(t, c.wantSame, seen == c.header)
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", "json")
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Get("/order/{orderId}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusAccepted, map[string]string{"id": "o1"})
+	})
+	r.Get("/boom", func(http.ResponseWriter, *http.Request) { panic("boom") })
+	h := RequestID(AccessLog(logger)(Recoverer(logger)(r)))
+
+	type tc struct {
+		name       string
+		path       string
+		wantStatus int
+		wantRoute  string
+		wantLevel  string
+	}
+	cases := []tc{
+		{name: "route pattern", path: "/order/o1", wantStatus: 202, wantRoute: "/order/{orderId}", wantLevel: "INFO"},
+		{name: "unmatched", path: "/nope", wantStatus: 404, wantLevel: "INFO"},
+		{name: "panic", path: "/boom", wantStatus: 500, wantRoute: "/boom", wantLevel: "ERROR"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest("GET", c.path, nil)
+			req.Header.Set(requestIDHeader, "req-1")
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			var access map[string]any
+			require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &access))
+			assert.Equal(t, "request", access["msg"])
+			assert.Equal(t, c.wantLevel, access["level"])
+			assert.Equal(t, "GET", access["method"])
+			assert.Equal(t, c.wantRoute, access["route"])
+			assert.EqualValues(t, c.wantStatus, access["status"])
+			assert.EqualValues(t, rr.Body.Len(), access["bytes"])
+			assert.Equal(t, "req-1", access["request_id"])
+			assert.Contains(t, access, "latency")
+		})
+	}
+}
+
+// TestRouter_AccessLog checks that requests rejected before chi routes them,
+// by the request validator or authentication, are still logged under the
+// route they were for.
+func TestRouter_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", "json")
+	require.NoError(t, err)
+	tn := Tenancy{Keys: servermock.NewKeyAuthenticator(t), DefaultStore: "default", LegacyAPIKey: "apitest"}
+	h, err := NewRouter(tn, openapi.Unimplemented{}, RouterOptions{Logger: logger})
+	require.NoError(t, err)
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		apiKey     string
+		body       string
+		wantStatus int
+		wantRoute  string
+	}
+	cases := []tc{
+		{name: "no key", method: "POST", path: "/order", body: `{}`, wantStatus: 401, wantRoute: "/order"},
+		{name: "invalid body", method: "POST", path: "/order", apiKey: "apitest", body: `{"items":"x"}`, wantStatus: 400, wantRoute: "/order"},
+		{name: "path parameter", method: "PUT", path: "/order/o1/status", body: `{}`, wantStatus: 401, wantRoute: "/order/{orderId}/status"},
+		{name: "routed", method: "GET", path: "/product/10", wantStatus: 501, wantRoute: "/product/{productId}"},
+		{name: "unmatched", method: "GET", path: "/no/such/path", wantStatus: 404},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			req.Header.Set("Content-Type", "application/json")
+			if c.apiKey != "" {
+				req.Header.Set("api_key", c.apiKey)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			var access map[string]any
+			require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &access))
+			assert.Equal(t, "request", access["msg"])
+			assert.Equal(t, c.wantRoute, access["route"])
+		})
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", "json")
+	require.NoError(t, err)
+	h := Recoverer(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"urn:kart:problem:internal","title":"Internal Server Error","status":500,"code":"internal","detail":"internal error"}`, rr.Body.String())
+	var rec map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	assert.Equal(t, "boom", rec["panic"])
+	assert.Contains(t, rec["stack"], "runtime/debug.Stack")
+}
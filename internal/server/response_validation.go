@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/websocket"
+)
+
+// ResponseValidation chooses what happens to API responses that do not match
+// the OpenAPI spec.
+type ResponseValidation int
+
+const (
+	// ResponseValidationOff sends responses unchecked.
+	ResponseValidationOff ResponseValidation = iota
+	// ResponseValidationLog logs responses that do not match the spec and
+	// sends them unchanged.
+	ResponseValidationLog
+	// ResponseValidationFail replaces responses that do not match the spec
+	// with a 500 problem saying why, so the mismatch cannot go unnoticed.
+	ResponseValidationFail
+)
+
+// ParseResponseValidation parses "off", "log" or "fail".
+func ParseResponseValidation(s string) (ResponseValidation, error) {
+	switch s {
+	case "off":
+		return ResponseValidationOff, nil
+	case "log":
+		return ResponseValidationLog, nil
+	case "fail":
+		return ResponseValidationFail, nil
+	}
+	return 0, fmt.Errorf("unknown response validation %q, want off, log or fail", s)
+}
+
+// responseValidator checks responses against the operation the request
+// matches in the spec.
+type responseValidator struct {
+	router routers.Router
+}
+
+func newResponseValidator(spec *openapi3.T) (*responseValidator, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("route OpenAPI spec: %w", err)
+	}
+	return &responseValidator{router: router}, nil
+}
+
+// validate checks the status, headers and body of the response to r. Requests
+// matching no operation have nothing to check against and pass.
+func (v *responseValidator) validate(ctx context.Context, r *http.Request, status int, header http.Header, body []byte) error {
+	route, params, err := v.router.FindRoute(r)
+	if err != nil {
+		return nil
+	}
+	return openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+		},
+		Status: status,
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	})
+}
+
+// middleware buffers each API response and checks it against the
+// spec before sending it, handling mismatches as mode says. It is meant for
+// development and tests: buffering delays the first byte until the handler
+// returns. WebSocket upgrades are exempt since they have no response to
+// buffer.
+func (v *responseValidator) middleware(mode ResponseValidation, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if mode == ResponseValidationOff {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if websocket.IsWebSocketUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			buf := &bufferedResponse{header: http.Header{}}
+			next.ServeHTTP(buf, r)
+
+			err := v.validate(r.Context(), r, buf.Status(), buf.header, buf.body.Bytes())
+			if err != nil {
+				logger.ErrorContext(r.Context(), "response does not match OpenAPI spec",
+					"method", r.Method, "path", r.URL.Path, "status", buf.Status(), "err", err)
+				if mode == ResponseValidationFail {
+					detail, _, _ := strings.Cut(err.Error(), "\n")
+					writeError(w, http.StatusInternalServerError, "invalid_response", detail)
+					return
+				}
+			}
+			buf.sendTo(w)
+		})
+	}
+}
+
+// bufferedResponse holds a response until it has been validated.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) Status() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+func (b *bufferedResponse) sendTo(w http.ResponseWriter) {
+	for k, vs := range b.header {
+		w.Header()[k] = vs
+	}
+	w.WriteHeader(b.Status())
+	_, _ = w.Write(b.body.Bytes())
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/openapi"
+)
+
+var testResponseValidator = sync.OnceValues(func() (*responseValidator, error) {
+	spec, err := loadSpec()
+	if err != nil {
+		return nil, err
+	}
+	return newResponseValidator(spec)
+})
+
+// recordValidated returns a recorder for a handler serving r, and fails the
+// test if the response recorded does not match the OpenAPI spec.
+func recordValidated(t *testing.T, r *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	v, err := testResponseValidator()
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	t.Cleanup(func() {
+		if err := v.validate(r.Context(), r, rr.Code, rr.Header(), rr.Body.Bytes()); err != nil {
+			t.Errorf("%s %s: %d response does not match the OpenAPI spec: %v", r.Method, r.URL.Path, rr.Code, err)
+		}
+	})
+	return rr
+}
+
+// productStub answers GetProduct with a canned response.
+type productStub struct {
+	openapi.Unimplemented
+	status      int
+	contentType string
+	body        string
+}
+
+func (s productStub) GetProduct(w http.ResponseWriter, _ *http.Request, _ string, _ openapi.GetProductParams) {
+	w.Header().Set("Content-Type", s.contentType)
+	w.WriteHeader(s.status)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func TestRouter_ResponseValidation(t *testing.T) {
+	const valid = `{"id":"10","name":"Chicken Waffle","price":"12.99","priceCents":1299,"currency":"AUD"}`
+	type tc struct {
+		name       string
+		mode       ResponseValidation
+		stub       productStub
+		wantStatus int
+		wantCode   string
+		wantLog    bool
+	}
+	cases := []tc{
+		{
+			name: "valid", mode: ResponseValidationFail,
+			stub:       productStub{status: 200, contentType: "application/json", body: valid},
+			wantStatus: 200,
+		},
+		{
+			name: "valid problem", mode: ResponseValidationFail,
+			stub:       productStub{status: 404, contentType: "application/problem+json", body: `{"type":"urn:kart:problem:product_not_found","title":"Not Found","status":404,"code":"product_not_found"}`},
+			wantStatus: 404,
+		},
+		{
+			name: "invalid body fails", mode: ResponseValidationFail,
+			stub:       productStub{status: 200, contentType: "application/json", body: `{"id":"10","priceCents":"12.99"}`},
+			wantStatus: 500, wantCode: "invalid_response", wantLog: true,
+		},
+		{
+			name: "undocumented media type fails", mode: ResponseValidationFail,
+			stub:       productStub{status: 404, contentType: "application/json", body: `{"error":"not found"}`},
+			wantStatus: 500, wantCode: "invalid_response", wantLog: true,
+		},
+		{
+			name: "invalid body logged", mode: ResponseValidationLog,
+			stub:       productStub{status: 200, contentType: "application/json", body: `{"id":"10","priceCents":"12.99"}`},
+			wantStatus: 200, wantLog: true,
+		},
+		{
+			name: "off", mode: ResponseValidationOff,
+			stub:       productStub{status: 200, contentType: "application/json", body: `{"id":"10","priceCents":"12.99"}`},
+			wantStatus: 200,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			h, err := NewRouter(Tenancy{DefaultStore: "default"}, c.stub, RouterOptions{
+				Logger:             slog.New(slog.NewTextHandler(&logs, nil)),
+				ResponseValidation: c.mode,
+			})
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest("GET", "/product/10", nil))
+
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			if c.wantCode != "" {
+				assert.Contains(t, rr.Body.String(), `"code":"`+c.wantCode+`"`)
+			} else {
+				assert.Equal(t, c.stub.body, rr.Body.String())
+				assert.Equal(t, c.stub.contentType, rr.Header().Get("Content-Type"))
+			}
+			assert.Equal(t, c.wantLog, bytes.Contains(logs.Bytes(), []byte("response does not match OpenAPI spec")), logs.String())
+		})
+	}
+}
+
+func TestParseResponseValidation(t *testing.T) {
+	type tc struct {
+		in      string
+		want    ResponseValidation
+		wantErr bool
+	}
+	cases := []tc{
+		{in: "off", want: ResponseValidationOff},
+		{in: "log", want: ResponseValidationLog},
+		{in: "fail", want: ResponseValidationFail},
+		{in: "", wantErr: true},
+		{in: "strict", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := ParseResponseValidation(c.in)
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
@@ -1,44 +1,154 @@
 package server
 
 import (
-	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
+	"net/netip"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
 	"github.com/go-chi/chi/v5"
 	oapimw "github.com/oapi-codegen/nethttp-middleware"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"kart/internal/health"
+	"kart/internal/metrics"
 	"kart/internal/openapi"
 )
 
-// NewRouter creates and configures a chi Router with OpenAPI request validation.
+// RouterOptions configures what NewRouter wraps the API in. The zero value
+// is usable.
+type RouterOptions struct {
+	// Logger receives access logs and panics; slog.Default() when nil.
+	Logger *slog.Logger
+	// Metrics, when set, counts and times requests.
+	Metrics *metrics.Metrics
+	// Gatherer, when set, is served at /metrics.
+	Gatherer prometheus.Gatherer
+	// Health runs the readiness checks served at /readyz. NewRouter adds a
+	// check that the OpenAPI spec is loaded.
+	Health *health.Checker
+	// RequestTimeout, when set, is the deadline for each API request.
+	RequestTimeout time.Duration
+	// ResponseValidation checks API responses against the spec; it is off
+	// by default as it buffers every response.
+	ResponseValidation ResponseValidation
+	// RateLimits throttle each caller on the routes they select.
+	RateLimits []RateLimit
+	// TrustedProxies are the proxies whose forwarding headers are believed
+	// when working out the client address.
+	TrustedProxies []netip.Prefix
+}
+
+// NewRouter creates and configures a chi Router with OpenAPI request validation
+// against the embedded spec, behind tenancy so every request is routed and
+// validated for one store. The spec and its reference docs are served too.
+// Every request gets a request ID, its client address, a trace span, an
+// access log line, metrics and panic recovery. API routes are rate limited.
 // It returns an error instead of exiting the process to enable graceful startup handling.
-func NewRouter(apiKey string, handlers openapi.ServerInterface) (http.Handler, error) {
-	loader := &openapi3.Loader{IsExternalRefsAllowed: true}
-	specPath := "api/openapi.yaml"
-	if _, err := os.Stat(specPath); os.IsNotExist(err) {
-		specPath = "/api/openapi.yaml"
+func NewRouter(tenancy Tenancy, handlers openapi.ServerInterface, opts RouterOptions) (http.Handler, error) {
+	spec, err := loadSpec()
+	if err != nil {
+		return nil, err
 	}
-	spec, err := loader.LoadFromFile(specPath)
+	docs, err := specHandlers(spec)
 	if err != nil {
-		return nil, fmt.Errorf("load OpenAPI spec: %w", err)
+		return nil, err
 	}
-	if err := spec.Validate(context.Background()); err != nil {
-		return nil, fmt.Errorf("validate OpenAPI spec: %w", err)
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
 	}
 
 	r := chi.NewRouter()
-	auth := NewOpenAPIAuthFunc(apiKey)
+	if opts.ResponseValidation != ResponseValidationOff {
+		rv, err := newResponseValidator(spec)
+		if err != nil {
+			return nil, err
+		}
+		// Outside the request validator, so its replies are checked too.
+		r.Use(rv.middleware(opts.ResponseValidation, logger))
+	}
+	routes, err := specRoutes(spec)
+	if err != nil {
+		return nil, err
+	}
+	r.Use(routes)
 	r.Use(oapimw.OapiRequestValidatorWithOptions(spec, &oapimw.Options{
 		SilenceServersWarning: true,
-		Options:               openapi3filter.Options{AuthenticationFunc: auth},
+		Options:               openapi3filter.Options{AuthenticationFunc: NewOpenAPIAuthFunc()},
+		ErrorHandlerWithOpts:  validationErrorHandler,
 	}))
 
 	h := openapi.HandlerWithOptions(handlers, openapi.ChiServerOptions{
-		BaseRouter: r,
+		BaseRouter:       r,
+		Middlewares:      []openapi.MiddlewareFunc{rateLimit(opts.RateLimits, logger)},
+		ErrorHandlerFunc: paramErrorHandler,
 	})
-	return h, nil
+	checker := opts.Health
+	if checker == nil {
+		checker = &health.Checker{}
+	}
+	checker.Add("openapi", specLoaded(spec))
+	ops := map[string]http.Handler{
+		"/healthz": http.HandlerFunc(healthz),
+		"/readyz":  readyz(checker, logger),
+		"/version": http.HandlerFunc(version),
+	}
+	for path, h := range docs {
+		ops[path] = h
+	}
+	if opts.Gatherer != nil {
+		ops["/metrics"] = metrics.Handler(opts.Gatherer)
+	}
+	for path, h := range ops {
+		ops[path] = getOnly(h)
+	}
+	api := withOperational(ops, Timeout(opts.RequestTimeout)(tenancy.Middleware(h)))
+	return RequestID(ClientIP(opts.TrustedProxies)(Trace(AccessLog(logger)(Instrument(opts.Metrics)(Recoverer(logger)(api)))))), nil
+}
+
+// withOperational serves the operational endpoints and docs in ops by exact
+// path, and everything else from api. They bypass tenancy, authentication
+// and validation since they are not part of the API.
+func withOperational(ops map[string]http.Handler, api http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := ops[r.URL.Path]
+		if !ok {
+			api.ServeHTTP(w, r)
+			return
+		}
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			rctx.RoutePatterns = append(rctx.RoutePatterns, r.URL.Path)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// specRoutes names the route of requests that are answered before chi routes
+// them, such as those the request validator rejects, after the path they
+// match in the spec. Access logs and metrics then group a 401 on POST /order
+// with the other POST /order requests rather than as unmatched. The spec
+// writes paths the way chi patterns do, so both name a route alike.
+func specRoutes(spec *openapi3.T) (func(http.Handler) http.Handler, error) {
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("route OpenAPI spec: %w", err)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil || rctx.RoutePattern() != "" {
+				return
+			}
+			if route, _, err := router.FindRoute(r); err == nil {
+				rctx.RoutePatterns = append(rctx.RoutePatterns, route.Path)
+			}
+		})
+	}, nil
 }
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	servermock "kart/internal/mocks/server"
+	"kart/internal/openapi"
+	"kart/internal/service"
+)
+
+func TestRouter_Scopes(t *testing.T) {
+	keys := servermock.NewKeyAuthenticator(t)
+	keys.On("Authenticate", mock.Anything, "writer").Maybe().
+		Return(auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default", Scopes: []string{auth.ScopeOrdersWrite}}, nil)
+	keys.On("Authenticate", mock.Anything, "staff").Maybe().
+		Return(auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default", Scopes: []string{auth.ScopeOrdersAdmin}}, nil)
+	keys.On("Authenticate", mock.Anything, "reader").Maybe().
+		Return(auth.Principal{Scheme: auth.SchemeAPIKey, StoreID: "default", Scopes: []string{}}, nil)
+	keys.On("Authenticate", mock.Anything, "nope").Maybe().Return(auth.Principal{}, service.ErrAPIKeyInvalid)
+	tokens := servermock.NewBearerVerifier(t)
+	tokens.On("Verify", mock.Anything, "customer").Maybe().
+		Return(auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "c1", Scopes: []string{auth.ScopeOrdersWrite}}, nil)
+	tokens.On("Verify", mock.Anything, "browser").Maybe().
+		Return(auth.Principal{Scheme: auth.SchemeBearer, CustomerID: "c2", Scopes: []string{}}, nil)
+	tokens.On("Verify", mock.Anything, "expired").Maybe().Return(auth.Principal{}, auth.ErrInvalidToken)
+	tn := Tenancy{Keys: keys, Tokens: tokens, DefaultStore: "default", LegacyAPIKey: "apitest"}
+	h, err := NewRouter(tn, openapi.Unimplemented{}, RouterOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.NoError(t, err)
+
+	type tc struct {
+		name       string
+		method     string
+		path       string
+		apiKey     string
+		bearer     string
+		wantStatus int
+		wantBody   string
+	}
+	cases := []tc{
+		{name: "scoped key", method: "POST", path: "/cart", apiKey: "writer", wantStatus: 501},
+		{name: "legacy key", method: "POST", path: "/cart", apiKey: "apitest", wantStatus: 501},
+		{name: "legacy key holds only orders:write", method: "GET", path: "/gift-cards/g1/transactions", apiKey: "apitest", wantStatus: 403, wantBody: "giftcards:admin"},
+		{name: "missing scope", method: "POST", path: "/cart", apiKey: "reader", wantStatus: 403, wantBody: "orders:write"},
+		{name: "no scope needed", method: "GET", path: "/cart/c1", apiKey: "reader", wantStatus: 501},
+		{name: "invalid key", method: "POST", path: "/cart", apiKey: "nope", wantStatus: 401},
+		{name: "no key", method: "POST", path: "/cart", wantStatus: 401},
+		{name: "customer token", method: "POST", path: "/cart", bearer: "customer", wantStatus: 501},
+		{name: "customer token without scope", method: "POST", path: "/cart", bearer: "browser", wantStatus: 403},
+		{name: "customer token on staff operation", method: "PUT", path: "/order/o1/status", bearer: "customer", wantStatus: 401},
+		{name: "status update needs orders:admin", method: "PUT", path: "/order/o1/status", apiKey: "writer", wantStatus: 403, wantBody: "orders:admin"},
+		{name: "refund needs orders:admin", method: "POST", path: "/order/o1/refund", apiKey: "writer", wantStatus: 403, wantBody: "orders:admin"},
+		{name: "legacy key cannot confirm payments", method: "POST", path: "/order/o1/payment/confirm", apiKey: "apitest", wantStatus: 403, wantBody: "orders:admin"},
+		{name: "staff key confirms payments", method: "POST", path: "/order/o1/payment/confirm", apiKey: "staff", wantStatus: 501},
+		{name: "gift card history needs admin scope", method: "GET", path: "/gift-cards/g1/transactions", apiKey: "writer", wantStatus: 403, wantBody: "giftcards:admin"},
+		{name: "invalid token", method: "POST", path: "/cart", bearer: "expired", wantStatus: 401},
+		{name: "method not allowed", method: "DELETE", path: "/cart", wantStatus: 405},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(c.method, c.path, nil)
+			if c.apiKey != "" {
+				req.Header.Set("api_key", c.apiKey)
+			}
+			if c.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+c.bearer)
+			}
+			h.ServeHTTP(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), c.wantBody)
+		})
+	}
+}
@@ -3,23 +3,55 @@ package server
 import (
 	"context"
 	"kart/internal/config"
+	"kart/internal/metrics"
 	"kart/internal/openapi"
+	"kart/internal/orderstatus"
+	"kart/internal/ratelimit"
 	"kart/internal/repo"
 	"kart/internal/service"
 )
 
 //go:generate mockery --name ProductService --dir . --output ../mocks/server --outpkg servermock --filename product_service_mock.go
 //go:generate mockery --name OrderService --dir . --output ../mocks/server --outpkg servermock --filename order_service_mocks.go
+//go:generate mockery --name CartService --dir . --output ../mocks/server --outpkg servermock --filename cart_service_mock.go
+//go:generate mockery --name GiftCardService --dir . --output ../mocks/server --outpkg servermock --filename gift_card_service_mock.go
 
 // ProductService is the minimal interface the handlers need.
 type ProductService interface {
-	List(ctx context.Context) ([]repo.Product, error)
-	Get(ctx context.Context, id string) (repo.Product, error)
+	List(ctx context.Context, currency string) ([]service.PricedProduct, error)
+	Get(ctx context.Context, id, currency string) (service.PricedProduct, error)
+	GetBySlug(ctx context.Context, slug, currency string) (service.PricedProduct, error)
 }
 
 // OrderService is the minimal interface the handlers need.
 type OrderService interface {
 	PlaceOrder(ctx context.Context, in service.PlaceOrderInput) (service.PlaceOrderResult, error)
+	Get(ctx context.Context, id string) (repo.Order, error)
+	CustomerOrders(ctx context.Context, customerID string, limit int) ([]repo.Order, error)
+	LoyaltyAccount(ctx context.Context, customerID string, limit int) (service.LoyaltyAccount, error)
+	UpdateStatus(ctx context.Context, in service.UpdateStatusInput) (repo.Order, error)
+	ConfirmPayment(ctx context.Context, orderID string) (service.PaymentResult, error)
+	Details(ctx context.Context, id string) (service.OrderDetails, error)
+	Refund(ctx context.Context, in service.RefundInput) (service.Refund, error)
+}
+
+// CartService is the minimal interface the handlers need.
+type CartService interface {
+	Create(ctx context.Context, currency string) (service.Cart, error)
+	Get(ctx context.Context, id string) (service.Cart, error)
+	AddItem(ctx context.Context, id, productID string, qty int32, currency string) (service.Cart, error)
+	SetItem(ctx context.Context, id, productID string, qty int32, currency string) (service.Cart, error)
+	RemoveItem(ctx context.Context, id, productID string) (service.Cart, error)
+	ApplyCoupon(ctx context.Context, id, code string) (service.Cart, error)
+	RemoveCoupon(ctx context.Context, id string) (service.Cart, error)
+	Checkout(ctx context.Context, id string, in service.CheckoutInput) (service.PlaceOrderResult, error)
+}
+
+// GiftCardService is the minimal interface the handlers need.
+type GiftCardService interface {
+	Issue(ctx context.Context, in service.IssueGiftCardInput) (service.IssuedGiftCard, error)
+	Balance(ctx context.Context, code string) (repo.GiftCard, error)
+	Transactions(ctx context.Context, id string) ([]repo.GiftCardTransaction, error)
 }
 
 // Server holds dependencies for HTTP handlers.
@@ -27,6 +59,20 @@ type Server struct {
 	Cfg      config.Config
 	Products ProductService
 	Orders   OrderService
+	Carts    CartService
+	// GiftCards is optional when no gift card operations are routed to the
+	// server.
+	GiftCards GiftCardService
+	// GiftCardLookups, when set, limits how often one caller can look up gift
+	// card codes, against guessing.
+	GiftCardLookups *ratelimit.Limiter
+	// Metrics is optional; when set, handlers count orders and coupon
+	// outcomes.
+	Metrics *metrics.Metrics
+
+	// StatusHub and StatusTokens back the order status WebSocket channel.
+	StatusHub    *orderstatus.Hub
+	StatusTokens *orderstatus.TokenSigner
 }
 
 // Ensure Server implements the generated interface.
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	swaggerfiles "github.com/swaggo/files/v2"
+
+	"kart/api"
+	"kart/internal/openapi"
+)
+
+// docsFiles is the page serving the interactive API reference at /docs. It
+// renders /openapi.json with the Swagger UI release in swaggerUIFiles and
+// loads nothing from elsewhere.
+//
+//go:embed docs
+var docsFiles embed.FS
+
+// swaggerUIFiles are the files of the Swagger UI distribution the docs page
+// loads, served under /docs. github.com/swaggo/files embeds the release.
+var swaggerUIFiles = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// loadSpec parses and validates the embedded spec, and checks that the
+// generated handlers were generated from it.
+func loadSpec() (*openapi3.T, error) {
+	spec, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("load OpenAPI spec: %w", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validate OpenAPI spec: %w", err)
+	}
+	if err := checkServerInterface(spec, reflect.TypeFor[openapi.ServerInterface]()); err != nil {
+		return nil, fmt.Errorf("OpenAPI spec does not match internal/openapi, regenerate it: %w", err)
+	}
+	return spec, nil
+}
+
+// checkServerInterface reports operations in spec without a method on iface
+// taking their path parameters, and methods no operation accounts for. Either
+// means the spec was edited without regenerating the server.
+func checkServerInterface(spec *openapi3.T, iface reflect.Type) error {
+	var problems []string
+	seen := map[string]bool{}
+	for p, item := range spec.Paths.Map() {
+		for method, op := range item.Operations() {
+			name := exportedName(op.OperationID)
+			seen[name] = true
+			m, ok := iface.MethodByName(name)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s %s: no method %s", method, p, name))
+				continue
+			}
+			// The generated methods take the writer and request, each path
+			// parameter, then a struct holding any other parameters.
+			want := 2
+			other := false
+			for _, ref := range append(item.Parameters, op.Parameters...) {
+				if ref.Value.In == openapi3.ParameterInPath {
+					want++
+				} else {
+					other = true
+				}
+			}
+			if other {
+				want++
+			}
+			if got := m.Type.NumIn(); got != want {
+				problems = append(problems, fmt.Sprintf("%s %s: %s takes %d arguments, want %d", method, p, name, got, want))
+			}
+		}
+	}
+	for i := range iface.NumMethod() {
+		if name := iface.Method(i).Name; !seen[name] {
+			problems = append(problems, fmt.Sprintf("%s: no operation in spec", name))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// exportedName is the Go method name oapi-codegen gives an operation ID.
+func exportedName(operationID string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range operationID {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// specHandlers serves the spec as written at /openapi.yaml, as JSON at
+// /openapi.json and the API reference at /docs.
+func specHandlers(spec *openapi3.T) (map[string]http.Handler, error) {
+	js, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("encode OpenAPI spec: %w", err)
+	}
+	hs := map[string]http.Handler{
+		"/openapi.yaml": staticFile("application/yaml", api.Spec),
+		"/openapi.json": staticFile("application/json", js),
+	}
+	err = fs.WalkDir(docsFiles, "docs", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := docsFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		route := "/" + name
+		if path.Base(name) == "index.html" {
+			route = "/" + path.Dir(name)
+		}
+		hs[route] = staticFile(contentType(name), b)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load API docs: %w", err)
+	}
+	for _, name := range swaggerUIFiles {
+		b, err := fs.ReadFile(swaggerfiles.FS, name)
+		if err != nil {
+			return nil, fmt.Errorf("load Swagger UI: %w", err)
+		}
+		hs["/docs/"+name] = staticFile(contentType(name), b)
+	}
+	return hs, nil
+}
+
+func contentType(name string) string {
+	switch path.Ext(name) {
+	case ".html":
+		return "text/html; charset=utf-8"
+	case ".css":
+		return "text/css; charset=utf-8"
+	case ".js":
+		return "text/javascript; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// staticFile serves b, which never changes while the process runs.
+func staticFile(contentType string, b []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(b)
+		}
+	})
+}
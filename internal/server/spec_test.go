@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kart/api"
+	"kart/internal/openapi"
+)
+
+func TestCheckServerInterface(t *testing.T) {
+	spec, err := loadSpec()
+	require.NoError(t, err)
+
+	type tc struct {
+		name    string
+		iface   reflect.Type
+		wantErr string
+	}
+	cases := []tc{
+		{name: "generated interface", iface: reflect.TypeFor[openapi.ServerInterface]()},
+		{
+			name: "operation missing",
+			iface: reflect.TypeFor[interface {
+				openapi.ServerInterface
+				Unknown(w http.ResponseWriter, r *http.Request)
+			}](),
+			wantErr: "Unknown: no operation in spec",
+		},
+		{
+			name:    "path parameter missing",
+			iface:   reflect.TypeFor[withoutProductID](),
+			wantErr: "GET /product/{productId}: GetProduct takes 3 arguments, want 4",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkServerInterface(spec, c.iface)
+			if c.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), c.wantErr)
+		})
+	}
+}
+
+// withoutProductID is the interface generated before a path parameter was
+// added to getProduct.
+type withoutProductID interface {
+	GetProduct(w http.ResponseWriter, r *http.Request, params openapi.GetProductParams)
+}
+
+func TestExportedName(t *testing.T) {
+	cases := map[string]string{
+		"getProduct":       "GetProduct",
+		"get-product-slug": "GetProductSlug",
+		"place_order_v2":   "PlaceOrderV2",
+		"ListGiftCardTxns": "ListGiftCardTxns",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, exportedName(in), in)
+	}
+}
+
+func TestRouter_Spec(t *testing.T) {
+	h, err := NewRouter(Tenancy{DefaultStore: "default"}, openapi.Unimplemented{}, RouterOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil)), ResponseValidation: ResponseValidationFail})
+	require.NoError(t, err)
+
+	type tc struct {
+		name        string
+		method      string
+		path        string
+		wantStatus  int
+		wantType    string
+		wantContain string
+	}
+	cases := []tc{
+		{name: "yaml", method: "GET", path: "/openapi.yaml", wantStatus: 200, wantType: "application/yaml", wantContain: "operationId: getProduct"},
+		{name: "json", method: "GET", path: "/openapi.json", wantStatus: 200, wantType: "application/json", wantContain: `"operationId":"getProduct"`},
+		{name: "docs", method: "GET", path: "/docs", wantStatus: 200, wantType: "text/html; charset=utf-8", wantContain: `src="docs/swagger-ui-bundle.js"`},
+		{name: "docs config", method: "GET", path: "/docs/init.js", wantStatus: 200, wantType: "text/javascript; charset=utf-8", wantContain: `url: "openapi.json"`},
+		{name: "swagger ui", method: "GET", path: "/docs/swagger-ui-bundle.js", wantStatus: 200, wantType: "text/javascript; charset=utf-8", wantContain: "SwaggerUIBundle"},
+		{name: "swagger ui styles", method: "GET", path: "/docs/swagger-ui.css", wantStatus: 200, wantType: "text/css; charset=utf-8", wantContain: ".swagger-ui"},
+		{name: "head", method: "HEAD", path: "/openapi.yaml", wantStatus: 200, wantType: "application/yaml"},
+		{name: "read only", method: "POST", path: "/openapi.json", wantStatus: 405},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, httptest.NewRequest(c.method, c.path, nil))
+			require.Equal(t, c.wantStatus, rr.Code)
+			if c.wantType != "" {
+				assert.Equal(t, c.wantType, rr.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, rr.Body.String(), c.wantContain)
+		})
+	}
+
+	t.Run("yaml is the embedded spec", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.yaml", nil))
+		assert.Equal(t, string(api.Spec), rr.Body.String())
+	})
+	t.Run("json parses", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", "/openapi.json", nil))
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+		assert.Contains(t, doc, "paths")
+	})
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"kart/internal/auth"
+	"kart/internal/repo"
+	"kart/internal/service"
+	"kart/internal/tenant"
+)
+
+//go:generate mockery --name StoreResolver --dir . --output ../mocks/server --outpkg servermock --filename store_resolver_mock.go
+//go:generate mockery --name KeyAuthenticator --dir . --output ../mocks/server --outpkg servermock --filename key_authenticator_mock.go
+//go:generate mockery --name BearerVerifier --dir . --output ../mocks/server --outpkg servermock --filename bearer_verifier_mock.go
+
+// StoreResolver is the minimal interface the tenancy middleware needs.
+type StoreResolver interface {
+	Get(ctx context.Context, id string) (repo.Store, error)
+}
+
+// KeyAuthenticator resolves an api_key header to the principal it stands for.
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+}
+
+// BearerVerifier resolves a bearer token to the customer it was issued to.
+type BearerVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// storePrefix addresses a store explicitly: /stores/{storeId}/product/10.
+const storePrefix = "/stores/"
+
+// Tenancy authenticates the api_key header or a bearer token and resolves
+// the store each request acts for, putting both in the request context. The
+// store comes from a /stores/{storeId} path prefix, which is stripped before
+// routing, else from the store the key is bound to, else DefaultStore. A key
+// used under another store's prefix is rejected. Bearer tokens are not bound
+// to a store.
+type Tenancy struct {
+	Stores StoreResolver
+	Keys   KeyAuthenticator
+	// Tokens verifies bearer tokens; nil disables them.
+	Tokens BearerVerifier
+	// DefaultStore serves requests that name no store.
+	DefaultStore string
+	// LegacyAPIKey is the single pre-tenancy key (API_KEY). It is bound to
+	// DefaultStore and holds only orders:write, so existing storefronts keep
+	// placing orders; staff operations need a key minted with orders:admin.
+	LegacyAPIKey string
+}
+
+// Middleware wraps next with store resolution.
+func (t Tenancy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		pathStore, ok := t.stripStorePrefix(w, r)
+		if !ok {
+			return
+		}
+		p, authed, err := t.authenticate(ctx, r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+		if authed && pathStore != "" && p.StoreID != "" && p.StoreID != pathStore {
+			writeError(w, http.StatusForbidden, "store_forbidden", "api_key is not valid for this store")
+			return
+		}
+
+		storeID := t.DefaultStore
+		switch {
+		case pathStore != "":
+			storeID = pathStore
+		case p.StoreID != "":
+			storeID = p.StoreID
+		}
+		ctx = tenant.WithStore(ctx, storeID)
+		if authed {
+			ctx = auth.WithPrincipal(ctx, p)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// stripStorePrefix removes a /stores/{storeId} prefix from r's path and
+// returns the store it named, or "" when there was none. It replies 404 and
+// returns false for an unknown store.
+func (t Tenancy) stripStorePrefix(w http.ResponseWriter, r *http.Request) (string, bool) {
+	rest, found := strings.CutPrefix(r.URL.Path, storePrefix)
+	if !found {
+		return "", true
+	}
+	id, path, _ := strings.Cut(rest, "/")
+	if !tenant.ValidID(id) {
+		writeError(w, http.StatusNotFound, "store_not_found", "store not found")
+		return "", false
+	}
+	if _, err := t.Stores.Get(r.Context(), id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "store_not_found", "store not found")
+		} else {
+			writeServiceError(w, r, err)
+		}
+		return "", false
+	}
+	r.URL.Path = "/" + path
+	r.URL.RawPath = ""
+	return id, true
+}
+
+// authenticate resolves r's api_key header, or failing that its bearer
+// token, to a principal. It reports false when r carries neither or they are
+// invalid; invalid credentials are left for the OpenAPI auth check to reject
+// on operations that require one.
+func (t Tenancy) authenticate(ctx context.Context, r *http.Request) (auth.Principal, bool, error) {
+	if key := r.Header.Get("api_key"); key != "" {
+		return t.authenticateKey(ctx, key)
+	}
+	token, ok := bearerToken(r)
+	if !ok || t.Tokens == nil {
+		return auth.Principal{}, false, nil
+	}
+	p, err := t.Tokens.Verify(ctx, token)
+	if errors.Is(err, auth.ErrInvalidToken) {
+		return auth.Principal{}, false, nil
+	}
+	if err != nil {
+		return auth.Principal{}, false, err
+	}
+	return p, true, nil
+}
+
+func (t Tenancy) authenticateKey(ctx context.Context, key string) (auth.Principal, bool, error) {
+	if t.LegacyAPIKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(t.LegacyAPIKey)) == 1 {
+		return auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:legacy", StoreID: t.DefaultStore, Scopes: []string{auth.ScopeOrdersWrite}}, true, nil
+	}
+	if t.Keys == nil {
+		return auth.Principal{}, false, nil
+	}
+	p, err := t.Keys.Authenticate(ctx, key)
+	if errors.Is(err, service.ErrAPIKeyInvalid) {
+		return auth.Principal{}, false, nil
+	}
+	if err != nil {
+		return auth.Principal{}, false, err
+	}
+	return p, true, nil
+}
+
+// bearerToken returns the token in r's Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
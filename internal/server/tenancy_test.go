@@ -0,0 +1,134 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/auth"
+	servermock "kart/internal/mocks/server"
+	sqlcmock "kart/internal/mocks/sqlc"
+	"kart/internal/openapi"
+	"kart/internal/repo"
+	"kart/internal/service"
+	"kart/internal/sqlc"
+	"kart/internal/tenant"
+)
+
+func TestTenancy_Middleware(t *testing.T) {
+	type tc struct {
+		name       string
+		path       string
+		apiKey     string
+		wantStatus int
+		wantStore  string
+		wantPath   string
+		wantAuthed bool
+		wantScopes []string
+	}
+	cases := []tc{
+		{name: "default store", path: "/product", wantStatus: 200, wantStore: "default", wantPath: "/product"},
+		{name: "legacy key", path: "/order", apiKey: "apitest", wantStatus: 200, wantStore: "default", wantPath: "/order", wantAuthed: true,
+			wantScopes: []string{auth.ScopeOrdersWrite}},
+		{name: "store key", path: "/order", apiKey: "acme-key", wantStatus: 200, wantStore: "acme", wantPath: "/order", wantAuthed: true},
+		{name: "unknown key", path: "/order", apiKey: "nope", wantStatus: 200, wantStore: "default", wantPath: "/order"},
+		{name: "path prefix", path: "/stores/acme/product/10", wantStatus: 200, wantStore: "acme", wantPath: "/product/10"},
+		{name: "path prefix with own key", path: "/stores/acme/order", apiKey: "acme-key", wantStatus: 200, wantStore: "acme", wantPath: "/order", wantAuthed: true},
+		{name: "key for another store", path: "/stores/beta/order", apiKey: "acme-key", wantStatus: 403},
+		{name: "legacy key for another store", path: "/stores/acme/order", apiKey: "apitest", wantStatus: 403},
+		{name: "key lookup fails", path: "/order", apiKey: "broken", wantStatus: 500},
+		{name: "unknown store", path: "/stores/ghost/product", wantStatus: 404},
+		{name: "malformed store", path: "/stores/Not_A_Store/product", wantStatus: 404},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stores := servermock.NewStoreResolver(t)
+			stores.On("Get", mock.Anything, "acme").Maybe().Return(repo.Store{ID: "acme"}, nil)
+			stores.On("Get", mock.Anything, "beta").Maybe().Return(repo.Store{ID: "beta"}, nil)
+			stores.On("Get", mock.Anything, "ghost").Maybe().Return(repo.Store{}, repo.ErrNotFound)
+			keys := servermock.NewKeyAuthenticator(t)
+			keys.On("Authenticate", mock.Anything, "acme-key").Maybe().
+				Return(auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:acme", StoreID: "acme"}, nil)
+			keys.On("Authenticate", mock.Anything, "nope").Maybe().Return(auth.Principal{}, service.ErrAPIKeyInvalid)
+			keys.On("Authenticate", mock.Anything, "broken").Maybe().Return(auth.Principal{}, errors.New("db down"))
+			tn := Tenancy{Stores: stores, Keys: keys, DefaultStore: "default", LegacyAPIKey: "apitest"}
+
+			var gotStore, gotPath string
+			var gotAuthed bool
+			var gotScopes []string
+			h := tn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotStore, _ = tenant.StoreID(r.Context())
+				gotPath = r.URL.Path
+				var p auth.Principal
+				p, gotAuthed = auth.PrincipalFrom(r.Context())
+				gotScopes = p.Scopes
+			}))
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", c.path, nil)
+			if c.apiKey != "" {
+				req.Header.Set("api_key", c.apiKey)
+			}
+			h.ServeHTTP(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code)
+			assert.Equal(t, c.wantStore, gotStore)
+			assert.Equal(t, c.wantPath, gotPath)
+			assert.Equal(t, c.wantAuthed, gotAuthed)
+			if c.wantScopes != nil {
+				assert.Equal(t, c.wantScopes, gotScopes)
+			}
+		})
+	}
+}
+
+// TestTenancy_Isolation drives real handlers, services and repositories
+// through the tenancy middleware and checks that each store only ever
+// queries its own rows.
+func TestTenancy_Isolation(t *testing.T) {
+	q := sqlcmock.NewQuerier(t)
+	q.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "acme", ID: "10"}).
+		Return(sqlc.Product{ID: "10", StoreID: "acme", Name: "Acme Waffle", PriceCents: 500}, nil)
+	q.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "beta", ID: "10"}).
+		Return(sqlc.Product{}, sql.ErrNoRows)
+	q.On("ListProducts", mock.Anything, "beta").Return([]sqlc.Product{{ID: "20", StoreID: "beta"}}, nil)
+
+	stores := servermock.NewStoreResolver(t)
+	stores.On("Get", mock.Anything, "acme").Maybe().Return(repo.Store{ID: "acme"}, nil)
+	stores.On("Get", mock.Anything, "beta").Maybe().Return(repo.Store{ID: "beta"}, nil)
+	keys := servermock.NewKeyAuthenticator(t)
+	keys.On("Authenticate", mock.Anything, "beta-key").Maybe().Return(auth.Principal{StoreID: "beta"}, nil)
+
+	s := &Server{Products: service.NewProductService(repo.NewProductRepo(q))}
+	h := Tenancy{Stores: stores, Keys: keys, DefaultStore: "default"}.Middleware(openapi.Handler(s))
+
+	type tc struct {
+		name       string
+		path       string
+		apiKey     string
+		wantStatus int
+		wantBody   string
+	}
+	cases := []tc{
+		{name: "own product", path: "/stores/acme/product/10", wantStatus: 200, wantBody: "Acme Waffle"},
+		{name: "other store's product", path: "/stores/beta/product/10", wantStatus: 404},
+		{name: "store by key", path: "/product", apiKey: "beta-key", wantStatus: 200, wantBody: `"id":"20"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", c.path, nil)
+			if c.apiKey != "" {
+				req.Header.Set("api_key", c.apiKey)
+			}
+			h.ServeHTTP(rr, req)
+			require.Equal(t, c.wantStatus, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), c.wantBody)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// unavailableRetryAfter is how long clients are asked to wait before retrying
+// a request that failed because a dependency was unavailable.
+var unavailableRetryAfter = 5 * time.Second
+
+// Timeout puts a deadline of d on each request's context, so a request
+// waiting for a connection from an exhausted database pool gives up and is
+// answered 503 instead of queueing until the client goes away. WebSocket
+// upgrades are exempt since the stream outlives any request deadline. A zero
+// d disables the deadline.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if websocket.IsWebSocketUpgrade(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	type tc struct {
+		name         string
+		timeout      time.Duration
+		upgrade      bool
+		wantDeadline bool
+	}
+	cases := []tc{
+		{name: "api request", timeout: time.Second, wantDeadline: true},
+		{name: "disabled"},
+		{name: "websocket upgrade", timeout: time.Second, upgrade: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var gotDeadline bool
+			h := Timeout(c.timeout)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				_, gotDeadline = r.Context().Deadline()
+			}))
+			req := httptest.NewRequest("GET", "/order/o1/events", nil)
+			if c.upgrade {
+				req.Header.Set("Connection", "Upgrade")
+				req.Header.Set("Upgrade", "websocket")
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, c.wantDeadline, gotDeadline)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kart/internal/apikey"
+	"kart/internal/auth"
+	"kart/internal/repo"
+)
+
+var (
+	// ErrAPIKeyInvalid covers unknown, malformed, expired and revoked keys
+	// alike, so callers learn nothing about which it was.
+	ErrAPIKeyInvalid  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrAPIKeyScope    = errors.New("unknown api key scope")
+	// ErrAPIKeyExpired is returned when minting a key that would already be
+	// expired, or rotating one that is.
+	ErrAPIKeyExpired = errors.New("api key expired")
+)
+
+// keyUsageInterval is how stale last_used_at may get before a use is
+// recorded again.
+const keyUsageInterval = time.Minute
+
+type APIKeyService struct {
+	Keys repo.APIKeyRepository
+
+	Clock Clock
+}
+
+func NewAPIKeyService(k repo.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{Keys: k, Clock: SystemClock{}}
+}
+
+func (s *APIKeyService) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+// MintedKey is a newly created key. Key is the plaintext, which is not
+// stored and cannot be shown again.
+type MintedKey struct {
+	repo.APIKey
+	Key string
+}
+
+// Mint creates a key for the context's store holding scopes. A zero
+// expiresAt never expires; otherwise it must be in the future.
+func (s *APIKeyService) Mint(ctx context.Context, label string, scopes []string, expiresAt time.Time) (MintedKey, error) {
+	if !expiresAt.IsZero() && !expiresAt.After(s.now()) {
+		return MintedKey{}, ErrAPIKeyExpired
+	}
+	for _, sc := range scopes {
+		if !auth.ValidScope(sc) {
+			return MintedKey{}, fmt.Errorf("%w %q", ErrAPIKeyScope, sc)
+		}
+	}
+	k, err := apikey.Generate()
+	if err != nil {
+		return MintedKey{}, err
+	}
+	row := repo.APIKey{
+		ID:        uuid.NewString(),
+		Prefix:    k.Prefix,
+		Salt:      k.Salt,
+		Hash:      k.Hash,
+		Scopes:    scopes,
+		Label:     label,
+		CreatedAt: s.now().UTC(),
+		ExpiresAt: sql.NullTime{Time: expiresAt.UTC(), Valid: !expiresAt.IsZero()},
+	}
+	if row.Scopes == nil {
+		row.Scopes = []string{}
+	}
+	if err := s.Keys.Create(ctx, row); err != nil {
+		return MintedKey{}, err
+	}
+	return MintedKey{APIKey: row, Key: k.Plaintext}, nil
+}
+
+// Rotate mints a replacement for the key with prefix, with the same label
+// and scopes, and lets the old key keep working for overlap so clients can
+// switch over without downtime. The replacement keeps the old key's expiry,
+// so an already expired key cannot be rotated.
+func (s *APIKeyService) Rotate(ctx context.Context, prefix string, overlap time.Duration) (MintedKey, error) {
+	old, err := s.Keys.GetByPrefix(ctx, prefix)
+	if errors.Is(err, repo.ErrNotFound) {
+		return MintedKey{}, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return MintedKey{}, err
+	}
+	var expiresAt time.Time
+	if old.ExpiresAt.Valid {
+		if !old.ExpiresAt.Time.After(s.now()) {
+			return MintedKey{}, ErrAPIKeyExpired
+		}
+		expiresAt = old.ExpiresAt.Time
+	}
+	ok, err := s.Keys.Expire(ctx, prefix, s.now().Add(overlap).UTC())
+	if err != nil {
+		return MintedKey{}, err
+	}
+	if !ok {
+		// Revoked, or another store's key.
+		return MintedKey{}, ErrAPIKeyNotFound
+	}
+	return s.Mint(ctx, old.Label, old.Scopes, expiresAt)
+}
+
+// Revoke disables the key with prefix immediately.
+func (s *APIKeyService) Revoke(ctx context.Context, prefix string) error {
+	ok, err := s.Keys.Revoke(ctx, prefix, s.now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]repo.APIKey, error) {
+	return s.Keys.List(ctx)
+}
+
+// Authenticate resolves a presented key to the principal it stands for.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	prefix, secret, ok := apikey.Parse(key)
+	if !ok {
+		return auth.Principal{}, ErrAPIKeyInvalid
+	}
+	k, err := s.Keys.GetByPrefix(ctx, prefix)
+	if errors.Is(err, repo.ErrNotFound) {
+		return auth.Principal{}, ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if !apikey.Verify(k.Salt, k.Hash, secret) {
+		return auth.Principal{}, ErrAPIKeyInvalid
+	}
+	now := s.now().UTC()
+	if k.RevokedAt.Valid || (k.ExpiresAt.Valid && !now.Before(k.ExpiresAt.Time)) {
+		return auth.Principal{}, ErrAPIKeyInvalid
+	}
+	// Usage tracking is best effort; a failed write must not fail the request.
+	if err := s.Keys.Touch(ctx, k.ID, now, now.Add(-keyUsageInterval)); err != nil {
+		slog.WarnContext(ctx, "record api key use", "prefix", k.Prefix, "err", err)
+	}
+	return auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:" + k.Prefix, StoreID: k.StoreID, Scopes: k.Scopes}, nil
+}
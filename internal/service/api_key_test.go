@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/apikey"
+	"kart/internal/auth"
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/repo"
+)
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 10, 8, 12, 0, 0, 0, time.UTC)
+	k, err := apikey.Generate()
+	require.NoError(t, err)
+	row := repo.APIKey{ID: "k1", StoreID: "acme", Prefix: k.Prefix, Salt: k.Salt, Hash: k.Hash, Scopes: []string{auth.ScopeOrdersWrite}}
+	expired := row
+	expired.ExpiresAt = sql.NullTime{Time: now, Valid: true}
+	overlapping := row
+	overlapping.ExpiresAt = sql.NullTime{Time: now.Add(time.Hour), Valid: true}
+	revoked := row
+	revoked.RevokedAt = sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+
+	type tc struct {
+		name    string
+		key     string
+		row     *repo.APIKey
+		lookup  error
+		wantErr error
+	}
+	cases := []tc{
+		{name: "ok", key: k.Plaintext, row: &row},
+		{name: "expiring during rotation overlap", key: k.Plaintext, row: &overlapping},
+		{name: "expired", key: k.Plaintext, row: &expired, wantErr: ErrAPIKeyInvalid},
+		{name: "revoked", key: k.Plaintext, row: &revoked, wantErr: ErrAPIKeyInvalid},
+		{name: "wrong secret", key: k.Plaintext + "x", row: &row, wantErr: ErrAPIKeyInvalid},
+		{name: "unknown prefix", key: k.Plaintext, lookup: repo.ErrNotFound, wantErr: ErrAPIKeyInvalid},
+		{name: "malformed", key: "apitest", wantErr: ErrAPIKeyInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := repomock.NewAPIKeyRepository(t)
+			if c.row != nil {
+				m.On("GetByPrefix", mock.Anything, k.Prefix).Return(*c.row, nil)
+			} else if c.lookup != nil {
+				m.On("GetByPrefix", mock.Anything, k.Prefix).Return(repo.APIKey{}, c.lookup)
+			}
+			if c.wantErr == nil {
+				m.On("Touch", mock.Anything, "k1", now, now.Add(-time.Minute)).Return(nil)
+			}
+			s := NewAPIKeyService(m)
+			s.Clock = FixedClock(now)
+
+			p, err := s.Authenticate(context.Background(), c.key)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, auth.Principal{Scheme: auth.SchemeAPIKey, Subject: "api_key:" + k.Prefix, StoreID: "acme", Scopes: []string{auth.ScopeOrdersWrite}}, p)
+		})
+	}
+}
+
+func TestAPIKeyService_Mint(t *testing.T) {
+	m := repomock.NewAPIKeyRepository(t)
+	var stored repo.APIKey
+	m.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(repo.APIKey)
+	}).Return(nil)
+	s := NewAPIKeyService(m)
+
+	minted, err := s.Mint(context.Background(), "pos", []string{auth.ScopeOrdersWrite, auth.ScopeCouponsAdmin}, time.Time{})
+	require.NoError(t, err)
+	prefix, secret, ok := apikey.Parse(minted.Key)
+	require.True(t, ok)
+	assert.Equal(t, stored.Prefix, prefix)
+	assert.True(t, apikey.Verify(stored.Salt, stored.Hash, secret))
+	assert.False(t, stored.ExpiresAt.Valid)
+
+	_, err = s.Mint(context.Background(), "pos", []string{"orders:read"}, time.Time{})
+	require.ErrorIs(t, err, ErrAPIKeyScope)
+
+	_, err = s.Mint(context.Background(), "pos", []string{auth.ScopeOrdersWrite}, time.Now().Add(-time.Hour))
+	require.ErrorIs(t, err, ErrAPIKeyExpired)
+}
+
+func TestAPIKeyService_Rotate(t *testing.T) {
+	now := time.Date(2025, 10, 8, 12, 0, 0, 0, time.UTC)
+	old := repo.APIKey{ID: "k1", StoreID: "acme", Prefix: "0123456789ab", Label: "pos", Scopes: []string{auth.ScopeOrdersWrite}}
+
+	m := repomock.NewAPIKeyRepository(t)
+	m.On("GetByPrefix", mock.Anything, old.Prefix).Return(old, nil)
+	m.On("Expire", mock.Anything, old.Prefix, now.Add(24*time.Hour)).Return(true, nil)
+	m.On("Create", mock.Anything, mock.MatchedBy(func(k repo.APIKey) bool {
+		return k.Prefix != old.Prefix && k.Label == "pos" && assert.ObjectsAreEqual(old.Scopes, k.Scopes)
+	})).Return(nil)
+	s := NewAPIKeyService(m)
+	s.Clock = FixedClock(now)
+
+	minted, err := s.Rotate(context.Background(), old.Prefix, 24*time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, minted.Key)
+}
+
+func TestAPIKeyService_Rotate_Expiry(t *testing.T) {
+	now := time.Date(2025, 10, 8, 12, 0, 0, 0, time.UTC)
+	type tc struct {
+		name      string
+		expiresAt time.Time
+		wantErr   error
+	}
+	cases := []tc{
+		{name: "expiry carried over", expiresAt: now.Add(30 * 24 * time.Hour)},
+		{name: "already expired", expiresAt: now.Add(-time.Hour), wantErr: ErrAPIKeyExpired},
+		{name: "expires now", expiresAt: now, wantErr: ErrAPIKeyExpired},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			old := repo.APIKey{Prefix: "0123456789ab", Label: "pos", Scopes: []string{auth.ScopeOrdersWrite},
+				ExpiresAt: sql.NullTime{Time: c.expiresAt, Valid: true}}
+			m := repomock.NewAPIKeyRepository(t)
+			m.On("GetByPrefix", mock.Anything, old.Prefix).Return(old, nil)
+			if c.wantErr == nil {
+				m.On("Expire", mock.Anything, old.Prefix, now.Add(time.Hour)).Return(true, nil)
+				m.On("Create", mock.Anything, mock.MatchedBy(func(k repo.APIKey) bool {
+					return k.ExpiresAt.Valid && k.ExpiresAt.Time.Equal(c.expiresAt)
+				})).Return(nil)
+			}
+			s := NewAPIKeyService(m)
+			s.Clock = FixedClock(now)
+
+			_, err := s.Rotate(context.Background(), old.Prefix, time.Hour)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke_NotFound(t *testing.T) {
+	m := repomock.NewAPIKeyRepository(t)
+	m.On("Revoke", mock.Anything, "nope", mock.Anything).Return(false, nil)
+	require.ErrorIs(t, NewAPIKeyService(m).Revoke(context.Background(), "nope"), ErrAPIKeyNotFound)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kart/internal/money"
+	"kart/internal/promo"
+	"kart/internal/repo"
+)
+
+var (
+	ErrCartExpired     = newError(KindConflict, "cart_expired", "cart expired")
+	ErrCartCheckedOut  = newError(KindConflict, "cart_checked_out", "cart already checked out")
+	ErrCartEmpty       = newError(KindValidation, "cart_empty", "cart is empty")
+	ErrProductNotFound = newError(KindValidation, "product_not_found", "product not found")
+	ErrInvalidQuantity = newError(KindValidation, "invalid_quantity", "quantity must be positive")
+)
+
+// OrderPlacer is the order path carts check out through, so coupon and item
+// rules are enforced in one place.
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, in PlaceOrderInput) (PlaceOrderResult, error)
+	ValidateCoupon(ctx context.Context, couponCode string) error
+	Quote(ctx context.Context, currency, couponCode string, items []OrderItemInput) (Pricing, error)
+}
+
+type CartService struct {
+	Carts    repo.CartRepository
+	Products repo.ProductRepository
+	Orders   OrderPlacer
+	// Prices is optional; without it carts are priced at base prices.
+	Prices *PriceLists
+	// TTL is how long a cart lives after its last modification.
+	TTL time.Duration
+	// CheckoutTimeout is how long a checkout may hold the cart before another
+	// may take it over, so a checkout that died part way does not lock the
+	// cart for good. Zero means DefaultCheckoutTimeout.
+	CheckoutTimeout time.Duration
+
+	Clock Clock
+}
+
+// DefaultCheckoutTimeout is the CheckoutTimeout used when none is set.
+const DefaultCheckoutTimeout = 5 * time.Minute
+
+func NewCartService(c repo.CartRepository, p repo.ProductRepository, o OrderPlacer, ttl time.Duration) *CartService {
+	return &CartService{Carts: c, Products: p, Orders: o, TTL: ttl, Clock: SystemClock{}}
+}
+
+func (s *CartService) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+// Cart is a cart with its lines priced from the current catalog. Amounts are
+// in minor units of Currency.
+type Cart struct {
+	ID            string
+	Currency      string
+	Lines         []CartLine
+	Coupon        *CouponPreview
+	SubtotalCents int64
+	DiscountCents int64
+	TaxCents      int64
+	TaxInclusive  bool
+	TotalCents    int64
+	// Promotions are the automatic promotions included in DiscountCents.
+	Promotions []promo.Applied
+	ExpiresAt  time.Time
+	OrderID    string
+}
+
+// Total is what checking out the cart would charge.
+func (c Cart) Total() money.Money { return money.New(c.TotalCents, c.Currency) }
+
+type CartLine struct {
+	Product        repo.Product
+	Quantity       int32
+	LineTotalCents int64
+}
+
+// CouponPreview reports whether the attached coupon would be accepted at
+// checkout right now, and what it would take off. The cart's DiscountCents
+// includes DiscountCents.
+type CouponPreview struct {
+	Code          string
+	Valid         bool
+	Reason        string
+	DiscountCents int64
+}
+
+// Create starts an empty cart priced in currency, or in the store's default
+// currency when it is empty. The currency is fixed for the life of the cart.
+func (s *CartService) Create(ctx context.Context, currency string) (Cart, error) {
+	list, err := s.priceLists().Resolve(ctx, currency)
+	if err != nil {
+		return Cart{}, err
+	}
+	id := uuid.NewString()
+	if err := s.Carts.Create(ctx, id, list.Currency, s.expiry()); err != nil {
+		return Cart{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+// Get returns the cart with computed totals. Carts converted to an order
+// remain readable; expired carts do not.
+func (s *CartService) Get(ctx context.Context, id string) (Cart, error) {
+	c, err := s.Carts.Get(ctx, id)
+	if err != nil {
+		return Cart{}, err
+	}
+	if !c.OrderID.Valid && !s.now().Before(c.ExpiresAt) {
+		return Cart{}, ErrCartExpired
+	}
+	items, err := s.Carts.Items(ctx, id)
+	if err != nil {
+		return Cart{}, err
+	}
+	ids := make([]string, len(items))
+	for i, it := range items {
+		ids[i] = it.ProductID
+	}
+	products, err := s.Products.GetMany(ctx, ids)
+	if err != nil {
+		return Cart{}, err
+	}
+	// Products the cart's price list no longer sells show unpriced, like
+	// deleted ones, and are left out of the totals.
+	list, err := s.priceLists().Resolve(ctx, c.Currency)
+	if err != nil {
+		return Cart{}, err
+	}
+	if err := s.priceLists().Apply(ctx, list, products); err != nil {
+		return Cart{}, err
+	}
+
+	out := Cart{ID: c.ID, Currency: c.Currency, ExpiresAt: c.ExpiresAt, OrderID: c.OrderID.String}
+	out.Lines = make([]CartLine, 0, len(items))
+	priced := make([]OrderItemInput, 0, len(items))
+	for _, it := range items {
+		p, ok := products[it.ProductID]
+		if !ok {
+			p = repo.Product{ID: it.ProductID}
+		} else {
+			priced = append(priced, OrderItemInput{ProductID: it.ProductID, Quantity: it.Quantity})
+		}
+		line := CartLine{Product: p, Quantity: it.Quantity, LineTotalCents: int64(p.PriceCents) * int64(it.Quantity)}
+		out.Lines = append(out.Lines, line)
+	}
+	// Totals come from the order pricing, coupon included, so they match
+	// what checkout charges. A coupon checkout would refuse shows as invalid
+	// and is left out of the totals.
+	pricing, err := s.Orders.Quote(ctx, c.Currency, c.CouponCode.String, priced)
+	if c.CouponCode.Valid {
+		out.Coupon = &CouponPreview{Code: c.CouponCode.String, Valid: err == nil, DiscountCents: pricing.CouponDiscountCents}
+		if IsCouponRejection(err) {
+			out.Coupon.Reason = err.Error()
+			pricing, err = s.Orders.Quote(ctx, c.Currency, "", priced)
+		}
+	}
+	if err != nil {
+		return Cart{}, err
+	}
+	out.SubtotalCents = pricing.SubtotalCents
+	out.DiscountCents = pricing.DiscountCents
+	out.TaxCents = pricing.TaxCents
+	out.TaxInclusive = pricing.TaxInclusive
+	out.TotalCents = pricing.TotalCents
+	out.Promotions = pricing.Promotions
+	return out, nil
+}
+
+// AddItem adds qty of productID to the cart, on top of any quantity already
+// in it. A non-empty currency must match the cart's.
+func (s *CartService) AddItem(ctx context.Context, id, productID string, qty int32, currency string) (Cart, error) {
+	if err := s.checkItem(ctx, id, productID, qty, currency); err != nil {
+		return Cart{}, err
+	}
+	if err := s.Carts.AddItem(ctx, id, productID, qty); err != nil {
+		return Cart{}, err
+	}
+	return s.touched(ctx, id)
+}
+
+// SetItem sets the quantity of productID in the cart. A non-empty currency
+// must match the cart's.
+func (s *CartService) SetItem(ctx context.Context, id, productID string, qty int32, currency string) (Cart, error) {
+	if err := s.checkItem(ctx, id, productID, qty, currency); err != nil {
+		return Cart{}, err
+	}
+	if err := s.Carts.SetItem(ctx, id, productID, qty); err != nil {
+		return Cart{}, err
+	}
+	return s.touched(ctx, id)
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, id, productID string) (Cart, error) {
+	if _, err := s.mutable(ctx, id); err != nil {
+		return Cart{}, err
+	}
+	found, err := s.Carts.RemoveItem(ctx, id, productID)
+	if err != nil {
+		return Cart{}, err
+	}
+	if !found {
+		return Cart{}, ErrProductNotFound
+	}
+	return s.touched(ctx, id)
+}
+
+// ApplyCoupon attaches code to the cart if it passes order coupon validation.
+func (s *CartService) ApplyCoupon(ctx context.Context, id, code string) (Cart, error) {
+	if _, err := s.mutable(ctx, id); err != nil {
+		return Cart{}, err
+	}
+	if err := s.Orders.ValidateCoupon(ctx, code); err != nil {
+		return Cart{}, err
+	}
+	if err := s.Carts.SetCoupon(ctx, id, sql.NullString{String: code, Valid: code != ""}); err != nil {
+		return Cart{}, err
+	}
+	return s.touched(ctx, id)
+}
+
+func (s *CartService) RemoveCoupon(ctx context.Context, id string) (Cart, error) {
+	if _, err := s.mutable(ctx, id); err != nil {
+		return Cart{}, err
+	}
+	if err := s.Carts.SetCoupon(ctx, id, sql.NullString{}); err != nil {
+		return Cart{}, err
+	}
+	return s.touched(ctx, id)
+}
+
+// CheckoutInput carries what an order needs beyond the cart's contents.
+type CheckoutInput struct {
+	PaymentToken string
+	Customer     CustomerInput
+	RedeemPoints int64
+	GiftCards    []string
+}
+
+// Checkout converts the cart into an order through OrderPlacer.PlaceOrder.
+// A cart can be checked out once; concurrent attempts get ErrCartCheckedOut
+// until the claim on the cart is older than CheckoutTimeout. A checkout that
+// takes over a stale claim still gets ErrCartCheckedOut if the earlier one
+// placed an order, since the order insert allows one live order per cart.
+func (s *CartService) Checkout(ctx context.Context, id string, co CheckoutInput) (PlaceOrderResult, error) {
+	c, err := s.mutable(ctx, id)
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	items, err := s.Carts.Items(ctx, id)
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	if len(items) == 0 {
+		return PlaceOrderResult{}, ErrCartEmpty
+	}
+
+	// The database keeps microseconds; the claim must compare equal when it
+	// is released.
+	started := s.now().UTC().Truncate(time.Microsecond)
+	claimed, err := s.Carts.ClaimCheckout(ctx, id, started, started.Add(-s.checkoutTimeout()))
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	if !claimed {
+		return PlaceOrderResult{}, ErrCartCheckedOut
+	}
+
+	in := PlaceOrderInput{
+		CouponCode:   c.CouponCode.String,
+		Items:        make([]OrderItemInput, len(items)),
+		PaymentToken: co.PaymentToken,
+		Currency:     c.Currency,
+		Customer:     co.Customer,
+		RedeemPoints: co.RedeemPoints,
+		GiftCards:    co.GiftCards,
+		CartID:       id,
+	}
+	for i, it := range items {
+		in.Items[i] = OrderItemInput{ProductID: it.ProductID, Quantity: it.Quantity}
+	}
+	res, err := s.Orders.PlaceOrder(ctx, in)
+	if err != nil {
+		// A declined payment fails the order it created, so the cart can be
+		// checked out again with another payment method.
+		if rerr := s.Carts.ReleaseCheckout(context.WithoutCancel(ctx), id, started); rerr != nil {
+			slog.ErrorContext(ctx, "release cart checkout", "cart", id, "err", rerr)
+		}
+		if errors.Is(err, repo.ErrCartOrdered) {
+			return PlaceOrderResult{}, ErrCartCheckedOut
+		}
+		return PlaceOrderResult{}, err
+	}
+	// The order exists at this point. If recording it on the cart fails the
+	// claim stays in place, which keeps the cart from being ordered again
+	// until the claim goes stale.
+	if err := s.Carts.CompleteCheckout(context.WithoutCancel(ctx), id, res.OrderID); err != nil {
+		slog.ErrorContext(ctx, "complete cart checkout", "cart", id, "order", res.OrderID, "err", err)
+	}
+	return res, nil
+}
+
+// PurgeExpired deletes carts past their expiry that were never checked out.
+func (s *CartService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.Carts.DeleteExpired(ctx, s.now())
+}
+
+// checkItem validates adding productID to the cart: the quantity, the
+// requested currency against the cart's, and that the cart's price list
+// sells the product.
+func (s *CartService) checkItem(ctx context.Context, id, productID string, qty int32, currency string) error {
+	if qty <= 0 {
+		return ErrInvalidQuantity
+	}
+	c, err := s.mutable(ctx, id)
+	if err != nil {
+		return err
+	}
+	if currency != "" && !strings.EqualFold(currency, c.Currency) {
+		return withDetail(ErrCartCurrency, "cart is in %s, not %s", c.Currency, strings.ToUpper(currency))
+	}
+	p, err := s.Products.Get(ctx, productID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+	list, err := s.priceLists().Resolve(ctx, c.Currency)
+	if err != nil {
+		return err
+	}
+	byID := map[string]repo.Product{productID: p}
+	if err := s.priceLists().Apply(ctx, list, byID); err != nil {
+		return err
+	}
+	if _, ok := byID[productID]; !ok {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+func (s *CartService) priceLists() *PriceLists {
+	if s.Prices != nil {
+		return s.Prices
+	}
+	return &PriceLists{}
+}
+
+// mutable loads the cart and rejects it if it expired or is checking out.
+// A checkout claim older than CheckoutTimeout no longer holds the cart.
+func (s *CartService) mutable(ctx context.Context, id string) (repo.Cart, error) {
+	c, err := s.Carts.Get(ctx, id)
+	if err != nil {
+		return repo.Cart{}, err
+	}
+	if c.OrderID.Valid || (c.CheckoutStartedAt.Valid && s.now().Sub(c.CheckoutStartedAt.Time) <= s.checkoutTimeout()) {
+		return repo.Cart{}, ErrCartCheckedOut
+	}
+	if !s.now().Before(c.ExpiresAt) {
+		return repo.Cart{}, ErrCartExpired
+	}
+	return c, nil
+}
+
+// touched extends the cart's expiry after a modification and returns it.
+func (s *CartService) touched(ctx context.Context, id string) (Cart, error) {
+	if err := s.Carts.Touch(ctx, id, s.expiry()); err != nil {
+		return Cart{}, err
+	}
+	return s.Get(ctx, id)
+}
+
+func (s *CartService) expiry() time.Time {
+	return s.now().Add(s.TTL).UTC()
+}
+
+func (s *CartService) checkoutTimeout() time.Duration {
+	if s.CheckoutTimeout > 0 {
+		return s.CheckoutTimeout
+	}
+	return DefaultCheckoutTimeout
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/repo"
+)
+
+type fakeOrderPlacer struct {
+	couponErr error
+	// couponPercent is what a valid coupon takes off.
+	couponPercent int64
+	placeErr      error
+	placed        []PlaceOrderInput
+}
+
+func (f *fakeOrderPlacer) PlaceOrder(_ context.Context, in PlaceOrderInput) (PlaceOrderResult, error) {
+	if f.placeErr != nil {
+		return PlaceOrderResult{}, f.placeErr
+	}
+	f.placed = append(f.placed, in)
+	return PlaceOrderResult{OrderID: "order-1", Status: StatusPlaced, Items: in.Items}, nil
+}
+
+func (f *fakeOrderPlacer) ValidateCoupon(context.Context, string) error { return f.couponErr }
+
+var quotePrices = map[string]int64{"10": 1299, "12": 499}
+
+// Quote prices items from quotePrices, less couponPercent for a coupon, with
+// GST included at one eleventh.
+func (f *fakeOrderPlacer) Quote(_ context.Context, currency, couponCode string, items []OrderItemInput) (Pricing, error) {
+	if couponCode != "" && f.couponErr != nil {
+		return Pricing{}, f.couponErr
+	}
+	p := Pricing{Currency: currency}
+	for _, it := range items {
+		p.SubtotalCents += quotePrices[it.ProductID] * int64(it.Quantity)
+	}
+	if couponCode != "" {
+		p.CouponDiscountCents = p.SubtotalCents * f.couponPercent / 100
+		p.DiscountCents = p.CouponDiscountCents
+	}
+	p.TotalCents = p.SubtotalCents - p.DiscountCents
+	p.TaxCents = (p.SubtotalCents + 5) / 11
+	p.TaxInclusive = true
+	return p, nil
+}
+
+var cartNow = time.Date(2025, 10, 2, 9, 0, 0, 0, time.UTC)
+
+func newTestCartService(t *testing.T, op *fakeOrderPlacer) (*CartService, *repomock.CartRepository, *repomock.ProductRepository) {
+	c := repomock.NewCartRepository(t)
+	p := repomock.NewProductRepository(t)
+	svc := NewCartService(c, p, op, time.Hour)
+	svc.Clock = FixedClock(cartNow)
+	return svc, c, p
+}
+
+func TestCartService_Get(t *testing.T) {
+	type tc struct {
+		name      string
+		cart      repo.Cart
+		couponErr error
+		wantErr   error
+		assert    func(t *testing.T, c Cart)
+	}
+	coupon := sql.NullString{String: "SUPER100", Valid: true}
+	cases := []tc{
+		{
+			name: "totals",
+			cart: repo.Cart{ID: "c1", Currency: "AUD", ExpiresAt: cartNow.Add(time.Minute)},
+			assert: func(t *testing.T, c Cart) {
+				require.Len(t, c.Lines, 2)
+				require.EqualValues(t, 1299*2+499, c.SubtotalCents)
+				require.EqualValues(t, c.SubtotalCents, c.TotalCents)
+				require.Equal(t, "30.97", c.Total().String())
+				require.EqualValues(t, 282, c.TaxCents)
+				require.True(t, c.TaxInclusive)
+				require.Nil(t, c.Coupon)
+			},
+		},
+		{
+			name:      "coupon preview invalid",
+			cart:      repo.Cart{ID: "c1", ExpiresAt: cartNow.Add(time.Minute), CouponCode: coupon},
+			couponErr: ErrCouponCategories,
+			assert: func(t *testing.T, c Cart) {
+				require.NotNil(t, c.Coupon)
+				require.False(t, c.Coupon.Valid)
+				require.Equal(t, ErrCouponCategories.Error(), c.Coupon.Reason)
+				require.Zero(t, c.Coupon.DiscountCents)
+				require.EqualValues(t, 1299*2+499, c.TotalCents)
+			},
+		},
+		{
+			// The fake takes 10% off, as checkout's pricing would.
+			name: "coupon preview discount",
+			cart: repo.Cart{ID: "c1", ExpiresAt: cartNow.Add(time.Minute), CouponCode: coupon},
+			assert: func(t *testing.T, c Cart) {
+				require.NotNil(t, c.Coupon)
+				require.True(t, c.Coupon.Valid)
+				require.EqualValues(t, 309, c.Coupon.DiscountCents)
+				require.EqualValues(t, 309, c.DiscountCents)
+				require.EqualValues(t, 1299*2+499-309, c.TotalCents)
+			},
+		},
+		{
+			name:    "expired",
+			cart:    repo.Cart{ID: "c1", ExpiresAt: cartNow},
+			wantErr: ErrCartExpired,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, carts, products := newTestCartService(t, &fakeOrderPlacer{couponErr: c.couponErr, couponPercent: 10})
+			carts.On("Get", mock.Anything, "c1").Return(c.cart, nil)
+			if c.wantErr == nil {
+				carts.On("Items", mock.Anything, "c1").Return([]repo.CartItem{
+					{CartID: "c1", ProductID: "10", Quantity: 2},
+					{CartID: "c1", ProductID: "12", Quantity: 1},
+				}, nil)
+				products.On("GetMany", mock.Anything, []string{"10", "12"}).Return(map[string]repo.Product{
+					"10": {ID: "10", PriceCents: 1299},
+					"12": {ID: "12", PriceCents: 499},
+				}, nil)
+			}
+
+			got, err := svc.Get(context.Background(), "c1")
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			c.assert(t, got)
+		})
+	}
+}
+
+func TestCartService_AddItem(t *testing.T) {
+	open := repo.Cart{ID: "c1", Currency: "AUD", ExpiresAt: cartNow.Add(time.Minute)}
+	type tc struct {
+		name     string
+		qty      int32
+		currency string
+		setup    func(c *repomock.CartRepository, p *repomock.ProductRepository)
+		wantErr  error
+	}
+	cases := []tc{
+		{
+			name: "ok extends expiry",
+			qty:  2,
+			setup: func(c *repomock.CartRepository, p *repomock.ProductRepository) {
+				c.On("Get", mock.Anything, "c1").Return(open, nil)
+				p.On("Get", mock.Anything, "10").Return(repo.Product{ID: "10"}, nil)
+				c.On("AddItem", mock.Anything, "c1", "10", int32(2)).Return(nil)
+				c.On("Touch", mock.Anything, "c1", cartNow.Add(time.Hour)).Return(nil)
+				c.On("Items", mock.Anything, "c1").Return([]repo.CartItem{}, nil)
+				p.On("GetMany", mock.Anything, []string{}).Return(map[string]repo.Product{}, nil)
+			},
+		},
+		{
+			name:    "zero quantity",
+			qty:     0,
+			setup:   func(*repomock.CartRepository, *repomock.ProductRepository) {},
+			wantErr: ErrInvalidQuantity,
+		},
+		{
+			name:     "other currency",
+			qty:      1,
+			currency: "NZD",
+			setup: func(c *repomock.CartRepository, _ *repomock.ProductRepository) {
+				c.On("Get", mock.Anything, "c1").Return(open, nil)
+			},
+			wantErr: ErrCartCurrency,
+		},
+		{
+			name: "unknown product",
+			qty:  1,
+			setup: func(c *repomock.CartRepository, p *repomock.ProductRepository) {
+				c.On("Get", mock.Anything, "c1").Return(open, nil)
+				p.On("Get", mock.Anything, "10").Return(repo.Product{}, repo.ErrNotFound)
+			},
+			wantErr: ErrProductNotFound,
+		},
+		{
+			name: "checking out",
+			qty:  1,
+			setup: func(c *repomock.CartRepository, _ *repomock.ProductRepository) {
+				locked := open
+				locked.CheckoutStartedAt = sql.NullTime{Time: cartNow, Valid: true}
+				c.On("Get", mock.Anything, "c1").Return(locked, nil)
+			},
+			wantErr: ErrCartCheckedOut,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, carts, products := newTestCartService(t, &fakeOrderPlacer{})
+			c.setup(carts, products)
+			_, err := svc.AddItem(context.Background(), "c1", "10", c.qty, c.currency)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCartService_ApplyCoupon_Rejected(t *testing.T) {
+	svc, carts, _ := newTestCartService(t, &fakeOrderPlacer{couponErr: ErrCouponLength})
+	carts.On("Get", mock.Anything, "c1").Return(repo.Cart{ID: "c1", ExpiresAt: cartNow.Add(time.Minute)}, nil)
+
+	_, err := svc.ApplyCoupon(context.Background(), "c1", "ABC")
+	require.ErrorIs(t, err, ErrCouponLength)
+	carts.AssertNotCalled(t, "SetCoupon", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCartService_Checkout(t *testing.T) {
+	open := repo.Cart{ID: "c1", Currency: "AUD", ExpiresAt: cartNow.Add(time.Minute), CouponCode: sql.NullString{String: "HAPPYHRS", Valid: true}}
+	items := []repo.CartItem{{CartID: "c1", ProductID: "10", Quantity: 2}}
+	stale := open
+	stale.CheckoutStartedAt = sql.NullTime{Time: cartNow.Add(-DefaultCheckoutTimeout - time.Second), Valid: true}
+	busy := open
+	busy.CheckoutStartedAt = sql.NullTime{Time: cartNow.Add(-time.Minute), Valid: true}
+	type tc struct {
+		name     string
+		cart     *repo.Cart
+		items    []repo.CartItem
+		claimed  bool
+		placeErr error
+		setup    func(c *repomock.CartRepository)
+		wantErr  error
+	}
+	cases := []tc{
+		{
+			name:    "ok",
+			items:   items,
+			claimed: true,
+			setup: func(c *repomock.CartRepository) {
+				c.On("CompleteCheckout", mock.Anything, "c1", "order-1").Return(nil)
+			},
+		},
+		{
+			name:    "stale claim is taken over",
+			cart:    &stale,
+			items:   items,
+			claimed: true,
+			setup: func(c *repomock.CartRepository) {
+				c.On("CompleteCheckout", mock.Anything, "c1", "order-1").Return(nil)
+			},
+		},
+		{
+			name:    "live claim holds the cart",
+			cart:    &busy,
+			wantErr: ErrCartCheckedOut,
+		},
+		{
+			name:    "empty cart",
+			items:   []repo.CartItem{},
+			wantErr: ErrCartEmpty,
+		},
+		{
+			name:    "already claimed",
+			items:   items,
+			claimed: false,
+			wantErr: ErrCartCheckedOut,
+		},
+		{
+			name:     "order rejected releases claim",
+			items:    items,
+			claimed:  true,
+			placeErr: repo.ErrCouponRedeemed,
+			setup: func(c *repomock.CartRepository) {
+				c.On("ReleaseCheckout", mock.Anything, "c1", cartNow).Return(nil)
+			},
+			wantErr: repo.ErrCouponRedeemed,
+		},
+		{
+			name:     "takeover while the first checkout placed an order",
+			cart:     &stale,
+			items:    items,
+			claimed:  true,
+			placeErr: repo.ErrCartOrdered,
+			setup: func(c *repomock.CartRepository) {
+				c.On("ReleaseCheckout", mock.Anything, "c1", cartNow).Return(nil)
+			},
+			wantErr: ErrCartCheckedOut,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			op := &fakeOrderPlacer{placeErr: c.placeErr}
+			svc, carts, _ := newTestCartService(t, op)
+			cart := open
+			if c.cart != nil {
+				cart = *c.cart
+			}
+			carts.On("Get", mock.Anything, "c1").Return(cart, nil)
+			carts.On("Items", mock.Anything, "c1").Maybe().Return(c.items, nil)
+			if len(c.items) > 0 {
+				carts.On("ClaimCheckout", mock.Anything, "c1", cartNow, cartNow.Add(-DefaultCheckoutTimeout)).Return(c.claimed, nil)
+			}
+			if c.setup != nil {
+				c.setup(carts)
+			}
+
+			res, err := svc.Checkout(context.Background(), "c1", CheckoutInput{PaymentToken: "tok_visa"})
+			if c.wantErr != nil {
+				require.True(t, errors.Is(err, c.wantErr), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "order-1", res.OrderID)
+			require.Len(t, op.placed, 1)
+			require.Equal(t, "HAPPYHRS", op.placed[0].CouponCode)
+			require.Equal(t, "tok_visa", op.placed[0].PaymentToken)
+			require.Equal(t, "AUD", op.placed[0].Currency)
+			require.Equal(t, "c1", op.placed[0].CartID)
+			require.Equal(t, []OrderItemInput{{ProductID: "10", Quantity: 2}}, op.placed[0].Items)
+		})
+	}
+}
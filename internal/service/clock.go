@@ -0,0 +1,19 @@
+package service
+
+import "time"
+
+// Clock tells the time. Services that depend on the time of day take one so
+// tests can pin it.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// FixedClock always reports the same time.
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time { return time.Time(c) }
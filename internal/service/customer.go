@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/mail"
+	"regexp"
+
+	"kart/internal/repo"
+)
+
+var (
+	ErrContactInvalid = newError(KindValidation, "contact_invalid", "contact email or phone is invalid")
+	// ErrNotCustomer is returned for customer-only operations by callers that
+	// are not signed in as a customer.
+	ErrNotCustomer = newError(KindValidation, "not_customer", "caller is not a customer")
+)
+
+// maxCustomerOrders caps one page of a customer's order history.
+const maxCustomerOrders = 100
+
+// e164 matches phone numbers in E.164 form, e.g. +61412345678.
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// CustomerInput identifies who an order is for. ID is set for a signed-in
+// customer and comes from their credentials, never from the request body.
+// Guests leave it empty and may give contact details instead.
+type CustomerInput struct {
+	ID    string
+	Email string
+	Phone string
+}
+
+func (c CustomerInput) validate() error {
+	if c.Email != "" {
+		if a, err := mail.ParseAddress(c.Email); err != nil || a.Address != c.Email {
+			return ErrContactInvalid
+		}
+	}
+	if c.Phone != "" && !e164.MatchString(c.Phone) {
+		return ErrContactInvalid
+	}
+	return nil
+}
+
+// apply records the customer on o.
+func (c CustomerInput) apply(o *repo.Order) {
+	o.CustomerID = sql.NullString{String: c.ID, Valid: c.ID != ""}
+	o.ContactEmail = sql.NullString{String: c.Email, Valid: c.Email != ""}
+	o.ContactPhone = sql.NullString{String: c.Phone, Valid: c.Phone != ""}
+}
+
+// CustomerOrders returns up to limit of the customer's orders in the
+// context's store, newest first.
+func (s *OrderService) CustomerOrders(ctx context.Context, customerID string, limit int) ([]repo.Order, error) {
+	if customerID == "" {
+		return nil, ErrNotCustomer
+	}
+	if limit <= 0 || limit > maxCustomerOrders {
+		limit = maxCustomerOrders
+	}
+	return s.Orders.ListByCustomer(ctx, customerID, int32(limit))
+}
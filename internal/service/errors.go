@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"kart/internal/repo"
+)
+
+// Kind classifies a service error by how the caller can react to it.
+type Kind int
+
+const (
+	// KindValidation means the request can never succeed as sent.
+	KindValidation Kind = iota + 1
+	// KindNotFound means the resource the request names does not exist.
+	KindNotFound
+	// KindConflict means the request clashes with the resource's current
+	// state, which may have been changed by someone else.
+	KindConflict
+	// KindUnavailable means a dependency failed; the same request may
+	// succeed later.
+	KindUnavailable
+)
+
+// Error is a failure the service reports to its callers. Code is stable
+// and safe to show to clients; Msg is the human-readable explanation.
+type Error struct {
+	Kind Kind
+	Code string
+	Msg  string
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+func newError(kind Kind, code, msg string) *Error {
+	return &Error{Kind: kind, Code: code, Msg: msg}
+}
+
+// detailError adds specifics the service wrote itself, such as which card was
+// refused, to a service error. Unlike a cause wrapped with fmt.Errorf, they
+// are safe to show clients.
+type detailError struct {
+	err    *Error
+	detail string
+}
+
+func (e *detailError) Error() string { return e.err.Msg + ": " + e.detail }
+
+func (e *detailError) Unwrap() error { return e.err }
+
+func withDetail(err *Error, format string, args ...any) error {
+	return &detailError{err: err, detail: fmt.Sprintf(format, args...)}
+}
+
+// repoErrors classifies the repository failures callers can act on, which
+// the services pass through unchanged.
+var repoErrors = []struct {
+	err error
+	as  *Error
+}{
+	{repo.ErrNotFound, newError(KindNotFound, "not_found", "not found")},
+	{repo.ErrCouponRedeemed, newError(KindConflict, "coupon_redeemed", "coupon already redeemed")},
+	{repo.ErrInsufficientPoints, newError(KindConflict, "insufficient_points", repo.ErrInsufficientPoints.Error())},
+	{repo.ErrGiftCardBalance, newError(KindConflict, "gift_card_balance_changed", repo.ErrGiftCardBalance.Error())},
+}
+
+// ErrDatabaseUnavailable classifies database failures that may clear up, such
+// as an exhausted connection pool.
+var ErrDatabaseUnavailable = newError(KindUnavailable, "database_unavailable", "database temporarily unavailable")
+
+// AsError returns the service error in err's chain, or the classification of
+// a repository failure it wraps. It reports false for unexpected failures,
+// whose messages must not reach clients.
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	for _, r := range repoErrors {
+		if errors.Is(err, r.err) {
+			return r.as, true
+		}
+	}
+	if repo.IsUnavailable(err) {
+		return ErrDatabaseUnavailable, true
+	}
+	return nil, false
+}
+
+// Detail is what clients are told about err: the service error's Msg and any
+// detail the service added with it, never the text of a cause wrapped along
+// with it, which may be a database or payment provider error. It is empty for
+// unexpected failures.
+func Detail(err error) string {
+	e, ok := AsError(err)
+	if !ok {
+		return ""
+	}
+	var d *detailError
+	if errors.As(err, &d) && d.err == e {
+		return d.Error()
+	}
+	return e.Msg
+}
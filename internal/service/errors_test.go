@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kart/internal/repo"
+)
+
+func TestAsError(t *testing.T) {
+	type tc struct {
+		name     string
+		err      error
+		wantKind Kind
+		wantCode string
+	}
+	cases := []tc{
+		{name: "service error", err: ErrCartEmpty, wantKind: KindValidation, wantCode: "cart_empty"},
+		{name: "with detail", err: withDetail(ErrCartCurrency, "cart is in AUD, not NZD"), wantKind: KindValidation, wantCode: "cart_currency"},
+		{name: "repository failure", err: fmt.Errorf("redeem: %w", repo.ErrInsufficientPoints), wantKind: KindConflict, wantCode: "insufficient_points"},
+		{name: "unexpected", err: errors.New("connection reset by peer")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, ok := AsError(c.err)
+			if c.wantCode == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, c.wantKind, e.Kind)
+			assert.Equal(t, c.wantCode, e.Code)
+		})
+	}
+}
+
+func TestDetail(t *testing.T) {
+	dbErr := errors.New("pq: deadlock detected")
+	type tc struct {
+		name string
+		err  error
+		want string
+	}
+	cases := []tc{
+		{name: "service error", err: ErrCartEmpty, want: "cart is empty"},
+		{name: "with detail", err: withDetail(ErrCartCurrency, "cart is in AUD, not NZD"), want: "cart is priced in a different currency: cart is in AUD, not NZD"},
+		{name: "detail joined with a cause", err: errors.Join(withDetail(ErrCartCurrency, "cart is in AUD, not NZD"), dbErr), want: "cart is priced in a different currency: cart is in AUD, not NZD"},
+		{name: "wrapped cause", err: fmt.Errorf("%w: %v", ErrPaymentUnavailable, dbErr), want: "payment provider unavailable"},
+		{name: "another error's detail", err: fmt.Errorf("%w: %w", ErrRefundExceeded, withDetail(ErrCartCurrency, "AUD")), want: "refund exceeds the amount still refundable"},
+		{name: "repository failure", err: fmt.Errorf("redeem: %w", repo.ErrInsufficientPoints), want: repo.ErrInsufficientPoints.Error()},
+		{name: "unexpected", err: dbErr},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, Detail(c.err))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kart/internal/giftcard"
+	"kart/internal/repo"
+)
+
+var (
+	ErrGiftCardNotFound     = newError(KindValidation, "gift_card_not_found", "gift card not found")
+	ErrGiftCardUnusable     = newError(KindValidation, "gift_card_unusable", "gift card is expired or disabled")
+	ErrGiftCardCurrency     = newError(KindValidation, "gift_card_currency", "gift card is in a different currency")
+	ErrGiftCardAmount       = newError(KindValidation, "gift_card_amount", "gift card amount must be positive")
+	ErrGiftCardsUnavailable = newError(KindValidation, "gift_cards_unavailable", "gift cards are not accepted")
+	ErrGiftCardLimit        = newError(KindValidation, "gift_card_limit", "too many gift cards on one order")
+)
+
+// maxGiftCardsPerOrder caps how many cards one order can be split across.
+const maxGiftCardsPerOrder = 5
+
+// GiftCardService issues gift cards and reports their balances.
+type GiftCardService struct {
+	Cards repo.GiftCardRepository
+	// Currency is the default currency of issued cards.
+	Currency string
+
+	Clock Clock
+}
+
+func NewGiftCardService(c repo.GiftCardRepository, currency string) *GiftCardService {
+	return &GiftCardService{Cards: c, Currency: currency, Clock: SystemClock{}}
+}
+
+func (s *GiftCardService) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+type IssueGiftCardInput struct {
+	AmountCents int64
+	// Currency defaults to the service's Currency.
+	Currency string
+	// ExpiresAt is optional; the zero time never expires.
+	ExpiresAt time.Time
+}
+
+// IssuedGiftCard is a newly issued card. Code is the plaintext, which is not
+// stored and cannot be shown again.
+type IssuedGiftCard struct {
+	repo.GiftCard
+	Code string
+}
+
+// Issue creates a card in the context's store holding AmountCents.
+func (s *GiftCardService) Issue(ctx context.Context, in IssueGiftCardInput) (IssuedGiftCard, error) {
+	if in.AmountCents <= 0 {
+		return IssuedGiftCard{}, ErrGiftCardAmount
+	}
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = s.Currency
+	}
+	code, err := giftcard.Generate()
+	if err != nil {
+		return IssuedGiftCard{}, err
+	}
+	card := repo.GiftCard{
+		ID:           uuid.NewString(),
+		CodeHash:     code.Hash,
+		Last4:        code.Last4,
+		Currency:     currency,
+		InitialCents: in.AmountCents,
+		BalanceCents: in.AmountCents,
+		CreatedAt:    s.now().UTC(),
+		ExpiresAt:    sql.NullTime{Time: in.ExpiresAt.UTC(), Valid: !in.ExpiresAt.IsZero()},
+	}
+	if err := s.Cards.Create(ctx, card); err != nil {
+		return IssuedGiftCard{}, err
+	}
+	return IssuedGiftCard{GiftCard: card, Code: code.Plaintext}, nil
+}
+
+// Balance looks a card up by its code.
+func (s *GiftCardService) Balance(ctx context.Context, code string) (repo.GiftCard, error) {
+	return lookupGiftCard(ctx, s.Cards, code)
+}
+
+// Transactions returns the card's history, oldest first.
+func (s *GiftCardService) Transactions(ctx context.Context, id string) ([]repo.GiftCardTransaction, error) {
+	if _, err := s.Cards.Get(ctx, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrGiftCardNotFound
+		}
+		return nil, err
+	}
+	return s.Cards.Transactions(ctx, id)
+}
+
+func lookupGiftCard(ctx context.Context, cards repo.GiftCardRepository, code string) (repo.GiftCard, error) {
+	n, ok := giftcard.Normalize(code)
+	if !ok {
+		return repo.GiftCard{}, ErrGiftCardNotFound
+	}
+	c, err := cards.GetByCode(ctx, giftcard.Hash(n))
+	if errors.Is(err, repo.ErrNotFound) {
+		return repo.GiftCard{}, ErrGiftCardNotFound
+	}
+	return c, err
+}
+
+// GiftCardUse is what an order took from one gift card.
+type GiftCardUse struct {
+	GiftCardID  string
+	Last4       string
+	AmountCents int64
+	// BalanceCents is what the card holds after the order.
+	BalanceCents int64
+}
+
+// planGiftCards works out how much of due to take from each card, in the
+// order given, leaving the rest of each card's balance on it. Cards not
+// needed once due is covered are left untouched.
+func (s *OrderService) planGiftCards(ctx context.Context, codes []string, currency string, due int64) ([]GiftCardUse, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+	if s.GiftCards == nil {
+		return nil, ErrGiftCardsUnavailable
+	}
+	if len(codes) > maxGiftCardsPerOrder {
+		return nil, ErrGiftCardLimit
+	}
+	var uses []GiftCardUse
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		c, err := lookupGiftCard(ctx, s.GiftCards, code)
+		if err != nil {
+			return nil, err
+		}
+		if seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+		if c.DisabledAt.Valid || (c.ExpiresAt.Valid && !s.now().Before(c.ExpiresAt.Time)) {
+			return nil, withDetail(ErrGiftCardUnusable, "card ending %s", c.Last4)
+		}
+		if c.Currency != currency {
+			return nil, withDetail(ErrGiftCardCurrency, "card ending %s is in %s", c.Last4, c.Currency)
+		}
+		amount := min(c.BalanceCents, due)
+		if amount == 0 {
+			continue
+		}
+		due -= amount
+		uses = append(uses, GiftCardUse{GiftCardID: c.ID, Last4: c.Last4, AmountCents: amount, BalanceCents: c.BalanceCents - amount})
+	}
+	return uses, nil
+}
+
+func giftCardDebits(uses []GiftCardUse) ([]repo.GiftCardDebit, int64) {
+	debits := make([]repo.GiftCardDebit, len(uses))
+	var total int64
+	for i, u := range uses {
+		debits[i] = repo.GiftCardDebit{GiftCardID: u.GiftCardID, AmountCents: u.AmountCents}
+		total += u.AmountCents
+	}
+	return debits, total
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"kart/internal/giftcard"
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/repo"
+)
+
+func TestGiftCardService_Issue(t *testing.T) {
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	type tc struct {
+		name         string
+		in           IssueGiftCardInput
+		wantCurrency string
+		wantErr      error
+	}
+	cases := []tc{
+		{name: "store currency", in: IssueGiftCardInput{AmountCents: 5000}, wantCurrency: "AUD"},
+		{name: "other currency and expiry", in: IssueGiftCardInput{AmountCents: 5000, Currency: "nzd", ExpiresAt: expires}, wantCurrency: "NZD"},
+		{name: "zero amount", in: IssueGiftCardInput{}, wantErr: ErrGiftCardAmount},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cards := repomock.NewGiftCardRepository(t)
+			var stored repo.GiftCard
+			if c.wantErr == nil {
+				cards.On("Create", mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) { stored = args.Get(1).(repo.GiftCard) }).
+					Return(nil)
+			}
+			s := NewGiftCardService(cards, "AUD")
+
+			got, err := s.Issue(context.Background(), c.in)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			n, ok := giftcard.Normalize(got.Code)
+			require.True(t, ok)
+			require.Equal(t, giftcard.Hash(n), stored.CodeHash, "only the code's hash is stored")
+			require.Equal(t, c.wantCurrency, stored.Currency)
+			require.Equal(t, c.in.AmountCents, stored.BalanceCents)
+			require.Equal(t, !c.in.ExpiresAt.IsZero(), stored.ExpiresAt.Valid)
+		})
+	}
+}
+
+func TestGiftCardService_Balance(t *testing.T) {
+	code, err := giftcard.Generate()
+	require.NoError(t, err)
+	type tc struct {
+		name    string
+		code    string
+		found   bool
+		wantErr error
+	}
+	cases := []tc{
+		{name: "found", code: code.Plaintext, found: true},
+		{name: "lower case without dashes", code: "  " + lowerNoDashes(code.Plaintext), found: true},
+		{name: "unknown", code: code.Plaintext, wantErr: ErrGiftCardNotFound},
+		{name: "malformed", code: "not-a-code", wantErr: ErrGiftCardNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cards := repomock.NewGiftCardRepository(t)
+			switch {
+			case c.found:
+				cards.On("GetByCode", mock.Anything, code.Hash).Return(repo.GiftCard{ID: "g1", BalanceCents: 1200}, nil)
+			case c.code == code.Plaintext:
+				cards.On("GetByCode", mock.Anything, code.Hash).Return(repo.GiftCard{}, repo.ErrNotFound)
+			}
+			got, err := NewGiftCardService(cards, "AUD").Balance(context.Background(), c.code)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int64(1200), got.BalanceCents)
+		})
+	}
+}
+
+func lowerNoDashes(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '-' {
+			out = append(out, s[i]|0x20)
+		}
+	}
+	return string(out)
+}
+
+func TestOrderService_PlaceOrder_GiftCards(t *testing.T) {
+	now := time.Date(2025, 10, 11, 12, 0, 0, 0, time.UTC)
+	first, err := giftcard.Generate()
+	require.NoError(t, err)
+	second, err := giftcard.Generate()
+	require.NoError(t, err)
+	card := func(id string, balance int64) repo.GiftCard {
+		return repo.GiftCard{ID: id, Last4: id, Currency: "AUD", InitialCents: 5000, BalanceCents: balance}
+	}
+	expired := card("g1", 1000)
+	expired.ExpiresAt = sql.NullTime{Time: now, Valid: true}
+	nzd := card("g1", 1000)
+	nzd.Currency = "NZD"
+
+	found := func(code giftcard.Code, c repo.GiftCard) func(*repomock.GiftCardRepository) {
+		return func(cards *repomock.GiftCardRepository) {
+			cards.On("GetByCode", mock.Anything, code.Hash).Return(c, nil)
+		}
+	}
+
+	type tc struct {
+		name        string
+		codes       []string
+		setup       []func(cards *repomock.GiftCardRepository)
+		wantDebits  []repo.GiftCardDebit
+		wantPayment int64
+		wantErr     error
+	}
+	cases := []tc{
+		{
+			name:        "card pays part of the order",
+			codes:       []string{first.Plaintext},
+			setup:       []func(*repomock.GiftCardRepository){found(first, card("g1", 1000))},
+			wantDebits:  []repo.GiftCardDebit{{GiftCardID: "g1", AmountCents: 1000}},
+			wantPayment: 1598,
+		},
+		{
+			name:       "cards pay the whole order",
+			codes:      []string{first.Plaintext, second.Plaintext},
+			setup:      []func(*repomock.GiftCardRepository){found(first, card("g1", 2000)), found(second, card("g2", 5000))},
+			wantDebits: []repo.GiftCardDebit{{GiftCardID: "g1", AmountCents: 2000}, {GiftCardID: "g2", AmountCents: 598}},
+		},
+		{
+			name:       "same card twice",
+			codes:      []string{first.Plaintext, first.Plaintext},
+			setup:      []func(*repomock.GiftCardRepository){found(first, card("g1", 5000))},
+			wantDebits: []repo.GiftCardDebit{{GiftCardID: "g1", AmountCents: 2598}},
+		},
+		{name: "expired", codes: []string{first.Plaintext}, setup: []func(*repomock.GiftCardRepository){found(first, expired)}, wantErr: ErrGiftCardUnusable},
+		{name: "other currency", codes: []string{first.Plaintext}, setup: []func(*repomock.GiftCardRepository){found(first, nzd)}, wantErr: ErrGiftCardCurrency},
+		{
+			name:  "unknown code",
+			codes: []string{first.Plaintext},
+			setup: []func(*repomock.GiftCardRepository){func(cards *repomock.GiftCardRepository) {
+				cards.On("GetByCode", mock.Anything, first.Hash).Return(repo.GiftCard{}, repo.ErrNotFound)
+			}},
+			wantErr: ErrGiftCardNotFound,
+		},
+		{name: "too many", codes: make([]string, maxGiftCardsPerOrder+1), wantErr: ErrGiftCardLimit},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, _ := newPaymentOrderService(t)
+			svc.Currency = "AUD"
+			svc.Clock = FixedClock(now)
+			cards := repomock.NewGiftCardRepository(t)
+			svc.GiftCards = cards
+			for _, setup := range c.setup {
+				setup(cards)
+			}
+
+			var debits []repo.GiftCardDebit
+			if c.wantErr == nil {
+				args := []any{mock.Anything, mock.Anything, mock.Anything, mock.Anything}
+				for range c.wantDebits {
+					args = append(args, mock.Anything)
+				}
+				o.On("CreateWithItems", args...).Run(func(args mock.Arguments) {
+					for _, a := range args[4:] {
+						debits = append(debits, a.(repo.GiftCardDebit))
+					}
+				}).Return("o1", nil)
+			}
+			if c.wantPayment > 0 {
+				pr.On("Create", mock.Anything, mock.MatchedBy(func(p repo.Payment) bool {
+					return p.AmountCents == c.wantPayment
+				})).Return(nil)
+				echoPaymentUpdates(pr)
+				o.On("UpdateStatus", mock.Anything, "o1", StatusPendingPayment, StatusPlaced, sql.NullTime{}).
+					Return(repo.Order{ID: "o1", Status: StatusPlaced}, nil)
+			}
+
+			res, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{
+				Items:        []OrderItemInput{{ProductID: "10", Quantity: 2}},
+				PaymentToken: "tok_visa",
+				GiftCards:    c.codes,
+			})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantDebits, debits)
+			require.Equal(t, StatusPlaced, res.Status)
+			require.Equal(t, int64(2598)-c.wantPayment, res.GiftCardCents)
+		})
+	}
+}
+
+func TestOrderService_UpdateStatus_RestoresGiftCards(t *testing.T) {
+	o := repomock.NewOrderRepository(t)
+	o.On("Get", mock.Anything, "o1").Return(repo.Order{ID: "o1", Status: StatusPlaced, GiftCardCents: 1000}, nil)
+	// Reverse credits the cards back in the same transaction as the cancel.
+	o.On("Reverse", mock.Anything, "o1", StatusPlaced, StatusCancelled, sql.NullTime{}).
+		Return(repo.Order{ID: "o1", Status: StatusCancelled, GiftCardCents: 1000}, nil).Once()
+
+	svc := NewOrderService(nil, nil, o)
+	svc.GiftCards = repomock.NewGiftCardRepository(t)
+	_, err := svc.UpdateStatus(context.Background(), UpdateStatusInput{OrderID: "o1", Status: StatusCancelled})
+	require.NoError(t, err)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"kart/internal/money"
+	"kart/internal/repo"
+)
+
+var (
+	ErrLoyaltyUnavailable = newError(KindValidation, "loyalty_unavailable", "loyalty points cannot be redeemed")
+	ErrPointsInvalid      = newError(KindValidation, "points_invalid", "redeemed points must be positive and worth no more than the order total")
+)
+
+// maxLoyaltyEntries caps one page of a customer's points history.
+const maxLoyaltyEntries = 100
+
+// Loyalty awards customers points for completed orders and lets them spend
+// points as part payment for new ones.
+//
+// Points are earned per major currency unit paid for each line, after its
+// share of the order discount, at the rate configured for the product's
+// category. The part of an order paid with points earns nothing. Refunds take
+// back the points earned on the refunded amount; orders that are cancelled,
+// fail payment or are refunded in full return the points they redeemed.
+type Loyalty struct {
+	Ledger repo.LoyaltyRepository
+	// PointValue is what one redeemed point is worth in minor units of the
+	// order currency. Zero disables redemption.
+	PointValue int64
+}
+
+// LoyaltyAccount is a customer's points balance with their latest entries.
+type LoyaltyAccount struct {
+	Balance int64
+	Entries []repo.LoyaltyEntry
+}
+
+// redemption prices redeeming points against an order totalling totalCents.
+func (l *Loyalty) redemption(customer CustomerInput, points, totalCents int64) (int64, error) {
+	if points == 0 {
+		return 0, nil
+	}
+	if l == nil || l.PointValue <= 0 {
+		return 0, ErrLoyaltyUnavailable
+	}
+	if customer.ID == "" {
+		return 0, ErrNotCustomer
+	}
+	if points < 0 || points > totalCents/l.PointValue {
+		return 0, ErrPointsInvalid
+	}
+	return points * l.PointValue, nil
+}
+
+// earnEntry returns the entry awarding the customer points for completing o,
+// if it earns any.
+func (l *Loyalty) earnEntry(ctx context.Context, s *OrderService, o repo.Order) ([]repo.LoyaltyEntry, error) {
+	if !o.CustomerID.Valid || o.TotalCents <= o.PointsCents {
+		return nil, nil
+	}
+	rules, err := l.Ledger.Rules(ctx)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	items, err := s.Orders.Items(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(items))
+	for i, it := range items {
+		ids[i] = it.ProductID
+	}
+	products, err := s.Products.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtotal int64
+	for _, it := range items {
+		subtotal += it.UnitPriceCents * int64(it.Quantity)
+	}
+	perLine := lineDiscountsRecorded(items)
+	// Sum of line value times rate, in minor units times points per unit.
+	var weighted int64
+	for _, it := range items {
+		rate, ok := rules[products[it.ProductID].Category]
+		if !ok {
+			rate = rules[repo.DefaultLoyaltyCategory]
+		}
+		net := it.UnitPriceCents * int64(it.Quantity)
+		switch {
+		case perLine:
+			net -= it.DiscountCents
+		case subtotal > 0:
+			net -= o.DiscountCents * net / subtotal
+		}
+		weighted += net * int64(rate)
+	}
+	paid := o.TotalCents - o.PointsCents
+	points := weighted * paid / o.TotalCents / pow10(money.Exponent(o.Currency))
+	if points <= 0 {
+		return nil, nil
+	}
+	return []repo.LoyaltyEntry{{
+		CustomerID: o.CustomerID.String,
+		OrderID:    sql.NullString{String: o.ID, Valid: true},
+		Kind:       repo.LoyaltyEarn,
+		Points:     points,
+		Key:        repo.LoyaltyEarn + ":" + o.ID,
+	}}, nil
+}
+
+// reverseEntry returns the entry taking back the points earned on o in
+// proportion to refundedCents of capturedCents, less what earlier refunds
+// already took back.
+func (l *Loyalty) reverseEntry(ctx context.Context, o repo.Order, refundID string, refundedCents, capturedCents int64) ([]repo.LoyaltyEntry, error) {
+	if !o.CustomerID.Valid || capturedCents <= 0 {
+		return nil, nil
+	}
+	entries, err := l.Ledger.OrderEntries(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	var earned, reversed int64
+	for _, e := range entries {
+		switch e.Kind {
+		case repo.LoyaltyEarn:
+			earned += e.Points
+		case repo.LoyaltyReverse:
+			reversed -= e.Points
+		}
+	}
+	points := earned*refundedCents/capturedCents - reversed
+	if points <= 0 {
+		return nil, nil
+	}
+	return []repo.LoyaltyEntry{{
+		CustomerID: o.CustomerID.String,
+		OrderID:    sql.NullString{String: o.ID, Valid: true},
+		Kind:       repo.LoyaltyReverse,
+		Points:     -points,
+		Key:        repo.LoyaltyReverse + ":" + refundID,
+	}}, nil
+}
+
+// restoreEntry returns the entry giving back the points o redeemed, if any.
+func restoreEntry(o repo.Order) []repo.LoyaltyEntry {
+	if !o.CustomerID.Valid || o.PointsRedeemed == 0 {
+		return nil
+	}
+	return []repo.LoyaltyEntry{repo.RestoreEntry(o)}
+}
+
+// LoyaltyAccount returns the customer's points balance and up to limit of
+// their latest ledger entries.
+func (s *OrderService) LoyaltyAccount(ctx context.Context, customerID string, limit int) (LoyaltyAccount, error) {
+	if customerID == "" {
+		return LoyaltyAccount{}, ErrNotCustomer
+	}
+	if s.Loyalty == nil {
+		return LoyaltyAccount{Entries: []repo.LoyaltyEntry{}}, nil
+	}
+	if limit <= 0 || limit > maxLoyaltyEntries {
+		limit = maxLoyaltyEntries
+	}
+	balance, err := s.Loyalty.Ledger.Balance(ctx, customerID)
+	if err != nil {
+		return LoyaltyAccount{}, err
+	}
+	entries, err := s.Loyalty.Ledger.Entries(ctx, customerID, int32(limit))
+	if err != nil {
+		return LoyaltyAccount{}, err
+	}
+	return LoyaltyAccount{Balance: balance, Entries: entries}, nil
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for range n {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+func TestLoyalty_Redemption(t *testing.T) {
+	customer := CustomerInput{ID: "cust_1"}
+	type tc struct {
+		name     string
+		loyalty  *Loyalty
+		customer CustomerInput
+		points   int64
+		want     int64
+		wantErr  error
+	}
+	cases := []tc{
+		{name: "nothing redeemed", customer: customer},
+		{name: "worth a cent each", loyalty: &Loyalty{PointValue: 1}, customer: customer, points: 500, want: 500},
+		{name: "worth five cents each", loyalty: &Loyalty{PointValue: 5}, customer: customer, points: 200, want: 1000},
+		{name: "whole order", loyalty: &Loyalty{PointValue: 1}, customer: customer, points: 2598, want: 2598},
+		{name: "more than the order", loyalty: &Loyalty{PointValue: 1}, customer: customer, points: 2599, wantErr: ErrPointsInvalid},
+		{name: "negative", loyalty: &Loyalty{PointValue: 1}, customer: customer, points: -1, wantErr: ErrPointsInvalid},
+		{name: "guest", loyalty: &Loyalty{PointValue: 1}, points: 100, wantErr: ErrNotCustomer},
+		{name: "loyalty disabled", customer: customer, points: 100, wantErr: ErrLoyaltyUnavailable},
+		{name: "redemption disabled", loyalty: &Loyalty{}, customer: customer, points: 100, wantErr: ErrLoyaltyUnavailable},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.loyalty.redemption(c.customer, c.points, 2598)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestOrderService_PlaceOrder_RedeemPoints(t *testing.T) {
+	type tc struct {
+		name       string
+		points     int64
+		setup      func(o *repomock.OrderRepository, pr *repomock.PaymentRepository)
+		wantStatus string
+		wantErr    error
+	}
+	cases := []tc{
+		{
+			name:   "points pay part of the order",
+			points: 598,
+			setup: func(o *repomock.OrderRepository, pr *repomock.PaymentRepository) {
+				pr.On("Create", mock.Anything, mock.MatchedBy(func(p repo.Payment) bool {
+					return p.AmountCents == 2000
+				})).Return(nil)
+				echoPaymentUpdates(pr)
+				o.On("UpdateStatus", mock.Anything, "o1", StatusPendingPayment, StatusPlaced, sql.NullTime{}).
+					Return(repo.Order{ID: "o1", Status: StatusPlaced}, nil)
+			},
+			wantStatus: StatusPlaced,
+		},
+		{
+			name:       "points pay the whole order",
+			points:     2598,
+			wantStatus: StatusPlaced,
+		},
+		{
+			name:    "balance too low",
+			points:  598,
+			wantErr: repo.ErrInsufficientPoints,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, _ := newPaymentOrderService(t)
+			svc.Loyalty = &Loyalty{Ledger: repomock.NewLoyaltyRepository(t), PointValue: 1}
+			var createErr error
+			if c.wantErr != nil {
+				createErr = c.wantErr
+			}
+			o.On("CreateWithItems", mock.Anything, mock.MatchedBy(func(ord repo.Order) bool {
+				return ord.TotalCents == 2598 && ord.PointsRedeemed == c.points && ord.PointsCents == c.points && ord.CustomerID.String == "cust_1"
+			}), mock.Anything, mock.Anything).Return("o1", createErr)
+			if c.setup != nil {
+				c.setup(o, pr)
+			}
+
+			res, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{
+				Items:        []OrderItemInput{{ProductID: "10", Quantity: 2}},
+				PaymentToken: "tok_visa",
+				Customer:     CustomerInput{ID: "cust_1"},
+				RedeemPoints: c.points,
+			})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantStatus, res.Status)
+			require.Equal(t, c.points, res.PointsRedeemed)
+		})
+	}
+}
+
+func TestOrderService_UpdateStatus_Loyalty(t *testing.T) {
+	// 30.00 of coffee and food, 10.00 of it paid with points.
+	placed := repo.Order{
+		ID: "o1", Status: StatusReady, Currency: "AUD",
+		SubtotalCents: 3000, TotalCents: 3000, PointsRedeemed: 1000, PointsCents: 1000,
+		CustomerID: sql.NullString{String: "cust_1", Valid: true},
+	}
+	guest := placed
+	guest.CustomerID = sql.NullString{}
+	guest.PointsRedeemed, guest.PointsCents = 0, 0
+	type tc struct {
+		name        string
+		order       repo.Order
+		status      string
+		wantEntries []repo.LoyaltyEntry
+	}
+	cases := []tc{
+		{
+			// (20.00 coffee x 2 + 10.00 food x 1) x 2/3 paid = 33 points.
+			name:   "completion earns on the paid share",
+			order:  placed,
+			status: StatusCompleted,
+			wantEntries: []repo.LoyaltyEntry{{
+				CustomerID: "cust_1", OrderID: sql.NullString{String: "o1", Valid: true},
+				Kind: repo.LoyaltyEarn, Points: 33, Key: "earn:o1",
+			}},
+		},
+		{
+			name:   "cancellation restores redeemed points",
+			order:  placed,
+			status: StatusCancelled,
+			wantEntries: []repo.LoyaltyEntry{{
+				CustomerID: "cust_1", OrderID: sql.NullString{String: "o1", Valid: true},
+				Kind: repo.LoyaltyRestore, Points: 1000, Key: "restore:o1",
+			}},
+		},
+		{name: "guests earn nothing", order: guest, status: StatusCompleted},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := repomock.NewProductRepository(t)
+			p.On("GetMany", mock.Anything, []string{"10", "11"}).Maybe().
+				Return(map[string]repo.Product{"10": {ID: "10", Category: "coffee"}, "11": {ID: "11", Category: "food"}}, nil)
+			o := repomock.NewOrderRepository(t)
+			o.On("Get", mock.Anything, "o1").Return(c.order, nil)
+			o.On("Items", mock.Anything, "o1").Maybe().Return([]repo.OrderItem{
+				{ProductID: "10", Quantity: 2, UnitPriceCents: 1000},
+				{ProductID: "11", Quantity: 1, UnitPriceCents: 1000},
+			}, nil)
+			ledger := repomock.NewLoyaltyRepository(t)
+			ledger.On("Rules", mock.Anything).Maybe().Return(map[string]int32{"coffee": 2, repo.DefaultLoyaltyCategory: 1}, nil)
+
+			var got []repo.LoyaltyEntry
+			for _, method := range []string{"UpdateStatus", "Reverse"} {
+				o.On(method, mock.Anything, "o1", StatusReady, c.status, sql.NullTime{}, mock.Anything).Maybe().
+					Run(func(args mock.Arguments) {
+						for _, a := range args[5:] {
+							got = append(got, a.(repo.LoyaltyEntry))
+						}
+					}).Return(repo.Order{ID: "o1", Status: c.status}, nil)
+				o.On(method, mock.Anything, "o1", StatusReady, c.status, sql.NullTime{}).Maybe().
+					Return(repo.Order{ID: "o1", Status: c.status}, nil)
+			}
+
+			svc := NewOrderService(p, nil, o)
+			svc.Loyalty = &Loyalty{Ledger: ledger, PointValue: 1}
+			_, err := svc.UpdateStatus(context.Background(), UpdateStatusInput{OrderID: "o1", Status: c.status})
+			require.NoError(t, err)
+			require.Equal(t, c.wantEntries, got)
+		})
+	}
+}
+
+func TestOrderService_Refund_Loyalty(t *testing.T) {
+	order := repo.Order{
+		ID: "o1", Status: StatusCompleted, TotalCents: 2800, PointsRedeemed: 300, PointsCents: 300,
+		CustomerID: sql.NullString{String: "cust_1", Valid: true},
+	}
+	earned := repo.LoyaltyEntry{Kind: repo.LoyaltyEarn, Points: 25}
+	type tc struct {
+		name     string
+		amount   int64
+		refunded int64
+		entries  []repo.LoyaltyEntry
+		setup    func(o *repomock.OrderRepository, ledger *repomock.LoyaltyRepository)
+	}
+	cases := []tc{
+		{
+			name:    "partial refund reverses its share of earned points",
+			amount:  1000,
+			entries: []repo.LoyaltyEntry{earned},
+			setup: func(_ *repomock.OrderRepository, ledger *repomock.LoyaltyRepository) {
+				ledger.On("Post", mock.Anything, mock.MatchedBy(func(e repo.LoyaltyEntry) bool {
+					return e.Kind == repo.LoyaltyReverse && e.Points == -10 && e.Key == "reverse:r1" && e.CustomerID == "cust_1"
+				})).Return(true, nil)
+			},
+		},
+		{
+			name:     "full refund reverses the rest and restores redeemed points",
+			amount:   1500,
+			refunded: 1000,
+			entries:  []repo.LoyaltyEntry{earned, {Kind: repo.LoyaltyReverse, Points: -10}},
+			setup: func(o *repomock.OrderRepository, _ *repomock.LoyaltyRepository) {
+				o.On("Reverse", mock.Anything, "o1", StatusCompleted, StatusRefunded, sql.NullTime{},
+					mock.MatchedBy(func(e repo.LoyaltyEntry) bool { return e.Kind == repo.LoyaltyReverse && e.Points == -15 }),
+					mock.MatchedBy(func(e repo.LoyaltyEntry) bool { return e.Kind == repo.LoyaltyRestore && e.Points == 300 }),
+				).Return(repo.Order{ID: "o1", Status: StatusRefunded}, nil)
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, fake := newPaymentOrderService(t)
+			rr := repomock.NewRefundRepository(t)
+			ledger := repomock.NewLoyaltyRepository(t)
+			svc.Refunds = rr
+			svc.Loyalty = &Loyalty{Ledger: ledger, PointValue: 1}
+
+			ctx := context.Background()
+			auth, err := fake.Authorize(ctx, payments.AuthorizeRequest{AmountCents: 2500, Token: "tok_visa"})
+			require.NoError(t, err)
+			_, err = fake.Capture(ctx, auth.Reference, 2500)
+			require.NoError(t, err)
+			if c.refunded > 0 {
+				_, err = fake.Refund(ctx, auth.Reference, c.refunded)
+				require.NoError(t, err)
+			}
+			ord := order
+			ord.RefundedCents = c.refunded
+			o.On("Get", mock.Anything, "o1").Return(ord, nil)
+			pr.On("GetByOrder", mock.Anything, "o1").Return(repo.Payment{
+				ID: "p1", OrderID: "o1", AmountCents: 2500, CapturedCents: 2500,
+				ProviderRef: sql.NullString{String: auth.Reference, Valid: true},
+			}, nil)
+			rr.On("Create", mock.Anything, mock.Anything, []repo.RefundItem(nil), int64(2500)).Return(nil)
+			rr.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(repo.Refund{ID: "r1", AmountCents: c.amount, Status: "succeeded"}, nil)
+			rr.On("Succeeded", mock.Anything, "o1").Return(c.refunded+c.amount, nil)
+			pr.On("Update", mock.Anything, mock.Anything).Return(repo.Payment{}, nil)
+			ledger.On("OrderEntries", mock.Anything, "o1").Return(c.entries, nil)
+			c.setup(o, ledger)
+
+			_, err = svc.Refund(ctx, RefundInput{OrderID: "o1", AmountCents: c.amount, Reason: "r", Operator: "sam"})
+			require.NoError(t, err)
+		})
+	}
+}
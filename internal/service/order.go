@@ -4,18 +4,111 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"kart/internal/money"
+	"kart/internal/orderstatus"
+	"kart/internal/payments"
 	"kart/internal/repo"
+	"kart/internal/tax"
+	"kart/internal/tracing"
 	"math/bits"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("kart/internal/service")
+
+// Order statuses, in lifecycle order. Orders awaiting payment become placed
+// once it is authorized; payment_failed, completed, cancelled and refunded are
+// terminal. Only a completed order whose captured payment has been refunded in
+// full becomes refunded.
+const (
+	StatusPendingPayment = "pending_payment"
+	StatusPaymentFailed  = "payment_failed"
+	StatusPlaced         = "placed"
+	StatusPreparing      = "preparing"
+	StatusReady          = "ready"
+	StatusCompleted      = "completed"
+	StatusCancelled      = "cancelled"
+	StatusRefunded       = "refunded"
+)
+
+// ErrInvalidStatusTransition indicates the requested status cannot follow the current one.
+var ErrInvalidStatusTransition = newError(KindConflict, "invalid_status_transition", "invalid order status transition")
+
+// Coupon validation failures. Any other error from coupon validation is an
+// infrastructure failure rather than a rejection of the code.
+var (
+	ErrCouponLength     = newError(KindValidation, "coupon_length", "coupon code must be between 8 and 10 characters")
+	ErrCouponNotFound   = newError(KindValidation, "coupon_not_found", "coupon not found")
+	ErrCouponCategories = newError(KindValidation, "coupon_categories", "coupon must apply to at least two categories")
 )
 
+// IsCouponRejection reports whether err means the coupon code itself is unusable.
+func IsCouponRejection(err error) bool {
+	return errors.Is(err, ErrCouponLength) || errors.Is(err, ErrCouponNotFound) || errors.Is(err, ErrCouponCategories)
+}
+
+// nextStatuses lists the statuses reachable from each status. Re-sending the
+// current status is always allowed so the ETA can be revised on its own.
+var nextStatuses = map[string][]string{
+	StatusPlaced:    {StatusPreparing, StatusCancelled},
+	StatusPreparing: {StatusReady, StatusCancelled},
+	StatusReady:     {StatusCompleted, StatusCancelled},
+}
+
+// StatusPublisher receives order status events after they are persisted.
+type StatusPublisher interface {
+	Publish(ev orderstatus.Event)
+}
+
 type OrderService struct {
 	Products repo.ProductRepository
 	Coupons  repo.CouponRepository
 	Orders   repo.OrderRepository
+	// Events is optional; when set, status changes are published to it.
+	Events StatusPublisher
+	// Payments and PaymentRecords are optional; when set, orders are only
+	// confirmed once their payment is authorized.
+	Payments       payments.Provider
+	PaymentRecords repo.PaymentRepository
+	// Refunds is optional; when set, captured payments can be refunded.
+	Refunds repo.RefundRepository
+	// Currency is the ISO 4217 code of base product prices.
+	Currency string
+	// Prices is optional; when set, orders are priced from the store price
+	// list for the requested currency.
+	Prices *PriceLists
+	// Tax is optional; when set, orders are taxed at the store's rates in
+	// TaxMode, rounded per TaxRounding.
+	Tax         repo.TaxRepository
+	TaxMode     tax.Mode
+	TaxRounding tax.Rounding
+	// Loyalty is optional; when set, customers earn points on completed
+	// orders and can redeem them when placing new ones.
+	Loyalty *Loyalty
+	// GiftCards is optional; when set, orders can be paid in part or in full
+	// with gift cards.
+	GiftCards repo.GiftCardRepository
+	// Promotions is optional; when set, the store's running promotions are
+	// applied when pricing orders and carts.
+	Promotions repo.PromotionRepository
+	// Clock decides which promotions are running; give Prices.Schedule the
+	// same one so promotions and happy hours agree on the time.
+	Clock Clock
 }
 
 func NewOrderService(p repo.ProductRepository, c repo.CouponRepository, o repo.OrderRepository) *OrderService {
-	return &OrderService{Products: p, Coupons: c, Orders: o}
+	return &OrderService{Products: p, Coupons: c, Orders: o, Clock: SystemClock{}}
+}
+
+func (s *OrderService) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
 }
 
 type OrderItemInput struct {
@@ -26,36 +119,124 @@ type OrderItemInput struct {
 type PlaceOrderInput struct {
 	CouponCode string
 	Items      []OrderItemInput
+	// PaymentToken identifies the customer's payment method when payments are enabled.
+	PaymentToken string
+	// Currency selects the price list; empty means the store's default.
+	Currency string
+	// Customer is who the order is for; empty for anonymous orders.
+	Customer CustomerInput
+	// RedeemPoints is how many of the customer's loyalty points to spend on
+	// the order.
+	RedeemPoints int64
+	// GiftCards are codes of gift cards to pay with, drawn on in order for
+	// whatever points leave to pay.
+	GiftCards []string
+	// CartID is the cart the order is checked out from, if any. A cart gets
+	// at most one order that has not failed payment.
+	CartID string
 }
 
 type PlaceOrderResult struct {
-	OrderID  string
-	Items    []OrderItemInput
-	Products []repo.Product
+	OrderID    string
+	Status     string
+	TotalCents int64
+	Pricing    Pricing
+	Items      []OrderItemInput
+	Products   []repo.Product
+	Payment    *PaymentResult
+	Customer   CustomerInput
+	// PointsRedeemed were spent on the order, worth PointsCents of TotalCents.
+	PointsRedeemed int64
+	PointsCents    int64
+	// GiftCards paid GiftCardCents of TotalCents.
+	GiftCards     []GiftCardUse
+	GiftCardCents int64
+}
+
+type UpdateStatusInput struct {
+	OrderID string
+	Status  string
+	ETA     *time.Time
 }
 
+// PlaceOrder prices and records an order and takes payment for it. It runs
+// in a span, with child spans for the coupon check, product lookup and the
+// insert transaction.
 func (s *OrderService) PlaceOrder(ctx context.Context, in PlaceOrderInput) (PlaceOrderResult, error) {
-	valid, err := s.validateCoupon(ctx, in.CouponCode)
-	if err != nil || !valid {
-		return PlaceOrderResult{}, err
+	ctx, span := tracer.Start(ctx, "OrderService.PlaceOrder", trace.WithAttributes(attribute.Int("order.items", len(in.Items))))
+	res, err := s.placeOrder(ctx, in)
+	if err == nil {
+		span.SetAttributes(attribute.String("order.id", res.OrderID))
 	}
+	tracing.End(span, err)
+	return res, err
+}
 
-	productsByID, err := s.fetchProductsMap(ctx, in.Items)
+func (s *OrderService) placeOrder(ctx context.Context, in PlaceOrderInput) (PlaceOrderResult, error) {
+	if err := in.Customer.validate(); err != nil {
+		return PlaceOrderResult{}, err
+	}
+	couponCtx, span := tracer.Start(ctx, "validateCoupon")
+	coupon, err := s.coupon(couponCtx, in.CouponCode)
+	tracing.End(span, err)
 	if err != nil {
 		return PlaceOrderResult{}, err
 	}
 
-	items, err := s.buildOrderItems(in.Items)
+	list, productsByID, err := s.listedProducts(ctx, in.Currency, in.Items)
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	pricing, err := s.price(ctx, list.Currency, in.Items, productsByID, coupon.PercentOff)
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	total := pricing.TotalCents
+	pointsCents, err := s.Loyalty.redemption(in.Customer, in.RedeemPoints, total)
+	if err != nil {
+		return PlaceOrderResult{}, err
+	}
+	uses, err := s.planGiftCards(ctx, in.GiftCards, pricing.Currency, total-pointsCents)
 	if err != nil {
 		return PlaceOrderResult{}, err
 	}
+	debits, giftCardCents := giftCardDebits(uses)
+	payable := total - pointsCents - giftCardCents
+
+	needsPayment := s.Payments != nil && payable > 0
+	if needsPayment && in.PaymentToken == "" {
+		return PlaceOrderResult{}, ErrPaymentRequired
+	}
+	status := StatusPlaced
+	if needsPayment {
+		status = StatusPendingPayment
+	}
 
-	coupon := sql.NullString{String: in.CouponCode, Valid: in.CouponCode != ""}
+	order := repo.Order{
+		CouponCode:     sql.NullString{String: in.CouponCode, Valid: in.CouponCode != ""},
+		Status:         status,
+		TotalCents:     total,
+		SubtotalCents:  pricing.SubtotalCents,
+		DiscountCents:  pricing.DiscountCents,
+		TaxCents:       pricing.TaxCents,
+		TaxInclusive:   pricing.TaxInclusive,
+		Currency:       pricing.Currency,
+		PriceListID:    sql.NullString{String: list.ID, Valid: list.ID != ""},
+		PointsRedeemed: in.RedeemPoints,
+		PointsCents:    pointsCents,
+		GiftCardCents:  giftCardCents,
+	}
+	order.CartID = sql.NullString{String: in.CartID, Valid: in.CartID != ""}
+	in.Customer.apply(&order)
+	createCtx, span := tracer.Start(ctx, "CreateWithItems")
 	orderID, err := s.Orders.CreateWithItems(
-		ctx,
-		repo.Order{CouponCode: coupon},
-		items,
+		createCtx,
+		order,
+		buildOrderItems(pricing.Lines),
+		buildOrderTaxes(pricing.Taxes),
+		debits...,
 	)
+	tracing.End(span, err)
 	if err != nil {
 		return PlaceOrderResult{}, err
 	}
@@ -66,11 +247,145 @@ func (s *OrderService) PlaceOrder(ctx context.Context, in PlaceOrderInput) (Plac
 		ps = append(ps, p)
 	}
 
-	return PlaceOrderResult{
-		OrderID:  orderID,
-		Items:    in.Items,
-		Products: ps,
-	}, nil
+	res := PlaceOrderResult{
+		OrderID:        orderID,
+		Status:         status,
+		TotalCents:     total,
+		Pricing:        pricing,
+		Items:          in.Items,
+		Products:       ps,
+		Customer:       in.Customer,
+		PointsRedeemed: in.RedeemPoints,
+		PointsCents:    pointsCents,
+		GiftCards:      uses,
+		GiftCardCents:  giftCardCents,
+	}
+	if needsPayment {
+		pay, st, err := s.authorizePayment(ctx, orderID, money.New(payable, pricing.Currency), in.PaymentToken)
+		res.Payment = &pay
+		res.Status = st
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (s *OrderService) Get(ctx context.Context, id string) (repo.Order, error) {
+	return s.Orders.Get(ctx, id)
+}
+
+// UpdateStatus moves an order to a new status and/or ETA and notifies
+// subscribers. Completing a customer's order awards their loyalty points;
+// cancelling it returns the points it redeemed. The payment is captured or
+// voided only once the order has moved, so a racing update cannot move money
+// for a transition it lost. If that settlement fails the order keeps its new
+// status and repeating the update retries it.
+func (s *OrderService) UpdateStatus(ctx context.Context, in UpdateStatusInput) (repo.Order, error) {
+	cur, err := s.Orders.Get(ctx, in.OrderID)
+	if err != nil {
+		return repo.Order{}, err
+	}
+	if cur.Status == in.Status && isSettledStatus(in.Status) {
+		return s.retrySettlement(ctx, cur)
+	}
+	if !canTransition(cur.Status, in.Status) {
+		return repo.Order{}, ErrInvalidStatusTransition
+	}
+
+	var entries []repo.LoyaltyEntry
+	if in.Status != cur.Status && s.Loyalty != nil {
+		switch in.Status {
+		case StatusCompleted:
+			entries, err = s.Loyalty.earnEntry(ctx, s, cur)
+		case StatusCancelled:
+			entries = restoreEntry(cur)
+		}
+		if err != nil {
+			return repo.Order{}, err
+		}
+	}
+
+	var eta sql.NullTime
+	if in.ETA != nil {
+		eta = sql.NullTime{Time: in.ETA.UTC(), Valid: true}
+	}
+	// The update only applies if nobody has moved the order on since it was
+	// read, so racing updates cannot skip the state machine.
+	// Cancelling credits back gift cards in the same transaction.
+	update := s.Orders.UpdateStatus
+	if in.Status == StatusCancelled && in.Status != cur.Status {
+		update = s.Orders.Reverse
+	}
+	o, err := update(ctx, in.OrderID, cur.Status, in.Status, eta, entries...)
+	if errors.Is(err, repo.ErrStatusChanged) {
+		return repo.Order{}, ErrInvalidStatusTransition
+	}
+	if err != nil {
+		return repo.Order{}, err
+	}
+	s.publish(o)
+	if in.Status != cur.Status {
+		if _, err := s.settlePayment(ctx, o.ID, o.Status); err != nil {
+			return repo.Order{}, err
+		}
+	}
+	return o, nil
+}
+
+// retrySettlement captures or voids the payment of an order already completed
+// or cancelled whose settlement failed. Orders with nothing left to settle
+// cannot be moved to the status they are in.
+func (s *OrderService) retrySettlement(ctx context.Context, o repo.Order) (repo.Order, error) {
+	settled, err := s.settlePayment(ctx, o.ID, o.Status)
+	if err != nil {
+		return repo.Order{}, err
+	}
+	if !settled {
+		return repo.Order{}, ErrInvalidStatusTransition
+	}
+	return o, nil
+}
+
+func (s *OrderService) publish(o repo.Order) {
+	if s.Events != nil {
+		s.Events.Publish(OrderStatusEvent(o))
+	}
+}
+
+// OrderStatusEvent describes the current status of o.
+func OrderStatusEvent(o repo.Order) orderstatus.Event {
+	ev := orderstatus.Event{OrderID: o.ID, Status: o.Status, At: o.UpdatedAt}
+	if o.EtaAt.Valid {
+		eta := o.EtaAt.Time
+		ev.ETA = &eta
+	}
+	return ev
+}
+
+// IsTerminalStatus reports whether no further transitions can follow status.
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case StatusCompleted, StatusCancelled, StatusPaymentFailed, StatusRefunded:
+		return true
+	}
+	return false
+}
+
+func canTransition(from, to string) bool {
+	// Orders awaiting payment only move on through the payment flow.
+	if from == StatusPendingPayment {
+		return false
+	}
+	if from == to {
+		return !IsTerminalStatus(from)
+	}
+	for _, next := range nextStatuses[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *OrderService) fetchProductsMap(ctx context.Context, items []OrderItemInput) (map[string]repo.Product, error) {
@@ -82,38 +397,94 @@ func (s *OrderService) fetchProductsMap(ctx context.Context, items []OrderItemIn
 			ids = append(ids, it.ProductID)
 		}
 	}
-	return s.Products.GetMany(ctx, ids)
+	ctx, span := tracer.Start(ctx, "fetchProductsMap", trace.WithAttributes(attribute.Int("products", len(ids))))
+	products, err := s.Products.GetMany(ctx, ids)
+	tracing.End(span, err)
+	return products, err
+}
+
+// listedProducts resolves the price list for currency and returns the items'
+// products priced from it.
+func (s *OrderService) listedProducts(ctx context.Context, currency string, items []OrderItemInput) (repo.PriceList, map[string]repo.Product, error) {
+	prices := s.priceLists()
+	list, err := prices.Resolve(ctx, currency)
+	if err != nil {
+		return repo.PriceList{}, nil, err
+	}
+	products, err := s.fetchProductsMap(ctx, items)
+	if err != nil {
+		return repo.PriceList{}, nil, err
+	}
+	if err := prices.Apply(ctx, list, products); err != nil {
+		return repo.PriceList{}, nil, err
+	}
+	return list, products, nil
+}
+
+func (s *OrderService) priceLists() *PriceLists {
+	if s.Prices != nil {
+		return s.Prices
+	}
+	return &PriceLists{BaseCurrency: s.currency()}
 }
 
-func (s *OrderService) buildOrderItems(inputs []OrderItemInput) ([]repo.OrderItem, error) {
-	items := make([]repo.OrderItem, len(inputs))
-	for i, in := range inputs {
+func buildOrderItems(lines []PricedLine) []repo.OrderItem {
+	items := make([]repo.OrderItem, len(lines))
+	for i, l := range lines {
 		items[i] = repo.OrderItem{
-			ProductID: in.ProductID,
-			Quantity:  in.Quantity,
+			ProductID:      l.ProductID,
+			Quantity:       l.Quantity,
+			UnitPriceCents: l.UnitPriceCents,
+			TaxClass:       sql.NullString{String: l.TaxClass, Valid: l.TaxClass != ""},
+			TaxCents:       l.TaxCents,
+			DiscountCents:  l.DiscountCents,
+		}
+	}
+	return items
+}
+
+func buildOrderTaxes(classes []tax.ClassTotal) []repo.OrderTax {
+	taxes := make([]repo.OrderTax, len(classes))
+	for i, c := range classes {
+		taxes[i] = repo.OrderTax{
+			TaxClass:        c.Class,
+			RateBasisPoints: c.RateBasisPoints,
+			TaxableCents:    c.TaxableCents,
+			TaxCents:        c.TaxCents,
 		}
 	}
-	return items, nil
+	return taxes
 }
 
-func (s *OrderService) validateCoupon(ctx context.Context, couponCode string) (bool, error) {
+// ValidateCoupon applies the same coupon rules as PlaceOrder without placing an order.
+func (s *OrderService) ValidateCoupon(ctx context.Context, couponCode string) error {
+	_, err := s.coupon(ctx, couponCode)
+	return err
+}
+
+// coupon looks up couponCode and checks it against the coupon rules. An empty
+// code is no coupon and takes nothing off.
+func (s *OrderService) coupon(ctx context.Context, couponCode string) (repo.Coupon, error) {
 	if couponCode == "" {
-		return true, nil
+		return repo.Coupon{}, nil
 	}
 	// Must be a string of length between 8 and 10 characters
 	if len(couponCode) < 8 || len(couponCode) > 10 {
-		return false, errors.New("coupon code must be between 8 and 10 characters")
+		return repo.Coupon{}, ErrCouponLength
 	}
 
 	c, err := s.Coupons.Get(ctx, couponCode)
 	if err != nil {
-		return false, err
+		if errors.Is(err, repo.ErrNotFound) {
+			return repo.Coupon{}, ErrCouponNotFound
+		}
+		return repo.Coupon{}, err
 	}
 
 	// Require coupon to apply to at least 2 categories
 	n := bits.OnesCount8(c.PresenceMask)
 	if n < 2 {
-		return false, errors.New("coupon must apply to at least two categories")
+		return repo.Coupon{}, ErrCouponCategories
 	}
-	return true, nil
+	return c, nil
 }
@@ -2,13 +2,16 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	repomock "kart/internal/mocks/repo"
+	"kart/internal/orderstatus"
 	"kart/internal/repo"
 )
 
@@ -35,7 +38,7 @@ func TestOrderService_PlaceOrder(t *testing.T) {
 			setupMocks: func(p *repomock.ProductRepository, _ *repomock.CouponRepository, o *repomock.OrderRepository) {
 				p.On("GetMany", mock.Anything, []string{"10", "11"}).
 					Return(map[string]repo.Product{"10": {ID: "10"}, "11": {ID: "11"}}, nil)
-				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.MatchedBy(func(items []repo.OrderItem) bool { return len(items) == 2 })).
+				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.MatchedBy(func(items []repo.OrderItem) bool { return len(items) == 2 }), mock.Anything).
 					Return("order-1", nil)
 			},
 			assertGood: func(t *testing.T, res PlaceOrderResult) {
@@ -52,7 +55,7 @@ func TestOrderService_PlaceOrder(t *testing.T) {
 					Return(repo.Coupon{Code: "SAVE20AA", PresenceMask: 3}, nil)
 				p.On("GetMany", mock.Anything, []string{"10", "11"}).
 					Return(map[string]repo.Product{"10": {ID: "10"}, "11": {ID: "11"}}, nil)
-				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything).
+				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return("order-2", nil)
 			},
 			assertGood: func(t *testing.T, res PlaceOrderResult) {
@@ -83,6 +86,41 @@ func TestOrderService_PlaceOrder(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "customer order",
+			in:   PlaceOrderInput{Items: items, Customer: CustomerInput{ID: "cust_1", Phone: "+61412345678"}},
+			setupMocks: func(p *repomock.ProductRepository, _ *repomock.CouponRepository, o *repomock.OrderRepository) {
+				p.On("GetMany", mock.Anything, []string{"10", "11"}).
+					Return(map[string]repo.Product{"10": {ID: "10"}, "11": {ID: "11"}}, nil)
+				o.On("CreateWithItems", mock.Anything, mock.MatchedBy(func(o repo.Order) bool {
+					return o.CustomerID.String == "cust_1" && o.ContactPhone.String == "+61412345678" && !o.ContactEmail.Valid
+				}), mock.Anything, mock.Anything).Return("order-3", nil)
+			},
+			assertGood: func(t *testing.T, res PlaceOrderResult) {
+				require.Equal(t, "cust_1", res.Customer.ID)
+			},
+		},
+		{
+			name: "guest order",
+			in:   PlaceOrderInput{Items: items, Customer: CustomerInput{Email: "jo@example.com"}},
+			setupMocks: func(p *repomock.ProductRepository, _ *repomock.CouponRepository, o *repomock.OrderRepository) {
+				p.On("GetMany", mock.Anything, []string{"10", "11"}).
+					Return(map[string]repo.Product{"10": {ID: "10"}, "11": {ID: "11"}}, nil)
+				o.On("CreateWithItems", mock.Anything, mock.MatchedBy(func(o repo.Order) bool {
+					return !o.CustomerID.Valid && o.ContactEmail.String == "jo@example.com"
+				}), mock.Anything, mock.Anything).Return("order-4", nil)
+			},
+		},
+		{
+			name:    "error invalid guest email",
+			in:      PlaceOrderInput{Items: items, Customer: CustomerInput{Email: "Jo <jo@example.com>"}},
+			wantErr: true,
+		},
+		{
+			name:    "error invalid guest phone",
+			in:      PlaceOrderInput{Items: items, Customer: CustomerInput{Phone: "0412 345 678"}},
+			wantErr: true,
+		},
 		{
 			name: "error products get many",
 			in:   PlaceOrderInput{CouponCode: "", Items: items},
@@ -98,7 +136,7 @@ func TestOrderService_PlaceOrder(t *testing.T) {
 			setupMocks: func(p *repomock.ProductRepository, _ *repomock.CouponRepository, o *repomock.OrderRepository) {
 				p.On("GetMany", mock.Anything, []string{"10", "11"}).
 					Return(map[string]repo.Product{"10": {ID: "10"}, "11": {ID: "11"}}, nil)
-				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything).
+				o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 					Return("", errors.New("bad order"))
 			},
 			wantErr: true,
@@ -129,3 +167,66 @@ func TestOrderService_PlaceOrder(t *testing.T) {
 		})
 	}
 }
+
+type recordingPublisher struct{ events []orderstatus.Event }
+
+func (p *recordingPublisher) Publish(ev orderstatus.Event) { p.events = append(p.events, ev) }
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	eta := time.Date(2025, 10, 1, 12, 30, 0, 0, time.UTC)
+	type tc struct {
+		name       string
+		current    string
+		in         UpdateStatusInput
+		getErr     error
+		updateErr  error
+		wantErr    error
+		wantUpdate bool
+	}
+	cases := []tc{
+		{name: "placed to preparing with eta", current: StatusPlaced, in: UpdateStatusInput{OrderID: "o1", Status: StatusPreparing, ETA: &eta}, wantUpdate: true},
+		{name: "eta revision keeps status", current: StatusPreparing, in: UpdateStatusInput{OrderID: "o1", Status: StatusPreparing, ETA: &eta}, wantUpdate: true},
+		{name: "cancel from ready", current: StatusReady, in: UpdateStatusInput{OrderID: "o1", Status: StatusCancelled}, wantUpdate: true},
+		{name: "skip ahead rejected", current: StatusPlaced, in: UpdateStatusInput{OrderID: "o1", Status: StatusCompleted}, wantErr: ErrInvalidStatusTransition},
+		{name: "terminal is final", current: StatusCompleted, in: UpdateStatusInput{OrderID: "o1", Status: StatusCompleted}, wantErr: ErrInvalidStatusTransition},
+		{name: "order not found", in: UpdateStatusInput{OrderID: "o1", Status: StatusPreparing}, getErr: repo.ErrNotFound, wantErr: repo.ErrNotFound},
+		{name: "moved on since read", current: StatusReady, in: UpdateStatusInput{OrderID: "o1", Status: StatusCancelled}, wantUpdate: true, updateErr: repo.ErrStatusChanged, wantErr: ErrInvalidStatusTransition},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := repomock.NewOrderRepository(t)
+			o.On("Get", mock.Anything, "o1").Return(repo.Order{ID: "o1", Status: c.current}, c.getErr)
+			if c.wantUpdate {
+				method := "UpdateStatus"
+				if c.in.Status == StatusCancelled {
+					method = "Reverse"
+				}
+				o.On(method, mock.Anything, "o1", c.current, c.in.Status, mock.AnythingOfType("sql.NullTime")).
+					Return(func(_ context.Context, id, _, status string, eta sql.NullTime, _ ...repo.LoyaltyEntry) (repo.Order, error) {
+						if c.updateErr != nil {
+							return repo.Order{}, c.updateErr
+						}
+						return repo.Order{ID: id, Status: status, EtaAt: eta}, nil
+					})
+			}
+			pub := &recordingPublisher{}
+			svc := NewOrderService(nil, nil, o)
+			svc.Events = pub
+
+			got, err := svc.UpdateStatus(context.Background(), c.in)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				require.Empty(t, pub.events)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.in.Status, got.Status)
+			require.Len(t, pub.events, 1)
+			require.Equal(t, c.in.Status, pub.events[0].Status)
+			if c.in.ETA != nil {
+				require.NotNil(t, pub.events[0].ETA)
+				require.True(t, c.in.ETA.Equal(*pub.events[0].ETA))
+			}
+		})
+	}
+}
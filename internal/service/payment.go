@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"kart/internal/money"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+var (
+	ErrPaymentRequired    = newError(KindValidation, "payment_required", "payment token is required")
+	ErrPaymentDeclined    = newError(KindValidation, "payment_declined", "payment declined")
+	ErrPaymentUnavailable = newError(KindUnavailable, "payment_unavailable", "payment provider unavailable")
+	ErrPaymentNotPending  = newError(KindConflict, "payment_not_pending", "payment is not awaiting confirmation")
+	// ErrPaymentsNotConfigured is returned by operations on payments when the
+	// server runs without a payment provider.
+	ErrPaymentsNotConfigured = newError(KindUnavailable, "payments_not_configured", "no payment provider is configured")
+)
+
+// PaymentResult summarises the payment attempt for an order.
+type PaymentResult struct {
+	ID            string
+	Status        string
+	AmountCents   int64
+	Currency      string
+	NextActionURL string
+	DeclineCode   string
+}
+
+// authorizePayment records a payment attempt for the order and asks the
+// provider to authorize it. The order is confirmed when the authorization
+// succeeds and failed, releasing its coupon, when it is declined or errors.
+// A pending (challenge) authorization leaves the order awaiting ConfirmPayment.
+func (s *OrderService) authorizePayment(ctx context.Context, orderID string, amount money.Money, token string) (PaymentResult, string, error) {
+	p := repo.Payment{
+		ID:          uuid.NewString(),
+		OrderID:     orderID,
+		Provider:    s.Payments.Name(),
+		Status:      string(payments.StatusPending),
+		AmountCents: amount.Amount,
+		Currency:    amount.Currency,
+	}
+	if err := s.PaymentRecords.Create(ctx, p); err != nil {
+		return PaymentResult{}, "", s.failOrder(ctx, orderID, err)
+	}
+
+	req := payments.AuthorizeRequest{
+		OrderID:        orderID,
+		AmountCents:    p.AmountCents,
+		Currency:       p.Currency,
+		Token:          token,
+		IdempotencyKey: p.ID,
+	}
+	res, err := s.Payments.Authorize(ctx, req)
+	if isAmbiguous(err) {
+		// The provider may have authorized before the error. Repeating the
+		// request under the same idempotency key returns that authorization,
+		// or makes one, so it can be voided before the order is failed.
+		if again, aerr := s.Payments.Authorize(context.WithoutCancel(ctx), req); aerr != nil {
+			slog.ErrorContext(ctx, "reconcile payment authorization", "order", orderID, "payment", p.ID, "err", aerr)
+		} else {
+			s.voidUnsettled(ctx, p, again.Reference)
+		}
+	}
+	return s.applyAuthorization(ctx, p, res, err)
+}
+
+// ConfirmPayment completes an authorization that was pending customer action.
+func (s *OrderService) ConfirmPayment(ctx context.Context, orderID string) (PaymentResult, error) {
+	if s.Payments == nil {
+		return PaymentResult{}, ErrPaymentsNotConfigured
+	}
+	p, err := s.PaymentRecords.GetByOrder(ctx, orderID)
+	if err != nil {
+		return PaymentResult{}, err
+	}
+	if p.Status != string(payments.StatusPending) || !p.ProviderRef.Valid {
+		return PaymentResult{}, ErrPaymentNotPending
+	}
+	res, err := s.Payments.Confirm(ctx, p.ProviderRef.String)
+	if isAmbiguous(err) {
+		s.voidUnsettled(ctx, p, p.ProviderRef.String)
+	}
+	pay, _, err := s.applyAuthorization(ctx, p, res, err)
+	return pay, err
+}
+
+// applyAuthorization persists the provider outcome and moves the order
+// accordingly. It returns the resulting order status.
+func (s *OrderService) applyAuthorization(ctx context.Context, p repo.Payment, res payments.Result, authErr error) (PaymentResult, string, error) {
+	// Compensation must run even if the client has gone away.
+	ctx = context.WithoutCancel(ctx)
+
+	if authErr != nil {
+		p.Status = string(payments.StatusFailed)
+		p.FailureReason = sql.NullString{String: authErr.Error(), Valid: true}
+		if _, err := s.PaymentRecords.Update(ctx, p); err != nil {
+			slog.ErrorContext(ctx, "record failed payment", "order", p.OrderID, "err", err)
+		}
+		return toPaymentResult(p, res), StatusPaymentFailed,
+			s.failOrder(ctx, p.OrderID, fmt.Errorf("%w: %v", ErrPaymentUnavailable, authErr))
+	}
+
+	p.Status = string(res.Status)
+	p.ProviderRef = sql.NullString{String: res.Reference, Valid: res.Reference != ""}
+	if res.DeclineCode != "" {
+		p.FailureReason = sql.NullString{String: res.DeclineCode, Valid: true}
+	}
+	p, err := s.PaymentRecords.Update(ctx, p)
+	if err != nil {
+		return PaymentResult{}, "", err
+	}
+	pay := toPaymentResult(p, res)
+
+	switch res.Status {
+	case payments.StatusAuthorized:
+		o, err := s.Orders.UpdateStatus(ctx, p.OrderID, StatusPendingPayment, StatusPlaced, sql.NullTime{})
+		if errors.Is(err, repo.ErrStatusChanged) {
+			return pay, "", ErrInvalidStatusTransition
+		}
+		if err != nil {
+			return pay, "", err
+		}
+		s.publish(o)
+		return pay, StatusPlaced, nil
+	case payments.StatusPending:
+		return pay, StatusPendingPayment, nil
+	default:
+		return pay, StatusPaymentFailed,
+			s.failOrder(ctx, p.OrderID, withDetail(ErrPaymentDeclined, "%s", res.DeclineCode))
+	}
+}
+
+// isAmbiguous reports whether a provider error leaves the outcome of the
+// request unknown, as a timeout does, rather than refusing it.
+func isAmbiguous(err error) bool {
+	return err != nil &&
+		!errors.Is(err, payments.ErrInvalidState) &&
+		!errors.Is(err, payments.ErrUnknownReference) &&
+		!errors.Is(err, payments.ErrAmountExceeded)
+}
+
+// voidUnsettled releases whatever the provider holds under ref for p, so a
+// payment whose outcome was unknown cannot be collected for a failed order.
+// Failures are logged with the payment ID for manual reconciliation.
+func (s *OrderService) voidUnsettled(ctx context.Context, p repo.Payment, ref string) {
+	if ref == "" {
+		return
+	}
+	_, err := s.Payments.Void(context.WithoutCancel(ctx), ref)
+	if err != nil && !errors.Is(err, payments.ErrInvalidState) {
+		slog.ErrorContext(ctx, "void payment after provider error", "order", p.OrderID, "payment", p.ID, "ref", ref, "err", err)
+	}
+}
+
+// settlePayment captures the authorized payment when an order completes and
+// voids it when the order is cancelled, reporting whether there was one to
+// settle. Orders without a payment are left alone.
+func (s *OrderService) settlePayment(ctx context.Context, orderID, status string) (bool, error) {
+	if s.Payments == nil || !isSettledStatus(status) {
+		return false, nil
+	}
+	p, err := s.PaymentRecords.GetByOrder(ctx, orderID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if p.Status != string(payments.StatusAuthorized) {
+		return false, nil
+	}
+
+	var res payments.Result
+	if status == StatusCompleted {
+		res, err = s.Payments.Capture(ctx, p.ProviderRef.String, p.AmountCents)
+		p.CapturedCents = p.AmountCents
+	} else {
+		res, err = s.Payments.Void(ctx, p.ProviderRef.String)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "settle payment", "order", orderID, "payment", p.ID, "status", status, "err", err)
+		return false, withDetail(ErrPaymentUnavailable, "order is %s but its payment was not settled; repeat the update to retry", status)
+	}
+	p.Status = string(res.Status)
+	if _, err = s.PaymentRecords.Update(ctx, p); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isSettledStatus reports whether an order in status has its payment
+// captured or voided.
+func isSettledStatus(status string) bool {
+	return status == StatusCompleted || status == StatusCancelled
+}
+
+// failOrder marks the order as payment_failed, releasing its coupon, and returns cause.
+func (s *OrderService) failOrder(ctx context.Context, orderID string, cause error) error {
+	if err := s.Orders.FailPayment(context.WithoutCancel(ctx), orderID, StatusPendingPayment, StatusPaymentFailed); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+func (s *OrderService) currency() string {
+	if s.Currency == "" {
+		return "AUD"
+	}
+	return s.Currency
+}
+
+func toPaymentResult(p repo.Payment, res payments.Result) PaymentResult {
+	return PaymentResult{
+		ID:            p.ID,
+		Status:        p.Status,
+		AmountCents:   p.AmountCents,
+		Currency:      p.Currency,
+		NextActionURL: res.NextActionURL,
+		DeclineCode:   res.DeclineCode,
+	}
+}
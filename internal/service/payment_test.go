@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+func newPaymentOrderService(t *testing.T) (*OrderService, *repomock.OrderRepository, *repomock.PaymentRepository, *payments.Fake) {
+	p := repomock.NewProductRepository(t)
+	p.On("GetMany", mock.Anything, []string{"10"}).Maybe().
+		Return(map[string]repo.Product{"10": {ID: "10", PriceCents: 1299}}, nil)
+	o := repomock.NewOrderRepository(t)
+	pr := repomock.NewPaymentRepository(t)
+	fake := payments.NewFake()
+	svc := NewOrderService(p, repomock.NewCouponRepository(t), o)
+	svc.Payments = fake
+	svc.PaymentRecords = pr
+	return svc, o, pr, fake
+}
+
+// echoPaymentUpdates makes PaymentRepository.Update return what it was given.
+func echoPaymentUpdates(pr *repomock.PaymentRepository) {
+	pr.On("Update", mock.Anything, mock.Anything).Return(func(_ context.Context, p repo.Payment) (repo.Payment, error) {
+		return p, nil
+	})
+}
+
+func TestOrderService_PlaceOrder_Payment(t *testing.T) {
+	type tc struct {
+		name       string
+		token      string
+		setup      func(o *repomock.OrderRepository, pr *repomock.PaymentRepository)
+		wantErr    error
+		wantStatus string
+		wantPay    payments.Status
+	}
+	cases := []tc{
+		{
+			name:  "authorized confirms order",
+			token: "tok_visa",
+			setup: func(o *repomock.OrderRepository, pr *repomock.PaymentRepository) {
+				pr.On("Create", mock.Anything, mock.MatchedBy(func(p repo.Payment) bool {
+					return p.OrderID == "o1" && p.AmountCents == 2598 && p.Currency == "AUD"
+				})).Return(nil)
+				echoPaymentUpdates(pr)
+				o.On("UpdateStatus", mock.Anything, "o1", StatusPendingPayment, StatusPlaced, sql.NullTime{}).
+					Return(repo.Order{ID: "o1", Status: StatusPlaced}, nil)
+			},
+			wantStatus: StatusPlaced,
+			wantPay:    payments.StatusAuthorized,
+		},
+		{
+			name:  "3ds leaves order pending",
+			token: payments.FakeToken3DS,
+			setup: func(o *repomock.OrderRepository, pr *repomock.PaymentRepository) {
+				pr.On("Create", mock.Anything, mock.Anything).Return(nil)
+				echoPaymentUpdates(pr)
+			},
+			wantStatus: StatusPendingPayment,
+			wantPay:    payments.StatusPending,
+		},
+		{
+			name:  "declined fails order and releases coupon",
+			token: payments.FakeTokenDecline,
+			setup: func(o *repomock.OrderRepository, pr *repomock.PaymentRepository) {
+				pr.On("Create", mock.Anything, mock.Anything).Return(nil)
+				echoPaymentUpdates(pr)
+				o.On("FailPayment", mock.Anything, "o1", StatusPendingPayment, StatusPaymentFailed).Return(nil)
+			},
+			wantErr:    ErrPaymentDeclined,
+			wantStatus: StatusPaymentFailed,
+			wantPay:    payments.StatusDeclined,
+		},
+		{
+			name:  "timeout fails order",
+			token: payments.FakeTokenTimeout,
+			setup: func(o *repomock.OrderRepository, pr *repomock.PaymentRepository) {
+				pr.On("Create", mock.Anything, mock.Anything).Return(nil)
+				echoPaymentUpdates(pr)
+				o.On("FailPayment", mock.Anything, "o1", StatusPendingPayment, StatusPaymentFailed).Return(nil)
+			},
+			wantErr:    ErrPaymentUnavailable,
+			wantStatus: StatusPaymentFailed,
+			wantPay:    payments.StatusFailed,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, _ := newPaymentOrderService(t)
+			o.On("CreateWithItems", mock.Anything, mock.MatchedBy(func(ord repo.Order) bool {
+				return ord.Status == StatusPendingPayment && ord.TotalCents == 2598
+			}), mock.Anything, mock.Anything).Return("o1", nil)
+			c.setup(o, pr)
+
+			res, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{
+				Items:        []OrderItemInput{{ProductID: "10", Quantity: 2}},
+				PaymentToken: c.token,
+			})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, c.wantStatus, res.Status)
+			require.NotNil(t, res.Payment)
+			require.Equal(t, string(c.wantPay), res.Payment.Status)
+		})
+	}
+}
+
+func TestOrderService_PlaceOrder_PaymentRequired(t *testing.T) {
+	svc, _, _, _ := newPaymentOrderService(t)
+	_, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{Items: []OrderItemInput{{ProductID: "10", Quantity: 1}}})
+	require.ErrorIs(t, err, ErrPaymentRequired)
+}
+
+func TestOrderService_ConfirmPayment(t *testing.T) {
+	svc, o, pr, fake := newPaymentOrderService(t)
+	auth, err := fake.Authorize(context.Background(), payments.AuthorizeRequest{AmountCents: 1000, Token: payments.FakeToken3DS})
+	require.NoError(t, err)
+
+	pending := repo.Payment{
+		ID:          "p1",
+		OrderID:     "o1",
+		Status:      string(payments.StatusPending),
+		ProviderRef: sql.NullString{String: auth.Reference, Valid: true},
+		AmountCents: 1000,
+	}
+	pr.On("GetByOrder", mock.Anything, "o1").Return(pending, nil).Once()
+	echoPaymentUpdates(pr)
+	o.On("UpdateStatus", mock.Anything, "o1", StatusPendingPayment, StatusPlaced, sql.NullTime{}).Return(repo.Order{ID: "o1", Status: StatusPlaced}, nil)
+
+	pay, err := svc.ConfirmPayment(context.Background(), "o1")
+	require.NoError(t, err)
+	require.Equal(t, string(payments.StatusAuthorized), pay.Status)
+
+	pr.On("GetByOrder", mock.Anything, "o1").Return(repo.Payment{ID: "p1", Status: string(payments.StatusAuthorized)}, nil).Once()
+	_, err = svc.ConfirmPayment(context.Background(), "o1")
+	require.ErrorIs(t, err, ErrPaymentNotPending)
+}
+
+func TestOrderService_WithoutPaymentProvider(t *testing.T) {
+	svc, _, _, _ := newPaymentOrderService(t)
+	svc.Payments = nil
+
+	_, err := svc.ConfirmPayment(context.Background(), "o1")
+	require.ErrorIs(t, err, ErrPaymentsNotConfigured)
+	_, err = svc.Refund(context.Background(), RefundInput{OrderID: "o1", AmountCents: 500, Reason: "cold", Operator: "sam"})
+	require.ErrorIs(t, err, ErrPaymentsNotConfigured)
+}
+
+func TestOrderService_UpdateStatus_SettlesPayment(t *testing.T) {
+	type tc struct {
+		name       string
+		from, to   string
+		unknownRef bool
+		updateErr  error
+		settled    bool
+		wantErr    error
+		wantStatus payments.Status
+	}
+	cases := []tc{
+		{name: "complete captures", from: StatusReady, to: StatusCompleted, wantStatus: payments.StatusCaptured},
+		{name: "cancel voids", from: StatusPlaced, to: StatusCancelled, wantStatus: payments.StatusVoided},
+		{name: "lost race leaves payment alone", from: StatusPlaced, to: StatusCancelled, updateErr: repo.ErrStatusChanged, wantErr: ErrInvalidStatusTransition},
+		{name: "failed capture keeps new status", from: StatusReady, to: StatusCompleted, unknownRef: true, wantErr: ErrPaymentUnavailable},
+		{name: "repeat retries settlement", from: StatusCompleted, to: StatusCompleted, wantStatus: payments.StatusCaptured},
+		{name: "repeat with nothing to settle", from: StatusCancelled, to: StatusCancelled, settled: true, wantErr: ErrInvalidStatusTransition},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, fake := newPaymentOrderService(t)
+			auth, err := fake.Authorize(context.Background(), payments.AuthorizeRequest{AmountCents: 1000, Token: "tok_visa"})
+			require.NoError(t, err)
+
+			pay := repo.Payment{
+				ID:          "p1",
+				OrderID:     "o1",
+				Status:      string(payments.StatusAuthorized),
+				ProviderRef: sql.NullString{String: auth.Reference, Valid: true},
+				AmountCents: 1000,
+			}
+			if c.unknownRef {
+				pay.ProviderRef.String = "fake_missing"
+			}
+			if c.settled {
+				pay.Status = string(payments.StatusVoided)
+			}
+			o.On("Get", mock.Anything, "o1").Return(repo.Order{ID: "o1", Status: c.from}, nil)
+			if c.updateErr == nil {
+				pr.On("GetByOrder", mock.Anything, "o1").Return(pay, nil)
+			}
+			if c.wantStatus != "" {
+				pr.On("Update", mock.Anything, mock.MatchedBy(func(p repo.Payment) bool {
+					return p.Status == string(c.wantStatus)
+				})).Return(repo.Payment{}, nil)
+			}
+			if c.from != c.to {
+				method := "UpdateStatus"
+				if c.to == StatusCancelled {
+					method = "Reverse"
+				}
+				o.On(method, mock.Anything, "o1", c.from, c.to, sql.NullTime{}).Return(repo.Order{ID: "o1", Status: c.to}, c.updateErr)
+			}
+
+			got, err := svc.UpdateStatus(context.Background(), UpdateStatusInput{OrderID: "o1", Status: c.to})
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.to, got.Status)
+		})
+	}
+}
+
+// lostReplyProvider authorizes through Fake but reports the first
+// authorization as timed out, as if its reply had been lost.
+type lostReplyProvider struct {
+	*payments.Fake
+	ref string
+}
+
+func (l *lostReplyProvider) Authorize(ctx context.Context, req payments.AuthorizeRequest) (payments.Result, error) {
+	res, err := l.Fake.Authorize(ctx, req)
+	if err == nil && l.ref == "" {
+		l.ref = res.Reference
+		return payments.Result{}, payments.ErrTimeout
+	}
+	return res, err
+}
+
+func TestOrderService_PlaceOrder_TimeoutVoidsAuthorization(t *testing.T) {
+	svc, o, pr, fake := newPaymentOrderService(t)
+	lost := &lostReplyProvider{Fake: fake}
+	svc.Payments = lost
+	o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("o1", nil)
+	pr.On("Create", mock.Anything, mock.Anything).Return(nil)
+	echoPaymentUpdates(pr)
+	o.On("FailPayment", mock.Anything, "o1", StatusPendingPayment, StatusPaymentFailed).Return(nil)
+
+	_, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{
+		Items:        []OrderItemInput{{ProductID: "10", Quantity: 1}},
+		PaymentToken: "tok_visa",
+	})
+	require.ErrorIs(t, err, ErrPaymentUnavailable)
+	require.NotEmpty(t, lost.ref)
+	_, err = fake.Capture(context.Background(), lost.ref, 1299)
+	require.ErrorIs(t, err, payments.ErrInvalidState, "the lost authorization must have been voided")
+}
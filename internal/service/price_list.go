@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"kart/internal/repo"
+)
+
+var (
+	ErrCurrencyUnavailable = newError(KindValidation, "currency_unavailable", "no price list for currency")
+	ErrCartCurrency        = newError(KindValidation, "cart_currency", "cart is priced in a different currency")
+)
+
+// PriceLists selects the request store's price list and prices products from it.
+// Without Lists every product sells at its base price in BaseCurrency.
+type PriceLists struct {
+	Lists        repo.PriceListRepository
+	BaseCurrency string
+	// Schedule is optional; its running adjustments come off list prices.
+	Schedule *PriceSchedule
+}
+
+// Resolve returns the price list for currency, or the store's default price
+// list when currency is empty.
+func (p *PriceLists) Resolve(ctx context.Context, currency string) (repo.PriceList, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if p.Lists == nil {
+		base := p.baseCurrency()
+		if currency != "" && currency != base {
+			return repo.PriceList{}, withDetail(ErrCurrencyUnavailable, "%s", currency)
+		}
+		return repo.PriceList{Currency: base, UsesBasePrices: true}, nil
+	}
+	l, err := p.Lists.Get(ctx, currency)
+	if errors.Is(err, repo.ErrNotFound) {
+		return repo.PriceList{}, withDetail(ErrCurrencyUnavailable, "%s", currency)
+	}
+	return l, err
+}
+
+// Apply reprices products from list, less any scheduled adjustment running
+// now. Products the list does not sell are removed, so callers treat them as
+// missing.
+func (p *PriceLists) Apply(ctx context.Context, list repo.PriceList, products map[string]repo.Product) error {
+	if err := p.applyList(ctx, list, products); err != nil {
+		return err
+	}
+	running, err := p.Schedule.Running(ctx)
+	if err != nil {
+		return err
+	}
+	running.ApplyAll(products)
+	return nil
+}
+
+func (p *PriceLists) applyList(ctx context.Context, list repo.PriceList, products map[string]repo.Product) error {
+	if p.Lists == nil || list.ID == "" {
+		return nil
+	}
+	ids := make([]string, 0, len(products))
+	for id := range products {
+		ids = append(ids, id)
+	}
+	prices, err := p.Lists.Prices(ctx, list.ID, ids)
+	if err != nil {
+		return err
+	}
+	for id, prod := range products {
+		if prod, ok := reprice(prod, list, prices); ok {
+			products[id] = prod
+		} else {
+			delete(products, id)
+		}
+	}
+	return nil
+}
+
+// applyListAll reprices a whole catalog listing from list, preserving its
+// order. Scheduled adjustments are left to the caller.
+func (p *PriceLists) applyListAll(ctx context.Context, list repo.PriceList, products []repo.Product) ([]repo.Product, error) {
+	if p.Lists == nil || list.ID == "" {
+		return products, nil
+	}
+	prices, err := p.Lists.Prices(ctx, list.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]repo.Product, 0, len(products))
+	for _, prod := range products {
+		if prod, ok := reprice(prod, list, prices); ok {
+			out = append(out, prod)
+		}
+	}
+	return out, nil
+}
+
+// reprice returns prod at its price in list, and false if list does not sell it.
+func reprice(prod repo.Product, list repo.PriceList, prices map[string]int32) (repo.Product, bool) {
+	if price, ok := prices[prod.ID]; ok {
+		prod.PriceCents = price
+		return prod, true
+	}
+	return prod, list.UsesBasePrices
+}
+
+func (p *PriceLists) baseCurrency() string {
+	if p.BaseCurrency == "" {
+		return "AUD"
+	}
+	return p.BaseCurrency
+}
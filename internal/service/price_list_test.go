@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/money"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+var (
+	audList = repo.PriceList{ID: "default-aud", StoreID: "default", Currency: "AUD", IsDefault: true, UsesBasePrices: true}
+	nzdList = repo.PriceList{ID: "default-nzd", StoreID: "default", Currency: "NZD"}
+)
+
+func newTestPriceLists(t *testing.T) (*PriceLists, *repomock.PriceListRepository) {
+	l := repomock.NewPriceListRepository(t)
+	l.On("Get", mock.Anything, "").Maybe().Return(audList, nil)
+	l.On("Get", mock.Anything, "AUD").Maybe().Return(audList, nil)
+	l.On("Get", mock.Anything, "NZD").Maybe().Return(nzdList, nil)
+	l.On("Get", mock.Anything, "USD").Maybe().Return(repo.PriceList{}, repo.ErrNotFound)
+	return &PriceLists{Lists: l}, l
+}
+
+func TestPriceLists_Resolve(t *testing.T) {
+	type tc struct {
+		name     string
+		lists    bool
+		currency string
+		wantID   string
+		wantCur  string
+		wantErr  error
+	}
+	cases := []tc{
+		{name: "store default", lists: true, wantID: "default-aud", wantCur: "AUD"},
+		{name: "requested currency is case insensitive", lists: true, currency: "nzd", wantID: "default-nzd", wantCur: "NZD"},
+		{name: "no list for currency", lists: true, currency: "USD", wantErr: ErrCurrencyUnavailable},
+		{name: "base prices without lists", wantCur: "AUD"},
+		{name: "other currency without lists", currency: "NZD", wantErr: ErrCurrencyUnavailable},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := &PriceLists{}
+			if c.lists {
+				p, _ = newTestPriceLists(t)
+			}
+			got, err := p.Resolve(context.Background(), c.currency)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantID, got.ID)
+			require.Equal(t, c.wantCur, got.Currency)
+		})
+	}
+}
+
+func TestProductService_List_PriceList(t *testing.T) {
+	products := []repo.Product{{ID: "10", PriceCents: 1299}, {ID: "12", PriceCents: 499}}
+	type tc struct {
+		name     string
+		currency string
+		prices   map[string]int32
+		want     []PricedProduct
+	}
+	cases := []tc{
+		{
+			// The AUD list uses base prices for anything it does not override.
+			name:   "default list overrides and falls back",
+			prices: map[string]int32{"12": 450},
+			want: []PricedProduct{
+				{Product: repo.Product{ID: "10", PriceCents: 1299}, Currency: "AUD"},
+				{Product: repo.Product{ID: "12", PriceCents: 450}, Currency: "AUD"},
+			},
+		},
+		{
+			name:     "other currency only sells listed products",
+			currency: "NZD",
+			prices:   map[string]int32{"10": 1416},
+			want:     []PricedProduct{{Product: repo.Product{ID: "10", PriceCents: 1416}, Currency: "NZD"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pl, l := newTestPriceLists(t)
+			l.On("Prices", mock.Anything, mock.Anything, []string(nil)).Return(c.prices, nil)
+			p := repomock.NewProductRepository(t)
+			p.On("List", mock.Anything).Return(products, nil)
+			svc := NewProductService(p)
+			svc.Prices = pl
+
+			got, err := svc.List(context.Background(), c.currency)
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestProductService_Get_NotInPriceList(t *testing.T) {
+	pl, l := newTestPriceLists(t)
+	l.On("Prices", mock.Anything, "default-nzd", []string{"12"}).Return(map[string]int32{}, nil)
+	p := repomock.NewProductRepository(t)
+	p.On("Get", mock.Anything, "12").Return(repo.Product{ID: "12", PriceCents: 499}, nil)
+	svc := NewProductService(p)
+	svc.Prices = pl
+
+	_, err := svc.Get(context.Background(), "12", "NZD")
+	require.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestOrderService_PlaceOrder_Currency(t *testing.T) {
+	pl, l := newTestPriceLists(t)
+	l.On("Prices", mock.Anything, "default-nzd", []string{"10"}).Return(map[string]int32{"10": 1416}, nil)
+	p := repomock.NewProductRepository(t)
+	p.On("GetMany", mock.Anything, []string{"10"}).Return(map[string]repo.Product{"10": {ID: "10", PriceCents: 1299}}, nil)
+	o := repomock.NewOrderRepository(t)
+	o.On("CreateWithItems", mock.Anything, mock.MatchedBy(func(ord repo.Order) bool {
+		return ord.Currency == "NZD" && ord.PriceListID.String == "default-nzd" && ord.TotalCents == 2832
+	}), mock.Anything, mock.Anything).Return("o1", nil)
+	pr := repomock.NewPaymentRepository(t)
+	pr.On("Create", mock.Anything, mock.MatchedBy(func(p repo.Payment) bool {
+		return p.Currency == "NZD" && p.AmountCents == 2832
+	})).Return(nil)
+	echoPaymentUpdates(pr)
+	o.On("UpdateStatus", mock.Anything, "o1", StatusPendingPayment, StatusPlaced, sql.NullTime{}).Return(repo.Order{ID: "o1", Status: StatusPlaced}, nil)
+
+	svc := NewOrderService(p, repomock.NewCouponRepository(t), o)
+	svc.Prices = pl
+	svc.Payments = payments.NewFake()
+	svc.PaymentRecords = pr
+
+	res, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{
+		Items:        []OrderItemInput{{ProductID: "10", Quantity: 2}},
+		PaymentToken: "tok_visa",
+		Currency:     "NZD",
+	})
+	require.NoError(t, err)
+	require.Equal(t, money.New(2832, "NZD"), res.Pricing.Total())
+	require.Equal(t, int64(1416), res.Pricing.Lines[0].UnitPriceCents)
+}
+
+func TestCartService_AddItem_NotInPriceList(t *testing.T) {
+	pl, l := newTestPriceLists(t)
+	l.On("Prices", mock.Anything, "default-nzd", []string{"12"}).Return(map[string]int32{}, nil)
+	svc, carts, products := newTestCartService(t, &fakeOrderPlacer{})
+	svc.Prices = pl
+	carts.On("Get", mock.Anything, "c1").Return(repo.Cart{ID: "c1", Currency: "NZD", ExpiresAt: cartNow.Add(1)}, nil)
+	products.On("Get", mock.Anything, "12").Return(repo.Product{ID: "12", PriceCents: 499}, nil)
+
+	_, err := svc.AddItem(context.Background(), "c1", "12", 1, "")
+	require.ErrorIs(t, err, ErrProductNotFound)
+}
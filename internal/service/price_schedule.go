@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kart/internal/repo"
+)
+
+// PriceSchedule takes scheduled adjustments, such as happy hours, off product
+// prices while their daily window is open in the store's time zone.
+type PriceSchedule struct {
+	Adjustments repo.PriceAdjustmentRepository
+	Clock       Clock
+}
+
+func NewPriceSchedule(a repo.PriceAdjustmentRepository) *PriceSchedule {
+	return &PriceSchedule{Adjustments: a, Clock: SystemClock{}}
+}
+
+// PriceAdjustment is a scheduled adjustment applied to a price, running until
+// EndsAt.
+type PriceAdjustment struct {
+	ID         string
+	Name       string
+	PercentOff int32
+	EndsAt     time.Time
+}
+
+// RunningAdjustments are the adjustments open at one moment, by the product
+// or category they cover.
+type RunningAdjustments struct {
+	byProduct  map[string]PriceAdjustment
+	byCategory map[string]PriceAdjustment
+}
+
+// Running returns the adjustments open now. A nil schedule has none.
+func (s *PriceSchedule) Running(ctx context.Context) (RunningAdjustments, error) {
+	var out RunningAdjustments
+	if s == nil || s.Adjustments == nil {
+		return out, nil
+	}
+	now := s.now()
+	rows, err := s.Adjustments.Scheduled(ctx, now)
+	if err != nil {
+		return out, err
+	}
+	locs := map[string]*time.Location{}
+	for _, a := range rows {
+		loc, ok := locs[a.Timezone]
+		if !ok {
+			if loc, err = time.LoadLocation(a.Timezone); err != nil {
+				return RunningAdjustments{}, fmt.Errorf("store time zone %q: %w", a.Timezone, err)
+			}
+			locs[a.Timezone] = loc
+		}
+		endsAt, open := window(a, now.In(loc))
+		if !open {
+			continue
+		}
+		adj := PriceAdjustment{ID: a.ID, Name: a.Name, PercentOff: a.PercentOff, EndsAt: endsAt}
+		switch {
+		case a.ProductID.Valid:
+			out.byProduct = keepLargest(out.byProduct, a.ProductID.String, adj)
+		case a.Category.Valid:
+			out.byCategory = keepLargest(out.byCategory, a.Category.String, adj)
+		}
+	}
+	return out, nil
+}
+
+// Apply returns p at its adjusted price and the adjustment taken off it, or
+// p unchanged and nil. An adjustment for the product wins over one for its
+// category.
+func (r RunningAdjustments) Apply(p repo.Product) (repo.Product, *PriceAdjustment) {
+	adj, ok := r.byProduct[p.ID]
+	if !ok {
+		if adj, ok = r.byCategory[p.Category]; !ok {
+			return p, nil
+		}
+	}
+	p.PriceCents = int32((int64(p.PriceCents)*int64(100-adj.PercentOff) + 50) / 100)
+	return p, &adj
+}
+
+// ApplyAll adjusts every product in place.
+func (r RunningAdjustments) ApplyAll(products map[string]repo.Product) {
+	if len(r.byProduct) == 0 && len(r.byCategory) == 0 {
+		return
+	}
+	for id, p := range products {
+		products[id], _ = r.Apply(p)
+	}
+}
+
+func (s *PriceSchedule) now() time.Time {
+	if s.Clock == nil {
+		return time.Now()
+	}
+	return s.Clock.Now()
+}
+
+// window reports whether a's daily window is open at local, a time in the
+// store's zone, and when it closes. Windows ending at or before their start
+// run past midnight and count as the day they start on.
+func window(a repo.PriceAdjustment, local time.Time) (time.Time, bool) {
+	start, end := secondOfDay(a.LocalStart), secondOfDay(a.LocalEnd)
+	now := secondOfDay(local)
+	day := local
+	switch {
+	case start < end:
+		if now < start || now >= end {
+			return time.Time{}, false
+		}
+	case now >= start:
+		// Opened today and closes tomorrow.
+	case now < end:
+		day = local.AddDate(0, 0, -1)
+	default:
+		return time.Time{}, false
+	}
+	if a.Weekdays&(1<<day.Weekday()) == 0 {
+		return time.Time{}, false
+	}
+	closeDay := day
+	if end <= start {
+		closeDay = day.AddDate(0, 0, 1)
+	}
+	y, m, d := closeDay.Date()
+	return time.Date(y, m, d, a.LocalEnd.Hour(), a.LocalEnd.Minute(), a.LocalEnd.Second(), 0, local.Location()), true
+}
+
+func secondOfDay(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+// keepLargest records adj under key unless a larger discount already is.
+// Adjustments arrive ordered by ID, so ties go to the first.
+func keepLargest(m map[string]PriceAdjustment, key string, adj PriceAdjustment) map[string]PriceAdjustment {
+	if m == nil {
+		m = map[string]PriceAdjustment{}
+	}
+	if cur, ok := m[key]; !ok || adj.PercentOff > cur.PercentOff {
+		m[key] = adj
+	}
+	return m
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/repo"
+)
+
+func timeOfDay(h, m int) time.Time { return time.Date(0, 1, 1, h, m, 0, 0, time.UTC) }
+
+func happyHour(id, category string, pct int32, weekdays int16, start, end time.Time) repo.PriceAdjustment {
+	return repo.PriceAdjustment{
+		ID:         id,
+		Name:       id,
+		Category:   sql.NullString{String: category, Valid: true},
+		PercentOff: pct,
+		Weekdays:   weekdays,
+		LocalStart: start,
+		LocalEnd:   end,
+		Timezone:   "Australia/Sydney",
+	}
+}
+
+func TestPriceSchedule_Running(t *testing.T) {
+	// Sydney moves from UTC+10 to UTC+11 on Sunday 5 October 2025.
+	afternoon := happyHour("coffee", "Beverage", 30, 127, timeOfDay(15, 0), timeOfDay(17, 0))
+	// Friday nights only, running into Saturday morning.
+	lateNight := happyHour("late", "Beverage", 20, 1<<time.Friday, timeOfDay(22, 0), timeOfDay(2, 0))
+	latte := repo.Product{ID: "12", Category: "Beverage", PriceCents: 495}
+
+	type tc struct {
+		name       string
+		adjustment repo.PriceAdjustment
+		now        time.Time
+		wantPrice  int32
+		wantEndsAt time.Time
+	}
+	cases := []tc{
+		{
+			name:       "inside the window before daylight saving",
+			adjustment: afternoon,
+			now:        time.Date(2025, 10, 3, 6, 30, 0, 0, time.UTC), // 16:30 AEST
+			wantPrice:  347,
+			wantEndsAt: time.Date(2025, 10, 3, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "same instant is after the window in daylight saving",
+			adjustment: afternoon,
+			now:        time.Date(2025, 10, 6, 6, 30, 0, 0, time.UTC), // 17:30 AEDT
+			wantPrice:  495,
+		},
+		{
+			name:       "inside the window in daylight saving",
+			adjustment: afternoon,
+			now:        time.Date(2025, 10, 6, 5, 30, 0, 0, time.UTC), // 16:30 AEDT
+			wantPrice:  347,
+			wantEndsAt: time.Date(2025, 10, 6, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "wrapping window on its start day",
+			adjustment: lateNight,
+			now:        time.Date(2025, 10, 10, 12, 0, 0, 0, time.UTC), // Fri 23:00 AEDT
+			wantPrice:  396,
+			wantEndsAt: time.Date(2025, 10, 10, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "wrapping window after midnight",
+			adjustment: lateNight,
+			now:        time.Date(2025, 10, 10, 14, 0, 0, 0, time.UTC), // Sat 01:00 AEDT
+			wantPrice:  396,
+			wantEndsAt: time.Date(2025, 10, 10, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "wrapping window started on an excluded day",
+			adjustment: lateNight,
+			now:        time.Date(2025, 10, 11, 14, 0, 0, 0, time.UTC), // Sun 01:00 AEDT
+			wantPrice:  495,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := repomock.NewPriceAdjustmentRepository(t)
+			a.On("Scheduled", mock.Anything, c.now).Return([]repo.PriceAdjustment{c.adjustment}, nil)
+			s := &PriceSchedule{Adjustments: a, Clock: FixedClock(c.now)}
+
+			running, err := s.Running(context.Background())
+			require.NoError(t, err)
+			got, adj := running.Apply(latte)
+			require.Equal(t, c.wantPrice, got.PriceCents)
+			if c.wantEndsAt.IsZero() {
+				require.Nil(t, adj)
+				return
+			}
+			require.NotNil(t, adj)
+			require.True(t, c.wantEndsAt.Equal(adj.EndsAt), "ends at %s", adj.EndsAt)
+		})
+	}
+}
+
+func TestRunningAdjustments_Apply_Precedence(t *testing.T) {
+	now := time.Date(2025, 10, 6, 5, 30, 0, 0, time.UTC)
+	allDay := func(a repo.PriceAdjustment) repo.PriceAdjustment {
+		a.LocalStart, a.LocalEnd = timeOfDay(0, 0), timeOfDay(0, 0)
+		return a
+	}
+	drinks := allDay(happyHour("drinks", "Beverage", 10, 127, time.Time{}, time.Time{}))
+	bigger := allDay(happyHour("drinks-big", "Beverage", 25, 127, time.Time{}, time.Time{}))
+	latteOnly := allDay(happyHour("latte", "", 5, 127, time.Time{}, time.Time{}))
+	latteOnly.Category = sql.NullString{}
+	latteOnly.ProductID = sql.NullString{String: "12", Valid: true}
+
+	a := repomock.NewPriceAdjustmentRepository(t)
+	a.On("Scheduled", mock.Anything, now).Return([]repo.PriceAdjustment{drinks, bigger, latteOnly}, nil)
+	running, err := (&PriceSchedule{Adjustments: a, Clock: FixedClock(now)}).Running(context.Background())
+	require.NoError(t, err)
+
+	// The product's own adjustment wins over a larger one for its category.
+	_, adj := running.Apply(repo.Product{ID: "12", Category: "Beverage", PriceCents: 500})
+	require.Equal(t, "latte", adj.ID)
+	// Otherwise the largest category discount wins.
+	p, adj := running.Apply(repo.Product{ID: "13", Category: "Beverage", PriceCents: 500})
+	require.Equal(t, "drinks-big", adj.ID)
+	require.EqualValues(t, 375, p.PriceCents)
+	_, adj = running.Apply(repo.Product{ID: "10", Category: "Waffle", PriceCents: 1100})
+	require.Nil(t, adj)
+}
+
+func TestProductService_Get_Adjusted(t *testing.T) {
+	now := time.Date(2025, 10, 6, 5, 30, 0, 0, time.UTC)
+	a := repomock.NewPriceAdjustmentRepository(t)
+	a.On("Scheduled", mock.Anything, now).Return([]repo.PriceAdjustment{
+		happyHour("coffee", "Beverage", 30, 127, timeOfDay(15, 0), timeOfDay(17, 0)),
+	}, nil)
+	p := repomock.NewProductRepository(t)
+	p.On("Get", mock.Anything, "12").Return(repo.Product{ID: "12", Category: "Beverage", PriceCents: 500}, nil)
+	svc := NewProductService(p)
+	svc.Prices = &PriceLists{Schedule: &PriceSchedule{Adjustments: a, Clock: FixedClock(now)}}
+
+	got, err := svc.Get(context.Background(), "12", "")
+	require.NoError(t, err)
+	require.EqualValues(t, 350, got.PriceCents)
+	require.EqualValues(t, 500, got.RegularPriceCents)
+	require.Equal(t, "coffee", got.Adjustment.ID)
+}
+
+func TestOrderService_Quote_Adjusted(t *testing.T) {
+	now := time.Date(2025, 10, 6, 5, 30, 0, 0, time.UTC)
+	a := repomock.NewPriceAdjustmentRepository(t)
+	a.On("Scheduled", mock.Anything, now).Return([]repo.PriceAdjustment{
+		happyHour("coffee", "Beverage", 30, 127, timeOfDay(15, 0), timeOfDay(17, 0)),
+	}, nil)
+	p := repomock.NewProductRepository(t)
+	p.On("GetMany", mock.Anything, []string{"12", "10"}).Return(map[string]repo.Product{
+		"10": {ID: "10", Category: "Waffle", PriceCents: 1100},
+		"12": {ID: "12", Category: "Beverage", PriceCents: 500},
+	}, nil)
+	svc := NewOrderService(p, repomock.NewCouponRepository(t), repomock.NewOrderRepository(t))
+	svc.Prices = &PriceLists{Schedule: &PriceSchedule{Adjustments: a, Clock: FixedClock(now)}}
+
+	got, err := svc.Quote(context.Background(), "", "", []OrderItemInput{{ProductID: "12", Quantity: 2}, {ProductID: "10", Quantity: 1}})
+	require.NoError(t, err)
+	require.EqualValues(t, 2*350+1100, got.SubtotalCents)
+}
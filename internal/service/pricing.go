@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"kart/internal/money"
+	"kart/internal/promo"
+	"kart/internal/repo"
+	"kart/internal/tax"
+)
+
+// Pricing is the priced breakdown of a set of order lines. TotalCents is what
+// the customer pays: the discounted subtotal, plus tax when prices exclude it.
+// DiscountCents includes what Promotions took off. All amounts are in minor
+// units of Currency.
+type Pricing struct {
+	Currency      string
+	Lines         []PricedLine
+	SubtotalCents int64
+	DiscountCents int64
+	// CouponDiscountCents is the coupon's part of DiscountCents.
+	CouponDiscountCents int64
+	TaxCents            int64
+	TotalCents          int64
+	TaxInclusive        bool
+	Taxes               []tax.ClassTotal
+	Promotions          []promo.Applied
+}
+
+func (p Pricing) Subtotal() money.Money { return money.New(p.SubtotalCents, p.Currency) }
+func (p Pricing) Discount() money.Money { return money.New(p.DiscountCents, p.Currency) }
+func (p Pricing) Tax() money.Money      { return money.New(p.TaxCents, p.Currency) }
+func (p Pricing) Total() money.Money    { return money.New(p.TotalCents, p.Currency) }
+
+type PricedLine struct {
+	ProductID      string
+	Quantity       int32
+	UnitPriceCents int64
+	TaxClass       string
+	// DiscountCents is what promotions took off the line plus its share of
+	// any other discount.
+	DiscountCents int64
+	// TaxCents is the line's tax, after its discount.
+	TaxCents int64
+}
+
+// Quote prices the items in currency as PlaceOrder would charge for them
+// with couponCode, which may be empty, without placing the order. A coupon
+// PlaceOrder would refuse fails the quote with the same error.
+func (s *OrderService) Quote(ctx context.Context, currency, couponCode string, items []OrderItemInput) (Pricing, error) {
+	c, err := s.coupon(ctx, couponCode)
+	if err != nil {
+		return Pricing{}, err
+	}
+	list, products, err := s.listedProducts(ctx, currency, items)
+	if err != nil {
+		return Pricing{}, err
+	}
+	return s.price(ctx, list.Currency, items, products, c.PercentOff)
+}
+
+// price computes line totals from products, already priced in currency,
+// applies the store's promotions, takes couponPercent off what the lines are
+// worth after them, spread over the lines, and taxes what remains.
+func (s *OrderService) price(ctx context.Context, currency string, items []OrderItemInput, products map[string]repo.Product, couponPercent int32) (Pricing, error) {
+	out := Pricing{
+		Currency:     currency,
+		Lines:        make([]PricedLine, len(items)),
+		TaxInclusive: s.taxMode() == tax.Inclusive,
+	}
+	gross := make([]int64, len(items))
+	for i, it := range items {
+		p, ok := products[it.ProductID]
+		if !ok {
+			return Pricing{}, ErrProductNotFound
+		}
+		out.Lines[i] = PricedLine{ProductID: it.ProductID, Quantity: it.Quantity, UnitPriceCents: int64(p.PriceCents)}
+		gross[i] = int64(p.PriceCents) * int64(it.Quantity)
+		out.SubtotalCents += gross[i]
+	}
+	promos, err := s.promotions(ctx, out.Lines, products)
+	if err != nil {
+		return Pricing{}, err
+	}
+	out.Promotions = promos.Applied
+	net := make([]int64, len(items))
+	var netTotal int64
+	for i := range net {
+		net[i] = gross[i] - promos.LineDiscounts[i]
+		netTotal += net[i]
+	}
+	out.CouponDiscountCents = netTotal * int64(couponPercent) / 100
+	shares := allocateDiscount(net, out.CouponDiscountCents)
+	for i := range out.Lines {
+		out.Lines[i].DiscountCents = promos.LineDiscounts[i] + shares[i]
+	}
+	out.DiscountCents = promos.DiscountCents + out.CouponDiscountCents
+	out.TotalCents = out.SubtotalCents - out.DiscountCents
+	if s.Tax == nil {
+		return out, nil
+	}
+
+	calc, classes, err := s.taxCalculator(ctx)
+	if err != nil {
+		return Pricing{}, err
+	}
+	lines := make([]tax.Line, len(items))
+	for i, it := range items {
+		out.Lines[i].TaxClass = taxClass(products[it.ProductID], classes)
+		lines[i] = tax.Line{Class: out.Lines[i].TaxClass, AmountCents: gross[i] - out.Lines[i].DiscountCents}
+	}
+	res, err := calc.Calculate(lines)
+	if err != nil {
+		return Pricing{}, err
+	}
+	for i := range out.Lines {
+		out.Lines[i].TaxCents = res.LineTax[i]
+	}
+	out.TaxCents = res.TaxCents
+	out.Taxes = res.Classes
+	if !out.TaxInclusive {
+		out.TotalCents += out.TaxCents
+	}
+	return out, nil
+}
+
+// promotions evaluates the store's running promotions against the priced
+// lines. A promotion whose rule cannot be read fails pricing rather than
+// silently charging full price.
+func (s *OrderService) promotions(ctx context.Context, lines []PricedLine, products map[string]repo.Product) (promo.Result, error) {
+	in := make([]promo.Line, len(lines))
+	for i, l := range lines {
+		in[i] = promo.Line{ProductID: l.ProductID, Category: products[l.ProductID].Category, Quantity: l.Quantity, UnitPriceCents: l.UnitPriceCents}
+	}
+	if s.Promotions == nil {
+		return promo.Evaluate(nil, in), nil
+	}
+	rows, err := s.Promotions.Active(ctx, s.now())
+	if err != nil {
+		return promo.Result{}, err
+	}
+	rules := make([]promo.Rule, 0, len(rows))
+	for _, p := range rows {
+		r := promo.Rule{ID: p.ID, Name: p.Name, Priority: p.Priority, Exclusive: p.Exclusive}
+		if err := json.Unmarshal(p.Rule, &r.Definition); err != nil {
+			return promo.Result{}, fmt.Errorf("promotion %s: %w: %v", p.ID, promo.ErrInvalidRule, err)
+		}
+		if err := r.Validate(); err != nil {
+			return promo.Result{}, fmt.Errorf("promotion %s: %w", p.ID, err)
+		}
+		rules = append(rules, r)
+	}
+	return promo.Evaluate(rules, in), nil
+}
+
+func (s *OrderService) taxCalculator(ctx context.Context) (tax.Calculator, map[string]string, error) {
+	rates, err := s.Tax.Rates(ctx)
+	if err != nil {
+		return tax.Calculator{}, nil, err
+	}
+	classes, err := s.Tax.CategoryClasses(ctx)
+	if err != nil {
+		return tax.Calculator{}, nil, err
+	}
+	rounding := s.TaxRounding
+	if rounding == "" {
+		rounding = tax.PerLine
+	}
+	return tax.Calculator{Mode: s.taxMode(), Rounding: rounding, Rates: rates}, classes, nil
+}
+
+func (s *OrderService) taxMode() tax.Mode {
+	if s.TaxMode == "" {
+		return tax.Inclusive
+	}
+	return s.TaxMode
+}
+
+// taxClass resolves a product's tax class: its own, else its category's,
+// else tax.DefaultClass.
+func taxClass(p repo.Product, categories map[string]string) string {
+	if p.TaxClass.Valid {
+		return p.TaxClass.String
+	}
+	if c, ok := categories[p.Category]; ok {
+		return c
+	}
+	return tax.DefaultClass
+}
+
+// allocateDiscount splits discount over lines in proportion to their value.
+// Shares are rounded down and the remainder goes to the last line, so they
+// always sum to discount.
+func allocateDiscount(gross []int64, discount int64) []int64 {
+	shares := make([]int64, len(gross))
+	if discount == 0 || len(gross) == 0 {
+		return shares
+	}
+	var subtotal int64
+	for _, g := range gross {
+		subtotal += g
+	}
+	if subtotal == 0 {
+		return shares
+	}
+	var allocated int64
+	for i, g := range gross {
+		shares[i] = discount * g / subtotal
+		allocated += shares[i]
+	}
+	shares[len(shares)-1] += discount - allocated
+	return shares
+}
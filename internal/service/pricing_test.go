@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/money"
+	"kart/internal/promo"
+	"kart/internal/repo"
+	"kart/internal/tax"
+)
+
+func TestOrderService_Quote_Tax(t *testing.T) {
+	products := map[string]repo.Product{
+		"10": {ID: "10", Category: "Waffle", PriceCents: 1100},
+		"12": {ID: "12", Category: "Beverage", PriceCents: 500},
+		"13": {ID: "13", Category: "Beverage", PriceCents: 300, TaxClass: sql.NullString{String: "standard", Valid: true}},
+	}
+	items := []OrderItemInput{{ProductID: "10", Quantity: 2}, {ProductID: "12", Quantity: 1}, {ProductID: "13", Quantity: 1}}
+	type tc struct {
+		name      string
+		mode      tax.Mode
+		wantTax   int64
+		wantTotal int64
+		wantLines []string
+	}
+	cases := []tc{
+		{
+			// Beverages are exempt by category; product 13 overrides that.
+			name:      "inclusive",
+			mode:      tax.Inclusive,
+			wantTax:   200 + 27,
+			wantTotal: 3000,
+			wantLines: []string{"standard", "exempt", "standard"},
+		},
+		{
+			name:      "exclusive",
+			mode:      tax.Exclusive,
+			wantTax:   220 + 30,
+			wantTotal: 3000 + 250,
+			wantLines: []string{"standard", "exempt", "standard"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := repomock.NewProductRepository(t)
+			p.On("GetMany", mock.Anything, []string{"10", "12", "13"}).Return(products, nil)
+			tr := repomock.NewTaxRepository(t)
+			tr.On("Rates", mock.Anything).Return(map[string]int32{"standard": 1000, "exempt": 0}, nil)
+			tr.On("CategoryClasses", mock.Anything).Return(map[string]string{"Beverage": "exempt"}, nil)
+
+			svc := NewOrderService(p, repomock.NewCouponRepository(t), repomock.NewOrderRepository(t))
+			svc.Tax = tr
+			svc.TaxMode = c.mode
+
+			got, err := svc.Quote(context.Background(), "", "", items)
+			require.NoError(t, err)
+			require.EqualValues(t, 3000, got.SubtotalCents)
+			require.Equal(t, c.wantTax, got.TaxCents)
+			require.Equal(t, c.wantTotal, got.TotalCents)
+			require.Equal(t, money.New(c.wantTotal, "AUD"), got.Total())
+			require.Equal(t, c.mode == tax.Inclusive, got.TaxInclusive)
+			for i, class := range c.wantLines {
+				require.Equal(t, class, got.Lines[i].TaxClass)
+			}
+			require.Len(t, got.Taxes, 2)
+		})
+	}
+}
+
+func TestOrderService_Quote_Promotions(t *testing.T) {
+	products := map[string]repo.Product{
+		"10": {ID: "10", Category: "Waffle", PriceCents: 1100},
+		"12": {ID: "12", Category: "Beverage", PriceCents: 500},
+	}
+	items := []OrderItemInput{{ProductID: "10", Quantity: 2}, {ProductID: "12", Quantity: 1}}
+	freeLatte := repo.Promotion{ID: "b2gl", Name: "Buy 2 waffles get a latte free", Priority: 1, Rule: []byte(
+		`{"buy":[{"categories":["Waffle"],"quantity":2}],"action":"free_item","get":{"products":["12"],"quantity":1}}`,
+	)}
+	type tc struct {
+		name          string
+		promotions    []repo.Promotion
+		coupon        string
+		wantDiscount  int64
+		wantCoupon    int64
+		wantLines     []int64
+		wantTax       int64
+		wantPromotion string
+		wantErr       error
+	}
+	cases := []tc{
+		{name: "no promotions", wantLines: []int64{0, 0}, wantTax: 200 + 45},
+		{
+			// The latte line is free, so it carries no tax.
+			name:          "free item",
+			promotions:    []repo.Promotion{freeLatte},
+			wantDiscount:  500,
+			wantLines:     []int64{0, 500},
+			wantTax:       200,
+			wantPromotion: "b2gl",
+		},
+		{
+			// The coupon takes 10% off what is left after the free latte,
+			// all of it from the waffles.
+			name:          "coupon after promotions",
+			promotions:    []repo.Promotion{freeLatte},
+			coupon:        "TENOFF01",
+			wantDiscount:  500 + 220,
+			wantCoupon:    220,
+			wantLines:     []int64{220, 500},
+			wantTax:       180,
+			wantPromotion: "b2gl",
+		},
+		{
+			name:       "unreadable rule",
+			promotions: []repo.Promotion{{ID: "bad", Rule: []byte(`{"action":"free_item"}`)}},
+			wantErr:    promo.ErrInvalidRule,
+		},
+	}
+	now := time.Date(2025, 10, 10, 12, 0, 0, 0, time.UTC)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := repomock.NewProductRepository(t)
+			p.On("GetMany", mock.Anything, []string{"10", "12"}).Return(products, nil)
+			tr := repomock.NewTaxRepository(t)
+			tr.On("Rates", mock.Anything).Maybe().Return(map[string]int32{"standard": 1000}, nil)
+			tr.On("CategoryClasses", mock.Anything).Maybe().Return(map[string]string{}, nil)
+			promos := repomock.NewPromotionRepository(t)
+			promos.On("Active", mock.Anything, now).Return(c.promotions, nil)
+
+			coupons := repomock.NewCouponRepository(t)
+			if c.coupon != "" {
+				coupons.On("Get", mock.Anything, c.coupon).Return(repo.Coupon{Code: c.coupon, PresenceMask: 0b11, PercentOff: 10}, nil)
+			}
+
+			svc := NewOrderService(p, coupons, repomock.NewOrderRepository(t))
+			svc.Tax = tr
+			svc.Promotions = promos
+			svc.Clock = FixedClock(now)
+
+			got, err := svc.Quote(context.Background(), "", c.coupon, items)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, 2700, got.SubtotalCents)
+			require.Equal(t, c.wantDiscount, got.DiscountCents)
+			require.Equal(t, c.wantCoupon, got.CouponDiscountCents)
+			require.Equal(t, 2700-c.wantDiscount, got.TotalCents)
+			require.Equal(t, c.wantTax, got.TaxCents)
+			for i, d := range c.wantLines {
+				require.Equal(t, d, got.Lines[i].DiscountCents)
+			}
+			if c.wantPromotion == "" {
+				require.Empty(t, got.Promotions)
+				return
+			}
+			require.Len(t, got.Promotions, 1)
+			require.Equal(t, c.wantPromotion, got.Promotions[0].RuleID)
+		})
+	}
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	require.Equal(t, []int64{0, 0}, allocateDiscount([]int64{100, 200}, 0))
+	require.Equal(t, []int64{33, 67}, allocateDiscount([]int64{100, 200}, 100))
+	require.Equal(t, []int64{3, 3, 4}, allocateDiscount([]int64{100, 100, 100}, 10))
+}
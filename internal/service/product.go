@@ -2,17 +2,133 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strconv"
+
+	"kart/internal/money"
 	"kart/internal/repo"
 )
 
-type ProductService struct{ Products repo.ProductRepository }
+type ProductService struct {
+	Products repo.ProductRepository
+	// Prices is optional; without it products sell at their base price.
+	Prices *PriceLists
+}
 
 func NewProductService(p repo.ProductRepository) *ProductService { return &ProductService{Products: p} }
 
-func (s *ProductService) List(ctx context.Context) ([]repo.Product, error) {
-	return s.Products.List(ctx)
+// PricedProduct is a product whose PriceCents comes from a price list in
+// Currency. While a scheduled adjustment runs, PriceCents is the adjusted
+// price, RegularPriceCents the list price and Adjustment what was taken off.
+type PricedProduct struct {
+	repo.Product
+	Currency          string
+	RegularPriceCents int32
+	Adjustment        *PriceAdjustment
+}
+
+func (p PricedProduct) Price() money.Money { return money.New(int64(p.PriceCents), p.Currency) }
+
+// List returns the products sold in currency, priced from its price list.
+// An empty currency selects the store's default price list.
+func (s *ProductService) List(ctx context.Context, currency string) ([]PricedProduct, error) {
+	prices := s.priceLists()
+	list, err := prices.Resolve(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := s.Products.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ps, err = prices.applyListAll(ctx, list, ps); err != nil {
+		return nil, err
+	}
+	running, err := prices.Schedule.Running(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PricedProduct, len(ps))
+	for i, p := range ps {
+		out[i] = adjusted(p, list.Currency, running)
+	}
+	return out, nil
+}
+
+// Get returns the product priced in currency. Products the price list does
+// not sell are reported as ErrProductNotFound. A numeric id that matches no
+// product is retried in canonical form ("010" finds "10"), as clients of the
+// API's integer IDs may send either.
+func (s *ProductService) Get(ctx context.Context, id, currency string) (PricedProduct, error) {
+	return s.get(ctx, currency, func() (repo.Product, error) {
+		p, err := s.Products.Get(ctx, id)
+		if errors.Is(err, repo.ErrNotFound) {
+			if canonical, ok := canonicalNumericID(id); ok {
+				return s.Products.Get(ctx, canonical)
+			}
+		}
+		return p, err
+	})
+}
+
+// GetBySlug returns the product with the given slug priced in currency,
+// reporting ErrProductNotFound like Get.
+func (s *ProductService) GetBySlug(ctx context.Context, slug, currency string) (PricedProduct, error) {
+	return s.get(ctx, currency, func() (repo.Product, error) {
+		return s.Products.GetBySlug(ctx, slug)
+	})
+}
+
+func (s *ProductService) get(ctx context.Context, currency string, lookup func() (repo.Product, error)) (PricedProduct, error) {
+	prices := s.priceLists()
+	list, err := prices.Resolve(ctx, currency)
+	if err != nil {
+		return PricedProduct{}, err
+	}
+	p, err := lookup()
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return PricedProduct{}, ErrProductNotFound
+		}
+		return PricedProduct{}, err
+	}
+	byID := map[string]repo.Product{p.ID: p}
+	if err := prices.applyList(ctx, list, byID); err != nil {
+		return PricedProduct{}, err
+	}
+	p, ok := byID[p.ID]
+	if !ok {
+		return PricedProduct{}, ErrProductNotFound
+	}
+	running, err := prices.Schedule.Running(ctx)
+	if err != nil {
+		return PricedProduct{}, err
+	}
+	return adjusted(p, list.Currency, running), nil
+}
+
+// canonicalNumericID returns id without leading zeros when id is a decimal
+// integer written non-canonically.
+func canonicalNumericID(id string) (string, bool) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n < 0 {
+		return "", false
+	}
+	canonical := strconv.FormatInt(n, 10)
+	return canonical, canonical != id
+}
+
+func adjusted(p repo.Product, currency string, running RunningAdjustments) PricedProduct {
+	out := PricedProduct{Product: p, Currency: currency}
+	if out.Product, out.Adjustment = running.Apply(p); out.Adjustment != nil {
+		out.RegularPriceCents = p.PriceCents
+	}
+	return out
 }
 
-func (s *ProductService) Get(ctx context.Context, id string) (repo.Product, error) {
-	return s.Products.Get(ctx, id)
+func (s *ProductService) priceLists() *PriceLists {
+	if s.Prices != nil {
+		return s.Prices
+	}
+	return &PriceLists{}
 }
@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
 	sqlcmock "kart/internal/mocks/sqlc"
 	"kart/internal/repo"
 	"kart/internal/sqlc"
+	"kart/internal/tenant"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -24,7 +26,7 @@ func TestProductService_List(t *testing.T) {
 		{
 			name: "success two products",
 			setupMock: func(m *sqlcmock.Querier) {
-				m.On("ListProducts", mock.Anything).
+				m.On("ListProducts", mock.Anything, "default").
 					Return([]sqlc.Product{{ID: "1"}, {ID: "2"}}, nil)
 			},
 			wantLen: 2,
@@ -32,7 +34,7 @@ func TestProductService_List(t *testing.T) {
 		{
 			name: "error from ListProducts",
 			setupMock: func(m *sqlcmock.Querier) {
-				m.On("ListProducts", mock.Anything).
+				m.On("ListProducts", mock.Anything, "default").
 					Return(([]sqlc.Product)(nil), errors.New("db down"))
 			},
 			wantErr: true,
@@ -41,14 +43,14 @@ func TestProductService_List(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx := tenant.WithStore(context.Background(), "default")
 			m := sqlcmock.NewQuerier(t)
 			if c.setupMock != nil {
 				c.setupMock(m)
 			}
 			repo := repo.NewProductRepo(m)
 			svc := NewProductService(repo)
-			got, err := svc.List(ctx)
+			got, err := svc.List(ctx, "")
 			if c.wantErr {
 				if err == nil {
 					t.Fatalf("expected error")
@@ -79,7 +81,7 @@ func TestProductService_Get(t *testing.T) {
 			name: "found",
 			id:   "1",
 			setupMock: func(m *sqlcmock.Querier) {
-				m.On("GetProduct", mock.Anything, "1").
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "1"}).
 					Return(sqlc.Product{ID: "1"}, nil)
 			},
 			wantID: "1",
@@ -88,23 +90,61 @@ func TestProductService_Get(t *testing.T) {
 			name: "not found",
 			id:   "x",
 			setupMock: func(m *sqlcmock.Querier) {
-				m.On("GetProduct", mock.Anything, "x").
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "x"}).
 					Return(sqlc.Product{}, errors.New("not found"))
 			},
 			wantErr: true,
 		},
+		{
+			name: "text id",
+			id:   "waffle-choc",
+			setupMock: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "waffle-choc"}).
+					Return(sqlc.Product{ID: "waffle-choc"}, nil)
+			},
+			wantID: "waffle-choc",
+		},
+		{
+			name: "leading zeros match numeric id",
+			id:   "010",
+			setupMock: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "010"}).
+					Return(sqlc.Product{}, sql.ErrNoRows)
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "10"}).
+					Return(sqlc.Product{ID: "10"}, nil)
+			},
+			wantID: "10",
+		},
+		{
+			name: "leading zeros id exists as sent",
+			id:   "010",
+			setupMock: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "010"}).
+					Return(sqlc.Product{ID: "010"}, nil)
+			},
+			wantID: "010",
+		},
+		{
+			name: "canonical numeric id not retried",
+			id:   "10",
+			setupMock: func(m *sqlcmock.Querier) {
+				m.On("GetProduct", mock.Anything, sqlc.GetProductParams{StoreID: "default", ID: "10"}).
+					Return(sqlc.Product{}, sql.ErrNoRows).Once()
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx := tenant.WithStore(context.Background(), "default")
 			m := sqlcmock.NewQuerier(t)
 			if c.setupMock != nil {
 				c.setupMock(m)
 			}
 			repo := repo.NewProductRepo(m)
 			svc := NewProductService(repo)
-			p, err := svc.Get(ctx, c.id)
+			p, err := svc.Get(ctx, c.id, "")
 			if c.wantErr {
 				if err == nil {
 					t.Fatalf("expected error")
@@ -120,3 +160,40 @@ func TestProductService_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestProductService_GetBySlug(t *testing.T) {
+	type tc struct {
+		name    string
+		slug    string
+		found   sqlc.Product
+		err     error
+		wantID  string
+		wantErr error
+	}
+	cases := []tc{
+		{name: "found", slug: "chicken-waffle", found: sqlc.Product{ID: "10"}, wantID: "10"},
+		{name: "missing", slug: "nope", err: sql.ErrNoRows, wantErr: ErrProductNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := tenant.WithStore(context.Background(), "default")
+			m := sqlcmock.NewQuerier(t)
+			m.On("GetProductBySlug", mock.Anything, sqlc.GetProductBySlugParams{StoreID: "default", Slug: sql.NullString{String: c.slug, Valid: true}}).
+				Return(c.found, c.err)
+			svc := NewProductService(repo.NewProductRepo(m))
+			p, err := svc.GetBySlug(ctx, c.slug, "")
+			if c.wantErr != nil {
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("want %v, got %v", c.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.ID != c.wantID {
+				t.Fatalf("want %s, got %s", c.wantID, p.ID)
+			}
+		})
+	}
+}
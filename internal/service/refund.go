@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"kart/internal/money"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+var (
+	ErrRefundNotAllowed = newError(KindConflict, "refund_not_allowed", "order has no captured payment to refund")
+	ErrRefundInvalid    = newError(KindValidation, "refund_invalid", "refund must specify either lines or a positive amount")
+	ErrRefundReason     = newError(KindValidation, "refund_reason", "refund reason and operator are required")
+	ErrRefundExceeded   = newError(KindValidation, "refund_exceeded", "refund exceeds the amount still refundable")
+	ErrRefundLine       = newError(KindValidation, "refund_line", "refund line does not match enough unrefunded items")
+)
+
+type RefundLineInput struct {
+	ProductID string
+	Quantity  int32
+}
+
+// RefundInput requests either a refund of specific order lines or of an
+// arbitrary amount; exactly one of Lines and AmountCents must be set.
+type RefundInput struct {
+	OrderID     string
+	Lines       []RefundLineInput
+	AmountCents int64
+	Reason      string
+	// Operator identifies who issued the refund for the audit trail. It is
+	// the authenticated caller, never something the caller wrote.
+	Operator string
+}
+
+type Refund struct {
+	ID          string
+	OrderID     string
+	AmountCents int64
+	Reason      string
+	Operator    string
+	Status      string
+	Lines       []RefundLine
+	CreatedAt   time.Time
+}
+
+type RefundLine struct {
+	ProductID   string
+	Quantity    int32
+	AmountCents int64
+}
+
+// OrderDetails is an order with its lines, tax breakdown, payment and refund
+// history. Amounts are in minor units of Currency.
+type OrderDetails struct {
+	Order    repo.Order
+	Currency string
+	Items    []repo.OrderItem
+	Taxes    []repo.OrderTax
+	Payment  *PaymentResult
+	Refunds  []Refund
+}
+
+// Total is what the customer was charged for the order.
+func (d OrderDetails) Total() money.Money { return money.New(d.Order.TotalCents, d.Currency) }
+
+// Details returns the order together with its lines, taxes, payment and refunds.
+func (s *OrderService) Details(ctx context.Context, id string) (OrderDetails, error) {
+	o, err := s.Orders.Get(ctx, id)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	items, err := s.Orders.Items(ctx, id)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	taxes, err := s.Orders.Taxes(ctx, id)
+	if err != nil {
+		return OrderDetails{}, err
+	}
+	out := OrderDetails{Order: o, Currency: o.Currency, Items: items, Taxes: taxes}
+
+	if s.PaymentRecords != nil {
+		p, err := s.PaymentRecords.GetByOrder(ctx, id)
+		switch {
+		case err == nil:
+			pay := toPaymentResult(p, payments.Result{})
+			out.Payment = &pay
+		case !errors.Is(err, repo.ErrNotFound):
+			return OrderDetails{}, err
+		}
+	}
+	if s.Refunds != nil {
+		out.Refunds, err = s.listRefunds(ctx, id, items)
+		if err != nil {
+			return OrderDetails{}, err
+		}
+	}
+	return out, nil
+}
+
+// Refund returns money from the order's captured payment to the customer.
+//
+// Line refunds are priced at the unit price fixed when the order was placed,
+// less the line's share of promotions and other discounts, plus the line's
+// tax when it was charged on top of the price. When gift cards or loyalty
+// points paid for part of the order, only the captured payment's share of
+// that is refunded; the rest goes back to the cards and points once the
+// order is refunded in full. The refund is reserved against
+// the order before the provider is called and released again if the provider
+// fails, so concurrent refunds never exceed what was captured.
+// An order refunded in full moves to StatusRefunded. With Loyalty set, the
+// customer loses the points earned on the refunded amount, and gets back the
+// points the order redeemed once it is refunded in full. Gift cards the order
+// was paid with are credited back once it is refunded in full.
+func (s *OrderService) Refund(ctx context.Context, in RefundInput) (Refund, error) {
+	in.Reason = strings.TrimSpace(in.Reason)
+	in.Operator = strings.TrimSpace(in.Operator)
+	if in.Reason == "" || in.Operator == "" {
+		return Refund{}, ErrRefundReason
+	}
+	if (len(in.Lines) == 0) == (in.AmountCents == 0) || in.AmountCents < 0 {
+		return Refund{}, ErrRefundInvalid
+	}
+	if s.Payments == nil || s.Refunds == nil {
+		return Refund{}, ErrPaymentsNotConfigured
+	}
+
+	o, err := s.Orders.Get(ctx, in.OrderID)
+	if err != nil {
+		return Refund{}, err
+	}
+	p, err := s.PaymentRecords.GetByOrder(ctx, in.OrderID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return Refund{}, ErrRefundNotAllowed
+	}
+	if err != nil {
+		return Refund{}, err
+	}
+	if p.CapturedCents == 0 || !p.ProviderRef.Valid {
+		return Refund{}, ErrRefundNotAllowed
+	}
+	remaining := p.CapturedCents - o.RefundedCents
+
+	var (
+		amount int64
+		items  []repo.RefundItem
+		lines  []RefundLine
+	)
+	if len(in.Lines) > 0 {
+		orderItems, err := s.Orders.Items(ctx, in.OrderID)
+		if err != nil {
+			return Refund{}, err
+		}
+		items, err = allocateRefundLines(o, orderItems, in.Lines, p.CapturedCents, remaining)
+		if err != nil {
+			return Refund{}, err
+		}
+		lines = refundLines(items, orderItems)
+		for _, it := range items {
+			amount += it.AmountCents
+		}
+	} else {
+		amount = in.AmountCents
+	}
+	if amount > remaining {
+		return Refund{}, ErrRefundExceeded
+	}
+
+	rec := repo.Refund{
+		ID:          uuid.NewString(),
+		OrderID:     o.ID,
+		PaymentID:   p.ID,
+		AmountCents: amount,
+		Reason:      in.Reason,
+		Operator:    in.Operator,
+	}
+	for i := range items {
+		items[i].RefundID = rec.ID
+	}
+	if err := s.Refunds.Create(ctx, rec, items, p.CapturedCents); err != nil {
+		if errors.Is(err, repo.ErrRefundExceedsCaptured) || errors.Is(err, repo.ErrRefundExceedsQuantity) {
+			return Refund{}, fmt.Errorf("%w: %v", ErrRefundExceeded, err)
+		}
+		return Refund{}, err
+	}
+
+	// The reservation must be settled even if the client has gone away.
+	ctx = context.WithoutCancel(ctx)
+	res, err := s.Payments.Refund(ctx, p.ProviderRef.String, amount)
+	if err != nil {
+		if ferr := s.Refunds.Fail(ctx, rec.ID, err.Error()); ferr != nil {
+			err = errors.Join(err, ferr)
+		}
+		if errors.Is(err, payments.ErrAmountExceeded) {
+			return Refund{}, fmt.Errorf("%w: %v", ErrRefundExceeded, err)
+		}
+		return Refund{}, fmt.Errorf("%w: %v", ErrPaymentUnavailable, err)
+	}
+	rec, err = s.Refunds.Complete(ctx, rec.ID, res.Reference)
+	if err != nil {
+		return Refund{}, err
+	}
+
+	p.Status = string(res.Status)
+	if _, err := s.PaymentRecords.Update(ctx, p); err != nil {
+		return Refund{}, err
+	}
+	// The order read above predates this refund's reservation, and other
+	// refunds may have completed since. Totalling the succeeded refunds after
+	// this one completed means the last of any concurrent refunds sees them
+	// all.
+	refunded, err := s.Refunds.Succeeded(ctx, o.ID)
+	if err != nil {
+		return Refund{}, err
+	}
+	var entries []repo.LoyaltyEntry
+	if s.Loyalty != nil {
+		if entries, err = s.Loyalty.reverseEntry(ctx, o, rec.ID, refunded, p.CapturedCents); err != nil {
+			return Refund{}, err
+		}
+	}
+	if refunded < p.CapturedCents {
+		return toRefund(rec, lines), s.postLoyalty(ctx, entries)
+	}
+	if s.Loyalty != nil {
+		entries = append(entries, restoreEntry(o)...)
+	}
+	updated, err := s.Orders.Reverse(ctx, o.ID, o.Status, StatusRefunded, o.EtaAt, entries...)
+	if errors.Is(err, repo.ErrStatusChanged) {
+		// A concurrent refund finished the order first and moved it on. The
+		// entries are keyed, so posting ones it already posted is harmless.
+		return toRefund(rec, lines), s.postLoyalty(ctx, entries)
+	}
+	if err != nil {
+		return Refund{}, err
+	}
+	s.publish(updated)
+	return toRefund(rec, lines), nil
+}
+
+// postLoyalty posts entries to the ledger one at a time, skipping any it
+// already holds.
+func (s *OrderService) postLoyalty(ctx context.Context, entries []repo.LoyaltyEntry) error {
+	for _, e := range entries {
+		if _, err := s.Loyalty.Ledger.Post(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocateRefundLines spreads each requested line over the order items for
+// that product that still have unrefunded units, and prices each share at
+// what the captured payment paid for it: when gift cards or points covered
+// part of the order total, every line is scaled down to the captured share.
+// The last share is trimmed if rounding would take the refund past remaining.
+func allocateRefundLines(o repo.Order, orderItems []repo.OrderItem, lines []RefundLineInput, captured, remaining int64) ([]repo.RefundItem, error) {
+	var subtotal int64
+	available := make([]int32, len(orderItems))
+	for i, it := range orderItems {
+		subtotal += it.UnitPriceCents * int64(it.Quantity)
+		available[i] = it.Quantity - it.RefundedQuantity
+	}
+	perLine := lineDiscountsRecorded(orderItems)
+
+	byItem := make(map[string]int, len(orderItems))
+	var out []repo.RefundItem
+	var total int64
+	for _, l := range lines {
+		if l.Quantity <= 0 {
+			return nil, ErrRefundLine
+		}
+		want := l.Quantity
+		for i, it := range orderItems {
+			if want == 0 {
+				break
+			}
+			if it.ProductID != l.ProductID || available[i] == 0 {
+				continue
+			}
+			n := min(want, available[i])
+			available[i] -= n
+			want -= n
+
+			gross := it.UnitPriceCents * int64(n)
+			share := gross
+			switch {
+			case perLine:
+				share -= it.DiscountCents * int64(n) / int64(it.Quantity)
+			case subtotal > 0:
+				share -= o.DiscountCents * gross / subtotal
+			}
+			if !o.TaxInclusive {
+				share += it.TaxCents * int64(n) / int64(it.Quantity)
+			}
+			if captured < o.TotalCents && o.TotalCents > 0 {
+				share = share * captured / o.TotalCents
+			}
+			total += share
+			if j, ok := byItem[it.ID]; ok {
+				out[j].Quantity += n
+				out[j].AmountCents += share
+				continue
+			}
+			byItem[it.ID] = len(out)
+			out = append(out, repo.RefundItem{OrderItemID: it.ID, Quantity: n, AmountCents: share})
+		}
+		if want > 0 {
+			return nil, ErrRefundLine
+		}
+	}
+	// Discount shares are rounded down, so refunding every remaining line can
+	// come to a few cents more than is left on the payment.
+	if over := total - remaining; over > 0 && over <= int64(len(out)) {
+		last := &out[len(out)-1]
+		last.AmountCents = max(last.AmountCents-over, 0)
+	}
+	return out, nil
+}
+
+// lineDiscountsRecorded reports whether the order's discount is recorded per
+// line. Orders placed before promotions only have the order total, which is
+// pro-rated over the lines by value instead.
+func lineDiscountsRecorded(items []repo.OrderItem) bool {
+	for _, it := range items {
+		if it.DiscountCents != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// listRefunds returns the order's refunds, including failed attempts, with
+// their lines resolved to products.
+func (s *OrderService) listRefunds(ctx context.Context, orderID string, orderItems []repo.OrderItem) ([]Refund, error) {
+	recs, err := s.Refunds.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.Refunds.ItemsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	byRefund := make(map[string][]repo.RefundItem, len(recs))
+	for _, it := range items {
+		byRefund[it.RefundID] = append(byRefund[it.RefundID], it)
+	}
+	out := make([]Refund, len(recs))
+	for i, r := range recs {
+		out[i] = toRefund(r, refundLines(byRefund[r.ID], orderItems))
+	}
+	return out, nil
+}
+
+func refundLines(items []repo.RefundItem, orderItems []repo.OrderItem) []RefundLine {
+	products := make(map[string]string, len(orderItems))
+	for _, it := range orderItems {
+		products[it.ID] = it.ProductID
+	}
+	lines := make([]RefundLine, len(items))
+	for i, it := range items {
+		lines[i] = RefundLine{ProductID: products[it.OrderItemID], Quantity: it.Quantity, AmountCents: it.AmountCents}
+	}
+	return lines
+}
+
+func toRefund(r repo.Refund, lines []RefundLine) Refund {
+	return Refund{
+		ID:          r.ID,
+		OrderID:     r.OrderID,
+		AmountCents: r.AmountCents,
+		Reason:      r.Reason,
+		Operator:    r.Operator,
+		Status:      r.Status,
+		Lines:       lines,
+		CreatedAt:   r.CreatedAt,
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/payments"
+	"kart/internal/repo"
+)
+
+func TestAllocateRefundLines(t *testing.T) {
+	items := []repo.OrderItem{
+		{ID: "i1", ProductID: "10", Quantity: 2, UnitPriceCents: 1000},
+		{ID: "i2", ProductID: "11", Quantity: 1, UnitPriceCents: 500},
+		{ID: "i3", ProductID: "10", Quantity: 1, UnitPriceCents: 1000, RefundedQuantity: 1},
+	}
+	// A latte free with two waffles: the whole discount is on its line.
+	promoted := []repo.OrderItem{
+		{ID: "i1", ProductID: "10", Quantity: 2, UnitPriceCents: 1000},
+		{ID: "i2", ProductID: "11", Quantity: 1, UnitPriceCents: 500, DiscountCents: 500},
+	}
+	type tc struct {
+		name      string
+		items     []repo.OrderItem
+		discount  int64
+		total     int64
+		captured  int64
+		remaining int64
+		lines     []RefundLineInput
+		want      []repo.RefundItem
+		wantErr   error
+	}
+	cases := []tc{
+		{
+			name:      "no discount",
+			remaining: 2500,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 1}},
+			want:      []repo.RefundItem{{OrderItemID: "i1", Quantity: 1, AmountCents: 1000}},
+		},
+		{
+			name:      "discount pro-rated by line value",
+			discount:  500,
+			remaining: 2000,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 2}, {ProductID: "11", Quantity: 1}},
+			want: []repo.RefundItem{
+				{OrderItemID: "i1", Quantity: 2, AmountCents: 2000 - 500*2000/3500},
+				{OrderItemID: "i2", Quantity: 1, AmountCents: 500 - 500*500/3500},
+			},
+		},
+		{
+			// 3400 captured, 972 already refunded for i3; the rounded-down
+			// discount shares of the rest come to 1943+486, one cent too many.
+			name:      "rounding never exceeds remaining",
+			discount:  100,
+			remaining: 3400 - 972,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 2}, {ProductID: "11", Quantity: 1}},
+			want: []repo.RefundItem{
+				{OrderItemID: "i1", Quantity: 2, AmountCents: 1943},
+				{OrderItemID: "i2", Quantity: 1, AmountCents: 485},
+			},
+		},
+		{
+			name:      "line discounts recorded",
+			items:     promoted,
+			discount:  500,
+			remaining: 2000,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 1}, {ProductID: "11", Quantity: 1}},
+			want: []repo.RefundItem{
+				{OrderItemID: "i1", Quantity: 1, AmountCents: 1000},
+				{OrderItemID: "i2", Quantity: 1, AmountCents: 0},
+			},
+		},
+		{
+			// 35.00 order, 21.00 of it paid by gift card: the payment only
+			// refunds its 40% share of the line.
+			name:      "gift card paid part of the order",
+			total:     3500,
+			captured:  1400,
+			remaining: 1400,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 1}},
+			want:      []repo.RefundItem{{OrderItemID: "i1", Quantity: 1, AmountCents: 400}},
+		},
+		{
+			name:      "more than ordered",
+			remaining: 3500,
+			lines:     []RefundLineInput{{ProductID: "10", Quantity: 3}},
+			wantErr:   ErrRefundLine,
+		},
+		{
+			name:      "unknown product",
+			remaining: 3500,
+			lines:     []RefundLineInput{{ProductID: "99", Quantity: 1}},
+			wantErr:   ErrRefundLine,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			orderItems := items
+			if c.items != nil {
+				orderItems = c.items
+			}
+			got, err := allocateRefundLines(repo.Order{DiscountCents: c.discount, TotalCents: c.total}, orderItems, c.lines, c.captured, c.remaining)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestOrderService_Refund(t *testing.T) {
+	type tc struct {
+		name       string
+		in         RefundInput
+		refunded   int64
+		captured   bool
+		setup      func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository)
+		wantErr    error
+		wantAmount int64
+	}
+	order := repo.Order{ID: "o1", Status: StatusCompleted, TotalCents: 2500}
+	items := []repo.OrderItem{
+		{ID: "i1", ProductID: "10", Quantity: 2, UnitPriceCents: 1000},
+		{ID: "i2", ProductID: "11", Quantity: 1, UnitPriceCents: 500},
+	}
+	cases := []tc{
+		{
+			name:     "partial line refund",
+			in:       RefundInput{OrderID: "o1", Lines: []RefundLineInput{{ProductID: "10", Quantity: 1}}, Reason: "cold", Operator: "sam"},
+			captured: true,
+			setup: func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository) {
+				o.On("Items", mock.Anything, "o1").Return(items, nil)
+				rr.On("Create", mock.Anything, mock.MatchedBy(func(r repo.Refund) bool {
+					return r.AmountCents == 1000 && r.Reason == "cold" && r.Operator == "sam" && r.PaymentID == "p1"
+				}), mock.MatchedBy(func(its []repo.RefundItem) bool {
+					return len(its) == 1 && its[0].OrderItemID == "i1" && its[0].Quantity == 1 && its[0].AmountCents == 1000 && its[0].RefundID != ""
+				}), int64(2500)).Return(nil)
+				rr.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(repo.Refund{ID: "r1", AmountCents: 1000, Status: "succeeded"}, nil)
+				rr.On("Succeeded", mock.Anything, "o1").Return(int64(1000), nil)
+				pr.On("Update", mock.Anything, mock.Anything).Return(repo.Payment{}, nil)
+			},
+			wantAmount: 1000,
+		},
+		{
+			name:     "full amount refund marks order refunded",
+			in:       RefundInput{OrderID: "o1", AmountCents: 1500, Reason: "wrong order", Operator: "sam"},
+			refunded: 1000,
+			captured: true,
+			setup: func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository) {
+				rr.On("Create", mock.Anything, mock.Anything, []repo.RefundItem(nil), int64(2500)).Return(nil)
+				rr.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(repo.Refund{ID: "r1", AmountCents: 1500, Status: "succeeded"}, nil)
+				rr.On("Succeeded", mock.Anything, "o1").Return(int64(2500), nil)
+				pr.On("Update", mock.Anything, mock.Anything).Return(repo.Payment{}, nil)
+				o.On("Reverse", mock.Anything, "o1", StatusCompleted, StatusRefunded, sql.NullTime{}).Return(repo.Order{ID: "o1", Status: StatusRefunded}, nil)
+			},
+			wantAmount: 1500,
+		},
+		{
+			// Another refund for the rest completed after this one read the
+			// order; whichever finishes last sees both and marks it refunded.
+			name:     "concurrent refunds complete the order",
+			in:       RefundInput{OrderID: "o1", AmountCents: 1000, Reason: "r", Operator: "sam"},
+			captured: true,
+			setup: func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository) {
+				rr.On("Create", mock.Anything, mock.Anything, []repo.RefundItem(nil), int64(2500)).Return(nil)
+				rr.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(repo.Refund{ID: "r2", AmountCents: 1000, Status: "succeeded"}, nil)
+				rr.On("Succeeded", mock.Anything, "o1").Return(int64(2500), nil)
+				pr.On("Update", mock.Anything, mock.Anything).Return(repo.Payment{}, nil)
+				o.On("Reverse", mock.Anything, "o1", StatusCompleted, StatusRefunded, sql.NullTime{}).Return(repo.Order{ID: "o1", Status: StatusRefunded}, nil)
+			},
+			wantAmount: 1000,
+		},
+		{
+			name:     "concurrent refund already marked the order refunded",
+			in:       RefundInput{OrderID: "o1", AmountCents: 1000, Reason: "r", Operator: "sam"},
+			captured: true,
+			setup: func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository) {
+				rr.On("Create", mock.Anything, mock.Anything, []repo.RefundItem(nil), int64(2500)).Return(nil)
+				rr.On("Complete", mock.Anything, mock.Anything, mock.Anything).Return(repo.Refund{ID: "r2", AmountCents: 1000, Status: "succeeded"}, nil)
+				rr.On("Succeeded", mock.Anything, "o1").Return(int64(2500), nil)
+				pr.On("Update", mock.Anything, mock.Anything).Return(repo.Payment{}, nil)
+				o.On("Reverse", mock.Anything, "o1", StatusCompleted, StatusRefunded, sql.NullTime{}).Return(repo.Order{}, repo.ErrStatusChanged)
+			},
+			wantAmount: 1000,
+		},
+		{
+			name:     "exceeds remaining",
+			in:       RefundInput{OrderID: "o1", AmountCents: 1600, Reason: "r", Operator: "sam"},
+			refunded: 1000,
+			captured: true,
+			wantErr:  ErrRefundExceeded,
+		},
+		{
+			name:     "concurrent refund wins the race",
+			in:       RefundInput{OrderID: "o1", AmountCents: 500, Reason: "r", Operator: "sam"},
+			captured: true,
+			setup: func(o *repomock.OrderRepository, rr *repomock.RefundRepository, pr *repomock.PaymentRepository) {
+				rr.On("Create", mock.Anything, mock.Anything, mock.Anything, int64(2500)).Return(repo.ErrRefundExceedsCaptured)
+			},
+			wantErr: ErrRefundExceeded,
+		},
+		{
+			name:    "not captured",
+			in:      RefundInput{OrderID: "o1", AmountCents: 500, Reason: "r", Operator: "sam"},
+			wantErr: ErrRefundNotAllowed,
+		},
+		{
+			name:    "missing operator",
+			in:      RefundInput{OrderID: "o1", AmountCents: 500, Reason: "r"},
+			wantErr: ErrRefundReason,
+		},
+		{
+			name:    "lines and amount",
+			in:      RefundInput{OrderID: "o1", AmountCents: 500, Lines: []RefundLineInput{{ProductID: "10", Quantity: 1}}, Reason: "r", Operator: "sam"},
+			wantErr: ErrRefundInvalid,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, o, pr, fake := newPaymentOrderService(t)
+			rr := repomock.NewRefundRepository(t)
+			svc.Refunds = rr
+
+			ctx := context.Background()
+			auth, err := fake.Authorize(ctx, payments.AuthorizeRequest{AmountCents: 2500, Token: "tok_visa"})
+			require.NoError(t, err)
+			p := repo.Payment{ID: "p1", OrderID: "o1", AmountCents: 2500, ProviderRef: sql.NullString{String: auth.Reference, Valid: true}}
+			if c.captured {
+				_, err = fake.Capture(ctx, auth.Reference, 2500)
+				require.NoError(t, err)
+				// Replay earlier refunds at the provider so its balance matches the order.
+				if c.refunded > 0 {
+					_, err = fake.Refund(ctx, auth.Reference, c.refunded)
+					require.NoError(t, err)
+				}
+				p.CapturedCents = 2500
+			}
+			ord := order
+			ord.RefundedCents = c.refunded
+			o.On("Get", mock.Anything, "o1").Return(ord, nil).Maybe()
+			pr.On("GetByOrder", mock.Anything, "o1").Return(p, nil).Maybe()
+			if c.setup != nil {
+				c.setup(o, rr, pr)
+			}
+
+			got, err := svc.Refund(ctx, c.in)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				rr.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantAmount, got.AmountCents)
+		})
+	}
+}
+
+func TestOrderService_Refund_ProviderFailureReleases(t *testing.T) {
+	svc, o, pr, _ := newPaymentOrderService(t)
+	rr := repomock.NewRefundRepository(t)
+	svc.Refunds = rr
+
+	o.On("Get", mock.Anything, "o1").Return(repo.Order{ID: "o1", Status: StatusCompleted}, nil)
+	// The provider has never seen this reference, so the refund call fails.
+	pr.On("GetByOrder", mock.Anything, "o1").Return(repo.Payment{
+		ID:            "p1",
+		CapturedCents: 1000,
+		ProviderRef:   sql.NullString{String: "fake_unknown", Valid: true},
+	}, nil)
+	rr.On("Create", mock.Anything, mock.Anything, mock.Anything, int64(1000)).Return(nil)
+	rr.On("Fail", mock.Anything, mock.Anything, payments.ErrUnknownReference.Error()).Return(nil)
+
+	_, err := svc.Refund(context.Background(), RefundInput{OrderID: "o1", AmountCents: 400, Reason: "r", Operator: "sam"})
+	require.ErrorIs(t, err, ErrPaymentUnavailable)
+}
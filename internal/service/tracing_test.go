@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	repomock "kart/internal/mocks/repo"
+	"kart/internal/repo"
+)
+
+func TestOrderService_PlaceOrder_Spans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	p := repomock.NewProductRepository(t)
+	p.On("GetMany", mock.Anything, []string{"10"}).Return(map[string]repo.Product{"10": {ID: "10", PriceCents: 500}}, nil)
+	c := repomock.NewCouponRepository(t)
+	c.On("Get", mock.Anything, "SAVE20AA").Return(repo.Coupon{Code: "SAVE20AA", PresenceMask: 3}, nil)
+	o := repomock.NewOrderRepository(t)
+	o.On("CreateWithItems", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("o1", nil)
+	svc := NewOrderService(p, c, o)
+
+	_, err := svc.PlaceOrder(context.Background(), PlaceOrderInput{CouponCode: "SAVE20AA", Items: []OrderItemInput{{ProductID: "10", Quantity: 1}}})
+	require.NoError(t, err)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		byName[s.Name()] = s
+	}
+	root, ok := byName["OrderService.PlaceOrder"]
+	require.True(t, ok)
+	for _, step := range []string{"validateCoupon", "fetchProductsMap", "CreateWithItems"} {
+		s, ok := byName[step]
+		require.True(t, ok, step)
+		require.Equal(t, root.SpanContext().TraceID(), s.SpanContext().TraceID(), step)
+	}
+}
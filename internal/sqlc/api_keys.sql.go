@@ -0,0 +1,160 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: api_keys.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const expireAPIKey = `-- name: ExpireAPIKey :execrows
+UPDATE api_keys SET expires_at = LEAST(expires_at, $1::timestamp)
+WHERE store_id = $2 AND prefix = $3 AND revoked_at IS NULL
+`
+
+type ExpireAPIKeyParams struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	StoreID   string    `json:"store_id"`
+	Prefix    string    `json:"prefix"`
+}
+
+func (q *Queries) ExpireAPIKey(ctx context.Context, arg ExpireAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireAPIKey, arg.ExpiresAt, arg.StoreID, arg.Prefix)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAPIKeyByPrefix = `-- name: GetAPIKeyByPrefix :one
+SELECT id, store_id, prefix, salt, hash, scopes, label, created_at, expires_at, revoked_at, last_used_at FROM api_keys WHERE prefix = $1
+`
+
+func (q *Queries) GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByPrefix, prefix)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Prefix,
+		&i.Salt,
+		&i.Hash,
+		pq.Array(&i.Scopes),
+		&i.Label,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const insertAPIKey = `-- name: InsertAPIKey :exec
+INSERT INTO api_keys (store_id, id, prefix, salt, hash, scopes, label, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertAPIKeyParams struct {
+	StoreID   string       `json:"store_id"`
+	ID        string       `json:"id"`
+	Prefix    string       `json:"prefix"`
+	Salt      []byte       `json:"salt"`
+	Hash      []byte       `json:"hash"`
+	Scopes    []string     `json:"scopes"`
+	Label     string       `json:"label"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+}
+
+func (q *Queries) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertAPIKey,
+		arg.StoreID,
+		arg.ID,
+		arg.Prefix,
+		arg.Salt,
+		arg.Hash,
+		pq.Array(arg.Scopes),
+		arg.Label,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, store_id, prefix, salt, hash, scopes, label, created_at, expires_at, revoked_at, last_used_at FROM api_keys WHERE store_id = $1 ORDER BY created_at, prefix
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context, storeID string) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeys, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.Prefix,
+			&i.Salt,
+			&i.Hash,
+			pq.Array(&i.Scopes),
+			&i.Label,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = $3
+WHERE store_id = $1 AND prefix = $2 AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	StoreID   string       `json:"store_id"`
+	Prefix    string       `json:"prefix"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAPIKey, arg.StoreID, arg.Prefix, arg.RevokedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = $1
+WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+`
+
+type TouchAPIKeyParams struct {
+	UsedAt      sql.NullTime `json:"used_at"`
+	ID          string       `json:"id"`
+	StaleBefore sql.NullTime `json:"stale_before"`
+}
+
+func (q *Queries) TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error {
+	_, err := q.db.ExecContext(ctx, touchAPIKey, arg.UsedAt, arg.ID, arg.StaleBefore)
+	return err
+}
@@ -0,0 +1,276 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: carts.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const addCartItem = `-- name: AddCartItem :exec
+INSERT INTO cart_items (store_id, cart_id, product_id, quantity)
+SELECT c.store_id, c.id, $1::text, $2::int4
+FROM carts c WHERE c.store_id = $3 AND c.id = $4
+ON CONFLICT (cart_id, product_id)
+DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+`
+
+type AddCartItemParams struct {
+	ProductID string `json:"product_id"`
+	Quantity  int32  `json:"quantity"`
+	StoreID   string `json:"store_id"`
+	CartID    string `json:"cart_id"`
+}
+
+func (q *Queries) AddCartItem(ctx context.Context, arg AddCartItemParams) error {
+	_, err := q.db.ExecContext(ctx, addCartItem,
+		arg.ProductID,
+		arg.Quantity,
+		arg.StoreID,
+		arg.CartID,
+	)
+	return err
+}
+
+const claimCartCheckout = `-- name: ClaimCartCheckout :execrows
+UPDATE carts SET checkout_started_at = $1
+WHERE store_id = $2 AND id = $3 AND order_id IS NULL
+  AND (checkout_started_at IS NULL OR checkout_started_at < $4::timestamp)
+`
+
+type ClaimCartCheckoutParams struct {
+	StartedAt   sql.NullTime `json:"started_at"`
+	StoreID     string       `json:"store_id"`
+	ID          string       `json:"id"`
+	StaleBefore time.Time    `json:"stale_before"`
+}
+
+func (q *Queries) ClaimCartCheckout(ctx context.Context, arg ClaimCartCheckoutParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, claimCartCheckout,
+		arg.StartedAt,
+		arg.StoreID,
+		arg.ID,
+		arg.StaleBefore,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const completeCartCheckout = `-- name: CompleteCartCheckout :exec
+UPDATE carts SET order_id = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2
+`
+
+type CompleteCartCheckoutParams struct {
+	StoreID string         `json:"store_id"`
+	ID      string         `json:"id"`
+	OrderID sql.NullString `json:"order_id"`
+}
+
+func (q *Queries) CompleteCartCheckout(ctx context.Context, arg CompleteCartCheckoutParams) error {
+	_, err := q.db.ExecContext(ctx, completeCartCheckout, arg.StoreID, arg.ID, arg.OrderID)
+	return err
+}
+
+const deleteCartItem = `-- name: DeleteCartItem :execrows
+DELETE FROM cart_items ci
+USING carts c
+WHERE c.id = ci.cart_id AND c.store_id = $1 AND ci.cart_id = $2 AND ci.product_id = $3
+`
+
+type DeleteCartItemParams struct {
+	StoreID   string `json:"store_id"`
+	CartID    string `json:"cart_id"`
+	ProductID string `json:"product_id"`
+}
+
+func (q *Queries) DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCartItem, arg.StoreID, arg.CartID, arg.ProductID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredCarts = `-- name: DeleteExpiredCarts :execrows
+DELETE FROM carts WHERE store_id = $1 AND order_id IS NULL AND expires_at < $2
+`
+
+type DeleteExpiredCartsParams struct {
+	StoreID   string    `json:"store_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) DeleteExpiredCarts(ctx context.Context, arg DeleteExpiredCartsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredCarts, arg.StoreID, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCart = `-- name: GetCart :one
+SELECT id, coupon_code, expires_at, checkout_started_at, order_id, created_at, updated_at, currency, store_id FROM carts WHERE store_id = $1 AND id = $2
+`
+
+type GetCartParams struct {
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) GetCart(ctx context.Context, arg GetCartParams) (Cart, error) {
+	row := q.db.QueryRowContext(ctx, getCart, arg.StoreID, arg.ID)
+	var i Cart
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.ExpiresAt,
+		&i.CheckoutStartedAt,
+		&i.OrderID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Currency,
+		&i.StoreID,
+	)
+	return i, err
+}
+
+const insertCart = `-- name: InsertCart :exec
+INSERT INTO carts (store_id, id, expires_at, currency)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertCartParams struct {
+	StoreID   string    `json:"store_id"`
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Currency  string    `json:"currency"`
+}
+
+func (q *Queries) InsertCart(ctx context.Context, arg InsertCartParams) error {
+	_, err := q.db.ExecContext(ctx, insertCart,
+		arg.StoreID,
+		arg.ID,
+		arg.ExpiresAt,
+		arg.Currency,
+	)
+	return err
+}
+
+const listCartItems = `-- name: ListCartItems :many
+SELECT ci.cart_id, ci.product_id, ci.quantity, ci.created_at, ci.updated_at, ci.store_id FROM cart_items ci
+JOIN carts c ON c.id = ci.cart_id
+WHERE c.store_id = $1 AND ci.cart_id = $2
+ORDER BY ci.created_at, ci.product_id
+`
+
+type ListCartItemsParams struct {
+	StoreID string `json:"store_id"`
+	CartID  string `json:"cart_id"`
+}
+
+func (q *Queries) ListCartItems(ctx context.Context, arg ListCartItemsParams) ([]CartItem, error) {
+	rows, err := q.db.QueryContext(ctx, listCartItems, arg.StoreID, arg.CartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CartItem
+	for rows.Next() {
+		var i CartItem
+		if err := rows.Scan(
+			&i.CartID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const releaseCartCheckout = `-- name: ReleaseCartCheckout :exec
+UPDATE carts SET checkout_started_at = NULL
+WHERE store_id = $1 AND id = $2 AND checkout_started_at = $3 AND order_id IS NULL
+`
+
+type ReleaseCartCheckoutParams struct {
+	StoreID           string       `json:"store_id"`
+	ID                string       `json:"id"`
+	CheckoutStartedAt sql.NullTime `json:"checkout_started_at"`
+}
+
+func (q *Queries) ReleaseCartCheckout(ctx context.Context, arg ReleaseCartCheckoutParams) error {
+	_, err := q.db.ExecContext(ctx, releaseCartCheckout, arg.StoreID, arg.ID, arg.CheckoutStartedAt)
+	return err
+}
+
+const setCartCoupon = `-- name: SetCartCoupon :exec
+UPDATE carts SET coupon_code = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2
+`
+
+type SetCartCouponParams struct {
+	StoreID    string         `json:"store_id"`
+	ID         string         `json:"id"`
+	CouponCode sql.NullString `json:"coupon_code"`
+}
+
+func (q *Queries) SetCartCoupon(ctx context.Context, arg SetCartCouponParams) error {
+	_, err := q.db.ExecContext(ctx, setCartCoupon, arg.StoreID, arg.ID, arg.CouponCode)
+	return err
+}
+
+const setCartItem = `-- name: SetCartItem :exec
+INSERT INTO cart_items (store_id, cart_id, product_id, quantity)
+SELECT c.store_id, c.id, $1::text, $2::int4
+FROM carts c WHERE c.store_id = $3 AND c.id = $4
+ON CONFLICT (cart_id, product_id)
+DO UPDATE SET quantity = EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+`
+
+type SetCartItemParams struct {
+	ProductID string `json:"product_id"`
+	Quantity  int32  `json:"quantity"`
+	StoreID   string `json:"store_id"`
+	CartID    string `json:"cart_id"`
+}
+
+func (q *Queries) SetCartItem(ctx context.Context, arg SetCartItemParams) error {
+	_, err := q.db.ExecContext(ctx, setCartItem,
+		arg.ProductID,
+		arg.Quantity,
+		arg.StoreID,
+		arg.CartID,
+	)
+	return err
+}
+
+const touchCart = `-- name: TouchCart :exec
+UPDATE carts SET expires_at = $3, updated_at = CURRENT_TIMESTAMP WHERE store_id = $1 AND id = $2
+`
+
+type TouchCartParams struct {
+	StoreID   string    `json:"store_id"`
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) TouchCart(ctx context.Context, arg TouchCartParams) error {
+	_, err := q.db.ExecContext(ctx, touchCart, arg.StoreID, arg.ID, arg.ExpiresAt)
+	return err
+}
@@ -7,33 +7,62 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 const getCoupon = `-- name: GetCoupon :one
-SELECT code, presence_mask, created_at, updated_at FROM coupons WHERE code = $1
+SELECT code, presence_mask, created_at, updated_at, store_id, percent_off FROM coupons WHERE store_id = $1 AND code = $2
 `
 
-func (q *Queries) GetCoupon(ctx context.Context, code string) (Coupon, error) {
-	row := q.db.QueryRowContext(ctx, getCoupon, code)
+type GetCouponParams struct {
+	StoreID string `json:"store_id"`
+	Code    string `json:"code"`
+}
+
+func (q *Queries) GetCoupon(ctx context.Context, arg GetCouponParams) (Coupon, error) {
+	row := q.db.QueryRowContext(ctx, getCoupon, arg.StoreID, arg.Code)
 	var i Coupon
 	err := row.Scan(
 		&i.Code,
 		&i.PresenceMask,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StoreID,
+		&i.PercentOff,
 	)
 	return i, err
 }
 
+const releaseRedemption = `-- name: ReleaseRedemption :exec
+DELETE FROM coupon_redemptions WHERE store_id = $1 AND code = $2
+`
+
+type ReleaseRedemptionParams struct {
+	StoreID string `json:"store_id"`
+	Code    string `json:"code"`
+}
+
+func (q *Queries) ReleaseRedemption(ctx context.Context, arg ReleaseRedemptionParams) error {
+	_, err := q.db.ExecContext(ctx, releaseRedemption, arg.StoreID, arg.Code)
+	return err
+}
+
 const tryRedeemSingleUse = `-- name: TryRedeemSingleUse :one
-INSERT INTO coupon_redemptions (code)
-VALUES ($1)
+INSERT INTO coupon_redemptions (store_id, code, customer_id)
+VALUES ($1, $2, $3)
 ON CONFLICT DO NOTHING
 RETURNING code
 `
 
-func (q *Queries) TryRedeemSingleUse(ctx context.Context, code string) (string, error) {
-	row := q.db.QueryRowContext(ctx, tryRedeemSingleUse, code)
+type TryRedeemSingleUseParams struct {
+	StoreID    string         `json:"store_id"`
+	Code       string         `json:"code"`
+	CustomerID sql.NullString `json:"customer_id"`
+}
+
+func (q *Queries) TryRedeemSingleUse(ctx context.Context, arg TryRedeemSingleUseParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, tryRedeemSingleUse, arg.StoreID, arg.Code, arg.CustomerID)
+	var code string
 	err := row.Scan(&code)
 	return code, err
 }
@@ -0,0 +1,38 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: customers.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const upsertCustomer = `-- name: UpsertCustomer :exec
+INSERT INTO customers (store_id, id, email, phone)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (store_id, id) DO UPDATE
+SET email = COALESCE(EXCLUDED.email, customers.email),
+    phone = COALESCE(EXCLUDED.phone, customers.phone),
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertCustomerParams struct {
+	StoreID string         `json:"store_id"`
+	ID      string         `json:"id"`
+	Email   sql.NullString `json:"email"`
+	Phone   sql.NullString `json:"phone"`
+}
+
+// Contact details are only overwritten by ones that were given.
+func (q *Queries) UpsertCustomer(ctx context.Context, arg UpsertCustomerParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCustomer,
+		arg.StoreID,
+		arg.ID,
+		arg.Email,
+		arg.Phone,
+	)
+	return err
+}
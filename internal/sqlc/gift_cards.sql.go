@@ -0,0 +1,212 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: gift_cards.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const debitGiftCard = `-- name: DebitGiftCard :execrows
+UPDATE gift_cards SET balance_cents = balance_cents - $1::int8
+WHERE store_id = $2 AND id = $3
+  AND disabled_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+  AND balance_cents >= $1::int8
+`
+
+type DebitGiftCardParams struct {
+	AmountCents int64  `json:"amount_cents"`
+	StoreID     string `json:"store_id"`
+	ID          string `json:"id"`
+}
+
+// Takes amount off the card unless it is disabled, expired or the balance
+// falls short.
+func (q *Queries) DebitGiftCard(ctx context.Context, arg DebitGiftCardParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, debitGiftCard, arg.AmountCents, arg.StoreID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getGiftCard = `-- name: GetGiftCard :one
+SELECT id, store_id, code_hash, last4, currency, initial_cents, balance_cents, created_at, expires_at, disabled_at FROM gift_cards WHERE store_id = $1 AND id = $2
+`
+
+type GetGiftCardParams struct {
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) GetGiftCard(ctx context.Context, arg GetGiftCardParams) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, getGiftCard, arg.StoreID, arg.ID)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.CodeHash,
+		&i.Last4,
+		&i.Currency,
+		&i.InitialCents,
+		&i.BalanceCents,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getGiftCardByCode = `-- name: GetGiftCardByCode :one
+SELECT id, store_id, code_hash, last4, currency, initial_cents, balance_cents, created_at, expires_at, disabled_at FROM gift_cards WHERE store_id = $1 AND code_hash = $2
+`
+
+type GetGiftCardByCodeParams struct {
+	StoreID  string `json:"store_id"`
+	CodeHash []byte `json:"code_hash"`
+}
+
+func (q *Queries) GetGiftCardByCode(ctx context.Context, arg GetGiftCardByCodeParams) (GiftCard, error) {
+	row := q.db.QueryRowContext(ctx, getGiftCardByCode, arg.StoreID, arg.CodeHash)
+	var i GiftCard
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.CodeHash,
+		&i.Last4,
+		&i.Currency,
+		&i.InitialCents,
+		&i.BalanceCents,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const insertGiftCard = `-- name: InsertGiftCard :exec
+INSERT INTO gift_cards (id, store_id, code_hash, last4, currency, initial_cents, balance_cents, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $6, $7)
+`
+
+type InsertGiftCardParams struct {
+	ID           string       `json:"id"`
+	StoreID      string       `json:"store_id"`
+	CodeHash     []byte       `json:"code_hash"`
+	Last4        string       `json:"last4"`
+	Currency     string       `json:"currency"`
+	InitialCents int64        `json:"initial_cents"`
+	ExpiresAt    sql.NullTime `json:"expires_at"`
+}
+
+func (q *Queries) InsertGiftCard(ctx context.Context, arg InsertGiftCardParams) error {
+	_, err := q.db.ExecContext(ctx, insertGiftCard,
+		arg.ID,
+		arg.StoreID,
+		arg.CodeHash,
+		arg.Last4,
+		arg.Currency,
+		arg.InitialCents,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const insertGiftCardTransaction = `-- name: InsertGiftCardTransaction :exec
+INSERT INTO gift_card_transactions (id, store_id, gift_card_id, order_id, kind, amount_cents)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type InsertGiftCardTransactionParams struct {
+	ID          string         `json:"id"`
+	StoreID     string         `json:"store_id"`
+	GiftCardID  string         `json:"gift_card_id"`
+	OrderID     sql.NullString `json:"order_id"`
+	Kind        string         `json:"kind"`
+	AmountCents int64          `json:"amount_cents"`
+}
+
+func (q *Queries) InsertGiftCardTransaction(ctx context.Context, arg InsertGiftCardTransactionParams) error {
+	_, err := q.db.ExecContext(ctx, insertGiftCardTransaction,
+		arg.ID,
+		arg.StoreID,
+		arg.GiftCardID,
+		arg.OrderID,
+		arg.Kind,
+		arg.AmountCents,
+	)
+	return err
+}
+
+const listGiftCardTransactions = `-- name: ListGiftCardTransactions :many
+SELECT id, store_id, gift_card_id, order_id, kind, amount_cents, created_at FROM gift_card_transactions
+WHERE store_id = $1 AND gift_card_id = $2
+ORDER BY created_at, id
+`
+
+type ListGiftCardTransactionsParams struct {
+	StoreID    string `json:"store_id"`
+	GiftCardID string `json:"gift_card_id"`
+}
+
+func (q *Queries) ListGiftCardTransactions(ctx context.Context, arg ListGiftCardTransactionsParams) ([]GiftCardTransaction, error) {
+	rows, err := q.db.QueryContext(ctx, listGiftCardTransactions, arg.StoreID, arg.GiftCardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GiftCardTransaction
+	for rows.Next() {
+		var i GiftCardTransaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.GiftCardID,
+			&i.OrderID,
+			&i.Kind,
+			&i.AmountCents,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreOrderGiftCards = `-- name: RestoreOrderGiftCards :execrows
+WITH r AS (
+  INSERT INTO gift_card_transactions (id, store_id, gift_card_id, order_id, kind, amount_cents)
+  SELECT gen_random_uuid()::text, t.store_id, t.gift_card_id, t.order_id, 'restore', -t.amount_cents
+  FROM gift_card_transactions t
+  WHERE t.store_id = $1 AND t.order_id = $2 AND t.kind = 'redeem'
+  ON CONFLICT DO NOTHING
+  RETURNING store_id, gift_card_id, amount_cents
+)
+UPDATE gift_cards g SET balance_cents = g.balance_cents + r.amount_cents
+FROM r WHERE g.store_id = r.store_id AND g.id = r.gift_card_id
+`
+
+type RestoreOrderGiftCardsParams struct {
+	StoreID string         `json:"store_id"`
+	OrderID sql.NullString `json:"order_id"`
+}
+
+// Credits back what the order took from each gift card, skipping cards
+// already restored for it.
+func (q *Queries) RestoreOrderGiftCards(ctx context.Context, arg RestoreOrderGiftCardsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreOrderGiftCards, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,232 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: loyalty.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const debitLoyaltyBalance = `-- name: DebitLoyaltyBalance :execrows
+UPDATE loyalty_balances
+SET balance = balance - $1::int8, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $2 AND customer_id = $3 AND balance >= $1::int8
+`
+
+type DebitLoyaltyBalanceParams struct {
+	Points     int64  `json:"points"`
+	StoreID    string `json:"store_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+// Takes points off the balance unless that would make it negative.
+func (q *Queries) DebitLoyaltyBalance(ctx context.Context, arg DebitLoyaltyBalanceParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, debitLoyaltyBalance, arg.Points, arg.StoreID, arg.CustomerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getLoyaltyBalance = `-- name: GetLoyaltyBalance :one
+SELECT balance FROM loyalty_balances WHERE store_id = $1 AND customer_id = $2
+`
+
+type GetLoyaltyBalanceParams struct {
+	StoreID    string `json:"store_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+func (q *Queries) GetLoyaltyBalance(ctx context.Context, arg GetLoyaltyBalanceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getLoyaltyBalance, arg.StoreID, arg.CustomerID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const insertLoyaltyEntry = `-- name: InsertLoyaltyEntry :exec
+INSERT INTO loyalty_entries (id, store_id, customer_id, order_id, kind, points, key)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertLoyaltyEntryParams struct {
+	ID         string         `json:"id"`
+	StoreID    string         `json:"store_id"`
+	CustomerID string         `json:"customer_id"`
+	OrderID    sql.NullString `json:"order_id"`
+	Kind       string         `json:"kind"`
+	Points     int64          `json:"points"`
+	Key        string         `json:"key"`
+}
+
+func (q *Queries) InsertLoyaltyEntry(ctx context.Context, arg InsertLoyaltyEntryParams) error {
+	_, err := q.db.ExecContext(ctx, insertLoyaltyEntry,
+		arg.ID,
+		arg.StoreID,
+		arg.CustomerID,
+		arg.OrderID,
+		arg.Kind,
+		arg.Points,
+		arg.Key,
+	)
+	return err
+}
+
+const listLoyaltyEntries = `-- name: ListLoyaltyEntries :many
+SELECT id, store_id, customer_id, order_id, kind, points, key, created_at FROM loyalty_entries
+WHERE store_id = $1 AND customer_id = $2
+ORDER BY created_at DESC, id
+LIMIT $3
+`
+
+type ListLoyaltyEntriesParams struct {
+	StoreID    string `json:"store_id"`
+	CustomerID string `json:"customer_id"`
+	Limit      int32  `json:"limit"`
+}
+
+func (q *Queries) ListLoyaltyEntries(ctx context.Context, arg ListLoyaltyEntriesParams) ([]LoyaltyEntry, error) {
+	rows, err := q.db.QueryContext(ctx, listLoyaltyEntries, arg.StoreID, arg.CustomerID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoyaltyEntry
+	for rows.Next() {
+		var i LoyaltyEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.CustomerID,
+			&i.OrderID,
+			&i.Kind,
+			&i.Points,
+			&i.Key,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoyaltyRules = `-- name: ListLoyaltyRules :many
+SELECT store_id, category, points_per_unit FROM loyalty_rules WHERE store_id = $1 ORDER BY category
+`
+
+func (q *Queries) ListLoyaltyRules(ctx context.Context, storeID string) ([]LoyaltyRule, error) {
+	rows, err := q.db.QueryContext(ctx, listLoyaltyRules, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoyaltyRule
+	for rows.Next() {
+		var i LoyaltyRule
+		if err := rows.Scan(&i.StoreID, &i.Category, &i.PointsPerUnit); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderLoyaltyEntries = `-- name: ListOrderLoyaltyEntries :many
+SELECT id, store_id, customer_id, order_id, kind, points, key, created_at FROM loyalty_entries
+WHERE store_id = $1 AND order_id = $2
+ORDER BY created_at, id
+`
+
+type ListOrderLoyaltyEntriesParams struct {
+	StoreID string         `json:"store_id"`
+	OrderID sql.NullString `json:"order_id"`
+}
+
+func (q *Queries) ListOrderLoyaltyEntries(ctx context.Context, arg ListOrderLoyaltyEntriesParams) ([]LoyaltyEntry, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderLoyaltyEntries, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoyaltyEntry
+	for rows.Next() {
+		var i LoyaltyEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.StoreID,
+			&i.CustomerID,
+			&i.OrderID,
+			&i.Kind,
+			&i.Points,
+			&i.Key,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const postLoyaltyEntry = `-- name: PostLoyaltyEntry :execrows
+WITH e AS (
+  INSERT INTO loyalty_entries (id, store_id, customer_id, order_id, kind, points, key)
+  VALUES ($1, $2, $3, $4, $5, $6, $7)
+  ON CONFLICT (store_id, key) DO NOTHING
+  RETURNING store_id, customer_id, points
+)
+INSERT INTO loyalty_balances (store_id, customer_id, balance)
+SELECT store_id, customer_id, points FROM e
+ON CONFLICT (store_id, customer_id) DO UPDATE
+SET balance = loyalty_balances.balance + EXCLUDED.balance, updated_at = CURRENT_TIMESTAMP
+`
+
+type PostLoyaltyEntryParams struct {
+	ID         string         `json:"id"`
+	StoreID    string         `json:"store_id"`
+	CustomerID string         `json:"customer_id"`
+	OrderID    sql.NullString `json:"order_id"`
+	Kind       string         `json:"kind"`
+	Points     int64          `json:"points"`
+	Key        string         `json:"key"`
+}
+
+// Appends the entry and applies it to the balance, or does nothing if an
+// entry with the same key was posted before.
+func (q *Queries) PostLoyaltyEntry(ctx context.Context, arg PostLoyaltyEntryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, postLoyaltyEntry,
+		arg.ID,
+		arg.StoreID,
+		arg.CustomerID,
+		arg.OrderID,
+		arg.Kind,
+		arg.Points,
+		arg.Key,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
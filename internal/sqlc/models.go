@@ -6,42 +6,283 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type ApiKey struct {
+	ID         string       `json:"id"`
+	StoreID    string       `json:"store_id"`
+	Prefix     string       `json:"prefix"`
+	Salt       []byte       `json:"salt"`
+	Hash       []byte       `json:"hash"`
+	Scopes     []string     `json:"scopes"`
+	Label      string       `json:"label"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+}
+
+type Cart struct {
+	ID                string         `json:"id"`
+	CouponCode        sql.NullString `json:"coupon_code"`
+	ExpiresAt         time.Time      `json:"expires_at"`
+	CheckoutStartedAt sql.NullTime   `json:"checkout_started_at"`
+	OrderID           sql.NullString `json:"order_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	Currency          string         `json:"currency"`
+	StoreID           string         `json:"store_id"`
+}
+
+type CartItem struct {
+	CartID    string    `json:"cart_id"`
+	ProductID string    `json:"product_id"`
+	Quantity  int32     `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	StoreID   string    `json:"store_id"`
+}
+
+type CategoryTaxClass struct {
+	Category string `json:"category"`
+	TaxClass string `json:"tax_class"`
+	StoreID  string `json:"store_id"`
+}
+
 type Coupon struct {
 	Code         string    `json:"code"`
 	PresenceMask uint8     `json:"presence_mask"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	StoreID      string    `json:"store_id"`
+	PercentOff   int32     `json:"percent_off"`
 }
 
 type CouponRedemption struct {
-	Code       string    `json:"code"`
-	RedeemedAt time.Time `json:"redeemed_at"`
+	Code       string         `json:"code"`
+	RedeemedAt time.Time      `json:"redeemed_at"`
+	StoreID    string         `json:"store_id"`
+	CustomerID sql.NullString `json:"customer_id"`
+}
+
+type Customer struct {
+	StoreID   string         `json:"store_id"`
+	ID        string         `json:"id"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type GiftCard struct {
+	ID           string       `json:"id"`
+	StoreID      string       `json:"store_id"`
+	CodeHash     []byte       `json:"code_hash"`
+	Last4        string       `json:"last4"`
+	Currency     string       `json:"currency"`
+	InitialCents int64        `json:"initial_cents"`
+	BalanceCents int64        `json:"balance_cents"`
+	CreatedAt    time.Time    `json:"created_at"`
+	ExpiresAt    sql.NullTime `json:"expires_at"`
+	DisabledAt   sql.NullTime `json:"disabled_at"`
+}
+
+type GiftCardTransaction struct {
+	ID          string         `json:"id"`
+	StoreID     string         `json:"store_id"`
+	GiftCardID  string         `json:"gift_card_id"`
+	OrderID     sql.NullString `json:"order_id"`
+	Kind        string         `json:"kind"`
+	AmountCents int64          `json:"amount_cents"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type LoyaltyBalance struct {
+	StoreID    string    `json:"store_id"`
+	CustomerID string    `json:"customer_id"`
+	Balance    int64     `json:"balance"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type LoyaltyEntry struct {
+	ID         string         `json:"id"`
+	StoreID    string         `json:"store_id"`
+	CustomerID string         `json:"customer_id"`
+	OrderID    sql.NullString `json:"order_id"`
+	Kind       string         `json:"kind"`
+	Points     int64          `json:"points"`
+	Key        string         `json:"key"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type LoyaltyRule struct {
+	StoreID       string `json:"store_id"`
+	Category      string `json:"category"`
+	PointsPerUnit int32  `json:"points_per_unit"`
 }
 
 type Order struct {
+	ID             string         `json:"id"`
+	CouponCode     sql.NullString `json:"coupon_code"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	Status         string         `json:"status"`
+	EtaAt          sql.NullTime   `json:"eta_at"`
+	TotalCents     int64          `json:"total_cents"`
+	DiscountCents  int64          `json:"discount_cents"`
+	RefundedCents  int64          `json:"refunded_cents"`
+	SubtotalCents  int64          `json:"subtotal_cents"`
+	TaxCents       int64          `json:"tax_cents"`
+	TaxInclusive   bool           `json:"tax_inclusive"`
+	Currency       string         `json:"currency"`
+	PriceListID    sql.NullString `json:"price_list_id"`
+	StoreID        string         `json:"store_id"`
+	CustomerID     sql.NullString `json:"customer_id"`
+	ContactEmail   sql.NullString `json:"contact_email"`
+	ContactPhone   sql.NullString `json:"contact_phone"`
+	PointsRedeemed int64          `json:"points_redeemed"`
+	PointsCents    int64          `json:"points_cents"`
+	GiftCardCents  int64          `json:"gift_card_cents"`
+	CartID         sql.NullString `json:"cart_id"`
+}
+
+type OrderItem struct {
+	ID               string         `json:"id"`
+	OrderID          string         `json:"order_id"`
+	ProductID        string         `json:"product_id"`
+	Quantity         int32          `json:"quantity"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	UnitPriceCents   int64          `json:"unit_price_cents"`
+	RefundedQuantity int32          `json:"refunded_quantity"`
+	TaxClass         sql.NullString `json:"tax_class"`
+	TaxCents         int64          `json:"tax_cents"`
+	StoreID          string         `json:"store_id"`
+	DiscountCents    int64          `json:"discount_cents"`
+}
+
+type OrderTax struct {
+	OrderID         string `json:"order_id"`
+	TaxClass        string `json:"tax_class"`
+	RateBasisPoints int32  `json:"rate_basis_points"`
+	TaxableCents    int64  `json:"taxable_cents"`
+	TaxCents        int64  `json:"tax_cents"`
+}
+
+type Payment struct {
+	ID            string         `json:"id"`
+	OrderID       string         `json:"order_id"`
+	Provider      string         `json:"provider"`
+	ProviderRef   sql.NullString `json:"provider_ref"`
+	Status        string         `json:"status"`
+	AmountCents   int64          `json:"amount_cents"`
+	CapturedCents int64          `json:"captured_cents"`
+	Currency      string         `json:"currency"`
+	FailureReason sql.NullString `json:"failure_reason"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	StoreID       string         `json:"store_id"`
+}
+
+type PriceAdjustment struct {
+	StoreID    string         `json:"store_id"`
 	ID         string         `json:"id"`
-	CouponCode sql.NullString `json:"coupon_code"`
+	Name       string         `json:"name"`
+	ProductID  sql.NullString `json:"product_id"`
+	Category   sql.NullString `json:"category"`
+	PercentOff int32          `json:"percent_off"`
+	Weekdays   int16          `json:"weekdays"`
+	LocalStart time.Time      `json:"local_start"`
+	LocalEnd   time.Time      `json:"local_end"`
+	StartsAt   sql.NullTime   `json:"starts_at"`
+	EndsAt     sql.NullTime   `json:"ends_at"`
+}
+
+type PriceList struct {
+	ID             string    `json:"id"`
+	StoreID        string    `json:"store_id"`
+	Currency       string    `json:"currency"`
+	IsDefault      bool      `json:"is_default"`
+	UsesBasePrices bool      `json:"uses_base_prices"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PriceListPrice struct {
+	PriceListID string `json:"price_list_id"`
+	ProductID   string `json:"product_id"`
+	PriceCents  int32  `json:"price_cents"`
+	StoreID     string `json:"store_id"`
+}
+
+type Product struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Category   string         `json:"category"`
+	PriceCents int32          `json:"price_cents"`
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
+	TaxClass   sql.NullString `json:"tax_class"`
+	StoreID    string         `json:"store_id"`
+	Slug       sql.NullString `json:"slug"`
 }
 
-type OrderItem struct {
+type Promotion struct {
+	StoreID   string          `json:"store_id"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Priority  int32           `json:"priority"`
+	Exclusive bool            `json:"exclusive"`
+	Rule      json.RawMessage `json:"rule"`
+	StartsAt  sql.NullTime    `json:"starts_at"`
+	EndsAt    sql.NullTime    `json:"ends_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	Rate      float64   `json:"rate"`
+	Burst     int32     `json:"burst"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Refund struct {
+	ID            string         `json:"id"`
+	OrderID       string         `json:"order_id"`
+	PaymentID     string         `json:"payment_id"`
+	AmountCents   int64          `json:"amount_cents"`
+	Reason        string         `json:"reason"`
+	Operator      string         `json:"operator"`
+	Status        string         `json:"status"`
+	ProviderRef   sql.NullString `json:"provider_ref"`
+	FailureReason sql.NullString `json:"failure_reason"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	StoreID       string         `json:"store_id"`
+}
+
+type RefundItem struct {
+	RefundID    string `json:"refund_id"`
+	OrderItemID string `json:"order_item_id"`
+	Quantity    int32  `json:"quantity"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type Store struct {
 	ID        string    `json:"id"`
-	OrderID   string    `json:"order_id"`
-	ProductID string    `json:"product_id"`
-	Quantity  int32     `json:"quantity"`
+	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Timezone  string    `json:"timezone"`
 }
 
-type Product struct {
-	ID         string    `json:"id"`
-	Name       string    `json:"name"`
-	Category   string    `json:"category"`
-	PriceCents int32     `json:"price_cents"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+type TaxClass struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type TaxRate struct {
+	StoreID         string `json:"store_id"`
+	TaxClass        string `json:"tax_class"`
+	RateBasisPoints int32  `json:"rate_basis_points"`
 }
@@ -12,61 +12,432 @@ import (
 	"github.com/lib/pq"
 )
 
-const insertOrder = `-- name: InsertOrder :exec
-INSERT INTO orders (id, coupon_code)
-VALUES ($1, $2)
+const addOrderItemRefunded = `-- name: AddOrderItemRefunded :execrows
+UPDATE order_items i
+SET refunded_quantity = i.refunded_quantity + $1::int4, updated_at = CURRENT_TIMESTAMP
+FROM orders o
+WHERE o.id = i.order_id AND o.store_id = $2 AND i.id = $3
+  AND i.refunded_quantity + $1::int4 BETWEEN 0 AND i.quantity
 `
 
-type InsertOrderParams struct {
-	ID         string         `json:"id"`
-	CouponCode sql.NullString `json:"coupon_code"`
+type AddOrderItemRefundedParams struct {
+	Delta   int32  `json:"delta"`
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
 }
 
-func (q *Queries) InsertOrder(ctx context.Context, arg InsertOrderParams) error {
-	_, err := q.db.ExecContext(ctx, insertOrder, arg.ID, arg.CouponCode)
+func (q *Queries) AddOrderItemRefunded(ctx context.Context, arg AddOrderItemRefundedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addOrderItemRefunded, arg.Delta, arg.StoreID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const addOrderRefunded = `-- name: AddOrderRefunded :exec
+UPDATE orders
+SET refunded_cents = refunded_cents + $1::int8, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $2 AND id = $3
+`
+
+type AddOrderRefundedParams struct {
+	AmountCents int64  `json:"amount_cents"`
+	StoreID     string `json:"store_id"`
+	ID          string `json:"id"`
+}
+
+func (q *Queries) AddOrderRefunded(ctx context.Context, arg AddOrderRefundedParams) error {
+	_, err := q.db.ExecContext(ctx, addOrderRefunded, arg.AmountCents, arg.StoreID, arg.ID)
 	return err
 }
 
-const insertOrderItem = `-- name: InsertOrderItem :exec
-INSERT INTO order_items (id, order_id, product_id, quantity)
-VALUES ($1, $2, $3, $4)
+const getOrder = `-- name: GetOrder :one
+SELECT id, coupon_code, created_at, updated_at, status, eta_at, total_cents, discount_cents, refunded_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, store_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id FROM orders WHERE store_id = $1 AND id = $2
+`
+
+type GetOrderParams struct {
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, getOrder, arg.StoreID, arg.ID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.EtaAt,
+		&i.TotalCents,
+		&i.DiscountCents,
+		&i.RefundedCents,
+		&i.SubtotalCents,
+		&i.TaxCents,
+		&i.TaxInclusive,
+		&i.Currency,
+		&i.PriceListID,
+		&i.StoreID,
+		&i.CustomerID,
+		&i.ContactEmail,
+		&i.ContactPhone,
+		&i.PointsRedeemed,
+		&i.PointsCents,
+		&i.GiftCardCents,
+		&i.CartID,
+	)
+	return i, err
+}
+
+const insertOrder = `-- name: InsertOrder :exec
+INSERT INTO orders (store_id, id, coupon_code, status, total_cents, discount_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id,
+  customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 `
 
-type InsertOrderItemParams struct {
-	ID        string `json:"id"`
-	OrderID   string `json:"order_id"`
-	ProductID string `json:"product_id"`
-	Quantity  int32  `json:"quantity"`
+type InsertOrderParams struct {
+	StoreID        string         `json:"store_id"`
+	ID             string         `json:"id"`
+	CouponCode     sql.NullString `json:"coupon_code"`
+	Status         string         `json:"status"`
+	TotalCents     int64          `json:"total_cents"`
+	DiscountCents  int64          `json:"discount_cents"`
+	SubtotalCents  int64          `json:"subtotal_cents"`
+	TaxCents       int64          `json:"tax_cents"`
+	TaxInclusive   bool           `json:"tax_inclusive"`
+	Currency       string         `json:"currency"`
+	PriceListID    sql.NullString `json:"price_list_id"`
+	CustomerID     sql.NullString `json:"customer_id"`
+	ContactEmail   sql.NullString `json:"contact_email"`
+	ContactPhone   sql.NullString `json:"contact_phone"`
+	PointsRedeemed int64          `json:"points_redeemed"`
+	PointsCents    int64          `json:"points_cents"`
+	GiftCardCents  int64          `json:"gift_card_cents"`
+	CartID         sql.NullString `json:"cart_id"`
 }
 
-func (q *Queries) InsertOrderItem(ctx context.Context, arg InsertOrderItemParams) error {
-	_, err := q.db.ExecContext(ctx, insertOrderItem,
+func (q *Queries) InsertOrder(ctx context.Context, arg InsertOrderParams) error {
+	_, err := q.db.ExecContext(ctx, insertOrder,
+		arg.StoreID,
 		arg.ID,
-		arg.OrderID,
-		arg.ProductID,
-		arg.Quantity,
+		arg.CouponCode,
+		arg.Status,
+		arg.TotalCents,
+		arg.DiscountCents,
+		arg.SubtotalCents,
+		arg.TaxCents,
+		arg.TaxInclusive,
+		arg.Currency,
+		arg.PriceListID,
+		arg.CustomerID,
+		arg.ContactEmail,
+		arg.ContactPhone,
+		arg.PointsRedeemed,
+		arg.PointsCents,
+		arg.GiftCardCents,
+		arg.CartID,
 	)
 	return err
 }
 
 const insertOrderItems = `-- name: InsertOrderItems :exec
-INSERT INTO order_items (id, order_id, product_id, quantity)
-SELECT UNNEST($1::text[]), UNNEST($2::text[]), UNNEST($3::text[]), UNNEST($4::int4[])
+INSERT INTO order_items (store_id, id, order_id, product_id, quantity, unit_price_cents, tax_class, tax_cents, discount_cents)
+SELECT $1::text, UNNEST($2::text[]), $3::text, UNNEST($4::text[]),
+  UNNEST($5::int4[]), UNNEST($6::int8[]),
+  UNNEST($7::text[]), UNNEST($8::int8[]),
+  UNNEST($9::int8[])
+WHERE EXISTS (SELECT 1 FROM orders o WHERE o.store_id = $1 AND o.id = $3)
 `
 
 type InsertOrderItemsParams struct {
-	Column1 []string `json:"column_1"`
-	Column2 []string `json:"column_2"`
-	Column3 []string `json:"column_3"`
-	Column4 []int32  `json:"column_4"`
+	StoreID       string   `json:"store_id"`
+	Ids           []string `json:"ids"`
+	OrderID       string   `json:"order_id"`
+	ProductIds    []string `json:"product_ids"`
+	Quantities    []int32  `json:"quantities"`
+	UnitPrices    []int64  `json:"unit_prices"`
+	TaxClasses    []string `json:"tax_classes"`
+	TaxCents      []int64  `json:"tax_cents"`
+	DiscountCents []int64  `json:"discount_cents"`
 }
 
 func (q *Queries) InsertOrderItems(ctx context.Context, arg InsertOrderItemsParams) error {
 	_, err := q.db.ExecContext(ctx, insertOrderItems,
-		pq.Array(arg.Column1),
-		pq.Array(arg.Column2),
-		pq.Array(arg.Column3),
-		pq.Array(arg.Column4),
+		arg.StoreID,
+		pq.Array(arg.Ids),
+		arg.OrderID,
+		pq.Array(arg.ProductIds),
+		pq.Array(arg.Quantities),
+		pq.Array(arg.UnitPrices),
+		pq.Array(arg.TaxClasses),
+		pq.Array(arg.TaxCents),
+		pq.Array(arg.DiscountCents),
+	)
+	return err
+}
+
+const insertOrderTax = `-- name: InsertOrderTax :exec
+INSERT INTO order_taxes (order_id, tax_class, rate_basis_points, taxable_cents, tax_cents)
+SELECT o.id, $1::text, $2::int4, $3::int8, $4::int8
+FROM orders o WHERE o.store_id = $5 AND o.id = $6
+`
+
+type InsertOrderTaxParams struct {
+	TaxClass        string `json:"tax_class"`
+	RateBasisPoints int32  `json:"rate_basis_points"`
+	TaxableCents    int64  `json:"taxable_cents"`
+	TaxCents        int64  `json:"tax_cents"`
+	StoreID         string `json:"store_id"`
+	OrderID         string `json:"order_id"`
+}
+
+func (q *Queries) InsertOrderTax(ctx context.Context, arg InsertOrderTaxParams) error {
+	_, err := q.db.ExecContext(ctx, insertOrderTax,
+		arg.TaxClass,
+		arg.RateBasisPoints,
+		arg.TaxableCents,
+		arg.TaxCents,
+		arg.StoreID,
+		arg.OrderID,
 	)
 	return err
 }
+
+const listCustomerOrders = `-- name: ListCustomerOrders :many
+SELECT id, coupon_code, created_at, updated_at, status, eta_at, total_cents, discount_cents, refunded_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, store_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id FROM orders
+WHERE store_id = $1 AND customer_id = $2
+ORDER BY created_at DESC, id
+LIMIT $3
+`
+
+type ListCustomerOrdersParams struct {
+	StoreID    string         `json:"store_id"`
+	CustomerID sql.NullString `json:"customer_id"`
+	Limit      int32          `json:"limit"`
+}
+
+func (q *Queries) ListCustomerOrders(ctx context.Context, arg ListCustomerOrdersParams) ([]Order, error) {
+	rows, err := q.db.QueryContext(ctx, listCustomerOrders, arg.StoreID, arg.CustomerID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.CouponCode,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.EtaAt,
+			&i.TotalCents,
+			&i.DiscountCents,
+			&i.RefundedCents,
+			&i.SubtotalCents,
+			&i.TaxCents,
+			&i.TaxInclusive,
+			&i.Currency,
+			&i.PriceListID,
+			&i.StoreID,
+			&i.CustomerID,
+			&i.ContactEmail,
+			&i.ContactPhone,
+			&i.PointsRedeemed,
+			&i.PointsCents,
+			&i.GiftCardCents,
+			&i.CartID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderItems = `-- name: ListOrderItems :many
+SELECT i.id, i.order_id, i.product_id, i.quantity, i.created_at, i.updated_at, i.unit_price_cents, i.refunded_quantity, i.tax_class, i.tax_cents, i.store_id, i.discount_cents FROM order_items i
+JOIN orders o ON o.id = i.order_id
+WHERE o.store_id = $1 AND i.order_id = $2
+ORDER BY i.created_at, i.id
+`
+
+type ListOrderItemsParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) ListOrderItems(ctx context.Context, arg ListOrderItemsParams) ([]OrderItem, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderItems, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderItem
+	for rows.Next() {
+		var i OrderItem
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.ProductID,
+			&i.Quantity,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UnitPriceCents,
+			&i.RefundedQuantity,
+			&i.TaxClass,
+			&i.TaxCents,
+			&i.StoreID,
+			&i.DiscountCents,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrderTaxes = `-- name: ListOrderTaxes :many
+SELECT t.order_id, t.tax_class, t.rate_basis_points, t.taxable_cents, t.tax_cents FROM order_taxes t
+JOIN orders o ON o.id = t.order_id
+WHERE o.store_id = $1 AND t.order_id = $2
+ORDER BY t.tax_class
+`
+
+type ListOrderTaxesParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) ListOrderTaxes(ctx context.Context, arg ListOrderTaxesParams) ([]OrderTax, error) {
+	rows, err := q.db.QueryContext(ctx, listOrderTaxes, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderTax
+	for rows.Next() {
+		var i OrderTax
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.TaxClass,
+			&i.RateBasisPoints,
+			&i.TaxableCents,
+			&i.TaxCents,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrder = `-- name: LockOrder :one
+SELECT id, coupon_code, created_at, updated_at, status, eta_at, total_cents, discount_cents, refunded_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, store_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id FROM orders WHERE store_id = $1 AND id = $2 FOR UPDATE
+`
+
+type LockOrderParams struct {
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) LockOrder(ctx context.Context, arg LockOrderParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, lockOrder, arg.StoreID, arg.ID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.EtaAt,
+		&i.TotalCents,
+		&i.DiscountCents,
+		&i.RefundedCents,
+		&i.SubtotalCents,
+		&i.TaxCents,
+		&i.TaxInclusive,
+		&i.Currency,
+		&i.PriceListID,
+		&i.StoreID,
+		&i.CustomerID,
+		&i.ContactEmail,
+		&i.ContactPhone,
+		&i.PointsRedeemed,
+		&i.PointsCents,
+		&i.GiftCardCents,
+		&i.CartID,
+	)
+	return i, err
+}
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :one
+UPDATE orders
+SET status = $1, eta_at = $2, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $3 AND id = $4 AND status = $5
+RETURNING id, coupon_code, created_at, updated_at, status, eta_at, total_cents, discount_cents, refunded_cents, subtotal_cents, tax_cents, tax_inclusive, currency, price_list_id, store_id, customer_id, contact_email, contact_phone, points_redeemed, points_cents, gift_card_cents, cart_id
+`
+
+type UpdateOrderStatusParams struct {
+	Status     string       `json:"status"`
+	EtaAt      sql.NullTime `json:"eta_at"`
+	StoreID    string       `json:"store_id"`
+	ID         string       `json:"id"`
+	FromStatus string       `json:"from_status"`
+}
+
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error) {
+	row := q.db.QueryRowContext(ctx, updateOrderStatus,
+		arg.Status,
+		arg.EtaAt,
+		arg.StoreID,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.CouponCode,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.EtaAt,
+		&i.TotalCents,
+		&i.DiscountCents,
+		&i.RefundedCents,
+		&i.SubtotalCents,
+		&i.TaxCents,
+		&i.TaxInclusive,
+		&i.Currency,
+		&i.PriceListID,
+		&i.StoreID,
+		&i.CustomerID,
+		&i.ContactEmail,
+		&i.ContactPhone,
+		&i.PointsRedeemed,
+		&i.PointsCents,
+		&i.GiftCardCents,
+		&i.CartID,
+	)
+	return i, err
+}
@@ -0,0 +1,111 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: payments.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getPaymentByOrder = `-- name: GetPaymentByOrder :one
+SELECT id, order_id, provider, provider_ref, status, amount_cents, captured_cents, currency, failure_reason, created_at, updated_at, store_id FROM payments WHERE store_id = $1 AND order_id = $2 ORDER BY created_at DESC LIMIT 1
+`
+
+type GetPaymentByOrderParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) GetPaymentByOrder(ctx context.Context, arg GetPaymentByOrderParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentByOrder, arg.StoreID, arg.OrderID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.Provider,
+		&i.ProviderRef,
+		&i.Status,
+		&i.AmountCents,
+		&i.CapturedCents,
+		&i.Currency,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StoreID,
+	)
+	return i, err
+}
+
+const insertPayment = `-- name: InsertPayment :exec
+INSERT INTO payments (store_id, id, order_id, provider, status, amount_cents, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertPaymentParams struct {
+	StoreID     string `json:"store_id"`
+	ID          string `json:"id"`
+	OrderID     string `json:"order_id"`
+	Provider    string `json:"provider"`
+	Status      string `json:"status"`
+	AmountCents int64  `json:"amount_cents"`
+	Currency    string `json:"currency"`
+}
+
+func (q *Queries) InsertPayment(ctx context.Context, arg InsertPaymentParams) error {
+	_, err := q.db.ExecContext(ctx, insertPayment,
+		arg.StoreID,
+		arg.ID,
+		arg.OrderID,
+		arg.Provider,
+		arg.Status,
+		arg.AmountCents,
+		arg.Currency,
+	)
+	return err
+}
+
+const updatePayment = `-- name: UpdatePayment :one
+UPDATE payments
+SET provider_ref = $3, status = $4, captured_cents = $5, failure_reason = $6, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $1 AND id = $2
+RETURNING id, order_id, provider, provider_ref, status, amount_cents, captured_cents, currency, failure_reason, created_at, updated_at, store_id
+`
+
+type UpdatePaymentParams struct {
+	StoreID       string         `json:"store_id"`
+	ID            string         `json:"id"`
+	ProviderRef   sql.NullString `json:"provider_ref"`
+	Status        string         `json:"status"`
+	CapturedCents int64          `json:"captured_cents"`
+	FailureReason sql.NullString `json:"failure_reason"`
+}
+
+func (q *Queries) UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error) {
+	row := q.db.QueryRowContext(ctx, updatePayment,
+		arg.StoreID,
+		arg.ID,
+		arg.ProviderRef,
+		arg.Status,
+		arg.CapturedCents,
+		arg.FailureReason,
+	)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.Provider,
+		&i.ProviderRef,
+		&i.Status,
+		&i.AmountCents,
+		&i.CapturedCents,
+		&i.Currency,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StoreID,
+	)
+	return i, err
+}
@@ -0,0 +1,75 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: price_adjustments.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const listPriceAdjustments = `-- name: ListPriceAdjustments :many
+SELECT pa.id, pa.name, pa.product_id, pa.category, pa.percent_off, pa.weekdays,
+  pa.local_start, pa.local_end, s.timezone
+FROM price_adjustments pa
+JOIN stores s ON s.id = pa.store_id
+WHERE pa.store_id = $1
+  AND (pa.starts_at IS NULL OR pa.starts_at <= $2::timestamp)
+  AND (pa.ends_at IS NULL OR pa.ends_at > $2::timestamp)
+ORDER BY pa.id
+`
+
+type ListPriceAdjustmentsParams struct {
+	StoreID string    `json:"store_id"`
+	At      time.Time `json:"at"`
+}
+
+type ListPriceAdjustmentsRow struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	ProductID  sql.NullString `json:"product_id"`
+	Category   sql.NullString `json:"category"`
+	PercentOff int32          `json:"percent_off"`
+	Weekdays   int16          `json:"weekdays"`
+	LocalStart time.Time      `json:"local_start"`
+	LocalEnd   time.Time      `json:"local_end"`
+	Timezone   string         `json:"timezone"`
+}
+
+// Returns the store's adjustments whose schedule covers at, with the store's
+// time zone to evaluate their daily windows in.
+func (q *Queries) ListPriceAdjustments(ctx context.Context, arg ListPriceAdjustmentsParams) ([]ListPriceAdjustmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPriceAdjustments, arg.StoreID, arg.At)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPriceAdjustmentsRow
+	for rows.Next() {
+		var i ListPriceAdjustmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ProductID,
+			&i.Category,
+			&i.PercentOff,
+			&i.Weekdays,
+			&i.LocalStart,
+			&i.LocalEnd,
+			&i.Timezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
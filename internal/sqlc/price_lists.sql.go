@@ -0,0 +1,133 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: price_lists.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+)
+
+const getDefaultPriceList = `-- name: GetDefaultPriceList :one
+SELECT id, store_id, currency, is_default, uses_base_prices, created_at FROM price_lists WHERE store_id = $1 AND is_default
+`
+
+func (q *Queries) GetDefaultPriceList(ctx context.Context, storeID string) (PriceList, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultPriceList, storeID)
+	var i PriceList
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Currency,
+		&i.IsDefault,
+		&i.UsesBasePrices,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPriceList = `-- name: GetPriceList :one
+SELECT id, store_id, currency, is_default, uses_base_prices, created_at FROM price_lists WHERE store_id = $1 AND currency = $2
+`
+
+type GetPriceListParams struct {
+	StoreID  string `json:"store_id"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetPriceList(ctx context.Context, arg GetPriceListParams) (PriceList, error) {
+	row := q.db.QueryRowContext(ctx, getPriceList, arg.StoreID, arg.Currency)
+	var i PriceList
+	err := row.Scan(
+		&i.ID,
+		&i.StoreID,
+		&i.Currency,
+		&i.IsDefault,
+		&i.UsesBasePrices,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPriceListPrices = `-- name: GetPriceListPrices :many
+SELECT p.price_list_id, p.product_id, p.price_cents, p.store_id FROM price_list_prices p
+JOIN price_lists l ON l.id = p.price_list_id
+WHERE l.store_id = $1 AND p.price_list_id = $2
+  AND p.product_id = ANY($3::text[])
+`
+
+type GetPriceListPricesParams struct {
+	StoreID     string   `json:"store_id"`
+	PriceListID string   `json:"price_list_id"`
+	ProductIds  []string `json:"product_ids"`
+}
+
+func (q *Queries) GetPriceListPrices(ctx context.Context, arg GetPriceListPricesParams) ([]PriceListPrice, error) {
+	rows, err := q.db.QueryContext(ctx, getPriceListPrices, arg.StoreID, arg.PriceListID, pq.Array(arg.ProductIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PriceListPrice
+	for rows.Next() {
+		var i PriceListPrice
+		if err := rows.Scan(
+			&i.PriceListID,
+			&i.ProductID,
+			&i.PriceCents,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPriceListPrices = `-- name: ListPriceListPrices :many
+SELECT p.price_list_id, p.product_id, p.price_cents, p.store_id FROM price_list_prices p
+JOIN price_lists l ON l.id = p.price_list_id
+WHERE l.store_id = $1 AND p.price_list_id = $2
+`
+
+type ListPriceListPricesParams struct {
+	StoreID     string `json:"store_id"`
+	PriceListID string `json:"price_list_id"`
+}
+
+func (q *Queries) ListPriceListPrices(ctx context.Context, arg ListPriceListPricesParams) ([]PriceListPrice, error) {
+	rows, err := q.db.QueryContext(ctx, listPriceListPrices, arg.StoreID, arg.PriceListID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PriceListPrice
+	for rows.Next() {
+		var i PriceListPrice
+		if err := rows.Scan(
+			&i.PriceListID,
+			&i.ProductID,
+			&i.PriceCents,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,16 +7,22 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/lib/pq"
 )
 
 const getProduct = `-- name: GetProduct :one
-SELECT id, name, category, price_cents, created_at, updated_at FROM products WHERE id = $1
+SELECT id, name, category, price_cents, created_at, updated_at, tax_class, store_id, slug FROM products WHERE store_id = $1 AND id = $2
 `
 
-func (q *Queries) GetProduct(ctx context.Context, id string) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProduct, id)
+type GetProductParams struct {
+	StoreID string `json:"store_id"`
+	ID      string `json:"id"`
+}
+
+func (q *Queries) GetProduct(ctx context.Context, arg GetProductParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getProduct, arg.StoreID, arg.ID)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -25,50 +31,50 @@ func (q *Queries) GetProduct(ctx context.Context, id string) (Product, error) {
 		&i.PriceCents,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TaxClass,
+		&i.StoreID,
+		&i.Slug,
 	)
 	return i, err
 }
 
-const getProductsByIDs = `-- name: GetProductsByIDs :many
-SELECT id, name, category, price_cents, created_at, updated_at FROM products WHERE id = ANY($1::text[])
+const getProductBySlug = `-- name: GetProductBySlug :one
+SELECT id, name, category, price_cents, created_at, updated_at, tax_class, store_id, slug FROM products WHERE store_id = $1 AND slug = $2
 `
 
-func (q *Queries) GetProductsByIDs(ctx context.Context, dollar_1 []string) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, getProductsByIDs, pq.Array(dollar_1))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Product
-	for rows.Next() {
-		var i Product
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Category,
-			&i.PriceCents,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type GetProductBySlugParams struct {
+	StoreID string         `json:"store_id"`
+	Slug    sql.NullString `json:"slug"`
+}
+
+func (q *Queries) GetProductBySlug(ctx context.Context, arg GetProductBySlugParams) (Product, error) {
+	row := q.db.QueryRowContext(ctx, getProductBySlug, arg.StoreID, arg.Slug)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Category,
+		&i.PriceCents,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TaxClass,
+		&i.StoreID,
+		&i.Slug,
+	)
+	return i, err
 }
 
-const listAllProducts = `-- name: ListAllProducts :many
-SELECT id, name, category, price_cents, created_at, updated_at FROM products ORDER BY id
+const getProductsByIDs = `-- name: GetProductsByIDs :many
+SELECT id, name, category, price_cents, created_at, updated_at, tax_class, store_id, slug FROM products WHERE store_id = $1 AND id = ANY($2::text[])
 `
 
-func (q *Queries) ListAllProducts(ctx context.Context) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listAllProducts)
+type GetProductsByIDsParams struct {
+	StoreID string   `json:"store_id"`
+	Ids     []string `json:"ids"`
+}
+
+func (q *Queries) GetProductsByIDs(ctx context.Context, arg GetProductsByIDsParams) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, getProductsByIDs, arg.StoreID, pq.Array(arg.Ids))
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +89,9 @@ func (q *Queries) ListAllProducts(ctx context.Context) ([]Product, error) {
 			&i.PriceCents,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TaxClass,
+			&i.StoreID,
+			&i.Slug,
 		); err != nil {
 			return nil, err
 		}
@@ -98,11 +107,11 @@ func (q *Queries) ListAllProducts(ctx context.Context) ([]Product, error) {
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, name, category, price_cents, created_at, updated_at FROM products ORDER BY id
+SELECT id, name, category, price_cents, created_at, updated_at, tax_class, store_id, slug FROM products WHERE store_id = $1 ORDER BY id
 `
 
-func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listProducts)
+func (q *Queries) ListProducts(ctx context.Context, storeID string) ([]Product, error) {
+	rows, err := q.db.QueryContext(ctx, listProducts, storeID)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +126,9 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.PriceCents,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TaxClass,
+			&i.StoreID,
+			&i.Slug,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,56 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: promotions.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+)
+
+const listActivePromotions = `-- name: ListActivePromotions :many
+SELECT store_id, id, name, priority, exclusive, rule, starts_at, ends_at FROM promotions
+WHERE store_id = $1
+  AND (starts_at IS NULL OR starts_at <= $2::timestamp)
+  AND (ends_at IS NULL OR ends_at > $2::timestamp)
+ORDER BY priority DESC, id
+`
+
+type ListActivePromotionsParams struct {
+	StoreID string    `json:"store_id"`
+	At      time.Time `json:"at"`
+}
+
+func (q *Queries) ListActivePromotions(ctx context.Context, arg ListActivePromotionsParams) ([]Promotion, error) {
+	rows, err := q.db.QueryContext(ctx, listActivePromotions, arg.StoreID, arg.At)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Promotion
+	for rows.Next() {
+		var i Promotion
+		if err := rows.Scan(
+			&i.StoreID,
+			&i.ID,
+			&i.Name,
+			&i.Priority,
+			&i.Exclusive,
+			&i.Rule,
+			&i.StartsAt,
+			&i.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -9,15 +9,93 @@ import (
 )
 
 type Querier interface {
-	GetCoupon(ctx context.Context, code string) (Coupon, error)
-	GetProduct(ctx context.Context, id string) (Product, error)
-	GetProductsByIDs(ctx context.Context, dollar_1 []string) ([]Product, error)
+	AddCartItem(ctx context.Context, arg AddCartItemParams) error
+	AddOrderItemRefunded(ctx context.Context, arg AddOrderItemRefundedParams) (int64, error)
+	AddOrderRefunded(ctx context.Context, arg AddOrderRefundedParams) error
+	ClaimCartCheckout(ctx context.Context, arg ClaimCartCheckoutParams) (int64, error)
+	CompleteCartCheckout(ctx context.Context, arg CompleteCartCheckoutParams) error
+	// Takes amount off the card unless it is disabled, expired or the balance
+	// falls short.
+	DebitGiftCard(ctx context.Context, arg DebitGiftCardParams) (int64, error)
+	// Takes points off the balance unless that would make it negative.
+	DebitLoyaltyBalance(ctx context.Context, arg DebitLoyaltyBalanceParams) (int64, error)
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error)
+	DeleteExpiredCarts(ctx context.Context, arg DeleteExpiredCartsParams) (int64, error)
+	ExpireAPIKey(ctx context.Context, arg ExpireAPIKeyParams) (int64, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetCart(ctx context.Context, arg GetCartParams) (Cart, error)
+	GetCoupon(ctx context.Context, arg GetCouponParams) (Coupon, error)
+	GetDefaultPriceList(ctx context.Context, storeID string) (PriceList, error)
+	GetGiftCard(ctx context.Context, arg GetGiftCardParams) (GiftCard, error)
+	GetGiftCardByCode(ctx context.Context, arg GetGiftCardByCodeParams) (GiftCard, error)
+	GetLoyaltyBalance(ctx context.Context, arg GetLoyaltyBalanceParams) (int64, error)
+	GetOrder(ctx context.Context, arg GetOrderParams) (Order, error)
+	GetPaymentByOrder(ctx context.Context, arg GetPaymentByOrderParams) (Payment, error)
+	GetPriceList(ctx context.Context, arg GetPriceListParams) (PriceList, error)
+	GetPriceListPrices(ctx context.Context, arg GetPriceListPricesParams) ([]PriceListPrice, error)
+	GetProduct(ctx context.Context, arg GetProductParams) (Product, error)
+	GetProductBySlug(ctx context.Context, arg GetProductBySlugParams) (Product, error)
+	GetProductsByIDs(ctx context.Context, arg GetProductsByIDsParams) ([]Product, error)
+	GetRateLimitTokens(ctx context.Context, key string) (float64, error)
+	GetStore(ctx context.Context, id string) (Store, error)
+	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) error
+	InsertCart(ctx context.Context, arg InsertCartParams) error
+	InsertGiftCard(ctx context.Context, arg InsertGiftCardParams) error
+	InsertGiftCardTransaction(ctx context.Context, arg InsertGiftCardTransactionParams) error
+	InsertLoyaltyEntry(ctx context.Context, arg InsertLoyaltyEntryParams) error
 	InsertOrder(ctx context.Context, arg InsertOrderParams) error
-	InsertOrderItem(ctx context.Context, arg InsertOrderItemParams) error
 	InsertOrderItems(ctx context.Context, arg InsertOrderItemsParams) error
-	ListAllProducts(ctx context.Context) ([]Product, error)
-	ListProducts(ctx context.Context) ([]Product, error)
-	TryRedeemSingleUse(ctx context.Context, code string) (string, error)
+	InsertOrderTax(ctx context.Context, arg InsertOrderTaxParams) error
+	InsertPayment(ctx context.Context, arg InsertPaymentParams) error
+	InsertRefund(ctx context.Context, arg InsertRefundParams) error
+	InsertRefundItem(ctx context.Context, arg InsertRefundItemParams) error
+	ListAPIKeys(ctx context.Context, storeID string) ([]ApiKey, error)
+	ListActivePromotions(ctx context.Context, arg ListActivePromotionsParams) ([]Promotion, error)
+	ListCartItems(ctx context.Context, arg ListCartItemsParams) ([]CartItem, error)
+	ListCategoryTaxClasses(ctx context.Context, storeID string) ([]CategoryTaxClass, error)
+	ListCustomerOrders(ctx context.Context, arg ListCustomerOrdersParams) ([]Order, error)
+	ListGiftCardTransactions(ctx context.Context, arg ListGiftCardTransactionsParams) ([]GiftCardTransaction, error)
+	ListLoyaltyEntries(ctx context.Context, arg ListLoyaltyEntriesParams) ([]LoyaltyEntry, error)
+	ListLoyaltyRules(ctx context.Context, storeID string) ([]LoyaltyRule, error)
+	ListOrderItems(ctx context.Context, arg ListOrderItemsParams) ([]OrderItem, error)
+	ListOrderLoyaltyEntries(ctx context.Context, arg ListOrderLoyaltyEntriesParams) ([]LoyaltyEntry, error)
+	ListOrderTaxes(ctx context.Context, arg ListOrderTaxesParams) ([]OrderTax, error)
+	// Returns the store's adjustments whose schedule covers at, with the store's
+	// time zone to evaluate their daily windows in.
+	ListPriceAdjustments(ctx context.Context, arg ListPriceAdjustmentsParams) ([]ListPriceAdjustmentsRow, error)
+	ListPriceListPrices(ctx context.Context, arg ListPriceListPricesParams) ([]PriceListPrice, error)
+	ListProducts(ctx context.Context, storeID string) ([]Product, error)
+	ListRefundItems(ctx context.Context, arg ListRefundItemsParams) ([]RefundItem, error)
+	ListRefundItemsByOrder(ctx context.Context, arg ListRefundItemsByOrderParams) ([]RefundItem, error)
+	ListRefundsByOrder(ctx context.Context, arg ListRefundsByOrderParams) ([]Refund, error)
+	ListStores(ctx context.Context) ([]Store, error)
+	ListTaxRates(ctx context.Context, storeID string) ([]TaxRate, error)
+	LockOrder(ctx context.Context, arg LockOrderParams) (Order, error)
+	// Appends the entry and applies it to the balance, or does nothing if an
+	// entry with the same key was posted before.
+	PostLoyaltyEntry(ctx context.Context, arg PostLoyaltyEntryParams) (int64, error)
+	// Buckets that have refilled behave like new ones.
+	PurgeFullRateLimitBuckets(ctx context.Context) (int64, error)
+	ReleaseCartCheckout(ctx context.Context, arg ReleaseCartCheckoutParams) error
+	ReleaseRedemption(ctx context.Context, arg ReleaseRedemptionParams) error
+	// Credits back what the order took from each gift card, skipping cards
+	// already restored for it.
+	RestoreOrderGiftCards(ctx context.Context, arg RestoreOrderGiftCardsParams) (int64, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	SetCartCoupon(ctx context.Context, arg SetCartCouponParams) error
+	SetCartItem(ctx context.Context, arg SetCartItemParams) error
+	SumSucceededRefunds(ctx context.Context, arg SumSucceededRefundsParams) (int64, error)
+	// Takes a token from the bucket, creating it full. No row is returned when
+	// the bucket is empty.
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error)
+	TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error
+	TouchCart(ctx context.Context, arg TouchCartParams) error
+	TryRedeemSingleUse(ctx context.Context, arg TryRedeemSingleUseParams) (string, error)
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	UpdatePayment(ctx context.Context, arg UpdatePaymentParams) (Payment, error)
+	UpdateRefund(ctx context.Context, arg UpdateRefundParams) (Refund, error)
+	// Contact details are only overwritten by ones that were given.
+	UpsertCustomer(ctx context.Context, arg UpsertCustomerParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,64 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: rate_limits.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getRateLimitTokens = `-- name: GetRateLimitTokens :one
+SELECT LEAST(burst, tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 * rate)::float8 AS tokens
+FROM rate_limit_buckets
+WHERE key = $1
+`
+
+func (q *Queries) GetRateLimitTokens(ctx context.Context, key string) (float64, error) {
+	row := q.db.QueryRowContext(ctx, getRateLimitTokens, key)
+	var tokens float64
+	err := row.Scan(&tokens)
+	return tokens, err
+}
+
+const purgeFullRateLimitBuckets = `-- name: PurgeFullRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 * rate >= burst
+`
+
+// Buckets that have refilled behave like new ones.
+func (q *Queries) PurgeFullRateLimitBuckets(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeFullRateLimitBuckets)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS b (key, tokens, rate, burst, updated_at)
+VALUES ($1, $2::integer - 1, $3::float8, $2::integer, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(EXCLUDED.burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * EXCLUDED.rate) - 1,
+    rate = EXCLUDED.rate,
+    burst = EXCLUDED.burst,
+    updated_at = now()
+WHERE LEAST(EXCLUDED.burst, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * EXCLUDED.rate) >= 1
+RETURNING tokens
+`
+
+type TakeRateLimitTokenParams struct {
+	Key   string  `json:"key"`
+	Burst int32   `json:"burst"`
+	Rate  float64 `json:"rate"`
+}
+
+// Takes a token from the bucket, creating it full. No row is returned when
+// the bucket is empty.
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error) {
+	row := q.db.QueryRowContext(ctx, takeRateLimitToken, arg.Key, arg.Burst, arg.Rate)
+	var tokens float64
+	err := row.Scan(&tokens)
+	return tokens, err
+}
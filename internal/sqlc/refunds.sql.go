@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: refunds.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const insertRefund = `-- name: InsertRefund :exec
+INSERT INTO refunds (store_id, id, order_id, payment_id, amount_cents, reason, operator)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type InsertRefundParams struct {
+	StoreID     string `json:"store_id"`
+	ID          string `json:"id"`
+	OrderID     string `json:"order_id"`
+	PaymentID   string `json:"payment_id"`
+	AmountCents int64  `json:"amount_cents"`
+	Reason      string `json:"reason"`
+	Operator    string `json:"operator"`
+}
+
+func (q *Queries) InsertRefund(ctx context.Context, arg InsertRefundParams) error {
+	_, err := q.db.ExecContext(ctx, insertRefund,
+		arg.StoreID,
+		arg.ID,
+		arg.OrderID,
+		arg.PaymentID,
+		arg.AmountCents,
+		arg.Reason,
+		arg.Operator,
+	)
+	return err
+}
+
+const insertRefundItem = `-- name: InsertRefundItem :exec
+INSERT INTO refund_items (refund_id, order_item_id, quantity, amount_cents)
+SELECT r.id, $1::text, $2::int4, $3::int8
+FROM refunds r WHERE r.store_id = $4 AND r.id = $5
+`
+
+type InsertRefundItemParams struct {
+	OrderItemID string `json:"order_item_id"`
+	Quantity    int32  `json:"quantity"`
+	AmountCents int64  `json:"amount_cents"`
+	StoreID     string `json:"store_id"`
+	RefundID    string `json:"refund_id"`
+}
+
+func (q *Queries) InsertRefundItem(ctx context.Context, arg InsertRefundItemParams) error {
+	_, err := q.db.ExecContext(ctx, insertRefundItem,
+		arg.OrderItemID,
+		arg.Quantity,
+		arg.AmountCents,
+		arg.StoreID,
+		arg.RefundID,
+	)
+	return err
+}
+
+const listRefundItems = `-- name: ListRefundItems :many
+SELECT ri.refund_id, ri.order_item_id, ri.quantity, ri.amount_cents FROM refund_items ri
+JOIN refunds r ON r.id = ri.refund_id
+WHERE r.store_id = $1 AND ri.refund_id = $2
+`
+
+type ListRefundItemsParams struct {
+	StoreID  string `json:"store_id"`
+	RefundID string `json:"refund_id"`
+}
+
+func (q *Queries) ListRefundItems(ctx context.Context, arg ListRefundItemsParams) ([]RefundItem, error) {
+	rows, err := q.db.QueryContext(ctx, listRefundItems, arg.StoreID, arg.RefundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefundItem
+	for rows.Next() {
+		var i RefundItem
+		if err := rows.Scan(
+			&i.RefundID,
+			&i.OrderItemID,
+			&i.Quantity,
+			&i.AmountCents,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRefundItemsByOrder = `-- name: ListRefundItemsByOrder :many
+SELECT ri.refund_id, ri.order_item_id, ri.quantity, ri.amount_cents FROM refund_items ri
+JOIN refunds r ON r.id = ri.refund_id
+WHERE r.store_id = $1 AND r.order_id = $2
+`
+
+type ListRefundItemsByOrderParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) ListRefundItemsByOrder(ctx context.Context, arg ListRefundItemsByOrderParams) ([]RefundItem, error) {
+	rows, err := q.db.QueryContext(ctx, listRefundItemsByOrder, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefundItem
+	for rows.Next() {
+		var i RefundItem
+		if err := rows.Scan(
+			&i.RefundID,
+			&i.OrderItemID,
+			&i.Quantity,
+			&i.AmountCents,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRefundsByOrder = `-- name: ListRefundsByOrder :many
+SELECT id, order_id, payment_id, amount_cents, reason, operator, status, provider_ref, failure_reason, created_at, updated_at, store_id FROM refunds WHERE store_id = $1 AND order_id = $2 ORDER BY created_at, id
+`
+
+type ListRefundsByOrderParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) ListRefundsByOrder(ctx context.Context, arg ListRefundsByOrderParams) ([]Refund, error) {
+	rows, err := q.db.QueryContext(ctx, listRefundsByOrder, arg.StoreID, arg.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Refund
+	for rows.Next() {
+		var i Refund
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PaymentID,
+			&i.AmountCents,
+			&i.Reason,
+			&i.Operator,
+			&i.Status,
+			&i.ProviderRef,
+			&i.FailureReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StoreID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumSucceededRefunds = `-- name: SumSucceededRefunds :one
+SELECT COALESCE(SUM(amount_cents), 0)::int8 FROM refunds
+WHERE store_id = $1 AND order_id = $2 AND status = 'succeeded'
+`
+
+type SumSucceededRefundsParams struct {
+	StoreID string `json:"store_id"`
+	OrderID string `json:"order_id"`
+}
+
+func (q *Queries) SumSucceededRefunds(ctx context.Context, arg SumSucceededRefundsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumSucceededRefunds, arg.StoreID, arg.OrderID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const updateRefund = `-- name: UpdateRefund :one
+UPDATE refunds
+SET status = $3, provider_ref = $4, failure_reason = $5, updated_at = CURRENT_TIMESTAMP
+WHERE store_id = $1 AND id = $2
+RETURNING id, order_id, payment_id, amount_cents, reason, operator, status, provider_ref, failure_reason, created_at, updated_at, store_id
+`
+
+type UpdateRefundParams struct {
+	StoreID       string         `json:"store_id"`
+	ID            string         `json:"id"`
+	Status        string         `json:"status"`
+	ProviderRef   sql.NullString `json:"provider_ref"`
+	FailureReason sql.NullString `json:"failure_reason"`
+}
+
+func (q *Queries) UpdateRefund(ctx context.Context, arg UpdateRefundParams) (Refund, error) {
+	row := q.db.QueryRowContext(ctx, updateRefund,
+		arg.StoreID,
+		arg.ID,
+		arg.Status,
+		arg.ProviderRef,
+		arg.FailureReason,
+	)
+	var i Refund
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.PaymentID,
+		&i.AmountCents,
+		&i.Reason,
+		&i.Operator,
+		&i.Status,
+		&i.ProviderRef,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StoreID,
+	)
+	return i, err
+}
@@ -0,0 +1,58 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: stores.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getStore = `-- name: GetStore :one
+SELECT id, name, created_at, timezone FROM stores WHERE id = $1
+`
+
+func (q *Queries) GetStore(ctx context.Context, id string) (Store, error) {
+	row := q.db.QueryRowContext(ctx, getStore, id)
+	var i Store
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const listStores = `-- name: ListStores :many
+SELECT id, name, created_at, timezone FROM stores ORDER BY id
+`
+
+func (q *Queries) ListStores(ctx context.Context) ([]Store, error) {
+	rows, err := q.db.QueryContext(ctx, listStores)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Store
+	for rows.Next() {
+		var i Store
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.Timezone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,64 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tax.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const listCategoryTaxClasses = `-- name: ListCategoryTaxClasses :many
+SELECT category, tax_class, store_id FROM category_tax_classes WHERE store_id = $1
+`
+
+func (q *Queries) ListCategoryTaxClasses(ctx context.Context, storeID string) ([]CategoryTaxClass, error) {
+	rows, err := q.db.QueryContext(ctx, listCategoryTaxClasses, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CategoryTaxClass
+	for rows.Next() {
+		var i CategoryTaxClass
+		if err := rows.Scan(&i.Category, &i.TaxClass, &i.StoreID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaxRates = `-- name: ListTaxRates :many
+SELECT store_id, tax_class, rate_basis_points FROM tax_rates WHERE store_id = $1
+`
+
+func (q *Queries) ListTaxRates(ctx context.Context, storeID string) ([]TaxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listTaxRates, storeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxRate
+	for rows.Next() {
+		var i TaxRate
+		if err := rows.Scan(&i.StoreID, &i.TaxClass, &i.RateBasisPoints); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
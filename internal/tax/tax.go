@@ -0,0 +1,129 @@
+// Package tax computes sales tax (GST/VAT) for priced order lines.
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Mode says whether catalog prices already include tax.
+type Mode string
+
+const (
+	// Inclusive prices contain the tax (Australian GST style); tax is
+	// extracted from them and the total is unchanged.
+	Inclusive Mode = "inclusive"
+	// Exclusive prices are net; tax is added on top.
+	Exclusive Mode = "exclusive"
+)
+
+// Rounding says where tax is rounded to whole cents.
+type Rounding string
+
+const (
+	// PerLine rounds the tax of every line and sums the rounded amounts.
+	PerLine Rounding = "line"
+	// PerOrder sums the taxable amounts of each class and rounds once per class.
+	PerOrder Rounding = "order"
+)
+
+// DefaultClass applies to products with no tax class of their own or of their category.
+const DefaultClass = "standard"
+
+// ErrUnknownClass is returned for a line whose tax class has no configured rate.
+var ErrUnknownClass = errors.New("no tax rate for tax class")
+
+// Rates maps tax class codes to rates in basis points (1000 = 10%).
+type Rates map[string]int32
+
+// Line is a taxable amount, after discounts, in a tax class.
+type Line struct {
+	Class       string
+	AmountCents int64
+}
+
+// ClassTotal is the tax collected for one tax class.
+type ClassTotal struct {
+	Class           string
+	RateBasisPoints int32
+	TaxableCents    int64
+	TaxCents        int64
+}
+
+// Result is the tax on a set of lines. LineTax holds the rounded tax of each
+// line in input order; under PerOrder rounding the class totals may differ
+// from the sum of LineTax by a few cents and are authoritative.
+type Result struct {
+	LineTax  []int64
+	Classes  []ClassTotal
+	TaxCents int64
+}
+
+type Calculator struct {
+	Mode     Mode
+	Rounding Rounding
+	Rates    Rates
+}
+
+// ParseMode validates a configured tax mode.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case Inclusive, Exclusive:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown tax mode %q", s)
+}
+
+// ParseRounding validates a configured rounding rule.
+func ParseRounding(s string) (Rounding, error) {
+	switch r := Rounding(s); r {
+	case PerLine, PerOrder:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown tax rounding %q", s)
+}
+
+// Calculate returns the tax on lines. Classes are reported in code order.
+func (c Calculator) Calculate(lines []Line) (Result, error) {
+	res := Result{LineTax: make([]int64, len(lines))}
+	byClass := make(map[string]*ClassTotal)
+	for i, l := range lines {
+		rate, ok := c.Rates[l.Class]
+		if !ok {
+			return Result{}, fmt.Errorf("%w %q", ErrUnknownClass, l.Class)
+		}
+		res.LineTax[i] = c.tax(l.AmountCents, rate)
+
+		ct, ok := byClass[l.Class]
+		if !ok {
+			ct = &ClassTotal{Class: l.Class, RateBasisPoints: rate}
+			byClass[l.Class] = ct
+		}
+		ct.TaxableCents += l.AmountCents
+		if c.Rounding != PerOrder {
+			ct.TaxCents += res.LineTax[i]
+		}
+	}
+
+	res.Classes = make([]ClassTotal, 0, len(byClass))
+	for _, ct := range byClass {
+		if c.Rounding == PerOrder {
+			ct.TaxCents = c.tax(ct.TaxableCents, ct.RateBasisPoints)
+		}
+		res.TaxCents += ct.TaxCents
+		res.Classes = append(res.Classes, *ct)
+	}
+	sort.Slice(res.Classes, func(i, j int) bool { return res.Classes[i].Class < res.Classes[j].Class })
+	return res, nil
+}
+
+// tax returns the tax on amount at rate basis points, rounded half up.
+func (c Calculator) tax(amount int64, rate int32) int64 {
+	num := amount * int64(rate)
+	den := int64(10000)
+	if c.Mode == Inclusive {
+		den += int64(rate)
+	}
+	return (2*num + den) / (2 * den)
+}
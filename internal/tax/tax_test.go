@@ -0,0 +1,83 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculator_Calculate(t *testing.T) {
+	rates := Rates{"standard": 1000, "exempt": 0}
+	type tc struct {
+		name      string
+		calc      Calculator
+		lines     []Line
+		wantLines []int64
+		wantTax   int64
+		wantErr   error
+	}
+	cases := []tc{
+		{
+			name:      "inclusive gst is one eleventh",
+			calc:      Calculator{Mode: Inclusive, Rounding: PerLine, Rates: rates},
+			lines:     []Line{{Class: "standard", AmountCents: 1100}, {Class: "exempt", AmountCents: 499}},
+			wantLines: []int64{100, 0},
+			wantTax:   100,
+		},
+		{
+			name:      "exclusive adds rate",
+			calc:      Calculator{Mode: Exclusive, Rounding: PerLine, Rates: rates},
+			lines:     []Line{{Class: "standard", AmountCents: 1299}},
+			wantLines: []int64{130},
+			wantTax:   130,
+		},
+		{
+			// 1.5c of tax per line rounds up to 2c each; 4.5c rounded once is 5c.
+			name:      "per line rounding",
+			calc:      Calculator{Mode: Exclusive, Rounding: PerLine, Rates: rates},
+			lines:     []Line{{Class: "standard", AmountCents: 15}, {Class: "standard", AmountCents: 15}, {Class: "standard", AmountCents: 15}},
+			wantLines: []int64{2, 2, 2},
+			wantTax:   6,
+		},
+		{
+			name:      "per order rounding",
+			calc:      Calculator{Mode: Exclusive, Rounding: PerOrder, Rates: rates},
+			lines:     []Line{{Class: "standard", AmountCents: 15}, {Class: "standard", AmountCents: 15}, {Class: "standard", AmountCents: 15}},
+			wantLines: []int64{2, 2, 2},
+			wantTax:   5,
+		},
+		{
+			name:    "unknown class",
+			calc:    Calculator{Mode: Inclusive, Rates: rates},
+			lines:   []Line{{Class: "luxury", AmountCents: 100}},
+			wantErr: ErrUnknownClass,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.calc.Calculate(c.lines)
+			if c.wantErr != nil {
+				require.ErrorIs(t, err, c.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.wantLines, got.LineTax)
+			require.Equal(t, c.wantTax, got.TaxCents)
+		})
+	}
+}
+
+func TestCalculator_ClassTotals(t *testing.T) {
+	calc := Calculator{Mode: Inclusive, Rounding: PerOrder, Rates: Rates{"standard": 1000, "exempt": 0}}
+	got, err := calc.Calculate([]Line{
+		{Class: "standard", AmountCents: 1299},
+		{Class: "exempt", AmountCents: 499},
+		{Class: "standard", AmountCents: 999},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ClassTotal{
+		{Class: "exempt", RateBasisPoints: 0, TaxableCents: 499, TaxCents: 0},
+		{Class: "standard", RateBasisPoints: 1000, TaxableCents: 2298, TaxCents: 209},
+	}, got.Classes)
+	require.Equal(t, int64(209), got.TaxCents)
+}
@@ -0,0 +1,37 @@
+// Package tenant carries the store a request acts for through its context.
+// Repositories refuse to run without one, so a request can only ever read or
+// write its own store's data.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrNoStore is returned when a context carries no store.
+var ErrNoStore = errors.New("no store in context")
+
+// idPattern is the shape of a store ID, which also appears in URL paths.
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type ctxKey struct{}
+
+// WithStore returns a copy of ctx acting for storeID.
+func WithStore(ctx context.Context, storeID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, storeID)
+}
+
+// StoreID returns the store ctx acts for.
+func StoreID(ctx context.Context) (string, error) {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	if id == "" {
+		return "", ErrNoStore
+	}
+	return id, nil
+}
+
+// ValidID reports whether id is a well-formed store ID.
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
@@ -0,0 +1,33 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreID(t *testing.T) {
+	_, err := StoreID(context.Background())
+	require.ErrorIs(t, err, ErrNoStore)
+
+	_, err = StoreID(WithStore(context.Background(), ""))
+	require.ErrorIs(t, err, ErrNoStore)
+
+	id, err := StoreID(WithStore(context.Background(), "bondi"))
+	require.NoError(t, err)
+	require.Equal(t, "bondi", id)
+}
+
+func TestValidID(t *testing.T) {
+	for id, want := range map[string]bool{
+		"default":     true,
+		"bondi-beach": true,
+		"Bondi":       false,
+		"-bondi":      false,
+		"":            false,
+		"a/b":         false,
+	} {
+		require.Equal(t, want, ValidID(id), id)
+	}
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"kart/internal/sqlc"
+)
+
+var dbTracer = otel.Tracer("kart/internal/tracing/db")
+
+// DB wraps db so every query runs in a client span named after its sqlc
+// statement, such as GetProductsByIDs.
+func DB(db sqlc.DBTX) sqlc.DBTX { return tracedDB{db} }
+
+type tracedDB struct{ db sqlc.DBTX }
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	res, err := t.db.ExecContext(ctx, query, args...)
+	if err == nil {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", n))
+		}
+	}
+	End(span, err)
+	return res, err
+}
+
+func (t tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuery(ctx, query)
+	stmt, err := t.db.PrepareContext(ctx, query)
+	End(span, err)
+	return stmt, err
+}
+
+// QueryContext's span covers the query until the first rows arrive, not
+// reading them.
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	End(span, err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	End(span, row.Err())
+	return row
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	name := StatementName(query)
+	return dbTracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+// StatementName returns the name sqlc gives query in its "-- name: X :kind"
+// header, or the query's first keyword for hand-written SQL.
+func StatementName(query string) string {
+	q := strings.TrimSpace(query)
+	if rest, ok := strings.CutPrefix(q, "-- name:"); ok {
+		if f := strings.Fields(rest); len(f) > 0 {
+			return f[0]
+		}
+	}
+	if f := strings.Fields(q); len(f) > 0 {
+		return strings.ToUpper(f[0])
+	}
+	return "query"
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStatementName(t *testing.T) {
+	type tc struct {
+		name  string
+		query string
+		want  string
+	}
+	cases := []tc{
+		{name: "sqlc header", query: "-- name: GetProductsByIDs :many\nSELECT id FROM products", want: "GetProductsByIDs"},
+		{name: "leading whitespace", query: "\n  -- name: InsertOrder :one\nINSERT INTO orders", want: "InsertOrder"},
+		{name: "hand written", query: "select 1", want: "SELECT"},
+		{name: "empty", query: "", want: "query"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.want, StatementName(c.query))
+		})
+	}
+}
+
+func TestDB(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+
+	db, sm, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sm.ExpectQuery("GetStore").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+	sm.ExpectExec("TouchAPIKey").WillReturnError(errors.New("connection reset"))
+
+	ctx := context.Background()
+	var id string
+	require.NoError(t, DB(db).QueryRowContext(ctx, "-- name: GetStore :one\nSELECT id FROM stores").Scan(&id))
+	_, err = DB(db).ExecContext(ctx, "-- name: TouchAPIKey :exec\nUPDATE api_keys SET last_used_at = now()")
+	require.Error(t, err)
+	require.NoError(t, sm.ExpectationsWereMet())
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "GetStore", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, "TouchAPIKey", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	type tc struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}
+	cases := []tc{
+		{name: "none", exporter: ExporterNone},
+		{name: "unset", exporter: ""},
+		{name: "unknown", exporter: "zipkin", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), c.exporter, "kart", io.Discard)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, shutdown(context.Background()))
+		})
+	}
+}